@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -11,10 +12,21 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/lupa-cidada/backend/internal/agendador"
+	"github.com/lupa-cidada/backend/internal/auth"
 	"github.com/lupa-cidada/backend/internal/config"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/gql"
 	"github.com/lupa-cidada/backend/internal/handlers"
+	"github.com/lupa-cidada/backend/internal/limite"
+	"github.com/lupa-cidada/backend/internal/migracoes"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/partidos"
 	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
 	"github.com/lupa-cidada/backend/internal/services"
+	"github.com/lupa-cidada/backend/internal/sync/arquivo"
+	"github.com/lupa-cidada/backend/internal/sync/orquestrador"
 	"github.com/lupa-cidada/backend/pkg/database"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -36,35 +48,165 @@ func main() {
 		defer mongoClient.Disconnect(context.Background())
 		db = mongoClient.Database("lupa_cidada")
 		log.Println("📦 Conectado ao MongoDB")
+
+		// As migrações podem percorrer coleções grandes: a API começa a responder enquanto elas
+		// rodam. O worker e o cmd/sync, que gravam os dados corrigidos por elas, esperam.
+		go func() {
+			if _, err := migracoes.Aplicar(context.Background(), db, migracoes.Todas); err != nil {
+				log.Printf("⚠️  Erro ao aplicar as migrações: %v", err)
+			}
+		}()
+		if err := partidos.Semear(context.Background(), db); err != nil {
+			log.Fatalf("Erro ao gravar os partidos padrão: %v", err)
+		}
 	} else {
 		log.Println("🔧 Modo DEBUG ativado - usando dados mockados")
 	}
 
-	// Inicializar repositórios (podem ser nil em modo debug)
-	var politicoRepo *repository.PoliticoRepository
-	var votacaoRepo *repository.VotacaoRepository
-	var despesaRepo *repository.DespesaRepository
-	var proposicaoRepo *repository.ProposicaoRepository
+	// Repositórios: MongoDB ou, em modo debug, memória com os dados mockados
+	var politicoRepo repository.Politicos
+	var votacaoRepo repository.Votacoes
+	var despesaRepo repository.Despesas
+	var proposicaoRepo repository.Proposicoes
+	var presencaRepo repository.Presencas
+	var alertaRepo repository.Alertas
+	var fornecedorRepo repository.Fornecedores
+	var partidoRepo repository.Partidos
+	var alteracaoRepo repository.Alteracoes
+	var unificacaoRepo repository.Unificacoes
+	var chaveRepo repository.Chaves
+	var qualidadeRepo repository.Qualidade
+	var execucaoSyncRepo repository.ExecucoesSync
+
+	// Sincronizações (nil em modo debug, em que nenhuma é iniciada)
+	var orquestradorSync *orquestrador.Orquestrador
+	var travaSync *agendador.Trava
 
 	if db != nil {
 		politicoRepo = repository.NewPoliticoRepository(db)
 		votacaoRepo = repository.NewVotacaoRepository(db)
 		despesaRepo = repository.NewDespesaRepository(db)
 		proposicaoRepo = repository.NewProposicaoRepository(db)
+		presencaRepo = repository.NewPresencaRepository(db)
+		alertaRepo = repository.NewAlertaRepository(db)
+		fornecedorRepo = repository.NewFornecedorRepository(db)
+		partidoRepo = repository.NewPartidoRepository(db)
+		alteracaoRepo = repository.NewAlteracaoRepository(db)
+		unificacaoRepo = repository.NewUnificacaoRepository(db)
+		chaveRepo = repository.NewChaveRepository(db)
+		execucaoSyncRepo = repository.NewExecucaoSyncRepository(db)
+		qualidadeRepo = repository.NewQualidadeRepository(db)
+
+		arquivoBruto, err := arquivo.Abrir(db, cfg.ArquivoBruto)
+		if err != nil {
+			log.Printf("⚠️  Arquivo de respostas das sincronizações desativado: %v", err)
+		}
+		orquestradorSync = orquestrador.New(db, arquivoBruto)
+
+		// As sincronizações pedidas pela API disputam as travas das entidades com o worker
+		hostname, _ := os.Hostname()
+		travaSync = agendador.NewTrava(db, fmt.Sprintf("api:%s:%d", hostname, os.Getpid()), 10*time.Minute)
+	} else {
+		dados := mock.Todos()
+		if cfg.DebugPoliticos > 0 {
+			dados = mock.Gerar(mock.Configuracao{
+				Semente:         int64(cfg.DebugSemente),
+				Politicos:       cfg.DebugPoliticos,
+				VotacoesPorCasa: mock.VotacoesPorCasaPadrao,
+			})
+			log.Printf("🎲 %d políticos sintéticos gerados (semente %d)", len(dados.Politicos), cfg.DebugSemente)
+		}
+		banco := memoria.NewBanco(dados)
+		politicoRepo = memoria.NewPoliticoRepository(banco)
+		votacaoRepo = memoria.NewVotacaoRepository(banco)
+		despesaRepo = memoria.NewDespesaRepository(banco)
+		proposicaoRepo = memoria.NewProposicaoRepository(banco)
+		presencaRepo = memoria.NewPresencaRepository(banco)
+		alertaRepo = memoria.NewAlertaRepository(banco)
+		fornecedorRepo = memoria.NewFornecedorRepository(banco)
+		partidoRepo = memoria.NewPartidoRepository(banco)
+		alteracaoRepo = memoria.NewAlteracaoRepository(banco)
+		unificacaoRepo = memoria.NewUnificacaoRepository(banco)
+		chaveRepo = memoria.NewChaveRepository(banco)
+		qualidadeRepo = memoria.NewQualidadeRepository(banco)
+		execucaoSyncRepo = memoria.NewExecucaoSyncRepository(banco)
+	}
+
+	// Autenticação das rotas protegidas (em modo debug, as chaves criadas ficam só em memória)
+	autenticador := auth.NewAutenticador(chaveRepo, cfg.JWTSecret)
+
+	// Limites de uso da API pública (contadores no Redis, ou em memória se indisponível)
+	contador := limite.NewContadorMemoria()
+	if !cfg.Debug {
+		contador = limite.NewContador(context.Background(), cfg.RedisURI)
+	}
+	regrasUso := limite.Regras{
+		Anonimo: limite.Regra{PorMinuto: cfg.LimiteAnonimoPorMinuto, CotaDiaria: cfg.LimiteAnonimoCotaDiaria},
+		Chave:   limite.Regra{PorMinuto: cfg.LimiteChavePorMinuto, CotaDiaria: cfg.LimiteChaveCotaDiaria},
+	}
+
+	// Inicializar serviços (só as sincronizações dependem do modo debug)
+	politicoService := services.NewPoliticoService(politicoRepo, votacaoRepo, despesaRepo, proposicaoRepo, presencaRepo)
+	despesaService := services.NewDespesaService(despesaRepo)
+	exportacaoService := services.NewExportacaoService(politicoRepo, votacaoRepo, despesaRepo, proposicaoRepo)
+	dadosAbertosService := services.NewDadosAbertosService(cfg.DadosAbertos)
+	alertaService := services.NewAlertaService(alertaRepo)
+	fornecedorService := services.NewFornecedorService(fornecedorRepo)
+	partidoService := services.NewPartidoService(partidoRepo)
+	alteracaoService := services.NewAlteracaoService(alteracaoRepo)
+	unificacaoService := services.NewUnificacaoService(unificacaoRepo)
+	chaveService := services.NewChaveService(chaveRepo)
+	syncService := services.NewSyncService(execucaoSyncRepo, orquestradorSync, travaSync)
+	qualidadeService := services.NewQualidadeService(qualidadeRepo)
+
+	// Execuções que ficaram em andamento quando algum processo caiu
+	if n, err := syncService.RecuperarInterrompidas(context.Background()); err != nil {
+		log.Printf("⚠️  Erro ao recuperar sincronizações interrompidas: %v", err)
+	} else if n > 0 {
+		log.Printf("🧹 %d sincronizações interrompidas marcadas como INTERROMPIDA", n)
 	}
 
-	// Inicializar serviços (passa cfg.Debug para decidir fonte dos dados)
-	politicoService := services.NewPoliticoService(cfg.Debug, politicoRepo, votacaoRepo, despesaRepo, proposicaoRepo)
+	// Esquema GraphQL sobre os mesmos repositórios
+	esquemaGraphQL, err := gql.NewEsquema(gql.Fontes{
+		Politicos:    politicoRepo,
+		Votacoes:     votacaoRepo,
+		Despesas:     despesaRepo,
+		Proposicoes:  proposicaoRepo,
+		Presencas:    presencaRepo,
+		Estatisticas: politicoService,
+	}, gql.Limites{Profundidade: cfg.GraphQLProfundidadeMaxima, Custo: cfg.GraphQLCustoMaximo})
+	if err != nil {
+		log.Fatalf("❌ Erro ao montar o esquema GraphQL: %v", err)
+	}
 
 	// Inicializar handlers
 	politicoHandler := handlers.NewPoliticoHandler(politicoService)
-	filtrosHandler := handlers.NewFiltrosHandler(db, cfg.Debug)
+	filtrosHandler := handlers.NewFiltrosHandler(partidoService)
 	estatisticasHandler := handlers.NewEstatisticasHandler(politicoService)
+	despesaHandler := handlers.NewDespesaHandler(despesaService)
+	alertaHandler := handlers.NewAlertaHandler(alertaService)
+	fornecedorHandler := handlers.NewFornecedorHandler(fornecedorService)
+	partidoHandler := handlers.NewPartidoHandler(partidoService)
+	alteracaoHandler := handlers.NewAlteracaoHandler(alteracaoService)
+	unificacaoHandler := handlers.NewUnificacaoHandler(unificacaoService)
+	authHandler := handlers.NewAuthHandler(chaveService, autenticador, cfg.JWTValidade)
+	syncHandler := handlers.NewSyncHandler(syncService)
+	qualidadeHandler := handlers.NewQualidadeHandler(qualidadeService)
+	exportacaoHandler := handlers.NewExportacaoHandler(exportacaoService)
+	dadosAbertosHandler := handlers.NewDadosAbertosHandler(dadosAbertosService)
+	graphqlHandler := handlers.NewGraphQLHandler(esquemaGraphQL)
 
 	// Configurar Echo
 	e := echo.New()
 	e.HideBanner = true
 
+	// IP dos clientes nos limites de uso: o da conexão, ou o X-Forwarded-For dos proxies confiáveis
+	extratorIP, err := limite.ExtratorIP(cfg.ProxiesConfiaveis)
+	if err != nil {
+		log.Fatalf("❌ PROXIES_CONFIAVEIS: %v", err)
+	}
+	e.IPExtractor = extratorIP
+
 	// Middlewares
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
@@ -80,8 +222,13 @@ func main() {
 		})
 	})
 
-	// API v1
-	api := e.Group("/api/v1")
+	// API v1 (chave de API opcional; identifica o cliente para os limites de uso). As credenciais
+	// recusadas são limitadas por IP antes de consultar o banco.
+	api := e.Group("/api/v1",
+		limite.Falhas(contador, limite.Regra{PorMinuto: cfg.LimiteFalhasPorMinuto}),
+		autenticador.Identificar,
+		limite.Middleware(contador, regrasUso),
+	)
 
 	// Rotas de políticos
 	politicos := api.Group("/politicos")
@@ -91,8 +238,13 @@ func main() {
 	politicos.GET("/:id/estatisticas", politicoHandler.BuscarEstatisticas)
 	politicos.GET("/:id/votacoes", politicoHandler.ListarVotacoes)
 	politicos.GET("/:id/despesas", politicoHandler.ListarDespesas)
+	politicos.GET("/:id/despesas/resumo", despesaHandler.ResumoPorPolitico)
 	politicos.GET("/:id/proposicoes", politicoHandler.ListarProposicoes)
 	politicos.GET("/:id/presencas", politicoHandler.ListarPresencas)
+	politicos.GET("/:id/alertas", alertaHandler.ListarPorPolitico)
+	politicos.GET("/:id/similares", politicoHandler.BuscarSimilares)
+	politicos.GET("/:id/partidos", politicoHandler.ListarPartidos)
+	politicos.GET("/:id/alteracoes", alteracaoHandler.ListarPorPolitico)
 
 	// Rotas de filtros
 	filtros := api.Group("/filtros")
@@ -105,9 +257,69 @@ func main() {
 	estatisticas.GET("/geral", estatisticasHandler.Geral)
 	estatisticas.GET("/ranking", estatisticasHandler.Ranking)
 
+	// Rotas de despesas
+	despesas := api.Group("/despesas")
+	despesas.GET("/resumo", despesaHandler.Resumo)
+
+	// Rotas de fornecedores
+	fornecedores := api.Group("/fornecedores")
+	fornecedores.GET("", fornecedorHandler.Ranking)
+	fornecedores.GET("/:cnpj", fornecedorHandler.BuscarPorCNPJ)
+
+	// Rotas de partidos
+	partidos := api.Group("/partidos")
+	partidos.GET("", filtrosHandler.ListarPartidos)
+	partidos.GET("/trocas", partidoHandler.TrocaTroca) // Deve vir antes de /:sigla
+	partidos.GET("/:sigla", partidoHandler.BuscarPorSigla)
+
+	// Rotas de exportação (conjuntos completos em CSV, JSON Lines ou Parquet)
+	exportar := api.Group("/export")
+	exportar.GET("/politicos", exportacaoHandler.Politicos)
+	exportar.GET("/despesas", exportacaoHandler.Despesas)
+	exportar.GET("/votacoes", exportacaoHandler.Votacoes)
+	exportar.GET("/proposicoes", exportacaoHandler.Proposicoes)
+
+	// Rotas de dados abertos (dumps datados gravados por cmd/dump)
+	dadosAbertos := api.Group("/dados-abertos")
+	dadosAbertos.GET("", dadosAbertosHandler.Listar)
+	dadosAbertos.GET("/:data", dadosAbertosHandler.Buscar)
+	dadosAbertos.GET("/:data/:arquivo", dadosAbertosHandler.Baixar)
+
+	// Rotas de alertas
+	api.GET("/alertas", alertaHandler.Listar)
+
 	// Rota de busca
 	api.GET("/busca", politicoHandler.Buscar)
 
+	// GraphQL (consultas com profundidade e custo limitados)
+	api.GET("/graphql", graphqlHandler.Executar)
+	api.POST("/graphql", graphqlHandler.Executar)
+
+	// Autenticação
+	api.POST("/auth/token", authHandler.Token, autenticador.Autenticar, auth.ExigirChaveAPI)
+
+	// Rotas administrativas (exigem chave de API ou token JWT; o papel mínimo varia por rota)
+	admin := api.Group("/admin", autenticador.Autenticar, auth.ExigirPapel(domain.PapelLeitor))
+	admin.GET("/unificacoes", unificacaoHandler.Listar)
+	admin.POST("/unificacoes", unificacaoHandler.Unificar, auth.ExigirPapel(domain.PapelEditor))
+	admin.POST("/unificacoes/:id/desfazer", unificacaoHandler.Desfazer, auth.ExigirPapel(domain.PapelEditor))
+	admin.GET("/revisoes-identidade", unificacaoHandler.ListarRevisoes)
+
+	sincronizacoes := admin.Group("/sync")
+	sincronizacoes.GET("", syncHandler.Listar)
+	sincronizacoes.POST("", syncHandler.Iniciar, auth.ExigirPapel(domain.PapelEditor))
+	sincronizacoes.GET("/fontes", syncHandler.Fontes) // Deve vir antes de /:id
+	sincronizacoes.GET("/:id", syncHandler.BuscarPorID)
+	sincronizacoes.POST("/:id/cancelar", syncHandler.Cancelar, auth.ExigirPapel(domain.PapelEditor))
+
+	admin.GET("/qualidade", qualidadeHandler.Relatorio)
+	admin.GET("/qualidade/problemas", qualidadeHandler.Problemas)
+
+	chaves := admin.Group("/chaves", auth.ExigirPapel(domain.PapelAdmin))
+	chaves.GET("", authHandler.ListarChaves)
+	chaves.POST("", authHandler.CriarChave)
+	chaves.DELETE("/:id", authHandler.RevogarChave)
+
 	// Iniciar servidor
 	go func() {
 		addr := ":" + cfg.Port
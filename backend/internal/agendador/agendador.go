@@ -0,0 +1,219 @@
+// Package agendador dispara sincronizações periódicas em horários no formato do cron,
+// usando uma trava no MongoDB para que vários workers não repitam o mesmo job.
+package agendador
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/sync/orquestrador"
+	"github.com/robfig/cron/v3"
+)
+
+// fuso é o horário em que as expressões são interpretadas (sessões e expedientes seguem Brasília)
+var fuso = carregarFuso()
+
+func carregarFuso() *time.Location {
+	if loc, err := time.LoadLocation("America/Sao_Paulo"); err == nil {
+		return loc
+	}
+	return time.FixedZone("BRT", -3*60*60)
+}
+
+// Agendamento é uma sincronização que roda nos horários da expressão cron
+// (minuto hora dia-do-mês mês dia-da-semana)
+type Agendamento struct {
+	Nome      string
+	Expressao string
+	Pedido    domain.PedidoSync
+
+	horario cron.Schedule
+}
+
+// Padrao retorna os agendamentos padrão. O ano das sincronizações é sempre o corrente.
+func Padrao() []Agendamento {
+	return []Agendamento{
+		// Cadastros mudam pouco: uma vez por dia, de madrugada
+		{Nome: "deputados", Expressao: "0 5 * * *", Pedido: domain.PedidoSync{Fonte: "camara", Entidades: []string{"deputados"}}},
+		{Nome: "senadores", Expressao: "15 5 * * *", Pedido: domain.PedidoSync{Fonte: "senado"}},
+		{Nome: "executivo", Expressao: "30 5 * * *", Pedido: domain.PedidoSync{Fonte: orquestrador.FonteTodas, Entidades: []string{"presidente", "governadores"}}},
+		// Votações de hora em hora durante as sessões (dias úteis, das 9h às 22h)
+		{Nome: "votacoes", Expressao: "0 9-22 * * 1-5", Pedido: domain.PedidoSync{Fonte: "camara", Entidades: []string{"votacoes"}}},
+		{Nome: "presencas", Expressao: "30 23 * * 1-5", Pedido: domain.PedidoSync{Fonte: "camara", Entidades: []string{"presencas"}}},
+		{Nome: "proposicoes", Expressao: "0 4 * * *", Pedido: domain.PedidoSync{Fonte: "camara", Entidades: []string{"proposicoes"}}},
+		// Despesas uma vez por semana, seguidas da análise que gera os alertas
+		{Nome: "despesas", Expressao: "0 2 * * 0", Pedido: domain.PedidoSync{Fonte: orquestrador.FonteTodas, Entidades: []string{"despesas", "alertas"}}},
+	}
+}
+
+// AplicarAmbiente permite mudar os horários pelas variáveis AGENDA_<NOME>, ex.:
+// AGENDA_VOTACOES="*/30 9-22 * * 1-5". O valor "desativado" remove o agendamento.
+func AplicarAmbiente(agendamentos []Agendamento) []Agendamento {
+	var resultado []Agendamento
+	for _, ag := range agendamentos {
+		valor := strings.TrimSpace(os.Getenv("AGENDA_" + strings.ToUpper(ag.Nome)))
+		if strings.EqualFold(valor, "desativado") {
+			continue
+		}
+		if valor != "" {
+			ag.Expressao = valor
+		}
+		resultado = append(resultado, ag)
+	}
+	return resultado
+}
+
+// Executor roda uma sincronização até o fim, registrando-a no histórico
+type Executor interface {
+	Executar(ctx context.Context, pedido domain.PedidoSync, iniciadaPor, agendamento string) (*domain.ExecucaoSync, error)
+}
+
+// Agendador dispara os agendamentos nos seus horários
+type Agendador struct {
+	agendamentos []Agendamento
+	dono         string
+
+	executor Executor
+	trava    *Trava
+}
+
+// New valida as expressões e cria o agendador. dono identifica este worker no histórico e na trava.
+func New(agendamentos []Agendamento, dono string) (*Agendador, error) {
+	for i := range agendamentos {
+		horario, err := cron.ParseStandard(agendamentos[i].Expressao)
+		if err != nil {
+			return nil, fmt.Errorf("agendamento %s: expressão inválida %q: %w", agendamentos[i].Nome, agendamentos[i].Expressao, err)
+		}
+		if _, err := orquestrador.Planejar(agendamentos[i].Pedido); err != nil {
+			return nil, fmt.Errorf("agendamento %s: %w", agendamentos[i].Nome, err)
+		}
+		agendamentos[i].horario = horario
+	}
+
+	return &Agendador{
+		agendamentos: agendamentos,
+		dono:         dono,
+	}, nil
+}
+
+// ProximaExecucao é o próximo horário de um agendamento
+type ProximaExecucao struct {
+	Nome      string
+	Expressao string
+	Em        time.Time
+}
+
+// Proximas lista o próximo horário de cada agendamento, do mais próximo ao mais distante
+func (a *Agendador) Proximas(agora time.Time) []ProximaExecucao {
+	proximas := make([]ProximaExecucao, 0, len(a.agendamentos))
+	for _, ag := range a.agendamentos {
+		proximas = append(proximas, ProximaExecucao{
+			Nome:      ag.Nome,
+			Expressao: ag.Expressao,
+			Em:        ag.horario.Next(agora.In(fuso)),
+		})
+	}
+	sort.Slice(proximas, func(i, j int) bool { return proximas[i].Em.Before(proximas[j].Em) })
+	return proximas
+}
+
+// Executar roda os agendamentos até o contexto ser cancelado e espera os jobs em andamento
+// terminarem (eles recebem o mesmo cancelamento).
+func (a *Agendador) Executar(ctx context.Context, executor Executor, trava *Trava) {
+	a.executor = executor
+	a.trava = trava
+
+	var wg gosync.WaitGroup
+	for _, ag := range a.agendamentos {
+		wg.Add(1)
+		go func(ag Agendamento) {
+			defer wg.Done()
+			a.loop(ctx, ag)
+		}(ag)
+	}
+	wg.Wait()
+}
+
+// loop espera cada horário do agendamento e o executa. Horários que passam enquanto o job
+// ainda roda são pulados.
+func (a *Agendador) loop(ctx context.Context, ag Agendamento) {
+	for {
+		programado := ag.horario.Next(time.Now().In(fuso))
+		timer := time.NewTimer(time.Until(programado))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		a.rodar(ctx, ag, programado)
+	}
+}
+
+// rodar executa o agendamento se este worker conseguir a trava do horário
+func (a *Agendador) rodar(ctx context.Context, ag Agendamento, programado time.Time) {
+	ok, err := a.trava.Adquirir(ctx, ag.Nome, programado)
+	if err != nil {
+		log.Printf("⚠️  Agendamento %s: erro ao adquirir trava: %v", ag.Nome, err)
+		return
+	}
+	if !ok {
+		log.Printf("⏭️  Agendamento %s (%s): executado por outro worker", ag.Nome, programado.Format(time.RFC3339))
+		return
+	}
+
+	// Renovar a trava enquanto o job roda. Se ela foi assumida por outro worker, ou não pôde
+	// ser renovada dentro da validade, o job é cancelado: outro worker pode estar com ele.
+	jobCtx, cancelarJob := context.WithCancel(ctx)
+	defer cancelarJob()
+	pronto := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(a.trava.validade / 3)
+		defer ticker.Stop()
+		renovadaEm := time.Now()
+		for {
+			select {
+			case <-ticker.C:
+				err := a.trava.Renovar(ctx, ag.Nome)
+				if err == nil {
+					renovadaEm = time.Now()
+					continue
+				}
+				log.Printf("⚠️  Agendamento %s: erro ao renovar trava: %v", ag.Nome, err)
+				if errors.Is(err, ErrTravaPerdida) || time.Since(renovadaEm) >= a.trava.validade {
+					log.Printf("🛑 Agendamento %s: trava perdida, cancelando", ag.Nome)
+					cancelarJob()
+					return
+				}
+			case <-pronto:
+				return
+			}
+		}
+	}()
+
+	log.Printf("⏰ Agendamento %s: iniciando (%s)", ag.Nome, programado.Format(time.RFC3339))
+	exec, err := a.executor.Executar(jobCtx, ag.Pedido, a.dono, ag.Nome)
+	close(pronto)
+
+	liberarCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if errLiberar := a.trava.Liberar(liberarCtx, ag.Nome); errLiberar != nil {
+		log.Printf("⚠️  Agendamento %s: erro ao liberar trava: %v", ag.Nome, errLiberar)
+	}
+
+	if err != nil {
+		log.Printf("❌ Agendamento %s: %v", ag.Nome, err)
+		return
+	}
+	log.Printf("✅ Agendamento %s: %s (execução %s)", ag.Nome, exec.Status, exec.ID.Hex())
+}
@@ -0,0 +1,181 @@
+package agendador
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTravaPerdida indica que a trava expirou e foi assumida por outro dono
+var ErrTravaPerdida = errors.New("a trava foi assumida por outro processo")
+
+// Trava garante, entre vários workers, que cada horário de um agendamento rode uma única vez.
+// Cada agendamento tem um documento na coleção "travas" com o dono atual, a validade da
+// trava e o último horário já assumido.
+//
+// A mesma coleção guarda as travas das entidades (AdquirirEntidades), que a API e os workers
+// tomam antes de sincronizar, para que a mesma entidade não seja sincronizada duas vezes ao
+// mesmo tempo.
+type Trava struct {
+	collection *mongo.Collection
+	dono       string
+	validade   time.Duration
+}
+
+// NewTrava cria a trava para o worker identificado por dono. A trava expira se não for
+// renovada dentro da validade, para que um worker que caiu não bloqueie os demais.
+func NewTrava(db *mongo.Database, dono string, validade time.Duration) *Trava {
+	return &Trava{
+		collection: db.Collection("travas"),
+		dono:       dono,
+		validade:   validade,
+	}
+}
+
+// Adquirir tenta assumir o agendamento para o horário programado. Retorna false se outro
+// worker está com a trava ou se esse horário (ou um posterior) já foi assumido.
+func (t *Trava) Adquirir(ctx context.Context, nome string, programado time.Time) (bool, error) {
+	agora := time.Now()
+	filter := bson.M{
+		"_id": nome,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"expira_em": bson.M{"$lte": agora}},
+				bson.M{"expira_em": bson.M{"$exists": false}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"programado_para": bson.M{"$lt": programado}},
+				bson.M{"programado_para": bson.M{"$exists": false}},
+			}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"dono":            t.dono,
+		"expira_em":       agora.Add(t.validade),
+		"programado_para": programado,
+		"adquirida_em":    agora,
+	}}
+
+	// Sem documento, o upsert cria a trava; se o documento existe mas não passou no filtro,
+	// o upsert tenta inserir o mesmo _id e falha com chave duplicada
+	_, err := t.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Renovar estende a validade da trava enquanto o job roda. Retorna ErrTravaPerdida se a
+// trava deixou de ser deste dono.
+func (t *Trava) Renovar(ctx context.Context, nome string) error {
+	res, err := t.collection.UpdateOne(ctx,
+		bson.M{"_id": nome, "dono": t.dono},
+		bson.M{"$set": bson.M{"expira_em": time.Now().Add(t.validade)}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrTravaPerdida
+	}
+	return nil
+}
+
+// Liberar solta a trava, mantendo o último horário assumido
+func (t *Trava) Liberar(ctx context.Context, nome string) error {
+	_, err := t.collection.UpdateOne(ctx,
+		bson.M{"_id": nome, "dono": t.dono},
+		bson.M{
+			"$set":   bson.M{"expira_em": time.Now()},
+			"$unset": bson.M{"dono": ""},
+		},
+	)
+	return err
+}
+
+// Validade é quanto a trava dura sem ser renovada
+func (t *Trava) Validade() time.Duration {
+	return t.validade
+}
+
+// ChaveEntidade é o nome da trava de uma entidade de uma fonte
+func ChaveEntidade(fonte, entidade string) string {
+	return "sync:" + fonte + ":" + entidade
+}
+
+// AdquirirEntidades tenta assumir todas as travas de uma vez: se alguma está com outro dono,
+// solta as que já tinha assumido e retorna o nome dela. Diferente de Adquirir, não há
+// horário; a trava vale enquanto for renovada. Os agendamentos de um worker usam o mesmo dono,
+// então dois deles também não assumem a mesma entidade.
+func (t *Trava) AdquirirEntidades(ctx context.Context, nomes []string) (string, error) {
+	// Sempre na mesma ordem, para que dois pedidos com entidades em comum não fiquem cada
+	// um com uma parte delas
+	nomes = append([]string(nil), nomes...)
+	sort.Strings(nomes)
+
+	var assumidas []string
+	for _, nome := range nomes {
+		agora := time.Now()
+		filter := bson.M{
+			"_id": nome,
+			"$or": bson.A{
+				bson.M{"expira_em": bson.M{"$lte": agora}},
+				bson.M{"expira_em": bson.M{"$exists": false}},
+			},
+		}
+		update := bson.M{"$set": bson.M{
+			"dono":         t.dono,
+			"expira_em":    agora.Add(t.validade),
+			"adquirida_em": agora,
+		}}
+
+		_, err := t.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err == nil {
+			assumidas = append(assumidas, nome)
+			continue
+		}
+
+		if errLiberar := t.LiberarEntidades(ctx, assumidas); errLiberar != nil {
+			return "", errLiberar
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nome, nil
+		}
+		return "", err
+	}
+	return "", nil
+}
+
+// RenovarEntidades estende a validade das travas; retorna ErrTravaPerdida se alguma deixou
+// de ser deste dono
+func (t *Trava) RenovarEntidades(ctx context.Context, nomes []string) error {
+	for _, nome := range nomes {
+		if err := t.Renovar(ctx, nome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LiberarEntidades solta as travas das entidades
+func (t *Trava) LiberarEntidades(ctx context.Context, nomes []string) error {
+	if len(nomes) == 0 {
+		return nil
+	}
+	_, err := t.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": nomes}, "dono": t.dono},
+		bson.M{
+			"$set":   bson.M{"expira_em": time.Now()},
+			"$unset": bson.M{"dono": ""},
+		},
+	)
+	return err
+}
@@ -0,0 +1,96 @@
+package agendador
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+)
+
+// Cada horário é assumido por um worker só, e só depois do último já assumido
+func TestTravaHorario(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	a := NewTrava(db, "a", time.Minute)
+	b := NewTrava(db, "b", time.Minute)
+	nove := time.Date(2024, 5, 2, 9, 0, 0, 0, fuso)
+
+	if ok, err := a.Adquirir(ctx, "votacoes", nove); !ok || err != nil {
+		t.Fatalf("a: %v, %v", ok, err)
+	}
+	if ok, err := b.Adquirir(ctx, "votacoes", nove); ok || err != nil {
+		t.Fatalf("b assumiu o horário de a: %v, %v", ok, err)
+	}
+
+	if err := a.Liberar(ctx, "votacoes"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.Adquirir(ctx, "votacoes", nove); ok {
+		t.Error("b repetiu o horário já executado por a")
+	}
+	if ok, err := b.Adquirir(ctx, "votacoes", nove.Add(time.Hour)); !ok || err != nil {
+		t.Errorf("b não assumiu o horário seguinte: %v, %v", ok, err)
+	}
+}
+
+// Quem teve a trava expirada e assumida por outro fica sabendo ao renovar
+func TestTravaPerdida(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	a := NewTrava(db, "a", time.Millisecond)
+	b := NewTrava(db, "b", time.Minute)
+	nove := time.Date(2024, 5, 2, 9, 0, 0, 0, fuso)
+
+	if ok, _ := a.Adquirir(ctx, "votacoes", nove); !ok {
+		t.Fatal("a não assumiu a trava")
+	}
+	if err := a.Renovar(ctx, "votacoes"); err != nil {
+		t.Fatalf("renovar a própria trava: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if ok, _ := b.Adquirir(ctx, "votacoes", nove.Add(time.Hour)); !ok {
+		t.Fatal("b não assumiu a trava expirada")
+	}
+	if err := a.Renovar(ctx, "votacoes"); !errors.Is(err, ErrTravaPerdida) {
+		t.Errorf("renovar depois de perder: %v; esperado ErrTravaPerdida", err)
+	}
+}
+
+// Um pedido com uma entidade ocupada não fica com nenhuma das outras
+func TestTravaEntidades(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	worker := NewTrava(db, "worker", time.Minute)
+	api := NewTrava(db, "api", time.Minute)
+	deputados := ChaveEntidade("camara", "deputados")
+	votacoes := ChaveEntidade("camara", "votacoes")
+	senadores := ChaveEntidade("senado", "senadores")
+
+	if ocupada, err := worker.AdquirirEntidades(ctx, []string{votacoes}); ocupada != "" || err != nil {
+		t.Fatalf("worker: %q, %v", ocupada, err)
+	}
+	if ocupada, err := worker.AdquirirEntidades(ctx, []string{votacoes}); ocupada != votacoes || err != nil {
+		t.Errorf("outro agendamento do mesmo worker: %q, %v; esperado %q ocupada", ocupada, err, votacoes)
+	}
+
+	ocupada, err := api.AdquirirEntidades(ctx, []string{deputados, votacoes, senadores})
+	if ocupada != votacoes || err != nil {
+		t.Fatalf("api: %q, %v; esperado %q ocupada", ocupada, err, votacoes)
+	}
+	if ocupada, _ := worker.AdquirirEntidades(ctx, []string{deputados, senadores}); ocupada != "" {
+		t.Errorf("%s ficou travada pelo pedido recusado da api", ocupada)
+	}
+
+	if err := worker.LiberarEntidades(ctx, []string{deputados, votacoes, senadores}); err != nil {
+		t.Fatal(err)
+	}
+	if ocupada, err := api.AdquirirEntidades(ctx, []string{deputados, votacoes, senadores}); ocupada != "" || err != nil {
+		t.Errorf("api depois de o worker liberar: %q, %v", ocupada, err)
+	}
+	if err := worker.RenovarEntidades(ctx, []string{votacoes}); !errors.Is(err, ErrTravaPerdida) {
+		t.Errorf("worker renovando a trava da api: %v; esperado ErrTravaPerdida", err)
+	}
+}
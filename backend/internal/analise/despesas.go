@@ -0,0 +1,720 @@
+package analise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/pkg/documento"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Fração do teto a partir da qual o gasto mensal é considerado "logo abaixo" do limite
+	fracaoTeto = 0.95
+	// Número de desvios padrão acima da média para caracterizar um pico de gastos
+	limiarPico = 3.0
+	// Mínimo de meses (ou de pares) para que a comparação seja estatisticamente útil
+	minimoAmostras = 6
+	// Intervalo máximo entre a abertura do CNPJ e o primeiro pagamento
+	diasFornecedorRecente = 180
+	// Tempo até consultar de novo um CNPJ que a BrasilAPI não encontrou
+	validadeSemCadastro = 30 * 24 * time.Hour
+)
+
+// errIncompleta indica que parte dos casos de uma regra não pôde ser avaliada (ex.: BrasilAPI
+// fora do ar). Os alertas encontrados são gravados, mas os anteriores não são retirados.
+var errIncompleta = errors.New("avaliação incompleta")
+
+// AnaliseDespesas procura padrões suspeitos nas despesas da cota parlamentar
+type AnaliseDespesas struct {
+	client *sync.HTTPClient
+	db     *mongo.Database
+}
+
+// NewAnaliseDespesas cria um novo analisador
+func NewAnaliseDespesas(db *mongo.Database) *AnaliseDespesas {
+	return &AnaliseDespesas{
+		client: sync.NewHTTPClient(2), // BrasilAPI limita requisições por IP
+		db:     db,
+	}
+}
+
+// regra representa uma verificação que produz alertas dos tipos informados para um ano
+type regra struct {
+	nome     string
+	tipos    []domain.TipoAlerta
+	executar func(ctx context.Context, ano int) ([]domain.Alerta, error)
+}
+
+// Executar roda todas as regras sobre as despesas do ano, grava os alertas e retira os que
+// a regra não encontrou mais (a condição deixou de existir, ex.: um recibo foi estornado)
+func (a *AnaliseDespesas) Executar(ctx context.Context, ano int) error {
+	log.Printf("🔎 Analisando despesas do ano %d...", ano)
+
+	regras := []regra{
+		{"recibos duplicados", []domain.TipoAlerta{domain.AlertaReciboDuplicado}, a.recibosDuplicados},
+		{"gastos próximos ao teto", []domain.TipoAlerta{domain.AlertaProximoAoTeto}, a.gastosProximosAoTeto},
+		{"picos de gastos", []domain.TipoAlerta{domain.AlertaPicoHistorico, domain.AlertaPicoPares}, a.picosDeGastos},
+		{"fornecedores recentes", []domain.TipoAlerta{domain.AlertaFornecedorRecente}, a.fornecedoresRecentes},
+	}
+
+	total := 0
+	for _, r := range regras {
+		alertas, err := r.executar(ctx, ano)
+		incompleta := errors.Is(err, errIncompleta)
+		if err != nil && !incompleta {
+			log.Printf("⚠️  Erro na regra de %s: %v", r.nome, err)
+			continue
+		}
+
+		if err := a.salvarAlertas(ctx, alertas); err != nil {
+			return fmt.Errorf("erro ao salvar alertas de %s: %w", r.nome, err)
+		}
+
+		retirados := int64(0)
+		if incompleta {
+			log.Printf("⚠️  Regra de %s: %v; os alertas anteriores foram mantidos", r.nome, err)
+		} else if retirados, err = a.retirarAlertas(ctx, ano, r.tipos, alertas); err != nil {
+			return fmt.Errorf("erro ao retirar alertas de %s: %w", r.nome, err)
+		}
+
+		log.Printf("   %s: %d alertas (%d retirados)", r.nome, len(alertas), retirados)
+		total += len(alertas)
+	}
+
+	log.Printf("✅ Análise de despesas concluída! (%d alertas)", total)
+	return nil
+}
+
+// recibosDuplicados encontra recibos com mesmo fornecedor, valor e data
+func (a *AnaliseDespesas) recibosDuplicados(ctx context.Context, ano int) ([]domain.Alerta, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"ano_referencia":  ano,
+			"cnpj_fornecedor": bson.M{"$ne": ""},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"politico_id":     "$politico_id",
+				"cnpj_fornecedor": "$cnpj_fornecedor",
+				"valor":           "$valor",
+				"data":            "$data",
+			},
+			"fornecedor": bson.M{"$first": "$fornecedor"},
+			"mes":        bson.M{"$first": "$mes_referencia"},
+			"ids":        bson.M{"$push": "$_id"},
+			"quantidade": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"quantidade": bson.M{"$gt": 1}}}},
+	}
+
+	cursor, err := a.db.Collection("despesas").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alertas []domain.Alerta
+	for cursor.Next(ctx) {
+		var item struct {
+			ID struct {
+				PoliticoID     primitive.ObjectID `bson:"politico_id"`
+				CNPJFornecedor string             `bson:"cnpj_fornecedor"`
+				Valor          float64            `bson:"valor"`
+				Data           time.Time          `bson:"data"`
+			} `bson:"_id"`
+			Fornecedor string               `bson:"fornecedor"`
+			Mes        int                  `bson:"mes"`
+			IDs        []primitive.ObjectID `bson:"ids"`
+			Quantidade int                  `bson:"quantidade"`
+		}
+		if err := cursor.Decode(&item); err != nil {
+			continue
+		}
+
+		severidade := domain.SeveridadeMedia
+		if item.Quantidade >= 3 {
+			severidade = domain.SeveridadeAlta
+		}
+
+		alertas = append(alertas, domain.Alerta{
+			Chave: fmt.Sprintf("%s:%s:%s:%.2f:%s", domain.AlertaReciboDuplicado, item.ID.PoliticoID.Hex(),
+				item.ID.CNPJFornecedor, item.ID.Valor, item.ID.Data.Format("2006-01-02")),
+			PoliticoID:  item.ID.PoliticoID,
+			DespesasIDs: item.IDs,
+			Tipo:        domain.AlertaReciboDuplicado,
+			Severidade:  severidade,
+			Motivo: fmt.Sprintf("%d recibos de %s (%s) com o mesmo valor de R$ %.2f na mesma data (%s)",
+				item.Quantidade, item.Fornecedor, item.ID.CNPJFornecedor, item.ID.Valor, item.ID.Data.Format("02/01/2006")),
+			Valor:         item.ID.Valor * float64(item.Quantidade),
+			MesReferencia: item.Mes,
+			AnoReferencia: ano,
+		})
+	}
+
+	return alertas, cursor.Err()
+}
+
+// gastosProximosAoTeto encontra meses em que o gasto de uma categoria ficou logo abaixo do
+// subteto dela, ou o gasto total ficou logo abaixo da cota mensal do estado do político
+func (a *AnaliseDespesas) gastosProximosAoTeto(ctx context.Context, ano int) ([]domain.Alerta, error) {
+	alertas, err := a.categoriasProximasAoTeto(ctx, ano)
+	if err != nil {
+		return nil, err
+	}
+	totais, err := a.totaisProximosACota(ctx, ano)
+	if err != nil {
+		return nil, err
+	}
+	return append(alertas, totais...), nil
+}
+
+// categoriasProximasAoTeto compara o gasto mensal de cada categoria com o subteto dela
+func (a *AnaliseDespesas) categoriasProximasAoTeto(ctx context.Context, ano int) ([]domain.Alerta, error) {
+	tipos := make([]string, 0, len(TetosCEAP))
+	for tipo := range TetosCEAP {
+		tipos = append(tipos, tipo)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"ano_referencia": ano,
+			"tipo":           bson.M{"$in": tipos},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"politico_id": "$politico_id",
+				"mes":         "$mes_referencia",
+				"tipo":        "$tipo",
+			},
+			"total": bson.M{"$sum": "$valor"},
+			"ids":   bson.M{"$push": "$_id"},
+		}}},
+	}
+
+	cursor, err := a.db.Collection("despesas").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alertas []domain.Alerta
+	for cursor.Next(ctx) {
+		var item struct {
+			ID struct {
+				PoliticoID primitive.ObjectID `bson:"politico_id"`
+				Mes        int                `bson:"mes"`
+				Tipo       string             `bson:"tipo"`
+			} `bson:"_id"`
+			Total float64              `bson:"total"`
+			IDs   []primitive.ObjectID `bson:"ids"`
+		}
+		if err := cursor.Decode(&item); err != nil {
+			continue
+		}
+
+		teto := TetosCEAP[item.ID.Tipo]
+		severidade, proximo := proximoAoTeto(item.Total, teto)
+		if !proximo {
+			continue
+		}
+
+		alertas = append(alertas, domain.Alerta{
+			Chave: fmt.Sprintf("%s:%s:%d:%d:%s", domain.AlertaProximoAoTeto, item.ID.PoliticoID.Hex(),
+				ano, item.ID.Mes, item.ID.Tipo),
+			PoliticoID:  item.ID.PoliticoID,
+			DespesasIDs: item.IDs,
+			Tipo:        domain.AlertaProximoAoTeto,
+			Severidade:  severidade,
+			Motivo: fmt.Sprintf("Gasto de R$ %.2f em %s em %02d/%d, %.1f%% do teto de R$ %.2f",
+				item.Total, strings.TrimSuffix(item.ID.Tipo, "."), item.ID.Mes, ano, item.Total/teto*100, teto),
+			Valor:         item.Total,
+			MesReferencia: item.ID.Mes,
+			AnoReferencia: ano,
+		})
+	}
+
+	return alertas, cursor.Err()
+}
+
+// totaisProximosACota compara o gasto mensal total de cada político com a cota do estado dele
+func (a *AnaliseDespesas) totaisProximosACota(ctx context.Context, ano int) ([]domain.Alerta, error) {
+	estados, err := a.estadosPorPolitico(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"ano_referencia": ano}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"politico_id": "$politico_id",
+				"mes":         "$mes_referencia",
+			},
+			"total": bson.M{"$sum": "$valor"},
+		}}},
+	}
+
+	cursor, err := a.db.Collection("despesas").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alertas []domain.Alerta
+	for cursor.Next(ctx) {
+		var item struct {
+			ID struct {
+				PoliticoID primitive.ObjectID `bson:"politico_id"`
+				Mes        int                `bson:"mes"`
+			} `bson:"_id"`
+			Total float64 `bson:"total"`
+		}
+		if err := cursor.Decode(&item); err != nil {
+			continue
+		}
+
+		estado := estados[item.ID.PoliticoID]
+		cota, ok := CotasCEAPPorUF[estado]
+		if !ok {
+			continue
+		}
+		severidade, proximo := proximoAoTeto(item.Total, cota)
+		if !proximo {
+			continue
+		}
+
+		alertas = append(alertas, domain.Alerta{
+			Chave: fmt.Sprintf("%s:%s:%d:%d:COTA", domain.AlertaProximoAoTeto, item.ID.PoliticoID.Hex(),
+				ano, item.ID.Mes),
+			PoliticoID: item.ID.PoliticoID,
+			Tipo:       domain.AlertaProximoAoTeto,
+			Severidade: severidade,
+			Motivo: fmt.Sprintf("Gasto total de R$ %.2f em %02d/%d, %.1f%% da cota mensal de %s (R$ %.2f)",
+				item.Total, item.ID.Mes, ano, item.Total/cota*100, estado, cota),
+			Valor:         item.Total,
+			MesReferencia: item.ID.Mes,
+			AnoReferencia: ano,
+		})
+	}
+
+	return alertas, cursor.Err()
+}
+
+// proximoAoTeto indica se o gasto ficou logo abaixo do teto, com a severidade do alerta
+func proximoAoTeto(total, teto float64) (domain.Severidade, bool) {
+	if teto <= 0 || total < teto*fracaoTeto || total > teto {
+		return "", false
+	}
+	if total >= teto*0.99 {
+		return domain.SeveridadeMedia, true
+	}
+	return domain.SeveridadeBaixa, true
+}
+
+// gastoMensal representa o total gasto por um político em um mês
+type gastoMensal struct {
+	PoliticoID primitive.ObjectID
+	Ano        int
+	Mes        int
+	Total      float64
+}
+
+// picosDeGastos compara cada mês com o histórico do próprio político e com os pares do mesmo estado
+func (a *AnaliseDespesas) picosDeGastos(ctx context.Context, ano int) ([]domain.Alerta, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"politico_id": "$politico_id",
+				"ano":         "$ano_referencia",
+				"mes":         "$mes_referencia",
+			},
+			"total": bson.M{"$sum": "$valor"},
+		}}},
+	}
+
+	cursor, err := a.db.Collection("despesas").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var gastos []gastoMensal
+	for cursor.Next(ctx) {
+		var item struct {
+			ID struct {
+				PoliticoID primitive.ObjectID `bson:"politico_id"`
+				Ano        int                `bson:"ano"`
+				Mes        int                `bson:"mes"`
+			} `bson:"_id"`
+			Total float64 `bson:"total"`
+		}
+		if err := cursor.Decode(&item); err != nil {
+			continue
+		}
+		gastos = append(gastos, gastoMensal{
+			PoliticoID: item.ID.PoliticoID,
+			Ano:        item.ID.Ano,
+			Mes:        item.ID.Mes,
+			Total:      item.Total,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	estados, err := a.estadosPorPolitico(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agrupar por político (histórico) e por estado+mês (pares)
+	porPolitico := make(map[primitive.ObjectID][]gastoMensal)
+	porEstadoMes := make(map[string][]gastoMensal)
+	for _, g := range gastos {
+		porPolitico[g.PoliticoID] = append(porPolitico[g.PoliticoID], g)
+		if estado := estados[g.PoliticoID]; estado != "" {
+			chave := fmt.Sprintf("%s:%d:%d", estado, g.Ano, g.Mes)
+			porEstadoMes[chave] = append(porEstadoMes[chave], g)
+		}
+	}
+
+	var alertas []domain.Alerta
+
+	for _, meses := range porPolitico {
+		for i, g := range meses {
+			if g.Ano != ano {
+				continue
+			}
+			outros := valoresExceto(meses, i)
+			if len(outros) < minimoAmostras {
+				continue
+			}
+			media, desvio := mediaDesvio(outros)
+			z, pico := ehPico(g.Total, media, desvio)
+			if !pico {
+				continue
+			}
+			alertas = append(alertas, domain.Alerta{
+				Chave:      fmt.Sprintf("%s:%s:%d:%d", domain.AlertaPicoHistorico, g.PoliticoID.Hex(), g.Ano, g.Mes),
+				PoliticoID: g.PoliticoID,
+				Tipo:       domain.AlertaPicoHistorico,
+				Severidade: severidadePorDesvio(z),
+				Motivo: fmt.Sprintf("Gasto de R$ %.2f em %02d/%d, %.1f desvios acima da média mensal do próprio político (R$ %.2f)",
+					g.Total, g.Mes, g.Ano, z, media),
+				Valor:         g.Total,
+				MesReferencia: g.Mes,
+				AnoReferencia: g.Ano,
+			})
+		}
+	}
+
+	for chave, pares := range porEstadoMes {
+		estado := strings.SplitN(chave, ":", 2)[0]
+		for i, g := range pares {
+			if g.Ano != ano {
+				continue
+			}
+			outros := valoresExceto(pares, i)
+			if len(outros) < minimoAmostras {
+				continue
+			}
+			media, desvio := mediaDesvio(outros)
+			z, pico := ehPico(g.Total, media, desvio)
+			if !pico {
+				continue
+			}
+			alertas = append(alertas, domain.Alerta{
+				Chave:      fmt.Sprintf("%s:%s:%d:%d", domain.AlertaPicoPares, g.PoliticoID.Hex(), g.Ano, g.Mes),
+				PoliticoID: g.PoliticoID,
+				Tipo:       domain.AlertaPicoPares,
+				Severidade: severidadePorDesvio(z),
+				Motivo: fmt.Sprintf("Gasto de R$ %.2f em %02d/%d, %.1f desvios acima da média dos %d pares de %s (R$ %.2f)",
+					g.Total, g.Mes, g.Ano, z, len(outros), estado, media),
+				Valor:         g.Total,
+				MesReferencia: g.Mes,
+				AnoReferencia: g.Ano,
+			})
+		}
+	}
+
+	return alertas, nil
+}
+
+// estadosPorPolitico retorna o estado do cargo atual de cada político
+func (a *AnaliseDespesas) estadosPorPolitico(ctx context.Context) (map[primitive.ObjectID]string, error) {
+	opts := options.Find().SetProjection(bson.M{"cargo_atual.estado": 1})
+	cursor, err := a.db.Collection("politicos").Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	estados := make(map[primitive.ObjectID]string)
+	for cursor.Next(ctx) {
+		var p domain.Politico
+		if err := cursor.Decode(&p); err != nil {
+			continue
+		}
+		estados[p.ID] = p.CargoAtual.Estado
+	}
+
+	return estados, cursor.Err()
+}
+
+// fornecedoresRecentes encontra pagamentos a empresas abertas pouco antes do primeiro pagamento
+func (a *AnaliseDespesas) fornecedoresRecentes(ctx context.Context, ano int) ([]domain.Alerta, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"cnpj_fornecedor": bson.M{"$ne": ""}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "data", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"politico_id":     "$politico_id",
+				"cnpj_fornecedor": "$cnpj_fornecedor",
+			},
+			"fornecedor":         bson.M{"$first": "$fornecedor"},
+			"primeiro_pagamento": bson.M{"$first": "$data"},
+			"primeira_despesa":   bson.M{"$first": "$_id"},
+			"valor":              bson.M{"$first": "$valor"},
+		}}},
+	}
+
+	cursor, err := a.db.Collection("despesas").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alertas []domain.Alerta
+	falhas := 0
+	for cursor.Next(ctx) {
+		var item struct {
+			ID struct {
+				PoliticoID     primitive.ObjectID `bson:"politico_id"`
+				CNPJFornecedor string             `bson:"cnpj_fornecedor"`
+			} `bson:"_id"`
+			Fornecedor        string             `bson:"fornecedor"`
+			PrimeiroPagamento time.Time          `bson:"primeiro_pagamento"`
+			PrimeiraDespesa   primitive.ObjectID `bson:"primeira_despesa"`
+			Valor             float64            `bson:"valor"`
+		}
+		if err := cursor.Decode(&item); err != nil {
+			continue
+		}
+
+		// Só interessa o primeiro pagamento ocorrido no ano analisado, e só pessoas jurídicas
+		if item.PrimeiroPagamento.Year() != ano {
+			continue
+		}
+		cnpj, tipo := documento.Identificar(item.ID.CNPJFornecedor)
+		if tipo != documento.TipoCNPJ {
+			continue
+		}
+
+		cadastro, err := a.buscarCadastro(ctx, cnpj)
+		if err != nil {
+			log.Printf("⚠️  Erro ao consultar CNPJ %s: %v", cnpj, err)
+			falhas++
+			continue
+		}
+		if cadastro == nil || cadastro.DataAbertura.IsZero() {
+			continue
+		}
+
+		dias := int(item.PrimeiroPagamento.Sub(cadastro.DataAbertura).Hours() / 24)
+		if dias < 0 || dias > diasFornecedorRecente {
+			continue
+		}
+
+		severidade := domain.SeveridadeBaixa
+		if dias <= 30 {
+			severidade = domain.SeveridadeAlta
+		} else if dias <= 90 {
+			severidade = domain.SeveridadeMedia
+		}
+
+		alertas = append(alertas, domain.Alerta{
+			Chave:       fmt.Sprintf("%s:%s:%s", domain.AlertaFornecedorRecente, item.ID.PoliticoID.Hex(), cnpj),
+			PoliticoID:  item.ID.PoliticoID,
+			DespesasIDs: []primitive.ObjectID{item.PrimeiraDespesa},
+			Tipo:        domain.AlertaFornecedorRecente,
+			Severidade:  severidade,
+			Motivo: fmt.Sprintf("%s (%s) foi aberta em %s, %d dias antes do primeiro pagamento (%s)",
+				item.Fornecedor, cnpj, cadastro.DataAbertura.Format("02/01/2006"), dias,
+				item.PrimeiroPagamento.Format("02/01/2006")),
+			Valor:         item.Valor,
+			MesReferencia: int(item.PrimeiroPagamento.Month()),
+			AnoReferencia: ano,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	if falhas > 0 {
+		return alertas, fmt.Errorf("%w: %d CNPJs não puderam ser consultados", errIncompleta, falhas)
+	}
+	return alertas, nil
+}
+
+// buscarCadastro retorna os dados cadastrais de um CNPJ, consultando a BrasilAPI se não estiverem em cache.
+// CNPJs que a BrasilAPI não encontrou também são guardados e só são consultados de novo depois de
+// validadeSemCadastro; falhas temporárias (tempo esgotado, 5xx, 429) retornam erro sem ir para o cache.
+func (a *AnaliseDespesas) buscarCadastro(ctx context.Context, cnpj string) (*CadastroFornecedor, error) {
+	collection := a.db.Collection("cadastro_fornecedores")
+
+	var cadastro CadastroFornecedor
+	err := collection.FindOne(ctx, bson.M{"cnpj": cnpj}).Decode(&cadastro)
+	if err == nil && (!cadastro.DataAbertura.IsZero() || time.Since(cadastro.ConsultadoEm) < validadeSemCadastro) {
+		return &cadastro, nil
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	var resp CNPJResponse
+	if err := a.client.Get(ctx, fmt.Sprintf("%s/%s", BrasilAPIURL, cnpj), &resp); err != nil {
+		if !semCadastro(err) {
+			return nil, err
+		}
+		log.Printf("   CNPJ %s sem dados cadastrais: %v", cnpj, err)
+	}
+
+	cadastro = CadastroFornecedor{
+		CNPJ:         cnpj,
+		RazaoSocial:  resp.RazaoSocial,
+		DataAbertura: ParseDate(resp.DataInicioAtividade),
+		ConsultadoEm: time.Now(),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"cnpj": cnpj}, cadastro, opts); err != nil {
+		return nil, err
+	}
+
+	return &cadastro, nil
+}
+
+// semCadastro indica se o erro da BrasilAPI é a resposta definitiva de que o CNPJ não tem
+// cadastro (404, ou 400 para CNPJ inválido), e não uma falha temporária
+func semCadastro(err error) bool {
+	var status *sync.ErroStatus
+	return errors.As(err, &status) && (status.Status == http.StatusNotFound || status.Status == http.StatusBadRequest)
+}
+
+// retirarAlertas remove os alertas do ano dos tipos da regra que ela não encontrou mais
+func (a *AnaliseDespesas) retirarAlertas(ctx context.Context, ano int, tipos []domain.TipoAlerta, encontrados []domain.Alerta) (int64, error) {
+	chaves := make([]string, 0, len(encontrados))
+	for _, alerta := range encontrados {
+		chaves = append(chaves, alerta.Chave)
+	}
+
+	result, err := a.db.Collection("alertas").DeleteMany(ctx, bson.M{
+		"ano_referencia": ano,
+		"tipo":           bson.M{"$in": tipos},
+		"chave":          bson.M{"$nin": chaves},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// salvarAlertas grava os alertas, atualizando os que já foram gerados em execuções anteriores
+func (a *AnaliseDespesas) salvarAlertas(ctx context.Context, alertas []domain.Alerta) error {
+	collection := a.db.Collection("alertas")
+
+	for _, alerta := range alertas {
+		update := bson.M{
+			"$set": bson.M{
+				"politico_id":    alerta.PoliticoID,
+				"despesas_ids":   alerta.DespesasIDs,
+				"tipo":           alerta.Tipo,
+				"severidade":     alerta.Severidade,
+				"motivo":         alerta.Motivo,
+				"valor":          alerta.Valor,
+				"mes_referencia": alerta.MesReferencia,
+				"ano_referencia": alerta.AnoReferencia,
+				"updated_at":     time.Now(),
+			},
+			"$setOnInsert": bson.M{
+				"_id":        primitive.NewObjectID(),
+				"created_at": time.Now(),
+			},
+		}
+
+		opts := options.Update().SetUpsert(true)
+		if _, err := collection.UpdateOne(ctx, bson.M{"chave": alerta.Chave}, update, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// valoresExceto retorna os totais de todos os meses menos o da posição informada
+func valoresExceto(gastos []gastoMensal, indice int) []float64 {
+	valores := make([]float64, 0, len(gastos)-1)
+	for i, g := range gastos {
+		if i != indice {
+			valores = append(valores, g.Total)
+		}
+	}
+	return valores
+}
+
+// mediaDesvio calcula a média e o desvio padrão amostral
+func mediaDesvio(valores []float64) (media, desvio float64) {
+	if len(valores) == 0 {
+		return 0, 0
+	}
+
+	for _, v := range valores {
+		media += v
+	}
+	media /= float64(len(valores))
+
+	if len(valores) < 2 {
+		return media, 0
+	}
+
+	for _, v := range valores {
+		desvio += (v - media) * (v - media)
+	}
+	desvio = math.Sqrt(desvio / float64(len(valores)-1))
+
+	return media, desvio
+}
+
+// ehPico indica se o valor está muito acima da média, retornando também o número de desvios
+func ehPico(valor, media, desvio float64) (float64, bool) {
+	if desvio == 0 || media <= 0 {
+		return 0, false
+	}
+	z := (valor - media) / desvio
+	// Exige também um aumento relevante em termos absolutos, para ignorar séries muito estáveis
+	return z, z >= limiarPico && valor >= media*1.5
+}
+
+// severidadePorDesvio classifica um pico pelo número de desvios acima da média
+func severidadePorDesvio(z float64) domain.Severidade {
+	switch {
+	case z >= 5:
+		return domain.SeveridadeAlta
+	case z >= 4:
+		return domain.SeveridadeMedia
+	default:
+		return domain.SeveridadeBaixa
+	}
+}
@@ -0,0 +1,201 @@
+package analise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMediaDesvio(t *testing.T) {
+	for _, caso := range []struct {
+		valores       []float64
+		media, desvio float64
+	}{
+		{nil, 0, 0},
+		{[]float64{7}, 7, 0},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, math.Sqrt(32.0 / 7)},
+	} {
+		media, desvio := mediaDesvio(caso.valores)
+		if math.Abs(media-caso.media) > 1e-9 || math.Abs(desvio-caso.desvio) > 1e-9 {
+			t.Errorf("mediaDesvio(%v) = %v, %v; esperado %v, %v", caso.valores, media, desvio, caso.media, caso.desvio)
+		}
+	}
+}
+
+func TestEhPico(t *testing.T) {
+	for _, caso := range []struct {
+		valor, media, desvio float64
+		pico                 bool
+	}{
+		{4000, 1000, 500, true},
+		{1400, 1000, 100, false}, // 4 desvios, mas menos de 50% acima da média
+		{2000, 1000, 500, false}, // 2 desvios
+		{5000, 1000, 0, false},   // Série constante
+		{5000, 0, 10, false},     // Sem gastos no histórico
+	} {
+		if _, pico := ehPico(caso.valor, caso.media, caso.desvio); pico != caso.pico {
+			t.Errorf("ehPico(%v, %v, %v) = %v", caso.valor, caso.media, caso.desvio, pico)
+		}
+	}
+}
+
+func TestSeveridadePorDesvio(t *testing.T) {
+	for z, esperada := range map[float64]domain.Severidade{
+		3:   domain.SeveridadeBaixa,
+		4:   domain.SeveridadeMedia,
+		4.9: domain.SeveridadeMedia,
+		5:   domain.SeveridadeAlta,
+	} {
+		if s := severidadePorDesvio(z); s != esperada {
+			t.Errorf("severidadePorDesvio(%v) = %s; esperada %s", z, s, esperada)
+		}
+	}
+}
+
+func TestProximoAoTeto(t *testing.T) {
+	for _, caso := range []struct {
+		total      float64
+		severidade domain.Severidade
+		proximo    bool
+	}{
+		{9400, "", false},
+		{9500, domain.SeveridadeBaixa, true},
+		{9900, domain.SeveridadeMedia, true},
+		{10000, domain.SeveridadeMedia, true},
+		{10001, "", false}, // Acima do teto não é "logo abaixo"
+	} {
+		severidade, proximo := proximoAoTeto(caso.total, 10000)
+		if severidade != caso.severidade || proximo != caso.proximo {
+			t.Errorf("proximoAoTeto(%v) = %s, %v", caso.total, severidade, proximo)
+		}
+	}
+	if _, proximo := proximoAoTeto(0, 0); proximo {
+		t.Error("teto zero não deveria gerar alerta")
+	}
+}
+
+func TestValoresExceto(t *testing.T) {
+	gastos := []gastoMensal{{Total: 1}, {Total: 2}, {Total: 3}}
+	if v := valoresExceto(gastos, 1); len(v) != 2 || v[0] != 1 || v[1] != 3 {
+		t.Errorf("valoresExceto = %v; esperado [1 3]", v)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	esperada := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{"2024-03-05", "2024-03-05T00:00:00", "05/03/2024"} {
+		if d := ParseDate(s); !d.Equal(esperada) {
+			t.Errorf("ParseDate(%q) = %v", s, d)
+		}
+	}
+	if d := ParseDate("março"); !d.IsZero() {
+		t.Errorf("data inválida deveria ser zero, veio %v", d)
+	}
+}
+
+// redirecionar envia as requisições à BrasilAPI para o servidor de teste
+type redirecionar string
+
+func (r redirecionar) RoundTrip(req *http.Request) (*http.Response, error) {
+	destino, _ := url.Parse(string(r))
+	req.URL.Scheme, req.URL.Host = destino.Scheme, destino.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBuscarCadastroNaoGuardaFalhasTemporarias(t *testing.T) {
+	status := map[string]int{"11222333000181": http.StatusServiceUnavailable, "11444777000161": http.StatusNotFound}
+	consultas := map[string]int{}
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnpj := r.URL.Path[len("/api/cnpj/v1/"):]
+		consultas[cnpj]++
+		if s, ok := status[cnpj]; ok {
+			w.WriteHeader(s)
+			return
+		}
+		fmt.Fprint(w, `{"razao_social": "Gráfica Nova", "data_inicio_atividade": "2024-01-10"}`)
+	}))
+	defer servidor.Close()
+
+	db := mongomemtest.Banco(t)
+	a := &AnaliseDespesas{client: sync.NewHTTPClient(1000).UsarTransporte(redirecionar(servidor.URL)), db: db}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := a.buscarCadastro(ctx, "11222333000181"); err == nil {
+			t.Error("falha temporária deveria retornar erro")
+		}
+		if cadastro, err := a.buscarCadastro(ctx, "11444777000161"); err != nil || !cadastro.DataAbertura.IsZero() {
+			t.Errorf("CNPJ inexistente: %+v, %v", cadastro, err)
+		}
+		if cadastro, err := a.buscarCadastro(ctx, "19131243000197"); err != nil || cadastro.RazaoSocial != "Gráfica Nova" {
+			t.Errorf("CNPJ encontrado: %+v, %v", cadastro, err)
+		}
+	}
+
+	// A falha temporária é consultada de novo; o inexistente e o encontrado vêm do cache
+	if consultas["11222333000181"] != 2 || consultas["11444777000161"] != 1 || consultas["19131243000197"] != 1 {
+		t.Errorf("consultas à BrasilAPI: %v", consultas)
+	}
+	if n, _ := db.Collection("cadastro_fornecedores").CountDocuments(ctx, bson.M{"cnpj": "11222333000181"}); n != 0 {
+		t.Error("a falha temporária não deveria ir para o cache")
+	}
+}
+
+func TestRetirarAlertas(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	a := &AnaliseDespesas{db: db}
+	ctx := context.Background()
+
+	politico := primitive.NewObjectID()
+	alerta := func(chave string, tipo domain.TipoAlerta, ano int) domain.Alerta {
+		return domain.Alerta{ID: primitive.NewObjectID(), Chave: chave, PoliticoID: politico, Tipo: tipo, AnoReferencia: ano}
+	}
+	_, err := db.Collection("alertas").InsertMany(ctx, []interface{}{
+		alerta("mantido", domain.AlertaReciboDuplicado, 2024),
+		alerta("resolvido", domain.AlertaReciboDuplicado, 2024),
+		alerta("outro-ano", domain.AlertaReciboDuplicado, 2023),
+		alerta("outra-regra", domain.AlertaPicoHistorico, 2024),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retirados, err := a.retirarAlertas(ctx, 2024, []domain.TipoAlerta{domain.AlertaReciboDuplicado}, []domain.Alerta{{Chave: "mantido"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retirados != 1 {
+		t.Errorf("%d alertas retirados; esperado 1", retirados)
+	}
+	if n, _ := db.Collection("alertas").CountDocuments(ctx, bson.M{"chave": "resolvido"}); n != 0 {
+		t.Error("o alerta cuja condição deixou de existir deveria ter sido retirado")
+	}
+}
+
+func TestSemCadastro(t *testing.T) {
+	for err, esperado := range map[error]bool{
+		&sync.ErroStatus{Status: http.StatusNotFound}:                      true,
+		&sync.ErroStatus{Status: http.StatusBadRequest}:                    true,
+		fmt.Errorf("x: %w", &sync.ErroStatus{Status: http.StatusNotFound}): true,
+		&sync.ErroStatus{Status: http.StatusTooManyRequests}:               false,
+		&sync.ErroStatus{Status: http.StatusBadGateway}:                    false,
+		context.DeadlineExceeded:                                           false,
+		errors.New("erro na requisição: connection refused"):               false,
+	} {
+		if semCadastro(err) != esperado {
+			t.Errorf("semCadastro(%v) = %v", err, !esperado)
+		}
+	}
+}
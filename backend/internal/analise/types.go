@@ -0,0 +1,61 @@
+package analise
+
+import "time"
+
+// BrasilAPIURL é a API pública usada para consultar dados cadastrais de CNPJ
+// Documentação: https://brasilapi.com.br/docs#tag/CNPJ
+const BrasilAPIURL = "https://brasilapi.com.br/api/cnpj/v1"
+
+// CNPJResponse representa a resposta da BrasilAPI para um CNPJ
+type CNPJResponse struct {
+	CNPJ                string `json:"cnpj"`
+	RazaoSocial         string `json:"razao_social"`
+	NomeFantasia        string `json:"nome_fantasia"`
+	DataInicioAtividade string `json:"data_inicio_atividade"`
+	UF                  string `json:"uf"`
+	Municipio           string `json:"municipio"`
+}
+
+// CadastroFornecedor representa os dados cadastrais de um fornecedor guardados em cache
+type CadastroFornecedor struct {
+	CNPJ         string    `bson:"cnpj"`
+	RazaoSocial  string    `bson:"razao_social"`
+	DataAbertura time.Time `bson:"data_abertura"`
+	ConsultadoEm time.Time `bson:"consultado_em"`
+}
+
+// TetosCEAP contém os limites mensais por categoria da cota parlamentar (Ato da Mesa nº 43/2009 e atualizações).
+// Categorias sem subteto próprio ficam de fora. Atualizar quando a Mesa reajustar os valores.
+var TetosCEAP = map[string]float64{
+	"COMBUSTÍVEIS E LUBRIFICANTES.":                            9392.00,
+	"LOCAÇÃO OU FRETAMENTO DE VEÍCULOS AUTOMOTORES":            12713.00,
+	"SERVIÇO DE SEGURANÇA PRESTADO POR EMPRESA ESPECIALIZADA.": 8700.00,
+}
+
+// CotasCEAPPorUF contém o valor mensal total da cota parlamentar de cada estado, que varia com o
+// custo das passagens até Brasília (valores de 2023). Atualizar quando a Mesa reajustar os valores.
+var CotasCEAPPorUF = map[string]float64{
+	"AC": 50426.64, "AL": 46737.90, "AM": 49363.92, "AP": 49168.58, "BA": 44804.65,
+	"CE": 48245.57, "DF": 36582.46, "ES": 43217.71, "GO": 41300.86, "MA": 47945.49,
+	"MG": 41886.51, "MS": 46336.64, "MT": 45221.83, "PA": 48021.25, "PB": 47826.36,
+	"PE": 47470.60, "PI": 46765.57, "PR": 44665.66, "RJ": 41553.77, "RN": 48525.79,
+	"RO": 49466.29, "RR": 51406.33, "RS": 46669.70, "SC": 45671.58, "SE": 45933.06,
+	"SP": 42837.33, "TO": 45297.41,
+}
+
+// ParseDate converte string de data para time.Time
+func ParseDate(dateStr string) time.Time {
+	layouts := []string{
+		"2006-01-02",
+		"2006-01-02T15:04:05",
+		"02/01/2006",
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, dateStr); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
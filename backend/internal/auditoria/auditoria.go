@@ -0,0 +1,220 @@
+// Package auditoria registra as alterações feitas pelas sincronizações no cadastro dos políticos.
+package auditoria
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/qualidade"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// camposIgnorados não geram registro: são carimbos de tempo ou históricos derivados de outros campos
+var camposIgnorados = map[string]bool{
+	"updated_at":         true,
+	"created_at":         true,
+	"historico_cargos":   true,
+	"historico_partidos": true,
+	"fontes":             true,
+}
+
+// ignorado indica se o campo não gera registro; os subcampos ("fontes.camara") seguem o campo principal
+func ignorado(campo string) bool {
+	principal, _, _ := strings.Cut(campo, ".")
+	return camposIgnorados[principal]
+}
+
+type chaveExecucao struct{}
+
+// ComExecucao associa ao contexto o identificador da execução da sincronização
+func ComExecucao(ctx context.Context, execucaoID string) context.Context {
+	return context.WithValue(ctx, chaveExecucao{}, execucaoID)
+}
+
+// Execucao retorna o identificador da execução associado ao contexto, ou "" se não houver
+func Execucao(ctx context.Context) string {
+	id, _ := ctx.Value(chaveExecucao{}).(string)
+	return id
+}
+
+// Auditoria aplica atualizações na coleção de políticos registrando cada campo alterado
+type Auditoria struct {
+	politicos  *mongo.Collection
+	alteracoes *mongo.Collection
+	fonte      string
+}
+
+// New cria um registrador de alterações para o sincronizador informado (ex.: "camara")
+func New(db *mongo.Database, fonte string) *Auditoria {
+	return &Auditoria{
+		politicos:  db.Collection("politicos"),
+		alteracoes: db.Collection("alteracoes"),
+		fonte:      fonte,
+	}
+}
+
+// AtualizarPolitico aplica o update ao político do filtro e registra as diferenças dos campos em $set.
+// Com upsert, a criação do político também é registrada; o _id deve vir em $setOnInsert.
+// Havendo um validador de qualidade no contexto, o cadastro resultante é validado.
+func (a *Auditoria) AtualizarPolitico(ctx context.Context, filter, update bson.M, upsert bool) error {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(upsert).
+		SetReturnDocument(options.Before)
+
+	set, _ := update["$set"].(bson.M)
+
+	var anterior bson.D
+	err := a.politicos.FindOneAndUpdate(ctx, filter, update, opts).Decode(&anterior)
+	if err == mongo.ErrNoDocuments {
+		if upsert {
+			a.registrarCriacao(ctx, update)
+			insert, _ := update["$setOnInsert"].(bson.M)
+			a.validar(ctx, nil, insert, set)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	a.registrarDiferencas(ctx, anterior, set)
+	a.validar(ctx, anterior, set)
+	return nil
+}
+
+// validar monta o cadastro como ficou após o update, sobrepondo os campos alterados
+// ao documento anterior, e o entrega ao validador de qualidade do contexto
+func (a *Auditoria) validar(ctx context.Context, anterior bson.D, campos ...bson.M) {
+	validador := qualidade.De(ctx)
+	if validador == nil {
+		return
+	}
+
+	doc := anterior.Map()
+	for _, c := range campos {
+		for campo, valor := range c {
+			doc[campo] = valor
+		}
+	}
+
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return
+	}
+	var politico domain.Politico
+	if err := bson.Unmarshal(data, &politico); err != nil {
+		log.Printf("⚠️ Erro ao validar político: %v", err)
+		return
+	}
+	validador.ValidarPolitico(ctx, a.fonte, politico)
+}
+
+func (a *Auditoria) registrarCriacao(ctx context.Context, update bson.M) {
+	insert, _ := update["$setOnInsert"].(bson.M)
+	id, ok := insert["_id"].(primitive.ObjectID)
+	if !ok {
+		return
+	}
+
+	a.salvar(ctx, []interface{}{a.nova(id, domain.OperacaoCriacao)})
+}
+
+func (a *Auditoria) registrarDiferencas(ctx context.Context, anterior bson.D, set bson.M) {
+	valores := anterior.Map()
+	id, ok := valores["_id"].(primitive.ObjectID)
+	if !ok {
+		return
+	}
+
+	var alteracoes []interface{}
+	for campo, valor := range set {
+		if ignorado(campo) {
+			continue
+		}
+
+		novo := normalizar(valor)
+		antigo, existia := valores[campo]
+		if !existia && vazio(novo) {
+			continue
+		}
+		if iguais(antigo, novo) {
+			continue
+		}
+
+		alteracao := a.nova(id, domain.OperacaoAtualizacao)
+		alteracao.Campo = campo
+		alteracao.ValorAnterior = antigo
+		alteracao.ValorNovo = novo
+		alteracoes = append(alteracoes, alteracao)
+	}
+
+	a.salvar(ctx, alteracoes)
+}
+
+func (a *Auditoria) nova(politicoID primitive.ObjectID, operacao domain.OperacaoAlteracao) domain.Alteracao {
+	return domain.Alteracao{
+		PoliticoID: politicoID,
+		Operacao:   operacao,
+		Fonte:      a.fonte,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func (a *Auditoria) salvar(ctx context.Context, alteracoes []interface{}) {
+	if len(alteracoes) == 0 {
+		return
+	}
+
+	execucao := Execucao(ctx)
+	for i := range alteracoes {
+		alteracao := alteracoes[i].(domain.Alteracao)
+		alteracao.ExecucaoID = execucao
+		alteracoes[i] = alteracao
+	}
+
+	// Falhar ao registrar a auditoria não deve interromper a sincronização
+	if _, err := a.alteracoes.InsertMany(ctx, alteracoes); err != nil {
+		log.Printf("⚠️ Erro ao registrar alterações: %v", err)
+	}
+}
+
+// normalizar converte o valor para a mesma representação que teria ao ser lido do banco
+func normalizar(valor interface{}) interface{} {
+	data, err := bson.Marshal(bson.D{{Key: "v", Value: valor}})
+	if err != nil {
+		return valor
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil || len(doc) == 0 {
+		return valor
+	}
+	return doc[0].Value
+}
+
+func iguais(a, b interface{}) bool {
+	da, errA := bson.Marshal(bson.D{{Key: "v", Value: a}})
+	db, errB := bson.Marshal(bson.D{{Key: "v", Value: b}})
+	return errA == nil && errB == nil && bytes.Equal(da, db)
+}
+
+func vazio(valor interface{}) bool {
+	switch v := valor.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bson.A:
+		return len(v) == 0
+	case bson.D:
+		return len(v) == 0
+	}
+	return false
+}
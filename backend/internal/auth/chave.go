@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// PrefixoChave identifica as chaves de API geradas pelo Lupa Cidadã
+const PrefixoChave = "lc_"
+
+// GerarChave cria uma nova chave de API aleatória e retorna o valor completo (mostrado uma
+// única vez), o prefixo usado para identificá-la nas listagens e o hash que é guardado no banco
+func GerarChave() (chave, prefixo, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+
+	chave = PrefixoChave + base64.RawURLEncoding.EncodeToString(b)
+	return chave, chave[:len(PrefixoChave)+6], HashChave(chave), nil
+}
+
+// HashChave calcula o hash guardado no banco para uma chave de API
+func HashChave(chave string) string {
+	sum := sha256.Sum256([]byte(chave))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+)
+
+var (
+	ErrTokenInvalido = errors.New("token inválido")
+	ErrTokenExpirado = errors.New("token expirado")
+)
+
+// Claims são os dados assinados no token JWT
+type Claims struct {
+	Sujeito   string       `json:"sub"`
+	Nome      string       `json:"nome,omitempty"`
+	Papel     domain.Papel `json:"papel"`
+	EmitidoEm int64        `json:"iat"`
+	ExpiraEm  int64        `json:"exp"`
+}
+
+var cabecalhoJWT = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// EmitirToken gera um JWT HS256 para a identidade, válido pela duração informada
+func EmitirToken(segredo []byte, id Identidade, validade time.Duration, agora time.Time) (string, error) {
+	if len(segredo) == 0 {
+		return "", ErrTokenInvalido
+	}
+
+	payload, err := json.Marshal(Claims{
+		Sujeito:   id.ID,
+		Nome:      id.Nome,
+		Papel:     id.Papel,
+		EmitidoEm: agora.Unix(),
+		ExpiraEm:  agora.Add(validade).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	conteudo := cabecalhoJWT + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return conteudo + "." + assinar(segredo, conteudo), nil
+}
+
+// ValidarToken confere a assinatura e a validade de um JWT emitido por EmitirToken
+func ValidarToken(segredo []byte, token string, agora time.Time) (*Claims, error) {
+	if len(segredo) == 0 {
+		return nil, ErrTokenInvalido
+	}
+
+	partes := strings.Split(token, ".")
+	if len(partes) != 3 || partes[0] != cabecalhoJWT {
+		return nil, ErrTokenInvalido
+	}
+
+	esperada := assinar(segredo, partes[0]+"."+partes[1])
+	if !hmac.Equal([]byte(esperada), []byte(partes[2])) {
+		return nil, ErrTokenInvalido
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(partes[1])
+	if err != nil {
+		return nil, ErrTokenInvalido
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrTokenInvalido
+	}
+	if !claims.Papel.Valido() || claims.Sujeito == "" {
+		return nil, ErrTokenInvalido
+	}
+	if agora.Unix() >= claims.ExpiraEm {
+		return nil, ErrTokenExpirado
+	}
+
+	return &claims, nil
+}
+
+func assinar(segredo []byte, conteudo string) string {
+	mac := hmac.New(sha256.New, segredo)
+	mac.Write([]byte(conteudo))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
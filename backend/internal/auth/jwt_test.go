@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+)
+
+var segredoTeste = []byte("segredo-de-teste")
+
+func TestEmitirEValidarToken(t *testing.T) {
+	agora := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	id := Identidade{ID: "65f0c0ffee", Nome: "painel", Papel: domain.PapelEditor}
+
+	token, err := EmitirToken(segredoTeste, id, time.Hour, agora)
+	if err != nil {
+		t.Fatalf("EmitirToken: %v", err)
+	}
+
+	claims, err := ValidarToken(segredoTeste, token, agora.Add(59*time.Minute))
+	if err != nil {
+		t.Fatalf("ValidarToken: %v", err)
+	}
+	if claims.Sujeito != id.ID || claims.Nome != id.Nome || claims.Papel != id.Papel || claims.ExpiraEm != agora.Add(time.Hour).Unix() {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if _, err := ValidarToken(segredoTeste, token, agora.Add(time.Hour)); !errors.Is(err, ErrTokenExpirado) {
+		t.Errorf("token vencido: erro %v; esperado ErrTokenExpirado", err)
+	}
+	if _, err := ValidarToken([]byte("outro-segredo"), token, agora); !errors.Is(err, ErrTokenInvalido) {
+		t.Errorf("outro segredo: erro %v; esperado ErrTokenInvalido", err)
+	}
+	if _, err := EmitirToken(nil, id, time.Hour, agora); !errors.Is(err, ErrTokenInvalido) {
+		t.Errorf("emissão sem segredo: erro %v; esperado ErrTokenInvalido", err)
+	}
+	if _, err := ValidarToken(nil, token, agora); !errors.Is(err, ErrTokenInvalido) {
+		t.Errorf("validação sem segredo: erro %v; esperado ErrTokenInvalido", err)
+	}
+}
+
+// Tokens alterados depois de assinados são recusados
+func TestValidarTokenAdulterado(t *testing.T) {
+	agora := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	token, err := EmitirToken(segredoTeste, Identidade{ID: "65f0c0ffee", Papel: domain.PapelLeitor}, time.Hour, agora)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partes := strings.Split(token, ".")
+	codificar := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	// Mesmo payload promovido a ADMIN, com a assinatura original
+	payload, _ := base64.RawURLEncoding.DecodeString(partes[1])
+	promovido := strings.Replace(string(payload), `"papel":"LEITOR"`, `"papel":"ADMIN"`, 1)
+
+	// Cabeçalho com outro algoritmo, assinado corretamente sobre o novo cabeçalho
+	hs512 := codificar(`{"alg":"HS512","typ":"JWT"}`)
+
+	for nome, adulterado := range map[string]string{
+		"payload alterado":     partes[0] + "." + codificar(promovido) + "." + partes[2],
+		"assinatura alterada":  partes[0] + "." + partes[1] + "." + assinar([]byte("outro"), partes[0]+"."+partes[1]),
+		"sem assinatura":       partes[0] + "." + partes[1] + ".",
+		"alg none":             codificar(`{"alg":"none","typ":"JWT"}`) + "." + partes[1] + ".",
+		"alg HS512":            hs512 + "." + partes[1] + "." + assinar(segredoTeste, hs512+"."+partes[1]),
+		"partes a menos":       partes[0] + "." + partes[1],
+		"papel desconhecido":   assinado(partes[0], codificar(strings.Replace(string(payload), `"LEITOR"`, `"ROOT"`, 1))),
+		"sem sujeito":          assinado(partes[0], codificar(strings.Replace(string(payload), `"sub":"65f0c0ffee"`, `"sub":""`, 1))),
+		"payload não é base64": assinado(partes[0], "!!!"),
+		"payload não é JSON":   assinado(partes[0], codificar("papel=ADMIN")),
+	} {
+		if _, err := ValidarToken(segredoTeste, adulterado, agora); !errors.Is(err, ErrTokenInvalido) {
+			t.Errorf("%s: erro %v; esperado ErrTokenInvalido", nome, err)
+		}
+	}
+}
+
+// assinado monta um token com assinatura válida, para testar as verificações do conteúdo
+func assinado(cabecalho, payload string) string {
+	return cabecalho + "." + payload + "." + assinar(segredoTeste, cabecalho+"."+payload)
+}
@@ -0,0 +1,225 @@
+// Package auth autentica as requisições às rotas protegidas da API, por chave de API ou
+// token JWT, e controla o acesso pelo papel da credencial.
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HeaderChaveAPI é o cabeçalho alternativo para enviar a chave de API
+const HeaderChaveAPI = "X-API-Key"
+
+const chaveContexto = "identidade"
+
+// validadeCache é por quanto tempo uma chave consultada no banco é reaproveitada. Chaves
+// revogadas podem continuar aceitas por até esse tempo.
+const validadeCache = time.Minute
+
+// maxCache limita as chaves guardadas, para que chaves inventadas (que também são guardadas,
+// como inexistentes) não façam o cache crescer sem limite
+const maxCache = 10000
+
+// Identidade é quem fez a requisição autenticada
+type Identidade struct {
+	ID      string             `json:"id"`
+	Nome    string             `json:"nome"`
+	Papel   domain.Papel       `json:"papel"`
+	Limites *domain.LimitesUso `json:"limites,omitempty"`
+
+	// PorToken indica que a credencial foi um token JWT, e não a chave de API. O token não é
+	// conferido no banco, então continua valendo até expirar mesmo se a chave for revogada.
+	PorToken bool `json:"-"`
+}
+
+// ArmazemChaves é onde as chaves de API são consultadas
+type ArmazemChaves interface {
+	BuscarPorHash(ctx context.Context, hash string) (*domain.ChaveAPI, error)
+	RegistrarUso(ctx context.Context, id primitive.ObjectID) error
+}
+
+type chaveEmCache struct {
+	chave  *domain.ChaveAPI // nil quando a chave não existe ou foi revogada
+	expira time.Time
+}
+
+// Autenticador identifica as requisições pela chave de API ou pelo token JWT
+type Autenticador struct {
+	chaves  ArmazemChaves
+	segredo []byte
+
+	mu    gosync.Mutex
+	cache map[string]chaveEmCache
+}
+
+// NewAutenticador cria o autenticador. Sem armazém de chaves (modo debug), só tokens JWT são
+// aceitos; sem segredo, só chaves de API.
+func NewAutenticador(chaves ArmazemChaves, segredoJWT string) *Autenticador {
+	return &Autenticador{
+		chaves:  chaves,
+		segredo: []byte(segredoJWT),
+		cache:   make(map[string]chaveEmCache),
+	}
+}
+
+// Identificar guarda no contexto a identidade da credencial enviada, se houver. Requisições
+// sem credencial seguem anônimas; credenciais inválidas são recusadas.
+func (a *Autenticador) Identificar(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		credencial := credencialDe(c)
+		if credencial == "" {
+			return next(c)
+		}
+
+		id, err := a.identificar(c.Request().Context(), credencial)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Erro ao validar credencial",
+			})
+		}
+		if id == nil {
+			return naoAutorizado(c)
+		}
+
+		c.Set(chaveContexto, id)
+		return next(c)
+	}
+}
+
+// Autenticar exige uma credencial válida, enviada em "Authorization: Bearer <chave ou token>"
+// ou no cabeçalho X-API-Key, e guarda a identidade no contexto da requisição
+func (a *Autenticador) Autenticar(next echo.HandlerFunc) echo.HandlerFunc {
+	return a.Identificar(func(c echo.Context) error {
+		if IdentidadeDe(c) == nil {
+			return naoAutorizado(c)
+		}
+		return next(c)
+	})
+}
+
+func credencialDe(c echo.Context) string {
+	if credencial := c.Request().Header.Get(HeaderChaveAPI); credencial != "" {
+		return credencial
+	}
+	cabecalho := c.Request().Header.Get(echo.HeaderAuthorization)
+	if strings.HasPrefix(cabecalho, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(cabecalho, "Bearer "))
+	}
+	return ""
+}
+
+// identificar retorna a identidade da credencial, ou nil se ela não for válida
+func (a *Autenticador) identificar(ctx context.Context, credencial string) (*Identidade, error) {
+	if strings.HasPrefix(credencial, PrefixoChave) {
+		chave, err := a.buscarChave(ctx, HashChave(credencial))
+		if err != nil || chave == nil {
+			return nil, err
+		}
+		return &Identidade{ID: chave.ID.Hex(), Nome: chave.Nome, Papel: chave.Papel, Limites: chave.Limites}, nil
+	}
+
+	claims, err := ValidarToken(a.segredo, credencial, time.Now())
+	if err != nil {
+		return nil, nil
+	}
+	return &Identidade{ID: claims.Sujeito, Nome: claims.Nome, Papel: claims.Papel, PorToken: true}, nil
+}
+
+// buscarChave consulta a chave no banco, reaproveitando consultas recentes para não
+// pesar em cada requisição pública
+func (a *Autenticador) buscarChave(ctx context.Context, hash string) (*domain.ChaveAPI, error) {
+	if a.chaves == nil {
+		return nil, nil
+	}
+
+	agora := time.Now()
+	a.mu.Lock()
+	emCache, ok := a.cache[hash]
+	a.mu.Unlock()
+	if ok && agora.Before(emCache.expira) {
+		return emCache.chave, nil
+	}
+
+	chave, err := a.chaves.BuscarPorHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if chave != nil {
+		// Falha ao registrar o uso não deve impedir o acesso
+		_ = a.chaves.RegistrarUso(ctx, chave.ID)
+	}
+
+	a.mu.Lock()
+	for h, e := range a.cache {
+		if agora.After(e.expira) {
+			delete(a.cache, h)
+		}
+	}
+	if len(a.cache) < maxCache {
+		a.cache[hash] = chaveEmCache{chave: chave, expira: agora.Add(validadeCache)}
+	}
+	a.mu.Unlock()
+
+	return chave, nil
+}
+
+// EmitirToken troca a identidade autenticada por um token JWT com o mesmo papel
+func (a *Autenticador) EmitirToken(id Identidade, validade time.Duration) (string, error) {
+	return EmitirToken(a.segredo, id, validade, time.Now())
+}
+
+// ExigirPapel permite a requisição apenas se a identidade autenticada tiver pelo menos o papel
+// informado. Deve ser usado depois de Autenticar.
+func ExigirPapel(minimo domain.Papel) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := IdentidadeDe(c)
+			if id == nil {
+				return naoAutorizado(c)
+			}
+			if !id.Papel.Inclui(minimo) {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Permissão insuficiente",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// ExigirChaveAPI permite a requisição apenas se a credencial for uma chave de API. Usado na
+// emissão de tokens: se um token pudesse ser trocado por outro, ele se renovaria para sempre,
+// mesmo depois de revogada a chave que o originou. Deve ser usado depois de Autenticar.
+func ExigirChaveAPI(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := IdentidadeDe(c)
+		if id == nil {
+			return naoAutorizado(c)
+		}
+		if id.PorToken {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Tokens são emitidos só em troca de uma chave de API",
+			})
+		}
+		return next(c)
+	}
+}
+
+// IdentidadeDe retorna a identidade autenticada da requisição, ou nil
+func IdentidadeDe(c echo.Context) *Identidade {
+	id, _ := c.Get(chaveContexto).(*Identidade)
+	return id
+}
+
+func naoAutorizado(c echo.Context) error {
+	return c.JSON(http.StatusUnauthorized, map[string]string{
+		"error": "Não autorizado",
+	})
+}
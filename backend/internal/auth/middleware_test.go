@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// armazemTeste guarda as chaves ativas pelo hash
+type armazemTeste map[string]*domain.ChaveAPI
+
+func (a armazemTeste) BuscarPorHash(ctx context.Context, hash string) (*domain.ChaveAPI, error) {
+	return a[hash], nil
+}
+
+func (a armazemTeste) RegistrarUso(ctx context.Context, id primitive.ObjectID) error {
+	return nil
+}
+
+func TestPapelInclui(t *testing.T) {
+	papeis := []domain.Papel{domain.PapelConsumidor, domain.PapelLeitor, domain.PapelEditor, domain.PapelAdmin}
+	for i, p := range papeis {
+		for j, minimo := range papeis {
+			if inclui := p.Inclui(minimo); inclui != (i >= j) {
+				t.Errorf("%s.Inclui(%s) = %v", p, minimo, inclui)
+			}
+		}
+	}
+	if domain.Papel("ROOT").Inclui(domain.PapelConsumidor) {
+		t.Error("papel desconhecido não deve incluir nenhum papel")
+	}
+}
+
+// executar passa a requisição com a credencial pelos middlewares e retorna o status
+func executar(t *testing.T, credencial string, middlewares ...echo.MiddlewareFunc) (int, *Identidade) {
+	t.Helper()
+	var identidade *Identidade
+	handler := echo.HandlerFunc(func(c echo.Context) error {
+		identidade = IdentidadeDe(c)
+		return c.NoContent(http.StatusOK)
+	})
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if credencial != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+credencial)
+	}
+	rec := httptest.NewRecorder()
+	if err := handler(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	return rec.Code, identidade
+}
+
+func TestAutenticarEExigirPapel(t *testing.T) {
+	leitor, _, hashLeitor, _ := GerarChave()
+	admin, _, hashAdmin, _ := GerarChave()
+	revogada, _, _, _ := GerarChave()
+	a := NewAutenticador(armazemTeste{
+		hashLeitor: {ID: primitive.NewObjectID(), Nome: "leitor", Papel: domain.PapelLeitor},
+		hashAdmin:  {ID: primitive.NewObjectID(), Nome: "admin", Papel: domain.PapelAdmin},
+	}, string(segredoTeste))
+
+	token, err := a.EmitirToken(Identidade{ID: "65f0c0ffee", Papel: domain.PapelEditor}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vencido, _ := EmitirToken(segredoTeste, Identidade{ID: "65f0c0ffee", Papel: domain.PapelAdmin}, time.Hour, time.Now().Add(-2*time.Hour))
+
+	editor := ExigirPapel(domain.PapelEditor)
+	for _, caso := range []struct {
+		nome       string
+		credencial string
+		esperado   int
+	}{
+		{"sem credencial", "", http.StatusUnauthorized},
+		{"chave revogada", revogada, http.StatusUnauthorized},
+		{"token vencido", vencido, http.StatusUnauthorized},
+		{"token adulterado", token + "x", http.StatusUnauthorized},
+		{"papel insuficiente", leitor, http.StatusForbidden},
+		{"papel igual (token)", token, http.StatusOK},
+		{"papel superior", admin, http.StatusOK},
+	} {
+		if status, _ := executar(t, caso.credencial, a.Autenticar, editor); status != caso.esperado {
+			t.Errorf("%s: status %d; esperado %d", caso.nome, status, caso.esperado)
+		}
+	}
+
+	// Sem Autenticar antes, ExigirPapel recusa a requisição
+	if status, _ := executar(t, admin, editor); status != http.StatusUnauthorized {
+		t.Errorf("ExigirPapel sem identidade: status %d; esperado 401", status)
+	}
+}
+
+// Um token não pode ser trocado por outro: só a chave de API, conferida no banco, emite tokens
+func TestExigirChaveAPI(t *testing.T) {
+	chave, _, hash, _ := GerarChave()
+	armazem := armazemTeste{hash: {ID: primitive.NewObjectID(), Nome: "painel", Papel: domain.PapelAdmin}}
+	a := NewAutenticador(armazem, string(segredoTeste))
+
+	status, id := executar(t, chave, a.Autenticar, ExigirChaveAPI)
+	if status != http.StatusOK || id == nil || id.PorToken {
+		t.Fatalf("chave de API: status %d, identidade %+v", status, id)
+	}
+
+	token, err := a.EmitirToken(*id, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := executar(t, token, a.Autenticar, ExigirChaveAPI); status != http.StatusForbidden {
+		t.Errorf("token trocado por token: status %d; esperado 403", status)
+	}
+
+	// O token carrega o ID da chave, que fica como sujeito
+	if status, tokenID := executar(t, token, a.Autenticar); status != http.StatusOK || tokenID.ID != id.ID || !tokenID.PorToken {
+		t.Errorf("token: status %d, identidade %+v", status, tokenID)
+	}
+}
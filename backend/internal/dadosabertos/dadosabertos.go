@@ -0,0 +1,398 @@
+// Package dadosabertos gera e lista os dumps de dados abertos: uma fotografia datada de cada
+// coleção pública em arquivos comprimidos, com um manifest.json (linhas, SHA-256, versão do
+// esquema e sincronizações de origem) que permite conferir e citar exatamente a versão usada.
+//
+// Cada dump fica em um subdiretório com a data (AAAA-MM-DD) dentro do diretório de dados
+// abertos. O dump é gravado em um diretório temporário e só aparece com o nome final quando
+// está completo, então quem lista nunca vê um dump pela metade.
+package dadosabertos
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/exportacao"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NomeManifesto é o nome do manifesto dentro do diretório de cada dump
+const NomeManifesto = "manifest.json"
+
+// formatoData é o formato do nome dos diretórios dos dumps
+const formatoData = "2006-01-02"
+
+var (
+	ErrDumpNaoEncontrado = errors.New("dump de dados abertos não encontrado")
+	ErrDumpExistente     = errors.New("já existe um dump com esta data")
+)
+
+// Arquivo descreve um arquivo do dump
+type Arquivo struct {
+	Nome    string             `json:"nome"` // Ex.: despesas.csv.gz
+	Colecao string             `json:"colecao"`
+	Formato exportacao.Formato `json:"formato"`
+	Linhas  int64              `json:"linhas"`
+	Bytes   int64              `json:"bytes"`  // Tamanho do arquivo comprimido
+	SHA256  string             `json:"sha256"` // Do arquivo comprimido, como é baixado
+	Colunas []string           `json:"colunas"`
+}
+
+// ExecucaoFonte é a sincronização mais recente de uma entidade de uma fonte concluída antes do
+// dump: os dados daquela entidade refletem o que essa execução gravou
+type ExecucaoFonte struct {
+	Fonte       string     `json:"fonte"`
+	Entidade    string     `json:"entidade"`
+	ExecucaoID  string     `json:"execucaoId"`
+	ConcluidaEm *time.Time `json:"concluidaEm,omitempty"`
+}
+
+// Manifesto é o conteúdo do manifest.json de um dump
+type Manifesto struct {
+	Data          string          `json:"data"`
+	VersaoEsquema int             `json:"versaoEsquema"`
+	GeradoEm      time.Time       `json:"geradoEm"`
+	Corte         time.Time       `json:"corte"` // Só entram os registros criados até este instante
+	Execucoes     []ExecucaoFonte `json:"execucoes"`
+	Arquivos      []Arquivo       `json:"arquivos"`
+}
+
+// Repositorios são as coleções publicadas nos dumps
+type Repositorios struct {
+	Politicos   repository.Politicos
+	Votacoes    repository.Votacoes
+	Despesas    repository.Despesas
+	Proposicoes repository.Proposicoes
+	Presencas   repository.Presencas
+}
+
+// Opcoes configura a geração de um dump
+type Opcoes struct {
+	Diretorio  string
+	Data       time.Time
+	Formatos   []exportacao.Formato
+	Substituir bool // Substitui um dump existente com a mesma data, em vez de falhar
+}
+
+// Gerar grava o dump de todas as coleções e o manifesto. As execuções de sincronização, das
+// mais recentes para as mais antigas, identificam a origem dos dados no manifesto.
+// Cada coleção é lida uma só vez, gravando todos os formatos na mesma passada do cursor.
+//
+// As coleções são lidas uma depois da outra, sem uma sessão de snapshot (o MongoDB do
+// projeto não roda em replica set). Para que uma sincronização em andamento não deixe, por
+// exemplo, votos de um político que não está no dump, todas as coleções são limitadas pelo
+// mesmo corte: só entram os registros criados até o início da geração, pela data do _id.
+func Gerar(ctx context.Context, repos Repositorios, execucoes []domain.ExecucaoSync, opcoes Opcoes) (*Manifesto, error) {
+	if len(opcoes.Formatos) == 0 {
+		return nil, errors.New("nenhum formato informado")
+	}
+	for _, f := range opcoes.Formatos {
+		if f != exportacao.FormatoCSV && f != exportacao.FormatoNDJSON {
+			return nil, fmt.Errorf("formato %q não é publicado nos dados abertos: use csv ou ndjson", f)
+		}
+	}
+
+	data := opcoes.Data.Format(formatoData)
+	final := filepath.Join(opcoes.Diretorio, data)
+	if _, err := os.Stat(final); err == nil && !opcoes.Substituir {
+		return nil, fmt.Errorf("%w: %s", ErrDumpExistente, final)
+	}
+
+	parcial := filepath.Join(opcoes.Diretorio, "."+data+".parcial")
+	if err := os.RemoveAll(parcial); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(parcial, 0o755); err != nil {
+		return nil, err
+	}
+
+	manifesto := &Manifesto{
+		Data:          data,
+		VersaoEsquema: exportacao.VersaoEsquema,
+		// O _id guarda a criação em segundos
+		Corte:     time.Now().UTC().Truncate(time.Second),
+		Execucoes: ultimasExecucoes(execucoes),
+	}
+
+	arquivos, err := gravarColecoes(ctx, repos, parcial, opcoes.Formatos, manifesto.Corte)
+	if err != nil {
+		os.RemoveAll(parcial)
+		return nil, err
+	}
+	manifesto.Arquivos = arquivos
+	manifesto.GeradoEm = time.Now().UTC()
+
+	conteudo, err := json.MarshalIndent(manifesto, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(parcial, NomeManifesto), append(conteudo, '\n'), 0o644)
+	}
+	if err == nil {
+		err = os.RemoveAll(final)
+	}
+	if err == nil {
+		err = os.Rename(parcial, final)
+	}
+	if err != nil {
+		os.RemoveAll(parcial)
+		return nil, err
+	}
+
+	return manifesto, nil
+}
+
+func gravarColecoes(ctx context.Context, repos Repositorios, dir string, formatos []exportacao.Formato, corte time.Time) ([]Arquivo, error) {
+	var arquivos []Arquivo
+	adicionar := func(a []Arquivo, err error) error {
+		arquivos = append(arquivos, a...)
+		return err
+	}
+
+	if err := adicionar(gravarColecao(dir, "politicos", formatos, func(fn func(domain.Politico) error) error {
+		return repos.Politicos.Exportar(ctx, domain.FiltrosPoliticos{}, criadosAte(corte, fn, func(d domain.Politico) primitive.ObjectID { return d.ID }))
+	}, exportacao.NovaLinhaPolitico)); err != nil {
+		return nil, err
+	}
+	if err := adicionar(gravarColecao(dir, "votacoes", formatos, func(fn func(domain.Votacao) error) error {
+		return repos.Votacoes.Exportar(ctx, domain.FiltrosVotacoes{}, criadosAte(corte, fn, func(d domain.Votacao) primitive.ObjectID { return d.ID }))
+	}, exportacao.NovaLinhaVotacao)); err != nil {
+		return nil, err
+	}
+	if err := adicionar(gravarColecao(dir, "despesas", formatos, func(fn func(domain.Despesa) error) error {
+		return repos.Despesas.Exportar(ctx, domain.FiltrosDespesas{}, criadosAte(corte, fn, func(d domain.Despesa) primitive.ObjectID { return d.ID }))
+	}, exportacao.NovaLinhaDespesa)); err != nil {
+		return nil, err
+	}
+	if err := adicionar(gravarColecao(dir, "proposicoes", formatos, func(fn func(domain.Proposicao) error) error {
+		return repos.Proposicoes.Exportar(ctx, domain.FiltrosProposicoes{}, criadosAte(corte, fn, func(d domain.Proposicao) primitive.ObjectID { return d.ID }))
+	}, exportacao.NovaLinhaProposicao)); err != nil {
+		return nil, err
+	}
+	if err := adicionar(gravarColecao(dir, "presencas", formatos, func(fn func(domain.Presenca) error) error {
+		return repos.Presencas.Exportar(ctx, criadosAte(corte, fn, func(d domain.Presenca) primitive.ObjectID { return d.ID }))
+	}, exportacao.NovaLinhaPresenca)); err != nil {
+		return nil, err
+	}
+
+	return arquivos, nil
+}
+
+// criadosAte repassa a fn só os registros criados até o corte
+func criadosAte[D any](corte time.Time, fn func(D) error, id func(D) primitive.ObjectID) func(D) error {
+	return func(d D) error {
+		if id(d).Timestamp().After(corte) {
+			return nil
+		}
+		return fn(d)
+	}
+}
+
+// saida é um arquivo .gz em gravação; conta e calcula o hash dos bytes comprimidos
+type saida struct {
+	arquivo *os.File
+	hash    hash.Hash
+	bytes   int64
+	gzip    *gzip.Writer
+}
+
+func (s *saida) Write(p []byte) (int, error) {
+	n, err := s.arquivo.Write(p)
+	s.hash.Write(p[:n])
+	s.bytes += int64(n)
+	return n, err
+}
+
+// gravarColecao grava a coleção em todos os formatos, lendo o cursor uma só vez
+func gravarColecao[D, L any](dir, colecao string, formatos []exportacao.Formato, percorrer func(func(D) error) error, linha func(D) L) ([]Arquivo, error) {
+	saidas := make([]*saida, 0, len(formatos))
+	escritores := make([]exportacao.Escritor[L], 0, len(formatos))
+	defer func() {
+		for _, s := range saidas {
+			s.arquivo.Close()
+		}
+	}()
+
+	for _, formato := range formatos {
+		arquivo, err := os.Create(filepath.Join(dir, colecao+"."+string(formato)+".gz"))
+		if err != nil {
+			return nil, err
+		}
+		// O cabeçalho gzip fica sem data de modificação, para que os mesmos dados gerem os
+		// mesmos bytes e o mesmo SHA-256
+		s := &saida{arquivo: arquivo, hash: sha256.New()}
+		s.gzip = gzip.NewWriter(s)
+		saidas = append(saidas, s)
+
+		escritor, err := exportacao.NovoEscritor[L](s.gzip, formato)
+		if err != nil {
+			return nil, err
+		}
+		escritores = append(escritores, escritor)
+	}
+
+	var linhas int64
+	if err := percorrer(func(registro D) error {
+		linhas++
+		l := linha(registro)
+		for _, e := range escritores {
+			if err := e.Escrever(l); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s: %w", colecao, err)
+	}
+
+	arquivos := make([]Arquivo, 0, len(formatos))
+	for i, formato := range formatos {
+		if err := escritores[i].Fechar(); err != nil {
+			return nil, err
+		}
+		if err := saidas[i].gzip.Close(); err != nil {
+			return nil, err
+		}
+		if err := saidas[i].arquivo.Sync(); err != nil {
+			return nil, err
+		}
+
+		arquivos = append(arquivos, Arquivo{
+			Nome:    filepath.Base(saidas[i].arquivo.Name()),
+			Colecao: colecao,
+			Formato: formato,
+			Linhas:  linhas,
+			Bytes:   saidas[i].bytes,
+			SHA256:  hex.EncodeToString(saidas[i].hash.Sum(nil)),
+			Colunas: exportacao.Colunas[L](),
+		})
+	}
+	return arquivos, nil
+}
+
+// ultimasExecucoes escolhe, para cada fonte e entidade, a etapa concluída mais recente
+func ultimasExecucoes(execucoes []domain.ExecucaoSync) []ExecucaoFonte {
+	vistas := make(map[[2]string]bool)
+	resultado := []ExecucaoFonte{}
+
+	for _, exec := range execucoes {
+		for _, etapa := range exec.Etapas {
+			chave := [2]string{etapa.Fonte, etapa.Entidade}
+			if etapa.Status != domain.StatusExecucaoConcluida || vistas[chave] {
+				continue
+			}
+			vistas[chave] = true
+			resultado = append(resultado, ExecucaoFonte{
+				Fonte:       etapa.Fonte,
+				Entidade:    etapa.Entidade,
+				ExecucaoID:  exec.ID.Hex(),
+				ConcluidaEm: etapa.FimEm,
+			})
+		}
+	}
+
+	sort.Slice(resultado, func(i, j int) bool {
+		if resultado[i].Fonte != resultado[j].Fonte {
+			return resultado[i].Fonte < resultado[j].Fonte
+		}
+		return resultado[i].Entidade < resultado[j].Entidade
+	})
+	return resultado
+}
+
+// Listar retorna os manifestos dos dumps do diretório, do mais recente para o mais antigo.
+// Um diretório inexistente não tem dumps.
+func Listar(dir string) ([]Manifesto, error) {
+	entradas, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Manifesto{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifestos := []Manifesto{}
+	for _, e := range entradas {
+		if !e.IsDir() {
+			continue
+		}
+		m, err := Buscar(dir, e.Name())
+		if errors.Is(err, ErrDumpNaoEncontrado) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		manifestos = append(manifestos, *m)
+	}
+
+	sort.Slice(manifestos, func(i, j int) bool { return manifestos[i].Data > manifestos[j].Data })
+	return manifestos, nil
+}
+
+// Buscar lê o manifesto do dump da data (AAAA-MM-DD)
+func Buscar(dir, data string) (*Manifesto, error) {
+	// Validar a data também impede que ela seja usada para sair do diretório
+	if _, err := time.Parse(formatoData, data); err != nil {
+		return nil, ErrDumpNaoEncontrado
+	}
+
+	conteudo, err := os.ReadFile(filepath.Join(dir, data, NomeManifesto))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrDumpNaoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifesto
+	if err := json.Unmarshal(conteudo, &m); err != nil {
+		return nil, fmt.Errorf("manifesto de %s inválido: %w", data, err)
+	}
+	return &m, nil
+}
+
+// Caminho retorna o caminho de um arquivo do dump; só são aceitos os arquivos do manifesto
+func Caminho(dir, data, nome string) (string, error) {
+	m, err := Buscar(dir, data)
+	if err != nil {
+		return "", err
+	}
+
+	if nome == NomeManifesto {
+		return filepath.Join(dir, data, NomeManifesto), nil
+	}
+	for _, a := range m.Arquivos {
+		if a.Nome == nome {
+			return filepath.Join(dir, data, a.Nome), nil
+		}
+	}
+	return "", ErrDumpNaoEncontrado
+}
+
+// Podar apaga os dumps mais antigos, mantendo os manter mais recentes, e retorna as datas
+// apagadas
+func Podar(dir string, manter int) ([]string, error) {
+	manifestos, err := Listar(dir)
+	if err != nil || len(manifestos) <= manter {
+		return nil, err
+	}
+
+	var apagados []string
+	for _, m := range manifestos[manter:] {
+		if err := os.RemoveAll(filepath.Join(dir, m.Data)); err != nil {
+			return apagados, err
+		}
+		apagados = append(apagados, m.Data)
+	}
+	return apagados, nil
+}
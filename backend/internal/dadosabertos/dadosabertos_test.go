@@ -0,0 +1,154 @@
+package dadosabertos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/exportacao"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func gerar(t *testing.T, dir string, data time.Time, dados mock.Dados, execucoes []domain.ExecucaoSync) *Manifesto {
+	t.Helper()
+
+	banco := memoria.NewBanco(dados)
+	m, err := Gerar(context.Background(), Repositorios{
+		Politicos:   memoria.NewPoliticoRepository(banco),
+		Votacoes:    memoria.NewVotacaoRepository(banco),
+		Despesas:    memoria.NewDespesaRepository(banco),
+		Proposicoes: memoria.NewProposicaoRepository(banco),
+		Presencas:   memoria.NewPresencaRepository(banco),
+	}, execucoes, Opcoes{
+		Diretorio: dir,
+		Data:      data,
+		Formatos:  []exportacao.Formato{exportacao.FormatoCSV, exportacao.FormatoNDJSON},
+	})
+	if err != nil {
+		t.Fatalf("Gerar: %v", err)
+	}
+	return m
+}
+
+func TestGerar(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 5, Politicos: 40, VotacoesPorCasa: 6})
+	dir := t.TempDir()
+	dia := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	antiga, recente := time.Now().Add(-48*time.Hour), time.Now()
+	execucoes := []domain.ExecucaoSync{
+		{ID: primitive.NewObjectID(), Etapas: []domain.EtapaSync{
+			{Fonte: "camara", Entidade: "despesas", Status: domain.StatusExecucaoConcluida, FimEm: &recente},
+			{Fonte: "camara", Entidade: "votacoes", Status: domain.StatusExecucaoFalhou},
+		}},
+		{ID: primitive.NewObjectID(), Etapas: []domain.EtapaSync{
+			{Fonte: "camara", Entidade: "despesas", Status: domain.StatusExecucaoConcluida, FimEm: &antiga},
+			{Fonte: "camara", Entidade: "votacoes", Status: domain.StatusExecucaoConcluida, FimEm: &antiga},
+		}},
+	}
+
+	// Um voto gravado por uma sincronização que começou depois do dump fica de fora
+	depois := dados.Votacoes[0]
+	depois.ID = primitive.NewObjectIDFromTimestamp(time.Now().Add(time.Hour))
+	dados.Votacoes = append(dados.Votacoes, depois)
+
+	m := gerar(t, dir, dia, dados, execucoes)
+
+	if m.Data != "2024-05-01" || m.VersaoEsquema != exportacao.VersaoEsquema {
+		t.Errorf("manifesto com data %q e versão %d", m.Data, m.VersaoEsquema)
+	}
+	if len(m.Arquivos) != 10 {
+		t.Fatalf("%d arquivos, esperados 10 (5 coleções em 2 formatos)", len(m.Arquivos))
+	}
+
+	esperadas := map[string]int{
+		"politicos":   len(dados.Politicos),
+		"votacoes":    len(dados.Votacoes) - 1,
+		"despesas":    len(dados.Despesas),
+		"proposicoes": len(dados.Proposicoes),
+		"presencas":   len(dados.Presencas),
+	}
+	for _, a := range m.Arquivos {
+		if a.Linhas != int64(esperadas[a.Colecao]) {
+			t.Errorf("%s: %d linhas, esperadas %d", a.Nome, a.Linhas, esperadas[a.Colecao])
+		}
+
+		conteudo, err := os.ReadFile(filepath.Join(dir, "2024-05-01", a.Nome))
+		if err != nil {
+			t.Fatalf("arquivo do manifesto não gravado: %v", err)
+		}
+		soma := sha256.Sum256(conteudo)
+		if hex.EncodeToString(soma[:]) != a.SHA256 || int64(len(conteudo)) != a.Bytes {
+			t.Errorf("%s: SHA-256 ou tamanho diferente do manifesto", a.Nome)
+		}
+	}
+
+	// A execução mais recente vale para despesas; a falha em votações não conta
+	if len(m.Execucoes) != 2 ||
+		m.Execucoes[0].Entidade != "despesas" || m.Execucoes[0].ExecucaoID != execucoes[0].ID.Hex() ||
+		m.Execucoes[1].Entidade != "votacoes" || m.Execucoes[1].ExecucaoID != execucoes[1].ID.Hex() {
+		t.Errorf("execuções de origem inesperadas: %+v", m.Execucoes)
+	}
+
+	// Os mesmos dados geram os mesmos arquivos
+	outro := gerar(t, t.TempDir(), dia, dados, execucoes)
+	for i := range m.Arquivos {
+		if m.Arquivos[i].SHA256 != outro.Arquivos[i].SHA256 {
+			t.Errorf("%s: SHA-256 mudou entre dois dumps dos mesmos dados", m.Arquivos[i].Nome)
+		}
+	}
+
+	if _, err := Gerar(context.Background(), Repositorios{}, nil, Opcoes{
+		Diretorio: dir,
+		Data:      dia,
+		Formatos:  []exportacao.Formato{exportacao.FormatoCSV},
+	}); !errors.Is(err, ErrDumpExistente) {
+		t.Errorf("dump repetido sem Substituir: esperado ErrDumpExistente, veio %v", err)
+	}
+}
+
+func TestListarECaminho(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 1, Politicos: 10, VotacoesPorCasa: 2})
+	dir := t.TempDir()
+
+	for _, dia := range []int{1, 3, 2} {
+		gerar(t, dir, time.Date(2024, 5, dia, 0, 0, 0, 0, time.UTC), dados, nil)
+	}
+	// Um dump interrompido não tem manifesto e não é listado
+	os.MkdirAll(filepath.Join(dir, "2024-05-04"), 0o755)
+
+	manifestos, err := Listar(dir)
+	if err != nil {
+		t.Fatalf("Listar: %v", err)
+	}
+	if len(manifestos) != 3 || manifestos[0].Data != "2024-05-03" || manifestos[2].Data != "2024-05-01" {
+		t.Fatalf("dumps fora de ordem ou faltando: %d", len(manifestos))
+	}
+
+	if _, err := Caminho(dir, "2024-05-02", "despesas.csv.gz"); err != nil {
+		t.Errorf("arquivo do manifesto recusado: %v", err)
+	}
+	for _, caso := range [][2]string{
+		{"2024-05-02", "../2024-05-01/despesas.csv.gz"},
+		{"2024-05-02", "outro.txt"},
+		{"..", "2024-05-01"},
+		{"2024-05-04", NomeManifesto},
+	} {
+		if _, err := Caminho(dir, caso[0], caso[1]); !errors.Is(err, ErrDumpNaoEncontrado) {
+			t.Errorf("Caminho(%q, %q): esperado ErrDumpNaoEncontrado, veio %v", caso[0], caso[1], err)
+		}
+	}
+
+	apagados, err := Podar(dir, 2)
+	if err != nil || len(apagados) != 1 || apagados[0] != "2024-05-01" {
+		t.Errorf("Podar apagou %v (erro %v), esperado só 2024-05-01", apagados, err)
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TipoAlerta representa o padrão suspeito encontrado nas despesas
+type TipoAlerta string
+
+const (
+	AlertaReciboDuplicado   TipoAlerta = "RECIBO_DUPLICADO"
+	AlertaProximoAoTeto     TipoAlerta = "PROXIMO_AO_TETO"
+	AlertaPicoHistorico     TipoAlerta = "PICO_HISTORICO"
+	AlertaPicoPares         TipoAlerta = "PICO_PARES"
+	AlertaFornecedorRecente TipoAlerta = "FORNECEDOR_RECENTE"
+)
+
+// Severidade representa a gravidade de um alerta
+type Severidade string
+
+const (
+	SeveridadeBaixa Severidade = "BAIXA"
+	SeveridadeMedia Severidade = "MEDIA"
+	SeveridadeAlta  Severidade = "ALTA"
+)
+
+// Alerta representa um padrão suspeito encontrado nas despesas de um político
+type Alerta struct {
+	ID            primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Chave         string               `json:"-" bson:"chave"` // Identifica o alerta entre execuções da análise
+	PoliticoID    primitive.ObjectID   `json:"politicoId" bson:"politico_id"`
+	DespesasIDs   []primitive.ObjectID `json:"despesasIds,omitempty" bson:"despesas_ids,omitempty"`
+	Tipo          TipoAlerta           `json:"tipo" bson:"tipo"`
+	Severidade    Severidade           `json:"severidade" bson:"severidade"`
+	Motivo        string               `json:"motivo" bson:"motivo"`
+	Valor         float64              `json:"valor" bson:"valor"`
+	MesReferencia int                  `json:"mesReferencia" bson:"mes_referencia"`
+	AnoReferencia int                  `json:"anoReferencia" bson:"ano_referencia"`
+	CreatedAt     time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt     time.Time            `json:"updatedAt" bson:"updated_at"`
+}
+
+// FiltrosAlertas representa os filtros da listagem de alertas
+type FiltrosAlertas struct {
+	PoliticoID string
+	Tipo       []TipoAlerta
+	Severidade []Severidade
+	Ano        *int
+	Paginacao
+}
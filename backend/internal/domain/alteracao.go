@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OperacaoAlteracao string
+
+const (
+	OperacaoCriacao     OperacaoAlteracao = "CRIACAO"
+	OperacaoAtualizacao OperacaoAlteracao = "ATUALIZACAO"
+)
+
+// Alteracao representa a mudança de um campo do cadastro de um político feita por uma sincronização
+type Alteracao struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	PoliticoID    primitive.ObjectID `json:"politicoId" bson:"politico_id"`
+	Operacao      OperacaoAlteracao  `json:"operacao" bson:"operacao"`
+	Campo         string             `json:"campo,omitempty" bson:"campo,omitempty"`
+	ValorAnterior interface{}        `json:"valorAnterior,omitempty" bson:"valor_anterior,omitempty"`
+	ValorNovo     interface{}        `json:"valorNovo,omitempty" bson:"valor_novo,omitempty"`
+	Fonte         string             `json:"fonte" bson:"fonte"`                                // Sincronizador que fez a alteração (camara, senado...)
+	ExecucaoID    string             `json:"execucaoId,omitempty" bson:"execucao_id,omitempty"` // Identificador da execução da sincronização
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+}
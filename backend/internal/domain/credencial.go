@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Papel define o que uma credencial pode fazer nas rotas protegidas.
+// Cada papel inclui as permissões dos anteriores: CONSUMIDOR < LEITOR < EDITOR < ADMIN.
+// CONSUMIDOR é o papel das chaves emitidas para quem consome a API pública e não dá acesso
+// às rotas administrativas.
+type Papel string
+
+const (
+	PapelConsumidor Papel = "CONSUMIDOR"
+	PapelLeitor     Papel = "LEITOR"
+	PapelEditor     Papel = "EDITOR"
+	PapelAdmin      Papel = "ADMIN"
+)
+
+var nivelPapel = map[Papel]int{
+	PapelConsumidor: 0,
+	PapelLeitor:     1,
+	PapelEditor:     2,
+	PapelAdmin:      3,
+}
+
+// Valido indica se o papel é um dos papéis conhecidos
+func (p Papel) Valido() bool {
+	_, ok := nivelPapel[p]
+	return ok
+}
+
+// Inclui indica se o papel tem pelo menos as permissões do papel mínimo informado
+func (p Papel) Inclui(minimo Papel) bool {
+	return p.Valido() && nivelPapel[p] >= nivelPapel[minimo]
+}
+
+// ChaveAPI é uma credencial de acesso às rotas protegidas. Só o hash da chave é guardado;
+// o valor completo é mostrado uma única vez, na criação.
+type ChaveAPI struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Nome        string             `json:"nome" bson:"nome"`
+	Prefixo     string             `json:"prefixo" bson:"prefixo"`
+	Hash        string             `json:"-" bson:"hash"`
+	Papel       Papel              `json:"papel" bson:"papel"`
+	Limites     *LimitesUso        `json:"limites,omitempty" bson:"limites,omitempty"`
+	CriadaPor   string             `json:"criadaPor,omitempty" bson:"criada_por,omitempty"`
+	UltimoUsoEm *time.Time         `json:"ultimoUsoEm,omitempty" bson:"ultimo_uso_em,omitempty"`
+	RevogadaEm  *time.Time         `json:"revogadaEm,omitempty" bson:"revogada_em,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// LimitesUso substitui, para uma chave, os limites padrão de uso da API pública.
+// Valores zero mantêm o padrão.
+type LimitesUso struct {
+	PorMinuto  int `json:"porMinuto,omitempty" bson:"por_minuto,omitempty"`
+	CotaDiaria int `json:"cotaDiaria,omitempty" bson:"cota_diaria,omitempty"`
+}
@@ -8,17 +8,25 @@ import (
 
 // Despesa representa uma despesa de um político
 type Despesa struct {
-	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	PoliticoID      primitive.ObjectID `json:"politicoId" bson:"politico_id"`
-	Tipo            string             `json:"tipo" bson:"tipo"`
-	Descricao       string             `json:"descricao" bson:"descricao"`
-	Fornecedor      string             `json:"fornecedor" bson:"fornecedor"`
-	CNPJFornecedor  string             `json:"cnpjFornecedor" bson:"cnpj_fornecedor"`
-	Valor           float64            `json:"valor" bson:"valor"`
-	Data            time.Time          `json:"data" bson:"data"`
-	MesReferencia   int                `json:"mesReferencia" bson:"mes_referencia"`
-	AnoReferencia   int                `json:"anoReferencia" bson:"ano_referencia"`
-	DocumentoURL    string             `json:"documentoUrl,omitempty" bson:"documento_url,omitempty"`
+	ID                      primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	PoliticoID              primitive.ObjectID `json:"politicoId" bson:"politico_id"`
+	Tipo                    string             `json:"tipo" bson:"tipo"`
+	Descricao               string             `json:"descricao" bson:"descricao"`
+	Fornecedor              string             `json:"fornecedor" bson:"fornecedor"`
+	CNPJFornecedor          string             `json:"cnpjFornecedor" bson:"cnpj_fornecedor"`                                        // Somente dígitos
+	TipoDocumentoFornecedor string             `json:"tipoDocumentoFornecedor,omitempty" bson:"tipo_documento_fornecedor,omitempty"` // CPF, CNPJ ou vazio se inválido
+	Valor                   float64            `json:"valor" bson:"valor"`
+	Data                    time.Time          `json:"data" bson:"data"`
+	MesReferencia           int                `json:"mesReferencia" bson:"mes_referencia"`
+	AnoReferencia           int                `json:"anoReferencia" bson:"ano_referencia"`
+	DocumentoURL            string             `json:"documentoUrl,omitempty" bson:"documento_url,omitempty"`
+	CodDocumento            int                `json:"codDocumento,omitempty" bson:"cod_documento,omitempty"` // Identificadores do documento na API da Câmara
+	NumDocumento            string             `json:"numDocumento,omitempty" bson:"num_documento,omitempty"`
+	CodLote                 int                `json:"codLote,omitempty" bson:"cod_lote,omitempty"`
+	Parcela                 int                `json:"parcela,omitempty" bson:"parcela,omitempty"`
+	NumRessarcimento        string             `json:"numRessarcimento,omitempty" bson:"num_ressarcimento,omitempty"`
+	SincronizadoEm          time.Time          `json:"-" bson:"sincronizado_em,omitempty"`
+	Fonte                   *Fonte             `json:"fonte,omitempty" bson:"fonte,omitempty"`
 }
 
 // Presenca representa a presença de um político em uma sessão
@@ -28,5 +36,48 @@ type Presenca struct {
 	Data       time.Time          `json:"data" bson:"data"`
 	TipoSessao string             `json:"tipoSessao" bson:"tipo_sessao"`
 	Presente   bool               `json:"presente" bson:"presente"`
+	Fonte      *Fonte             `json:"fonte,omitempty" bson:"fonte,omitempty"`
 }
 
+// FiltrosDespesas representa os filtros aceitos pelos resumos de despesas
+type FiltrosDespesas struct {
+	PoliticoID string
+	Partido    string
+	Estado     string
+	Ano        *int
+	Mes        *int
+	Limite     int
+}
+
+// TotalPorTipo representa o total gasto em uma categoria da cota parlamentar
+type TotalPorTipo struct {
+	Tipo       string  `json:"tipo" bson:"_id"`
+	Total      float64 `json:"total" bson:"total"`
+	Quantidade int     `json:"quantidade" bson:"quantidade"`
+}
+
+// TotalPorFornecedor representa o total pago a um fornecedor
+type TotalPorFornecedor struct {
+	CNPJ           string  `json:"cnpj" bson:"cnpj"`
+	Nome           string  `json:"nome" bson:"nome"`
+	Total          float64 `json:"total" bson:"total"`
+	Quantidade     int     `json:"quantidade" bson:"quantidade"`
+	TotalPoliticos int     `json:"totalPoliticos,omitempty" bson:"total_politicos,omitempty"`
+}
+
+// TotalMensal representa um ponto da série temporal de despesas
+type TotalMensal struct {
+	Ano        int     `json:"ano" bson:"ano"`
+	Mes        int     `json:"mes" bson:"mes"`
+	Total      float64 `json:"total" bson:"total"`
+	Quantidade int     `json:"quantidade" bson:"quantidade"`
+}
+
+// ResumoDespesas representa os gastos agregados por categoria, fornecedor e mês
+type ResumoDespesas struct {
+	Total           float64              `json:"total"`
+	Quantidade      int                  `json:"quantidade"`
+	PorTipo         []TotalPorTipo       `json:"porTipo"`
+	TopFornecedores []TotalPorFornecedor `json:"topFornecedores"`
+	SerieMensal     []TotalMensal        `json:"serieMensal"`
+}
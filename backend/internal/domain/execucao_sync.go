@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StatusExecucao string
+
+const (
+	StatusExecucaoPendente   StatusExecucao = "PENDENTE"
+	StatusExecucaoExecutando StatusExecucao = "EXECUTANDO"
+	StatusExecucaoConcluida  StatusExecucao = "CONCLUIDA"
+	StatusExecucaoFalhou     StatusExecucao = "FALHOU"
+	StatusExecucaoCancelada  StatusExecucao = "CANCELADA"
+
+	// StatusExecucaoInterrompida marca as execuções cujo processo parou sem registrar o fim
+	StatusExecucaoInterrompida StatusExecucao = "INTERROMPIDA"
+)
+
+// PedidoSync descreve o que sincronizar. Sem fonte, sincroniza todas; sem entidades,
+// todas as entidades da fonte.
+type PedidoSync struct {
+	Fonte     string   `json:"fonte,omitempty" bson:"fonte,omitempty"`
+	Ano       int      `json:"ano" bson:"ano"`
+	Entidades []string `json:"entidades,omitempty" bson:"entidades,omitempty"`
+}
+
+// EtapaSync é a sincronização de uma entidade de uma fonte dentro de uma execução
+type EtapaSync struct {
+	Fonte       string         `json:"fonte" bson:"fonte"`
+	Entidade    string         `json:"entidade" bson:"entidade"`
+	Status      StatusExecucao `json:"status" bson:"status"`
+	Total       int            `json:"total" bson:"total"`
+	Processados int            `json:"processados" bson:"processados"`
+	Erros       int            `json:"erros" bson:"erros"`
+	Mensagem    string         `json:"mensagem,omitempty" bson:"mensagem,omitempty"`
+	InicioEm    *time.Time     `json:"inicioEm,omitempty" bson:"inicio_em,omitempty"`
+	FimEm       *time.Time     `json:"fimEm,omitempty" bson:"fim_em,omitempty"`
+}
+
+// ExecucaoSync é um job de sincronização e o seu andamento. O ID também identifica
+// a execução nas alterações registradas pela auditoria.
+type ExecucaoSync struct {
+	ID          primitive.ObjectID     `json:"id" bson:"_id"`
+	Pedido      PedidoSync             `json:"pedido" bson:"pedido"`
+	Status      StatusExecucao         `json:"status" bson:"status"`
+	Etapas      []EtapaSync            `json:"etapas" bson:"etapas"`
+	Erros       []string               `json:"erros" bson:"erros"` // Últimos erros, do mais antigo ao mais recente
+	IniciadaPor string                 `json:"iniciadaPor,omitempty" bson:"iniciada_por,omitempty"`
+	Agendamento string                 `json:"agendamento,omitempty" bson:"agendamento,omitempty"` // Agendamento do worker que disparou a execução
+	Qualidade   map[RegraQualidade]int `json:"qualidade,omitempty" bson:"qualidade,omitempty"`     // Problemas de qualidade encontrados, por regra
+	CreatedAt   time.Time              `json:"createdAt" bson:"created_at"`
+	InicioEm    *time.Time             `json:"inicioEm,omitempty" bson:"inicio_em,omitempty"`
+	FimEm       *time.Time             `json:"fimEm,omitempty" bson:"fim_em,omitempty"`
+
+	// BatimentoEm é a última gravação do processo que executa; sem batimentos, a execução é
+	// considerada interrompida
+	BatimentoEm *time.Time `json:"batimentoEm,omitempty" bson:"batimento_em,omitempty"`
+	// CancelamentoSolicitado é o pedido de cancelamento visto pelo processo que executa, que
+	// pode ser outra instância da API ou o worker
+	CancelamentoSolicitado bool `json:"cancelamentoSolicitado,omitempty" bson:"cancelamento_solicitado,omitempty"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Fonte identifica o registro oficial de onde um documento foi copiado, para que qualquer
+// número exibido possa ser conferido e citado na origem
+type Fonte struct {
+	Sistema    string    `json:"sistema" bson:"sistema"`                          // camara, senado, presidente ou governadores
+	IDExterno  string    `json:"idExterno,omitempty" bson:"id_externo,omitempty"` // Identificador do registro no sistema de origem
+	URL        string    `json:"url,omitempty" bson:"url,omitempty"`              // Endereço do registro na origem
+	ColetadoEm time.Time `json:"coletadoEm" bson:"coletado_em"`
+	ExecucaoID string    `json:"execucaoId,omitempty" bson:"execucao_id,omitempty"` // Sincronização que gravou o registro
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PagamentoPorPolitico representa o total pago a um fornecedor por um político
+type PagamentoPorPolitico struct {
+	PoliticoID primitive.ObjectID `json:"politicoId" bson:"politico_id"`
+	Nome       string             `json:"nome" bson:"nome"`
+	Partido    string             `json:"partido" bson:"partido"`
+	Estado     string             `json:"estado" bson:"estado"`
+	Total      float64            `json:"total" bson:"total"`
+	Quantidade int                `json:"quantidade" bson:"quantidade"`
+}
+
+// PerfilFornecedor representa tudo o que um fornecedor recebeu da cota parlamentar
+type PerfilFornecedor struct {
+	CNPJ              string                 `json:"cnpj"`
+	TipoDocumento     string                 `json:"tipoDocumento"`
+	Nome              string                 `json:"nome"`
+	RazaoSocial       string                 `json:"razaoSocial,omitempty"`
+	DataAbertura      *time.Time             `json:"dataAbertura,omitempty"`
+	Total             float64                `json:"total"`
+	Quantidade        int                    `json:"quantidade"`
+	PrimeiroPagamento time.Time              `json:"primeiroPagamento"`
+	UltimoPagamento   time.Time              `json:"ultimoPagamento"`
+	Politicos         []PagamentoPorPolitico `json:"politicos"`
+	PorTipo           []TotalPorTipo         `json:"porTipo"`
+	SerieMensal       []TotalMensal          `json:"serieMensal"`
+}
+
+// FiltrosFornecedores representa os filtros do ranking de fornecedores
+type FiltrosFornecedores struct {
+	Tipo      string
+	Ano       *int
+	Mes       *int
+	Pagina    int
+	PorPagina int
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StatusRevisao string
+
+const (
+	StatusRevisaoPendente  StatusRevisao = "PENDENTE"
+	StatusRevisaoResolvida StatusRevisao = "RESOLVIDA"
+)
+
+// RevisaoIdentidade representa um registro recebido de uma fonte que pode corresponder a
+// políticos já cadastrados, mas sem certeza suficiente para unificar automaticamente
+type RevisaoIdentidade struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Fonte          string               `json:"fonte" bson:"fonte"`
+	IDExterno      string               `json:"idExterno,omitempty" bson:"id_externo,omitempty"`
+	Nome           string               `json:"nome" bson:"nome"`
+	NomeCivil      string               `json:"nomeCivil" bson:"nome_civil"`
+	DataNascimento time.Time            `json:"dataNascimento" bson:"data_nascimento"`
+	CandidatosIDs  []primitive.ObjectID `json:"candidatosIds" bson:"candidatos_ids"`
+	Motivo         string               `json:"motivo" bson:"motivo"`
+	Status         StatusRevisao        `json:"status" bson:"status"`
+	CreatedAt      time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt      time.Time            `json:"updatedAt" bson:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInclusaoInvalida = errors.New("inclusão inválida")
+
+// Inclusoes são os documentos relacionados que uma listagem embute em cada item
+// (?incluir=proposicao,autor), em vez de só o ID deles
+type Inclusoes struct {
+	Proposicao bool // A proposição votada, nas votações
+	Autor      bool // O autor da proposição; nas votações, implica Proposicao
+}
+
+// ParseInclusoes lê a lista separada por vírgulas de ?incluir=, aceitando só os nomes
+// informados em aceitas
+func ParseInclusoes(valor string, aceitas ...string) (Inclusoes, error) {
+	var inclusoes Inclusoes
+	for _, nome := range strings.Split(valor, ",") {
+		nome = strings.ToLower(strings.TrimSpace(nome))
+		if nome == "" {
+			continue
+		}
+
+		aceita := false
+		for _, a := range aceitas {
+			aceita = aceita || a == nome
+		}
+		if !aceita {
+			return Inclusoes{}, fmt.Errorf("%w: %q (aceitas: %s)", ErrInclusaoInvalida, nome, strings.Join(aceitas, ", "))
+		}
+
+		switch nome {
+		case "proposicao":
+			inclusoes.Proposicao = true
+		case "autor":
+			inclusoes.Autor = true
+		}
+	}
+	return inclusoes, nil
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrCursorInvalido = errors.New("cursor de paginação inválido")
+
+// Paginacao é a página pedida de uma listagem. Por número de página, a busca pula os itens
+// das páginas anteriores e conta o total, o que fica lento no fundo das coleções grandes e
+// repete ou pula itens se a coleção muda entre uma página e outra. Por cursor, a busca continua
+// logo depois do último item da página anterior, e o total só é contado se pedido.
+type Paginacao struct {
+	Pagina    int
+	PorPagina int
+
+	PorCursor bool
+	Cursor    string // O nextCursor da página anterior; vazio pede a primeira página
+	ComTotal  bool   // Contar o total também na paginação por cursor
+
+	// Campos limita os campos de cada item trazidos do banco (?campos=, nomes do JSON, com
+	// ponto nos documentos embutidos); vazio traz o item inteiro
+	Campos []string
+}
+
+// paginaNumerada tem os campos de PaginatedResponse sem o MarshalJSON
+type paginaNumerada[T any] PaginatedResponse[T]
+
+type paginaCursor[T any] struct {
+	Data          []T    `json:"data"`
+	Total         *int64 `json:"total,omitempty"`
+	PorPagina     int    `json:"porPagina"`
+	ProximoCursor string `json:"nextCursor,omitempty"`
+}
+
+// MarshalJSON mantém a resposta por número de página como sempre foi e, na por cursor, omite
+// os campos de página e, se não foi contado, o total
+func (p PaginatedResponse[T]) MarshalJSON() ([]byte, error) {
+	if !p.PorCursor {
+		return json.Marshal(paginaNumerada[T](p))
+	}
+
+	resposta := paginaCursor[T]{Data: p.Data, PorPagina: p.PorPagina, ProximoCursor: p.ProximoCursor}
+	if !p.SemTotal {
+		resposta.Total = &p.Total
+	}
+	return json.Marshal(resposta)
+}
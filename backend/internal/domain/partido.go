@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RegistroPartido representa um partido na coleção de partidos, fonte única dos metadados
+// (nome, cor, siglas antigas e fusões) usados pela API e pelas sincronizações
+type RegistroPartido struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Sigla            string             `json:"sigla" bson:"sigla"`
+	Nome             string             `json:"nome" bson:"nome"`
+	Cor              string             `json:"cor" bson:"cor"`
+	SiglasAnteriores []string           `json:"siglasAnteriores,omitempty" bson:"siglas_anteriores,omitempty"` // Renomeações (ex.: PMDB → MDB)
+	IncorporadoPor   string             `json:"incorporadoPor,omitempty" bson:"incorporado_por,omitempty"`     // Sigla do partido resultante de fusão ou incorporação
+	Observacao       string             `json:"observacao,omitempty" bson:"observacao,omitempty"`
+}
+
+// Partido retorna os dados do partido no formato guardado em cada político
+func (r RegistroPartido) Partido() Partido {
+	return Partido{
+		Sigla: r.Sigla,
+		Nome:  r.Nome,
+		Cor:   r.Cor,
+	}
+}
+
+// PerfilPartido representa as estatísticas agregadas dos membros de um partido
+type PerfilPartido struct {
+	Partido              RegistroPartido `json:"partido"`
+	TotalMembros         int             `json:"totalMembros"`
+	MembrosPorCargo      map[Cargo]int   `json:"membrosPorCargo"`
+	MembrosPorEstado     map[string]int  `json:"membrosPorEstado"`
+	MembrosPorGenero     map[Genero]int  `json:"membrosPorGenero"`
+	PercentualPresenca   float64         `json:"percentualPresenca"`
+	TotalDespesas        float64         `json:"totalDespesas"`
+	TotalProposicoes     int             `json:"totalProposicoes"`
+	ProposicoesAprovadas int             `json:"proposicoesAprovadas"`
+	Coesao               float64         `json:"coesao"`             // Percentual médio de membros que votam com a maioria da bancada
+	VotacoesAnalisadas   int             `json:"votacoesAnalisadas"` // Votações usadas no cálculo da coesão
+}
+
+// FiliacaoPartidaria representa um período de filiação de um político a um partido.
+// As datas são as observadas pela sincronização quando a fonte não informa a data oficial.
+type FiliacaoPartidaria struct {
+	Partido    Partido    `json:"partido" bson:"partido"`
+	DataInicio time.Time  `json:"dataInicio" bson:"data_inicio"`
+	DataFim    *time.Time `json:"dataFim,omitempty" bson:"data_fim,omitempty"`
+	PorFusao   bool       `json:"porFusao,omitempty" bson:"por_fusao,omitempty"` // Mudança causada por renomeação ou fusão, não por troca do político
+}
+
+// TrocaPartido representa a saída de um político de um partido para outro
+type TrocaPartido struct {
+	PoliticoID primitive.ObjectID `json:"politicoId" bson:"politico_id"`
+	Nome       string             `json:"nome" bson:"nome"`
+	Cargo      Cargo              `json:"cargo" bson:"cargo"`
+	Estado     string             `json:"estado" bson:"estado"`
+	De         Partido            `json:"de" bson:"de"`
+	Para       Partido            `json:"para" bson:"para"`
+	Data       time.Time          `json:"data" bson:"data"`
+}
+
+// SaldoPartido resume as entradas e saídas de um partido em um período
+type SaldoPartido struct {
+	Sigla    string `json:"sigla"`
+	Entradas int    `json:"entradas"`
+	Saidas   int    `json:"saidas"`
+	Saldo    int    `json:"saldo"`
+}
+
+// RelatorioTrocas representa as trocas de partido ocorridas em uma janela de tempo
+type RelatorioTrocas struct {
+	Inicio time.Time      `json:"inicio"`
+	Fim    time.Time      `json:"fim"`
+	Total  int            `json:"total"`
+	Trocas []TrocaPartido `json:"trocas"`
+	Saldos []SaldoPartido `json:"saldos"`
+}
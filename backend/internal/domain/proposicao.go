@@ -36,13 +36,21 @@ type Proposicao struct {
 	Situacao     SituacaoProposicao   `json:"situacao" bson:"situacao"`
 	Tema         []string             `json:"tema" bson:"tema"`
 	Tramitacao   []TramitacaoItem     `json:"tramitacao" bson:"tramitacao"`
+	Fonte        *Fonte               `json:"fonte,omitempty" bson:"fonte,omitempty"`
 	CreatedAt    time.Time            `json:"createdAt" bson:"created_at"`
 	UpdatedAt    time.Time            `json:"updatedAt" bson:"updated_at"`
 }
 
 // ProposicaoComAutor inclui os dados do autor na proposição
 type ProposicaoComAutor struct {
-	Proposicao
-	Autor *Politico `json:"autor,omitempty"`
+	Proposicao `bson:",inline"`
+	Autor      *Politico `json:"autor,omitempty" bson:"autor,omitempty"`
 }
 
+// FiltrosProposicoes representa os filtros da exportação de proposições
+type FiltrosProposicoes struct {
+	AutorID  string
+	Tipo     string
+	Ano      *int
+	Situacao SituacaoProposicao
+}
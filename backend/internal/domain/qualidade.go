@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RegraQualidade identifica uma verificação feita nos registros gravados pelas sincronizações
+type RegraQualidade string
+
+const (
+	RegraCPFAusente               RegraQualidade = "cpf_ausente"
+	RegraDataNascimentoAusente    RegraQualidade = "data_nascimento_ausente"
+	RegraPartidoDesconhecido      RegraQualidade = "partido_desconhecido"
+	RegraDespesaValorNegativo     RegraQualidade = "despesa_valor_negativo"
+	RegraVotoPoliticoDesconhecido RegraQualidade = "voto_politico_desconhecido"
+)
+
+// RegrasQualidade lista as regras na ordem em que aparecem nos relatórios
+var RegrasQualidade = []RegraQualidade{
+	RegraCPFAusente,
+	RegraDataNascimentoAusente,
+	RegraPartidoDesconhecido,
+	RegraDespesaValorNegativo,
+	RegraVotoPoliticoDesconhecido,
+}
+
+// Valida indica se a regra é uma das regras conhecidas
+func (r RegraQualidade) Valida() bool {
+	for _, regra := range RegrasQualidade {
+		if r == regra {
+			return true
+		}
+	}
+	return false
+}
+
+// ProblemaQualidade é uma regra violada por um registro. Registro identifica o responsável:
+// o ID do político ou, quando ele não existe no banco, o ID externo na fonte
+// (ex.: "camara:deputado:204554"). O mesmo problema encontrado de novo por outra execução
+// não gera outro documento: a Chave o identifica, e ExecucaoID e VistoEm passam a ser os da
+// execução mais recente que o encontrou.
+type ProblemaQualidade struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Chave      string              `json:"-" bson:"chave"`
+	ExecucaoID string              `json:"execucaoId" bson:"execucao_id"`
+	Regra      RegraQualidade      `json:"regra" bson:"regra"`
+	Fonte      string              `json:"fonte" bson:"fonte"`
+	Entidade   string              `json:"entidade" bson:"entidade"` // politico, despesa ou voto
+	Registro   string              `json:"registro" bson:"registro"`
+	PoliticoID *primitive.ObjectID `json:"politicoId,omitempty" bson:"politico_id,omitempty"`
+	Nome       string              `json:"nome,omitempty" bson:"nome,omitempty"`
+	Detalhe    string              `json:"detalhe,omitempty" bson:"detalhe,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt" bson:"created_at"` // Quando foi encontrado pela primeira vez
+	VistoEm    time.Time           `json:"vistoEm" bson:"visto_em"`
+}
+
+// ChaveProblema identifica o problema entre as execuções: a regra, o registro e o detalhe
+func ChaveProblema(regra RegraQualidade, registro, detalhe string) string {
+	return string(regra) + "|" + registro + "|" + detalhe
+}
+
+// OfensorQualidade agrupa os problemas de um mesmo registro
+type OfensorQualidade struct {
+	Registro   string              `json:"registro" bson:"_id"`
+	PoliticoID *primitive.ObjectID `json:"politicoId,omitempty" bson:"politico_id,omitempty"`
+	Nome       string              `json:"nome,omitempty" bson:"nome,omitempty"`
+	Fonte      string              `json:"fonte" bson:"fonte"`
+	Problemas  int                 `json:"problemas" bson:"problemas"`
+	Regras     []RegraQualidade    `json:"regras" bson:"regras"`
+}
+
+// RelatorioQualidade resume os problemas de uma execução, com os registros que mais violaram regras
+type RelatorioQualidade struct {
+	ExecucaoID string                 `json:"execucaoId"`
+	Total      int                    `json:"total"`
+	PorRegra   map[RegraQualidade]int `json:"porRegra"`
+	Piores     []OfensorQualidade     `json:"piores"`
+	GeradoEm   time.Time              `json:"geradoEm"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StatusUnificacao string
+
+const (
+	StatusUnificacaoAtiva    StatusUnificacao = "ATIVA"
+	StatusUnificacaoDesfeita StatusUnificacao = "DESFEITA"
+)
+
+// ReferenciasMovidas guarda os documentos que passaram do político duplicado para o principal,
+// permitindo desfazer a unificação sem afetar registros que já eram do principal
+type ReferenciasMovidas struct {
+	Votacoes                 []primitive.ObjectID `json:"votacoes" bson:"votacoes"`
+	Despesas                 []primitive.ObjectID `json:"despesas" bson:"despesas"`
+	Presencas                []primitive.ObjectID `json:"presencas" bson:"presencas"`
+	Alteracoes               []primitive.ObjectID `json:"alteracoes" bson:"alteracoes"`
+	Alertas                  []primitive.ObjectID `json:"alertas" bson:"alertas"`
+	ProposicoesAutor         []primitive.ObjectID `json:"proposicoesAutor" bson:"proposicoes_autor"`
+	ProposicoesCoautor       []primitive.ObjectID `json:"proposicoesCoautor" bson:"proposicoes_coautor"`
+	CoautoriasCompartilhadas []primitive.ObjectID `json:"coautoriasCompartilhadas" bson:"coautorias_compartilhadas"` // Proposições em que os dois já eram coautores
+	CargosAdicionados        []CargoAtual         `json:"cargosAdicionados" bson:"cargos_adicionados"`               // Cargos do duplicado incluídos no histórico do principal
+	CamposCopiados           []string             `json:"camposCopiados" bson:"campos_copiados"`                     // Identificadores copiados para o principal
+	Repetidos                RegistrosRepetidos   `json:"repetidos" bson:"repetidos"`
+}
+
+// RegistrosRepetidos são os documentos do duplicado que repetiam um registro da fonte que o
+// principal já tinha (o mesmo voto, despesa ou presença). São removidos na unificação, para não
+// contar o registro duas vezes, e restaurados como estavam ao desfazer.
+type RegistrosRepetidos struct {
+	Votacoes  []primitive.M `json:"votacoes,omitempty" bson:"votacoes,omitempty"`
+	Despesas  []primitive.M `json:"despesas,omitempty" bson:"despesas,omitempty"`
+	Presencas []primitive.M `json:"presencas,omitempty" bson:"presencas,omitempty"`
+}
+
+// Unificacao registra a junção manual de dois cadastros da mesma pessoa.
+// As sincronizações consultam as unificações ativas para não recriar o cadastro duplicado.
+type Unificacao struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	PrincipalID primitive.ObjectID `json:"principalId" bson:"principal_id"`
+	DuplicadoID primitive.ObjectID `json:"duplicadoId" bson:"duplicado_id"`
+	Duplicado   Politico           `json:"duplicado" bson:"duplicado"` // Cópia do cadastro removido, restaurada ao desfazer
+	Referencias ReferenciasMovidas `json:"referencias" bson:"referencias"`
+	Motivo      string             `json:"motivo" bson:"motivo"`
+	Status      StatusUnificacao   `json:"status" bson:"status"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	DesfeitaEm  *time.Time         `json:"desfeitaEm,omitempty" bson:"desfeita_em,omitempty"`
+}
@@ -19,17 +19,45 @@ const (
 
 // Votacao representa uma votação de um político
 type Votacao struct {
-	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	PoliticoID   primitive.ObjectID `json:"politicoId" bson:"politico_id"`
-	ProposicaoID primitive.ObjectID `json:"proposicaoId" bson:"proposicao_id"`
-	Voto         TipoVoto           `json:"voto" bson:"voto"`
-	Data         time.Time          `json:"data" bson:"data"`
-	Sessao       string             `json:"sessao" bson:"sessao"`
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	PoliticoID       primitive.ObjectID `json:"politicoId" bson:"politico_id"`
+	ProposicaoID     primitive.ObjectID `json:"proposicaoId" bson:"proposicao_id"`
+	VotacaoIDExterno string             `json:"votacaoIdExterno,omitempty" bson:"votacao_id_externo,omitempty"` // ID da votação na API de origem, comum a todos os votos
+	Voto             TipoVoto           `json:"voto" bson:"voto"`
+	Data             time.Time          `json:"data" bson:"data"`
+	Sessao           string             `json:"sessao" bson:"sessao"`
+	Fonte            *Fonte             `json:"fonte,omitempty" bson:"fonte,omitempty"`
 }
 
-// VotacaoComProposicao inclui os dados da proposição na votação
+// VotacaoComProposicao inclui os dados da proposição na votação e, se pedido, os do autor dela
 type VotacaoComProposicao struct {
-	Votacao
-	Proposicao *Proposicao `json:"proposicao,omitempty"`
+	Votacao    `bson:",inline"`
+	Proposicao *ProposicaoComAutor `json:"proposicao,omitempty" bson:"proposicao,omitempty"`
 }
 
+// AlinhamentoPolitico representa o quanto dois políticos votam da mesma forma
+type AlinhamentoPolitico struct {
+	PoliticoID       primitive.ObjectID `json:"politicoId" bson:"_id"`
+	Nome             string             `json:"nome,omitempty" bson:"-"`
+	Partido          string             `json:"partido,omitempty" bson:"-"`
+	Estado           string             `json:"estado,omitempty" bson:"-"`
+	VotacoesEmComum  int                `json:"votacoesEmComum" bson:"votacoes_em_comum"`
+	Concordancias    int                `json:"concordancias" bson:"concordancias"`
+	PercentualAcordo float64            `json:"percentualAcordo" bson:"-"`
+}
+
+// PoliticosSimilares representa os colegas mais e menos alinhados a um político
+type PoliticosSimilares struct {
+	MaisAlinhados  []AlinhamentoPolitico `json:"maisAlinhados"`
+	MenosAlinhados []AlinhamentoPolitico `json:"menosAlinhados"`
+}
+
+// FiltrosVotacoes representa os filtros da exportação de votos.
+// Partido e estado são os atuais do político.
+type FiltrosVotacoes struct {
+	PoliticoID string
+	Partido    string
+	Estado     string
+	Ano        *int
+	Voto       TipoVoto
+}
@@ -0,0 +1,224 @@
+// Package exportacao grava conjuntos completos de registros em CSV, JSON Lines ou Parquet.
+// Os registros são escritos um a um, à medida que chegam do cursor do banco, para que uma
+// exportação não precise carregar a coleção inteira na memória.
+package exportacao
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Formato é o formato de arquivo da exportação
+type Formato string
+
+const (
+	FormatoCSV     Formato = "csv"
+	FormatoNDJSON  Formato = "ndjson"
+	FormatoParquet Formato = "parquet"
+)
+
+// Formatos lista os formatos aceitos, na ordem em que são documentados
+var Formatos = []Formato{FormatoCSV, FormatoNDJSON, FormatoParquet}
+
+// VersaoEsquema identifica o formato das linhas; deve ser incrementada sempre que uma coluna
+// for criada, removida ou mudar de sentido, e vai no manifesto dos dados abertos
+const VersaoEsquema = 1
+
+// linhasPorGrupo limita as linhas mantidas em memória pelo Parquet antes de gravar um row group
+const linhasPorGrupo = 10000
+
+func (f Formato) Valido() bool {
+	switch f {
+	case FormatoCSV, FormatoNDJSON, FormatoParquet:
+		return true
+	}
+	return false
+}
+
+// ContentType é o tipo MIME do formato
+func (f Formato) ContentType() string {
+	switch f {
+	case FormatoNDJSON:
+		return "application/x-ndjson"
+	case FormatoParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Escritor grava linhas de um tipo em um formato. Fechar deve ser chamado ao fim, para
+// descarregar os buffers e, no Parquet, gravar o rodapé do arquivo.
+type Escritor[T any] interface {
+	Escrever(linha T) error
+	Fechar() error
+}
+
+// NovoEscritor cria o escritor do formato sobre w. T deve ser uma das structs de linha deste
+// pacote: os nomes das colunas vêm das tags json (CSV e JSON Lines) e parquet.
+func NovoEscritor[T any](w io.Writer, formato Formato) (Escritor[T], error) {
+	switch formato {
+	case FormatoCSV:
+		return novoEscritorCSV[T](w), nil
+	case FormatoNDJSON:
+		b := bufio.NewWriter(w)
+		return &escritorNDJSON[T]{buffer: b, encoder: json.NewEncoder(b)}, nil
+	case FormatoParquet:
+		return &escritorParquet[T]{writer: parquet.NewGenericWriter[T](w,
+			parquet.MaxRowsPerRowGroup(linhasPorGrupo),
+			parquet.Compression(&parquet.Snappy),
+		)}, nil
+	default:
+		return nil, fmt.Errorf("formato de exportação desconhecido: %q", formato)
+	}
+}
+
+// Gravar converte cada registro entregue por percorrer em uma linha e a grava no formato, e
+// retorna quantas linhas foram gravadas. Nada é escrito em w antes do primeiro registro.
+func Gravar[D, L any](w io.Writer, formato Formato, percorrer func(func(D) error) error, linha func(D) L) (int64, error) {
+	escritor, err := NovoEscritor[L](w, formato)
+	if err != nil {
+		return 0, err
+	}
+
+	var linhas int64
+	if err := percorrer(func(registro D) error {
+		linhas++
+		return escritor.Escrever(linha(registro))
+	}); err != nil {
+		return linhas, err
+	}
+	return linhas, escritor.Fechar()
+}
+
+// Colunas lista os nomes das colunas de T, na ordem do CSV
+func Colunas[T any]() []string {
+	var colunas []string
+	tipo := reflect.TypeOf((*T)(nil)).Elem()
+	for i := 0; i < tipo.NumField(); i++ {
+		nome, _, _ := strings.Cut(tipo.Field(i).Tag.Get("json"), ",")
+		colunas = append(colunas, nome)
+	}
+	return colunas
+}
+
+type escritorCSV[T any] struct {
+	writer    *csv.Writer
+	cabecalho []string
+	valores   []string
+	escreveu  bool
+}
+
+func novoEscritorCSV[T any](w io.Writer) *escritorCSV[T] {
+	cabecalho := Colunas[T]()
+	return &escritorCSV[T]{
+		writer:    csv.NewWriter(w),
+		cabecalho: cabecalho,
+		valores:   make([]string, len(cabecalho)),
+	}
+}
+
+func (e *escritorCSV[T]) Escrever(linha T) error {
+	if !e.escreveu {
+		e.escreveu = true
+		if err := e.writer.Write(e.cabecalho); err != nil {
+			return err
+		}
+	}
+
+	v := reflect.ValueOf(linha)
+	for i := range e.valores {
+		e.valores[i] = celula(v.Field(i))
+	}
+	return e.writer.Write(e.valores)
+}
+
+func (e *escritorCSV[T]) Fechar() error {
+	// Uma exportação vazia ainda tem o cabeçalho
+	if !e.escreveu {
+		if err := e.writer.Write(e.cabecalho); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// celula formata um campo para o CSV: datas em RFC 3339, listas separadas por ponto e vírgula
+// e ponteiros nulos como célula vazia
+func celula(v reflect.Value) string {
+	switch valor := v.Interface().(type) {
+	case time.Time:
+		return valor.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if valor == nil {
+			return ""
+		}
+		return valor.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(valor, ";")
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+type escritorNDJSON[T any] struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *escritorNDJSON[T]) Escrever(linha T) error {
+	return e.encoder.Encode(linha)
+}
+
+func (e *escritorNDJSON[T]) Fechar() error {
+	return e.buffer.Flush()
+}
+
+type escritorParquet[T any] struct {
+	writer *parquet.GenericWriter[T]
+	linhas []T
+}
+
+// Escrever acumula as linhas e as entrega ao writer em lotes, que é como o parquet-go é
+// eficiente; o writer grava um row group a cada linhasPorGrupo linhas
+func (e *escritorParquet[T]) Escrever(linha T) error {
+	e.linhas = append(e.linhas, linha)
+	if len(e.linhas) < 1000 {
+		return nil
+	}
+	return e.descarregar()
+}
+
+func (e *escritorParquet[T]) descarregar() error {
+	_, err := e.writer.Write(e.linhas)
+	e.linhas = e.linhas[:0]
+	return err
+}
+
+func (e *escritorParquet[T]) Fechar() error {
+	if err := e.descarregar(); err != nil {
+		return err
+	}
+	return e.writer.Close()
+}
@@ -0,0 +1,126 @@
+package exportacao
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+var linhasTeste = []LinhaProposicao{
+	{
+		ID: "a1", Tipo: "PL", Numero: "12", Ano: 2024, Ementa: "Dispõe sobre \"aspas\", vírgulas e\nquebras de linha.",
+		AutorID: "b2", CoautoresIDs: []string{"c3", "d4"}, Situacao: "APROVADA", Temas: []string{"Saúde"},
+		ApresentadaEm: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), AtualizadaEm: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC),
+	},
+	{
+		ID: "e5", Tipo: "PEC", Numero: "3", Ano: 2023, CoautoresIDs: []string{}, Temas: []string{},
+		ApresentadaEm: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), AtualizadaEm: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func escrever[T any](t *testing.T, formato Formato, linhas []T) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	escritor, err := NovoEscritor[T](&b, formato)
+	if err != nil {
+		t.Fatalf("NovoEscritor(%s): %v", formato, err)
+	}
+	for _, l := range linhas {
+		if err := escritor.Escrever(l); err != nil {
+			t.Fatalf("Escrever(%s): %v", formato, err)
+		}
+	}
+	if err := escritor.Fechar(); err != nil {
+		t.Fatalf("Fechar(%s): %v", formato, err)
+	}
+	return b.Bytes()
+}
+
+func TestCSV(t *testing.T) {
+	registros, err := csv.NewReader(bytes.NewReader(escrever(t, FormatoCSV, linhasTeste))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV ilegível: %v", err)
+	}
+
+	esperado := [][]string{
+		{"id", "tipo", "numero", "ano", "ementa", "autorId", "coautoresIds", "situacao", "temas", "apresentadaEm", "atualizadaEm"},
+		{"a1", "PL", "12", "2024", "Dispõe sobre \"aspas\", vírgulas e\nquebras de linha.", "b2", "c3;d4", "APROVADA", "Saúde", "2024-03-05T00:00:00Z", "2024-06-01T12:30:00Z"},
+		{"e5", "PEC", "3", "2023", "", "", "", "", "", "2023-01-02T00:00:00Z", "2023-01-02T00:00:00Z"},
+	}
+	if !reflect.DeepEqual(registros, esperado) {
+		t.Errorf("CSV =\n%q\nesperado\n%q", registros, esperado)
+	}
+
+	// Sem linhas, o arquivo ainda tem o cabeçalho
+	vazio := escrever[LinhaDespesa](t, FormatoCSV, nil)
+	if !bytes.HasPrefix(vazio, []byte("id,politicoId,ano,mes,data,")) {
+		t.Errorf("CSV vazio = %q", vazio)
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	scanner := bufio.NewScanner(bytes.NewReader(escrever(t, FormatoNDJSON, linhasTeste)))
+
+	var lidas []LinhaProposicao
+	for scanner.Scan() {
+		var l LinhaProposicao
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			t.Fatalf("linha %q: %v", scanner.Text(), err)
+		}
+		lidas = append(lidas, l)
+	}
+	if !reflect.DeepEqual(lidas, linhasTeste) {
+		t.Errorf("NDJSON = %+v", lidas)
+	}
+}
+
+func TestParquet(t *testing.T) {
+	// Mais linhas que um row group, para que o arquivo tenha mais de um
+	var linhas []LinhaVotacao
+	for i := 0; i < linhasPorGrupo+500; i++ {
+		linhas = append(linhas, LinhaVotacao{
+			ID:         fmt.Sprintf("v%d", i),
+			PoliticoID: "p",
+			Data:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i%365),
+			Voto:       []string{"SIM", "NAO", "AUSENTE"}[i%3],
+		})
+	}
+
+	dados := escrever(t, FormatoParquet, linhas)
+	arquivo, err := parquet.OpenFile(bytes.NewReader(dados), int64(len(dados)))
+	if err != nil {
+		t.Fatalf("Parquet ilegível: %v", err)
+	}
+	if n := len(arquivo.RowGroups()); n != 2 {
+		t.Errorf("%d row groups; esperado 2", n)
+	}
+
+	lidas, err := parquet.Read[LinhaVotacao](bytes.NewReader(dados), int64(len(dados)))
+	if err != nil {
+		t.Fatalf("parquet.Read: %v", err)
+	}
+	if !reflect.DeepEqual(lidas, linhas) {
+		t.Errorf("Parquet: %d linhas lidas, diferentes das %d escritas", len(lidas), len(linhas))
+	}
+
+	// Datas não preenchidas são nulas, não 0001-01-01
+	politicos := escrever(t, FormatoParquet, []LinhaPolitico{{ID: "x"}})
+	lidos, err := parquet.Read[LinhaPolitico](bytes.NewReader(politicos), int64(len(politicos)))
+	if err != nil || len(lidos) != 1 || lidos[0].DataNascimento != nil {
+		t.Errorf("político sem data de nascimento = %+v (%v)", lidos, err)
+	}
+}
+
+func TestFormatoInvalido(t *testing.T) {
+	if _, err := NovoEscritor[LinhaDespesa](&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("formato xml deveria ser recusado")
+	}
+}
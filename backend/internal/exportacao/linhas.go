@@ -0,0 +1,197 @@
+package exportacao
+
+import (
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// As linhas são versões planas dos documentos: dos objetos aninhados (partido, cargo atual,
+// contato) ficam só os campos mais usados, e as referências a outros registros viram IDs em
+// hexadecimal.
+
+// LinhaPolitico é uma linha da exportação de políticos
+type LinhaPolitico struct {
+	ID              string     `json:"id" parquet:"id"`
+	Nome            string     `json:"nome" parquet:"nome"`
+	NomeCivil       string     `json:"nomeCivil" parquet:"nome_civil"`
+	Genero          string     `json:"genero" parquet:"genero"`
+	DataNascimento  *time.Time `json:"dataNascimento" parquet:"data_nascimento,optional"`
+	UFNascimento    string     `json:"ufNascimento" parquet:"uf_nascimento"`
+	Escolaridade    string     `json:"escolaridade" parquet:"escolaridade"`
+	Partido         string     `json:"partido" parquet:"partido"`
+	Cargo           string     `json:"cargo" parquet:"cargo"`
+	Esfera          string     `json:"esfera" parquet:"esfera"`
+	Estado          string     `json:"estado" parquet:"estado"`
+	Municipio       string     `json:"municipio" parquet:"municipio"`
+	InicioMandato   *time.Time `json:"inicioMandato" parquet:"inicio_mandato,optional"`
+	FimMandato      *time.Time `json:"fimMandato" parquet:"fim_mandato,optional"`
+	EmExercicio     bool       `json:"emExercicio" parquet:"em_exercicio"`
+	Email           string     `json:"email" parquet:"email"`
+	SalarioBruto    float64    `json:"salarioBruto" parquet:"salario_bruto"`
+	IDExternoCamara int64      `json:"idExternoCamara" parquet:"id_externo_camara"`
+	IDExternoSenado string     `json:"idExternoSenado" parquet:"id_externo_senado"`
+	IDExternoTSE    string     `json:"idExternoTse" parquet:"id_externo_tse"`
+	AtualizadoEm    time.Time  `json:"atualizadoEm" parquet:"atualizado_em"`
+}
+
+func NovaLinhaPolitico(p domain.Politico) LinhaPolitico {
+	return LinhaPolitico{
+		ID:              p.ID.Hex(),
+		Nome:            p.Nome,
+		NomeCivil:       p.NomeCivil,
+		Genero:          string(p.Genero),
+		DataNascimento:  data(p.DataNascimento),
+		UFNascimento:    p.UFNascimento,
+		Escolaridade:    p.Escolaridade,
+		Partido:         p.Partido.Sigla,
+		Cargo:           string(p.CargoAtual.Tipo),
+		Esfera:          string(p.CargoAtual.Esfera),
+		Estado:          p.CargoAtual.Estado,
+		Municipio:       p.CargoAtual.Municipio,
+		InicioMandato:   data(p.CargoAtual.DataInicio),
+		FimMandato:      data(p.CargoAtual.DataFim),
+		EmExercicio:     p.CargoAtual.EmExercicio,
+		Email:           p.Contato.Email,
+		SalarioBruto:    p.SalarioBruto,
+		IDExternoCamara: int64(p.IDExternoCamara),
+		IDExternoSenado: p.IDExternoSenado,
+		IDExternoTSE:    p.IDExternoTSE,
+		AtualizadoEm:    p.UpdatedAt.UTC(),
+	}
+}
+
+// LinhaDespesa é uma linha da exportação de despesas
+type LinhaDespesa struct {
+	ID                      string    `json:"id" parquet:"id"`
+	PoliticoID              string    `json:"politicoId" parquet:"politico_id"`
+	Ano                     int64     `json:"ano" parquet:"ano"`
+	Mes                     int64     `json:"mes" parquet:"mes"`
+	Data                    time.Time `json:"data" parquet:"data"`
+	Tipo                    string    `json:"tipo" parquet:"tipo,dict"`
+	Descricao               string    `json:"descricao" parquet:"descricao"`
+	Fornecedor              string    `json:"fornecedor" parquet:"fornecedor"`
+	DocumentoFornecedor     string    `json:"documentoFornecedor" parquet:"documento_fornecedor"`
+	TipoDocumentoFornecedor string    `json:"tipoDocumentoFornecedor" parquet:"tipo_documento_fornecedor,dict"`
+	Valor                   float64   `json:"valor" parquet:"valor"`
+	NumDocumento            string    `json:"numDocumento" parquet:"num_documento"`
+	DocumentoURL            string    `json:"documentoUrl" parquet:"documento_url"`
+}
+
+func NovaLinhaDespesa(d domain.Despesa) LinhaDespesa {
+	return LinhaDespesa{
+		ID:                      d.ID.Hex(),
+		PoliticoID:              d.PoliticoID.Hex(),
+		Ano:                     int64(d.AnoReferencia),
+		Mes:                     int64(d.MesReferencia),
+		Data:                    d.Data.UTC(),
+		Tipo:                    d.Tipo,
+		Descricao:               d.Descricao,
+		Fornecedor:              d.Fornecedor,
+		DocumentoFornecedor:     d.CNPJFornecedor,
+		TipoDocumentoFornecedor: d.TipoDocumentoFornecedor,
+		Valor:                   d.Valor,
+		NumDocumento:            d.NumDocumento,
+		DocumentoURL:            d.DocumentoURL,
+	}
+}
+
+// LinhaVotacao é uma linha da exportação de votações: um voto de um político
+type LinhaVotacao struct {
+	ID               string    `json:"id" parquet:"id"`
+	VotacaoIDExterno string    `json:"votacaoIdExterno" parquet:"votacao_id_externo"`
+	PoliticoID       string    `json:"politicoId" parquet:"politico_id"`
+	ProposicaoID     string    `json:"proposicaoId" parquet:"proposicao_id"`
+	Data             time.Time `json:"data" parquet:"data"`
+	Sessao           string    `json:"sessao" parquet:"sessao,dict"`
+	Voto             string    `json:"voto" parquet:"voto,dict"`
+}
+
+func NovaLinhaVotacao(v domain.Votacao) LinhaVotacao {
+	return LinhaVotacao{
+		ID:               v.ID.Hex(),
+		VotacaoIDExterno: v.VotacaoIDExterno,
+		PoliticoID:       v.PoliticoID.Hex(),
+		ProposicaoID:     hex(v.ProposicaoID),
+		Data:             v.Data.UTC(),
+		Sessao:           v.Sessao,
+		Voto:             string(v.Voto),
+	}
+}
+
+// LinhaProposicao é uma linha da exportação de proposições
+type LinhaProposicao struct {
+	ID            string    `json:"id" parquet:"id"`
+	Tipo          string    `json:"tipo" parquet:"tipo,dict"`
+	Numero        string    `json:"numero" parquet:"numero"`
+	Ano           int64     `json:"ano" parquet:"ano"`
+	Ementa        string    `json:"ementa" parquet:"ementa"`
+	AutorID       string    `json:"autorId" parquet:"autor_id"`
+	CoautoresIDs  []string  `json:"coautoresIds" parquet:"coautores_ids,list"`
+	Situacao      string    `json:"situacao" parquet:"situacao,dict"`
+	Temas         []string  `json:"temas" parquet:"temas,list"`
+	ApresentadaEm time.Time `json:"apresentadaEm" parquet:"apresentada_em"`
+	AtualizadaEm  time.Time `json:"atualizadaEm" parquet:"atualizada_em"`
+}
+
+func NovaLinhaProposicao(p domain.Proposicao) LinhaProposicao {
+	coautores := make([]string, 0, len(p.CoautoresIDs))
+	for _, id := range p.CoautoresIDs {
+		coautores = append(coautores, id.Hex())
+	}
+	temas := p.Tema
+	if temas == nil {
+		temas = []string{}
+	}
+
+	return LinhaProposicao{
+		ID:            p.ID.Hex(),
+		Tipo:          p.Tipo,
+		Numero:        p.Numero,
+		Ano:           int64(p.Ano),
+		Ementa:        p.Ementa,
+		AutorID:       hex(p.AutorID),
+		CoautoresIDs:  coautores,
+		Situacao:      string(p.Situacao),
+		Temas:         temas,
+		ApresentadaEm: p.CreatedAt.UTC(),
+		AtualizadaEm:  p.UpdatedAt.UTC(),
+	}
+}
+
+// LinhaPresenca é uma linha da exportação de presenças em sessões
+type LinhaPresenca struct {
+	ID         string    `json:"id" parquet:"id"`
+	PoliticoID string    `json:"politicoId" parquet:"politico_id"`
+	Data       time.Time `json:"data" parquet:"data"`
+	TipoSessao string    `json:"tipoSessao" parquet:"tipo_sessao,dict"`
+	Presente   bool      `json:"presente" parquet:"presente"`
+}
+
+func NovaLinhaPresenca(p domain.Presenca) LinhaPresenca {
+	return LinhaPresenca{
+		ID:         p.ID.Hex(),
+		PoliticoID: p.PoliticoID.Hex(),
+		Data:       p.Data.UTC(),
+		TipoSessao: p.TipoSessao,
+		Presente:   p.Presente,
+	}
+}
+
+// data converte datas não preenchidas em nulo
+func data(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// hex deixa vazias as referências não preenchidas, em vez de 000000000000000000000000
+func hex(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
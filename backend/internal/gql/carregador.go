@@ -0,0 +1,173 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+// carregador agrupa as buscas por ID de uma consulta, como um dataloader. Cada resolver
+// registra o ID de que precisa e retorna um thunk; o executor só chama os thunks depois de
+// resolver os demais campos do mesmo nível, e o primeiro deles busca de uma vez todos os IDs
+// registrados até ali. Os itens ficam guardados até o fim da consulta, então o mesmo ID
+// (a mesma proposição votada por vários políticos, por exemplo) é buscado uma vez só.
+type carregador[T any] struct {
+	mu        sync.Mutex
+	buscou    *sync.Cond // Avisa quem espera um ID que estava no lote de outra busca
+	buscar    func(ctx context.Context, ids []string) (map[string]*T, error)
+	pendentes map[string]bool
+	emBusca   map[string]bool // IDs de lotes sendo buscados, sem a trava
+	itens     map[string]*T   // nil para os IDs buscados que não existem
+	erros     map[string]error
+	lotes     int // Buscas feitas no repositório
+}
+
+func novoCarregador[T any](buscar func(ctx context.Context, ids []string) (map[string]*T, error)) *carregador[T] {
+	c := &carregador[T]{
+		buscar:    buscar,
+		pendentes: make(map[string]bool),
+		emBusca:   make(map[string]bool),
+		itens:     make(map[string]*T),
+		erros:     make(map[string]error),
+	}
+	c.buscou = sync.NewCond(&c.mu)
+	return c
+}
+
+// registrar marca o ID para a próxima busca, se ainda não foi buscado nem está marcado.
+// Chamado com c.mu travado.
+func (c *carregador[T]) registrar(id string) {
+	if _, buscado := c.itens[id]; !buscado && c.erros[id] == nil {
+		c.pendentes[id] = true
+	}
+}
+
+// resolver retorna o item do ID, buscando antes os pendentes se ele ainda não foi buscado.
+// Um ID já buscado não dispara a busca: o executor completa cada item assim que o thunk
+// retorna, e os IDs que esse item registra para o nível de baixo devem esperar os demais.
+//
+// O lote é retirado dos pendentes com a trava e buscado sem ela, para que os resolvers que
+// só registram IDs não esperem o repositório. Se o ID está no lote de outra busca em
+// andamento, espera por ela em vez de buscá-lo de novo.
+func (c *carregador[T]) resolver(ctx context.Context, id string) (*T, error) {
+	c.mu.Lock()
+	for {
+		if _, buscado := c.itens[id]; buscado || c.erros[id] != nil {
+			item, err := c.itens[id], c.erros[id]
+			c.mu.Unlock()
+			return item, err
+		}
+		if !c.emBusca[id] {
+			break
+		}
+		c.buscou.Wait()
+	}
+
+	c.pendentes[id] = true
+	lote := make([]string, 0, len(c.pendentes))
+	for pendente := range c.pendentes {
+		lote = append(lote, pendente)
+		c.emBusca[pendente] = true
+	}
+	c.pendentes = make(map[string]bool)
+	c.lotes++
+	c.mu.Unlock()
+
+	itens, err := c.buscar(ctx, lote)
+
+	c.mu.Lock()
+	for _, pendente := range lote {
+		if err != nil {
+			c.erros[pendente] = err
+		} else {
+			c.itens[pendente] = itens[pendente]
+		}
+		delete(c.emBusca, pendente)
+	}
+	item, errItem := c.itens[id], c.erros[id]
+	c.buscou.Broadcast()
+	c.mu.Unlock()
+	return item, errItem
+}
+
+// carregar retorna o thunk que entrega o item do ID, ou null se não existir
+func (c *carregador[T]) carregar(ctx context.Context, id string) func() (interface{}, error) {
+	c.mu.Lock()
+	c.registrar(id)
+	c.mu.Unlock()
+
+	return func() (interface{}, error) {
+		item, err := c.resolver(ctx, id)
+		if err != nil || item == nil {
+			return nil, err
+		}
+		return item, nil
+	}
+}
+
+// carregarVarios retorna o thunk que entrega os itens existentes dos IDs, na ordem dos IDs
+func (c *carregador[T]) carregarVarios(ctx context.Context, ids []string) func() (interface{}, error) {
+	c.mu.Lock()
+	for _, id := range ids {
+		c.registrar(id)
+	}
+	c.mu.Unlock()
+
+	return func() (interface{}, error) {
+		itens := make([]*T, 0, len(ids))
+		for _, id := range ids {
+			item, err := c.resolver(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				itens = append(itens, item)
+			}
+		}
+		return itens, nil
+	}
+}
+
+// carregadores são os carregadores de uma consulta; cada execução cria os seus, para que
+// nada fique guardado entre uma consulta e outra
+type carregadores struct {
+	politicos   *carregador[domain.Politico]
+	proposicoes *carregador[domain.Proposicao]
+}
+
+func novosCarregadores(politicos repository.Politicos, proposicoes repository.Proposicoes) *carregadores {
+	return &carregadores{
+		politicos: novoCarregador(func(ctx context.Context, ids []string) (map[string]*domain.Politico, error) {
+			encontrados, err := politicos.BuscarPorIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			porID := make(map[string]*domain.Politico, len(encontrados))
+			for i := range encontrados {
+				porID[encontrados[i].ID.Hex()] = &encontrados[i]
+			}
+			return porID, nil
+		}),
+		proposicoes: novoCarregador(func(ctx context.Context, ids []string) (map[string]*domain.Proposicao, error) {
+			encontradas, err := proposicoes.BuscarPorIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			porID := make(map[string]*domain.Proposicao, len(encontradas))
+			for i := range encontradas {
+				porID[encontradas[i].ID.Hex()] = &encontradas[i]
+			}
+			return porID, nil
+		}),
+	}
+}
+
+type chaveCarregadores struct{}
+
+// carregadoresDe retorna os carregadores da consulta guardados no contexto por Executar
+func carregadoresDe(ctx context.Context) *carregadores {
+	c, _ := ctx.Value(chaveCarregadores{}).(*carregadores)
+	return c
+}
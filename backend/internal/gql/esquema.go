@@ -0,0 +1,491 @@
+// Package gql implementa a API GraphQL sobre os repositórios de políticos, votações,
+// proposições, despesas e presenças. As referências por ID (o político de um voto, a
+// proposição votada, o autor e os coautores) são buscadas em lote pelos carregadores, e
+// cada consulta tem a profundidade e o custo limitados antes de executar (ver medidor).
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+var ErrLimiteExcedido = errors.New("consulta excede o limite")
+
+// Estatisticas são as contagens gerais e por político, do serviço de políticos
+type Estatisticas interface {
+	BuscarEstatisticas(ctx context.Context, id string) (*domain.EstatisticasPolitico, error)
+	ContarPoliticos(ctx context.Context) (int64, error)
+	ContarVotacoes(ctx context.Context) (int64, error)
+	ContarProposicoes(ctx context.Context) (int64, error)
+	TotalDespesas(ctx context.Context) (float64, error)
+}
+
+// Fontes são os repositórios consultados pelos resolvers
+type Fontes struct {
+	Politicos    repository.Politicos
+	Votacoes     repository.Votacoes
+	Despesas     repository.Despesas
+	Proposicoes  repository.Proposicoes
+	Presencas    repository.Presencas
+	Estatisticas Estatisticas
+}
+
+// Requisicao é o corpo de uma requisição GraphQL
+type Requisicao struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+type Esquema struct {
+	schema  graphql.Schema
+	fontes  Fontes
+	limites Limites
+}
+
+func NewEsquema(fontes Fontes, limites Limites) (*Esquema, error) {
+	e := &Esquema{fontes: fontes, limites: limites}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: e.query()})
+	if err != nil {
+		return nil, err
+	}
+	e.schema = schema
+	return e, nil
+}
+
+// Executar valida a consulta, confere os limites e a executa com carregadores novos.
+// rejeitada indica que a consulta foi recusada antes de executar (sintaxe, validação,
+// profundidade ou custo); erros da execução, como uma falha do banco, não a rejeitam.
+func (e *Esquema) Executar(ctx context.Context, requisicao Requisicao) (resultado *graphql.Result, rejeitada bool) {
+	documento, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(requisicao.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, true
+	}
+
+	validacao := graphql.ValidateDocument(&e.schema, documento, nil)
+	if !validacao.IsValid {
+		return &graphql.Result{Errors: validacao.Errors}, true
+	}
+
+	profundidade, custo := medir(&e.schema, documento, requisicao.OperationName, requisicao.Variables)
+	if e.limites.Profundidade > 0 && profundidade > e.limites.Profundidade {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(fmt.Errorf("%w: profundidade %d, máxima %d", ErrLimiteExcedido, profundidade, e.limites.Profundidade))}, true
+	}
+	if e.limites.Custo > 0 && custo > e.limites.Custo {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(fmt.Errorf("%w: custo %d, máximo %d", ErrLimiteExcedido, custo, e.limites.Custo))}, true
+	}
+
+	ctx = context.WithValue(ctx, chaveCarregadores{}, novosCarregadores(e.fontes.Politicos, e.fontes.Proposicoes))
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           documento,
+		OperationName: requisicao.OperationName,
+		Args:          requisicao.Variables,
+		Context:       ctx,
+	}), false
+}
+
+// fonte retorna o item que originou o campo, venha ele por valor (itens de uma página) ou por
+// ponteiro (itens dos carregadores)
+func fonte[T any](p graphql.ResolveParams) *T {
+	switch v := p.Source.(type) {
+	case T:
+		return &v
+	case *T:
+		return v
+	}
+	return nil
+}
+
+// paginaCursor é a página de uma lista aninhada: os itens e o cursor da próxima, ou null
+func paginaCursor[T any](itens []T, proximo string) map[string]interface{} {
+	pagina := map[string]interface{}{"data": itens, "nextCursor": nil}
+	if proximo != "" {
+		pagina["nextCursor"] = proximo
+	}
+	return pagina
+}
+
+// paginacao lê porPagina e cursor dos argumentos de uma lista aninhada
+func paginacao(p graphql.ResolveParams) domain.Paginacao {
+	paginacao := domain.Paginacao{PorCursor: true}
+	paginacao.PorPagina, _ = p.Args["porPagina"].(int)
+	paginacao.Cursor, _ = p.Args["cursor"].(string)
+	return paginacao
+}
+
+// anoMes lê os argumentos opcionais ano e mes
+func anoMes(p graphql.ResolveParams) (ano, mes *int) {
+	if v, ok := p.Args["ano"].(int); ok {
+		ano = &v
+	}
+	if v, ok := p.Args["mes"].(int); ok {
+		mes = &v
+	}
+	return ano, mes
+}
+
+// textos lê um argumento [String!]
+func textos(p graphql.ResolveParams, nome string) []string {
+	lista, _ := p.Args[nome].([]interface{})
+	textos := make([]string, 0, len(lista))
+	for _, v := range lista {
+		if s, ok := v.(string); ok {
+			textos = append(textos, s)
+		}
+	}
+	return textos
+}
+
+// resolverID resolve o campo id de qualquer documento com ID primitive.ObjectID
+func resolverID[T any](id func(*T) string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if item := fonte[T](p); item != nil {
+			return id(item), nil
+		}
+		return nil, nil
+	}
+}
+
+// argumentosLista são os argumentos das listas aninhadas, paginadas por cursor
+func argumentosLista(extras graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	argumentos := graphql.FieldConfigArgument{
+		"porPagina": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10, Description: "Itens por página (máximo 100)"},
+		"cursor":    &graphql.ArgumentConfig{Type: graphql.String, Description: "O nextCursor da página anterior"},
+	}
+	for nome, argumento := range extras {
+		argumentos[nome] = argumento
+	}
+	return argumentos
+}
+
+func tipoPaginaCursor(nome string, item graphql.Type) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: nome,
+		Fields: graphql.Fields{
+			"data":       &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item)))},
+			"nextCursor": &graphql.Field{Type: graphql.String},
+		},
+	})
+}
+
+func (e *Esquema) query() *graphql.Object {
+	partido := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Partido",
+		Fields: graphql.Fields{
+			"sigla": &graphql.Field{Type: graphql.String},
+			"nome":  &graphql.Field{Type: graphql.String},
+			"cor":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	cargo := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Cargo",
+		Fields: graphql.Fields{
+			"tipo":        &graphql.Field{Type: graphql.String},
+			"esfera":      &graphql.Field{Type: graphql.String},
+			"estado":      &graphql.Field{Type: graphql.String},
+			"municipio":   &graphql.Field{Type: graphql.String},
+			"dataInicio":  &graphql.Field{Type: graphql.DateTime},
+			"dataFim":     &graphql.Field{Type: graphql.DateTime},
+			"emExercicio": &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	estatisticasPolitico := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EstatisticasPolitico",
+		Fields: graphql.Fields{
+			"totalVotacoes":        &graphql.Field{Type: graphql.Int},
+			"votosSim":             &graphql.Field{Type: graphql.Int},
+			"votosNao":             &graphql.Field{Type: graphql.Int},
+			"abstencoes":           &graphql.Field{Type: graphql.Int},
+			"ausencias":            &graphql.Field{Type: graphql.Int},
+			"percentualPresenca":   &graphql.Field{Type: graphql.Float},
+			"totalProposicoes":     &graphql.Field{Type: graphql.Int},
+			"proposicoesAprovadas": &graphql.Field{Type: graphql.Int},
+			"totalDespesas":        &graphql.Field{Type: graphql.Float},
+			"mediaGastoMensal":     &graphql.Field{Type: graphql.Float},
+		},
+	})
+
+	estatisticasGerais := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EstatisticasGerais",
+		Fields: graphql.Fields{
+			"totalPoliticos":   &graphql.Field{Type: graphql.Int},
+			"totalVotacoes":    &graphql.Field{Type: graphql.Int},
+			"totalProposicoes": &graphql.Field{Type: graphql.Int},
+			"totalDespesas":    &graphql.Field{Type: graphql.Float},
+		},
+	})
+
+	// Os tipos se referenciam (o político tem votos, e cada voto o seu político), então os
+	// campos de referência são adicionados depois de criados todos os tipos
+	politico := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Politico",
+		Fields: graphql.Fields{
+			"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolverID(func(p *domain.Politico) string { return p.ID.Hex() })},
+			"nome":           &graphql.Field{Type: graphql.String},
+			"nomeCivil":      &graphql.Field{Type: graphql.String},
+			"nomeEleitoral":  &graphql.Field{Type: graphql.String},
+			"fotoUrl":        &graphql.Field{Type: graphql.String},
+			"dataNascimento": &graphql.Field{Type: graphql.DateTime},
+			"genero":         &graphql.Field{Type: graphql.String},
+			"partido":        &graphql.Field{Type: partido},
+			"cargoAtual":     &graphql.Field{Type: cargo},
+			"salarioBruto":   &graphql.Field{Type: graphql.Float},
+			"salarioLiquido": &graphql.Field{Type: graphql.Float},
+			"escolaridade":   &graphql.Field{Type: graphql.String},
+			"website":        &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	votacao := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Votacao",
+		Fields: graphql.Fields{
+			"id":               &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolverID(func(v *domain.Votacao) string { return v.ID.Hex() })},
+			"voto":             &graphql.Field{Type: graphql.String},
+			"data":             &graphql.Field{Type: graphql.DateTime},
+			"sessao":           &graphql.Field{Type: graphql.String},
+			"votacaoIdExterno": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	proposicao := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Proposicao",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolverID(func(p *domain.Proposicao) string { return p.ID.Hex() })},
+			"tipo":     &graphql.Field{Type: graphql.String},
+			"numero":   &graphql.Field{Type: graphql.String},
+			"ano":      &graphql.Field{Type: graphql.Int},
+			"ementa":   &graphql.Field{Type: graphql.String},
+			"situacao": &graphql.Field{Type: graphql.String},
+			"tema":     &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	despesa := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Despesa",
+		Fields: graphql.Fields{
+			"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolverID(func(d *domain.Despesa) string { return d.ID.Hex() })},
+			"tipo":           &graphql.Field{Type: graphql.String},
+			"descricao":      &graphql.Field{Type: graphql.String},
+			"fornecedor":     &graphql.Field{Type: graphql.String},
+			"cnpjFornecedor": &graphql.Field{Type: graphql.String},
+			"valor":          &graphql.Field{Type: graphql.Float},
+			"data":           &graphql.Field{Type: graphql.DateTime},
+			"mesReferencia":  &graphql.Field{Type: graphql.Int},
+			"anoReferencia":  &graphql.Field{Type: graphql.Int},
+			"documentoUrl":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	presenca := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Presenca",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolverID(func(p *domain.Presenca) string { return p.ID.Hex() })},
+			"data":       &graphql.Field{Type: graphql.DateTime},
+			"tipoSessao": &graphql.Field{Type: graphql.String},
+			"presente":   &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	// Referências por ID, resolvidas pelos carregadores
+	politicoDe := func(id func(graphql.ResolveParams) string) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			if hex := id(p); hex != "" {
+				return carregadoresDe(p.Context).politicos.carregar(p.Context, hex), nil
+			}
+			return nil, nil
+		}
+	}
+	votacao.AddFieldConfig("politico", &graphql.Field{Type: politico, Resolve: politicoDe(func(p graphql.ResolveParams) string {
+		return fonte[domain.Votacao](p).PoliticoID.Hex()
+	})})
+	despesa.AddFieldConfig("politico", &graphql.Field{Type: politico, Resolve: politicoDe(func(p graphql.ResolveParams) string {
+		return fonte[domain.Despesa](p).PoliticoID.Hex()
+	})})
+	presenca.AddFieldConfig("politico", &graphql.Field{Type: politico, Resolve: politicoDe(func(p graphql.ResolveParams) string {
+		return fonte[domain.Presenca](p).PoliticoID.Hex()
+	})})
+	proposicao.AddFieldConfig("autor", &graphql.Field{Type: politico, Resolve: politicoDe(func(p graphql.ResolveParams) string {
+		if autor := fonte[domain.Proposicao](p).AutorID; !autor.IsZero() {
+			return autor.Hex()
+		}
+		return ""
+	})})
+	proposicao.AddFieldConfig("coautores", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(politico))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			coautores := fonte[domain.Proposicao](p).CoautoresIDs
+			ids := make([]string, len(coautores))
+			for i, id := range coautores {
+				ids[i] = id.Hex()
+			}
+			return carregadoresDe(p.Context).politicos.carregarVarios(p.Context, ids), nil
+		},
+	})
+	votacao.AddFieldConfig("proposicao", &graphql.Field{
+		Type:        proposicao,
+		Description: "A proposição votada; null nas votações sem proposição (requerimentos, por exemplo)",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id := fonte[domain.Votacao](p).ProposicaoID
+			if id.IsZero() {
+				return nil, nil
+			}
+			return carregadoresDe(p.Context).proposicoes.carregar(p.Context, id.Hex()), nil
+		},
+	})
+
+	// Listas de um político, paginadas por cursor
+	idPolitico := func(p graphql.ResolveParams) string { return fonte[domain.Politico](p).ID.Hex() }
+	filtroAnoMes := graphql.FieldConfigArgument{
+		"ano": &graphql.ArgumentConfig{Type: graphql.Int},
+		"mes": &graphql.ArgumentConfig{Type: graphql.Int},
+	}
+
+	politico.AddFieldConfig("votacoes", &graphql.Field{
+		Type: graphql.NewNonNull(tipoPaginaCursor("PaginaVotacoes", votacao)),
+		Args: argumentosLista(nil),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			resposta, err := e.fontes.Votacoes.ListarPorPolitico(p.Context, idPolitico(p), paginacao(p), domain.Inclusoes{})
+			if err != nil {
+				return nil, err
+			}
+			votacoes := make([]domain.Votacao, len(resposta.Data))
+			for i, v := range resposta.Data {
+				votacoes[i] = v.Votacao
+			}
+			return paginaCursor(votacoes, resposta.ProximoCursor), nil
+		},
+	})
+	politico.AddFieldConfig("proposicoes", &graphql.Field{
+		Type: graphql.NewNonNull(tipoPaginaCursor("PaginaProposicoes", proposicao)),
+		Args: argumentosLista(nil),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			resposta, err := e.fontes.Proposicoes.ListarPorAutor(p.Context, idPolitico(p), paginacao(p), domain.Inclusoes{})
+			if err != nil {
+				return nil, err
+			}
+			proposicoes := make([]domain.Proposicao, len(resposta.Data))
+			for i, v := range resposta.Data {
+				proposicoes[i] = v.Proposicao
+			}
+			return paginaCursor(proposicoes, resposta.ProximoCursor), nil
+		},
+	})
+	politico.AddFieldConfig("despesas", &graphql.Field{
+		Type: graphql.NewNonNull(tipoPaginaCursor("PaginaDespesas", despesa)),
+		Args: argumentosLista(filtroAnoMes),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			ano, mes := anoMes(p)
+			resposta, err := e.fontes.Despesas.ListarPorPolitico(p.Context, idPolitico(p), ano, mes, paginacao(p))
+			if err != nil {
+				return nil, err
+			}
+			return paginaCursor(resposta.Data, resposta.ProximoCursor), nil
+		},
+	})
+	politico.AddFieldConfig("presencas", &graphql.Field{
+		Type: graphql.NewNonNull(tipoPaginaCursor("PaginaPresencas", presenca)),
+		Args: argumentosLista(filtroAnoMes),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			ano, mes := anoMes(p)
+			resposta, err := e.fontes.Presencas.ListarPorPolitico(p.Context, idPolitico(p), ano, mes, paginacao(p))
+			if err != nil {
+				return nil, err
+			}
+			return paginaCursor(resposta.Data, resposta.ProximoCursor), nil
+		},
+	})
+	politico.AddFieldConfig("estatisticas", &graphql.Field{
+		Type: estatisticasPolitico,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return e.fontes.Estatisticas.BuscarEstatisticas(p.Context, idPolitico(p))
+		},
+	})
+
+	paginaPoliticos := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PaginaPoliticos",
+		Fields: graphql.Fields{
+			"data":         &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(politico)))},
+			"total":        &graphql.Field{Type: graphql.Int},
+			"pagina":       &graphql.Field{Type: graphql.Int},
+			"porPagina":    &graphql.Field{Type: graphql.Int},
+			"totalPaginas": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"politico": &graphql.Field{
+				Type: politico,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, _ := p.Args["id"].(string)
+					return carregadoresDe(p.Context).politicos.carregar(p.Context, id), nil
+				},
+			},
+			"politicos": &graphql.Field{
+				Type: graphql.NewNonNull(paginaPoliticos),
+				Args: graphql.FieldConfigArgument{
+					"nome":        &graphql.ArgumentConfig{Type: graphql.String},
+					"partido":     &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"estado":      &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"cargo":       &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"esfera":      &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"emExercicio": &graphql.ArgumentConfig{Type: graphql.Boolean},
+					"pagina":      &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"porPagina":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 12, Description: "Itens por página (máximo 100)"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					filtros := domain.FiltrosPoliticos{Partido: textos(p, "partido"), Estado: textos(p, "estado")}
+					filtros.Nome, _ = p.Args["nome"].(string)
+					filtros.Pagina, _ = p.Args["pagina"].(int)
+					filtros.PorPagina, _ = p.Args["porPagina"].(int)
+					for _, c := range textos(p, "cargo") {
+						filtros.Cargo = append(filtros.Cargo, domain.Cargo(c))
+					}
+					for _, esfera := range textos(p, "esfera") {
+						filtros.Esfera = append(filtros.Esfera, domain.Esfera(esfera))
+					}
+					if emExercicio, ok := p.Args["emExercicio"].(bool); ok {
+						filtros.EmExercicio = &emExercicio
+					}
+					return e.fontes.Politicos.Listar(p.Context, filtros)
+				},
+			},
+			"proposicao": &graphql.Field{
+				Type: proposicao,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, _ := p.Args["id"].(string)
+					return carregadoresDe(p.Context).proposicoes.carregar(p.Context, id), nil
+				},
+			},
+			"estatisticas": &graphql.Field{
+				Type: graphql.NewNonNull(estatisticasGerais),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					// Como em /estatisticas/geral, uma contagem que falha vale zero
+					geral := map[string]interface{}{}
+					geral["totalPoliticos"], _ = e.fontes.Estatisticas.ContarPoliticos(p.Context)
+					geral["totalVotacoes"], _ = e.fontes.Estatisticas.ContarVotacoes(p.Context)
+					geral["totalProposicoes"], _ = e.fontes.Estatisticas.ContarProposicoes(p.Context)
+					geral["totalDespesas"], _ = e.fontes.Estatisticas.TotalDespesas(p.Context)
+					return geral, nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,209 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+// politicosContados e proposicoesContadas contam as buscas por ID feitas nos repositórios
+type politicosContados struct {
+	repository.Politicos
+	buscas int
+}
+
+func (r *politicosContados) BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Politico, error) {
+	r.buscas++
+	return r.Politicos.BuscarPorIDs(ctx, ids)
+}
+
+type proposicoesContadas struct {
+	repository.Proposicoes
+	buscas int
+}
+
+func (r *proposicoesContadas) BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Proposicao, error) {
+	r.buscas++
+	return r.Proposicoes.BuscarPorIDs(ctx, ids)
+}
+
+func novoEsquema(t *testing.T, limites Limites) (*Esquema, *politicosContados, *proposicoesContadas) {
+	t.Helper()
+	banco := memoria.NewBanco(mock.Gerar(mock.Configuracao{Semente: 5, Politicos: 30, VotacoesPorCasa: 20}))
+	politicos := &politicosContados{Politicos: memoria.NewPoliticoRepository(banco)}
+	proposicoes := &proposicoesContadas{Proposicoes: memoria.NewProposicaoRepository(banco)}
+	fontes := Fontes{
+		Politicos:   politicos,
+		Votacoes:    memoria.NewVotacaoRepository(banco),
+		Despesas:    memoria.NewDespesaRepository(banco),
+		Proposicoes: proposicoes,
+		Presencas:   memoria.NewPresencaRepository(banco),
+	}
+	fontes.Estatisticas = services.NewPoliticoService(fontes.Politicos, fontes.Votacoes, fontes.Despesas, fontes.Proposicoes, fontes.Presencas)
+
+	esquema, err := NewEsquema(fontes, limites)
+	if err != nil {
+		t.Fatalf("NewEsquema: %v", err)
+	}
+	return esquema, politicos, proposicoes
+}
+
+// As referências por ID de uma lista são buscadas em lote: uma busca por nível, não uma por item
+func TestCarregadoresAgrupamBuscas(t *testing.T) {
+	esquema, politicos, proposicoes := novoEsquema(t, LimitesPadrao)
+
+	resultado, _ := esquema.Executar(context.Background(), Requisicao{Query: `{
+		politicos(porPagina: 5) {
+			data {
+				nome
+				votacoes(porPagina: 10) {
+					data { voto politico { nome } proposicao { id autor { nome } coautores { nome } } }
+				}
+			}
+		}
+	}`})
+	if len(resultado.Errors) > 0 {
+		t.Fatalf("erros: %v", resultado.Errors)
+	}
+
+	// Um lote para o político dos votos e outro para autores e coautores das proposições
+	if politicos.buscas != 2 || proposicoes.buscas != 1 {
+		t.Errorf("buscas por ID: %d de políticos e %d de proposições; esperadas 2 e 1", politicos.buscas, proposicoes.buscas)
+	}
+
+	pagina := resultado.Data.(map[string]interface{})["politicos"].(map[string]interface{})
+	votos, comProposicao := 0, 0
+	for _, p := range pagina["data"].([]interface{}) {
+		politico := p.(map[string]interface{})
+		for _, v := range politico["votacoes"].(map[string]interface{})["data"].([]interface{}) {
+			voto := v.(map[string]interface{})
+			votos++
+			if nome := voto["politico"].(map[string]interface{})["nome"]; nome != politico["nome"] {
+				t.Errorf("voto de %v trouxe o político %v", politico["nome"], nome)
+			}
+			if proposicao, ok := voto["proposicao"].(map[string]interface{}); ok {
+				comProposicao++
+				if proposicao["autor"] == nil {
+					t.Errorf("proposição %v sem o autor", proposicao["id"])
+				}
+			}
+		}
+	}
+	if votos == 0 || comProposicao == 0 {
+		t.Errorf("%d votos, %d com proposição; esperados votos com proposições", votos, comProposicao)
+	}
+}
+
+func TestLimites(t *testing.T) {
+	esquema, _, _ := novoEsquema(t, Limites{Profundidade: 6, Custo: 1000})
+	ctx := context.Background()
+
+	for _, caso := range []struct {
+		nome      string
+		query     string
+		variaveis map[string]interface{}
+		rejeitada string
+	}{
+		{
+			nome:  "dentro dos limites",
+			query: `{ politicos(porPagina: 5) { data { votacoes(porPagina: 10) { data { proposicao { ementa } } } } } }`,
+		},
+		{
+			nome:      "profunda demais",
+			query:     `{ politicos { data { votacoes { data { proposicao { autor { votacoes { data { voto } } } } } } } } }`,
+			rejeitada: "profundidade 9",
+		},
+		{
+			// A profundidade dos fragmentos conta onde eles são usados
+			nome:      "profunda demais com fragmentos",
+			query:     `{ politicos { data { ...Votos } } } fragment Votos on Politico { votacoes { data { proposicao { autor { ...Nome } } } } } fragment Nome on Politico { votacoes { data { voto } } }`,
+			rejeitada: "profundidade 9",
+		},
+		{
+			// 1 + 100 × (1 + 1 + 100 × (1 + 1)): cada político e cada voto multiplicam o custo
+			nome:      "cara demais",
+			query:     `{ politicos(porPagina: 100) { data { votacoes(porPagina: 100) { data { proposicao { ementa } } } } } }`,
+			rejeitada: "custo 20201",
+		},
+		{
+			nome:      "cara demais por variável",
+			query:     `query($n: Int) { politicos(porPagina: $n) { data { votacoes(porPagina: $n) { data { politico { nome } } } } } }`,
+			variaveis: map[string]interface{}{"n": float64(50)},
+			rejeitada: "custo 5101",
+		},
+		{
+			nome:  "introspecção não conta",
+			query: `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`,
+		},
+	} {
+		resultado, rejeitada := esquema.Executar(ctx, Requisicao{Query: caso.query, Variables: caso.variaveis})
+		switch {
+		case rejeitada != (caso.rejeitada != ""):
+			t.Errorf("%s: rejeitada = %v", caso.nome, rejeitada)
+		case caso.rejeitada == "" && len(resultado.Errors) > 0:
+			t.Errorf("%s: erros %v", caso.nome, resultado.Errors)
+		case caso.rejeitada != "" && (resultado.Data != nil || len(resultado.Errors) != 1 || !strings.Contains(resultado.Errors[0].Message, caso.rejeitada)):
+			t.Errorf("%s: resultado %+v; esperada a rejeição por %s", caso.nome, resultado, caso.rejeitada)
+		case caso.rejeitada != "" && !errors.Is(resultado.Errors[0].OriginalError(), ErrLimiteExcedido):
+			t.Errorf("%s: erro %v não é ErrLimiteExcedido", caso.nome, resultado.Errors[0].OriginalError())
+		}
+	}
+}
+
+// A busca no repositório é feita sem a trava: enquanto um lote está sendo buscado, os
+// resolvers continuam registrando IDs, e quem precisa de um ID do lote espera por ele
+func TestCarregadorBuscaSemTrava(t *testing.T) {
+	liberar := make(chan struct{})
+	buscas := 0
+	c := novoCarregador(func(ctx context.Context, ids []string) (map[string]*string, error) {
+		buscas++
+		<-liberar
+		itens := map[string]*string{}
+		for _, id := range ids {
+			id := id
+			itens[id] = &id
+		}
+		return itens, nil
+	})
+	ctx := context.Background()
+
+	primeiro := c.carregar(ctx, "a")
+	resultados := make(chan interface{}, 2)
+	go func() {
+		item, _ := primeiro()
+		resultados <- item
+	}()
+	go func() {
+		item, _ := c.carregar(ctx, "a")()
+		resultados <- item
+	}()
+
+	registrado := make(chan struct{})
+	go func() {
+		c.carregar(ctx, "b")
+		close(registrado)
+	}()
+	select {
+	case <-registrado:
+	case <-time.After(time.Second):
+		t.Fatal("registrar um ID esperou a busca em andamento")
+	}
+
+	close(liberar)
+	for i := 0; i < 2; i++ {
+		if item, _ := (<-resultados).(*string); item == nil || *item != "a" {
+			t.Errorf("item = %v; esperado a", item)
+		}
+	}
+	if buscas != 1 {
+		t.Errorf("%d buscas; o mesmo ID deveria ser buscado uma vez só", buscas)
+	}
+}
@@ -0,0 +1,190 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limites de uma consulta, verificados antes de executá-la
+type Limites struct {
+	Profundidade int // Níveis de seleção aninhados
+	Custo        int // Custo estimado (ver medidor)
+}
+
+var LimitesPadrao = Limites{Profundidade: 10, Custo: 5000}
+
+// pesos dos campos que custam mais que uma busca simples
+var pesos = map[string]int{
+	"Query.estatisticas":    5,  // Quatro contagens nas coleções inteiras
+	"Politico.estatisticas": 10, // Sete consultas por político
+}
+
+// itensEstimados das listas sem argumento porPagina
+var itensEstimados = map[string]int{
+	"Proposicao.coautores": 5,
+}
+
+// medidor estima a profundidade e o custo de uma consulta sem executá-la. Cada campo com
+// subseleção (um objeto ou lista de objetos, que em geral é uma busca) custa 1, ou o peso
+// dele; os campos escalares não custam nada. O custo das subseleções das listas é
+// multiplicado pelo número de itens pedido em porPagina (ou pelo padrão do argumento).
+// Os campos de introspecção (__schema, __type, __typename) não contam.
+type medidor struct {
+	esquema    *graphql.Schema
+	fragmentos map[string]*ast.FragmentDefinition
+	variaveis  map[string]interface{}
+}
+
+// medir retorna a profundidade e o custo da operação; o documento já deve ter sido validado
+func medir(esquema *graphql.Schema, documento *ast.Document, operacao string, variaveis map[string]interface{}) (profundidade, custo int) {
+	m := medidor{esquema: esquema, fragmentos: map[string]*ast.FragmentDefinition{}, variaveis: variaveis}
+
+	var escolhida *ast.OperationDefinition
+	for _, definicao := range documento.Definitions {
+		switch d := definicao.(type) {
+		case *ast.FragmentDefinition:
+			m.fragmentos[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operacao == "" || (d.Name != nil && d.Name.Value == operacao) {
+				escolhida = d
+			}
+		}
+	}
+	if escolhida == nil {
+		return 0, 0
+	}
+	return m.selecao(esquema.QueryType(), escolhida.SelectionSet, 1, map[string]bool{})
+}
+
+func (m *medidor) selecao(tipo *graphql.Object, selecao *ast.SelectionSet, nivel int, visitados map[string]bool) (profundidade, custo int) {
+	if tipo == nil || selecao == nil {
+		return 0, 0
+	}
+
+	for _, s := range selecao.Selections {
+		var p, c int
+		switch s := s.(type) {
+		case *ast.Field:
+			p, c = m.campo(tipo, s, nivel, visitados)
+		case *ast.InlineFragment:
+			p, c = m.selecao(m.condicao(tipo, s.TypeCondition), s.SelectionSet, nivel, visitados)
+		case *ast.FragmentSpread:
+			fragmento := m.fragmentos[s.Name.Value]
+			if fragmento == nil || visitados[s.Name.Value] {
+				continue
+			}
+			visitados[s.Name.Value] = true
+			p, c = m.selecao(m.condicao(tipo, fragmento.TypeCondition), fragmento.SelectionSet, nivel, visitados)
+			delete(visitados, s.Name.Value)
+		}
+		if p > profundidade {
+			profundidade = p
+		}
+		custo += c
+	}
+	return profundidade, custo
+}
+
+func (m *medidor) campo(tipo *graphql.Object, campo *ast.Field, nivel int, visitados map[string]bool) (profundidade, custo int) {
+	nome := campo.Name.Value
+	definicao := tipo.Fields()[nome]
+	if strings.HasPrefix(nome, "__") || definicao == nil {
+		return 0, 0
+	}
+	if campo.SelectionSet == nil {
+		return nivel, 0
+	}
+
+	// Desembrulha NonNull e List até o objeto, notando se é uma lista
+	lista := false
+	var saida graphql.Type = definicao.Type
+	for {
+		switch t := saida.(type) {
+		case *graphql.NonNull:
+			saida = t.OfType
+			continue
+		case *graphql.List:
+			lista = true
+			saida = t.OfType
+			continue
+		}
+		break
+	}
+	objeto, _ := saida.(*graphql.Object)
+
+	profundidade, custo = m.selecao(objeto, campo.SelectionSet, nivel+1, visitados)
+	if itens := m.itens(tipo.Name()+"."+nome, definicao, campo); itens > 1 || lista {
+		custo *= itens
+	}
+
+	peso, ok := pesos[tipo.Name()+"."+nome]
+	if !ok {
+		peso = 1
+	}
+	return profundidade, peso + custo
+}
+
+// itens retorna quantos itens o campo pode entregar: o porPagina pedido, limitado a 100
+// como nos repositórios, o padrão do argumento, ou a estimativa das listas sem porPagina
+func (m *medidor) itens(chave string, definicao *graphql.FieldDefinition, campo *ast.Field) int {
+	if n, ok := itensEstimados[chave]; ok {
+		return n
+	}
+
+	var argumento *graphql.Argument
+	for _, a := range definicao.Args {
+		if a.Name() == "porPagina" {
+			argumento = a
+		}
+	}
+	if argumento == nil {
+		return 1
+	}
+
+	n := inteiro(argumento.DefaultValue)
+	for _, a := range campo.Arguments {
+		if a.Name.Value != "porPagina" {
+			continue
+		}
+		switch v := a.Value.(type) {
+		case *ast.IntValue:
+			n, _ = strconv.Atoi(v.Value)
+		case *ast.Variable:
+			if valor, ok := m.variaveis[v.Name.Value]; ok && valor != nil {
+				n = inteiro(valor)
+			}
+		}
+	}
+	if n < 1 || n > 100 {
+		n = 100
+	}
+	return n
+}
+
+// condicao retorna o tipo da condição de um fragmento, ou o tipo atual se não houver
+func (m *medidor) condicao(tipo *graphql.Object, condicao *ast.Named) *graphql.Object {
+	if condicao == nil {
+		return tipo
+	}
+	objeto, _ := m.esquema.Type(condicao.Name.Value).(*graphql.Object)
+	return objeto
+}
+
+// inteiro converte um valor de variável (float64 vindo do JSON) ou padrão de argumento
+func inteiro(v interface{}) int {
+	switch v := v.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	n, _ := strconv.Atoi(fmt.Sprint(v))
+	return n
+}
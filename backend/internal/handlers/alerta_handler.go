@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type AlertaHandler struct {
+	service *services.AlertaService
+}
+
+func NewAlertaHandler(service *services.AlertaService) *AlertaHandler {
+	return &AlertaHandler{service: service}
+}
+
+// Listar retorna os alertas de todos os políticos
+func (h *AlertaHandler) Listar(c echo.Context) error {
+	filtros := parseFiltrosAlertas(c)
+	filtros.PoliticoID = c.QueryParam("politicoId")
+
+	return h.listar(c, filtros)
+}
+
+// ListarPorPolitico retorna os alertas de um político
+func (h *AlertaHandler) ListarPorPolitico(c echo.Context) error {
+	filtros := parseFiltrosAlertas(c)
+	filtros.PoliticoID = c.Param("id")
+
+	return h.listar(c, filtros)
+}
+
+func (h *AlertaHandler) listar(c echo.Context, filtros domain.FiltrosAlertas) error {
+	result, err := h.service.Listar(c.Request().Context(), filtros)
+	if err != nil {
+		return erroListagem(c, err, "Erro ao listar alertas")
+	}
+
+	return responderPagina(c, result)
+}
+
+// parseFiltrosAlertas lê tipo, severidade, ano e paginação da query string
+func parseFiltrosAlertas(c echo.Context) domain.FiltrosAlertas {
+	var filtros domain.FiltrosAlertas
+
+	filtros.Paginacao = parsePaginacao(c)
+
+	if tipo := c.QueryParam("tipo"); tipo != "" {
+		for _, t := range strings.Split(tipo, ",") {
+			filtros.Tipo = append(filtros.Tipo, domain.TipoAlerta(t))
+		}
+	}
+
+	if severidade := c.QueryParam("severidade"); severidade != "" {
+		for _, s := range strings.Split(severidade, ",") {
+			filtros.Severidade = append(filtros.Severidade, domain.Severidade(s))
+		}
+	}
+
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		filtros.Ano = &a
+	}
+
+	return filtros
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type AlteracaoHandler struct {
+	service *services.AlteracaoService
+}
+
+func NewAlteracaoHandler(service *services.AlteracaoService) *AlteracaoHandler {
+	return &AlteracaoHandler{service: service}
+}
+
+// ListarPorPolitico retorna o histórico de alterações do cadastro de um político,
+// opcionalmente filtrado por campo (ex.: ?campo=partido)
+func (h *AlteracaoHandler) ListarPorPolitico(c echo.Context) error {
+	result, err := h.service.ListarPorPolitico(c.Request().Context(), c.Param("id"), c.QueryParam("campo"), parsePaginacao(c))
+	if err != nil {
+		return erroListagem(c, err, "Erro ao listar alterações")
+	}
+
+	return responderPagina(c, result)
+}
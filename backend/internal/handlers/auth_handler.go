@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/auth"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type AuthHandler struct {
+	service      *services.ChaveService
+	autenticador *auth.Autenticador
+	validade     time.Duration
+}
+
+func NewAuthHandler(service *services.ChaveService, autenticador *auth.Autenticador, validade time.Duration) *AuthHandler {
+	return &AuthHandler{
+		service:      service,
+		autenticador: autenticador,
+		validade:     validade,
+	}
+}
+
+// Token troca a chave de API da requisição por um token JWT de curta duração com o mesmo papel
+// (a rota recusa tokens com auth.ExigirChaveAPI)
+func (h *AuthHandler) Token(c echo.Context) error {
+	id := auth.IdentidadeDe(c)
+	if id == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Não autorizado",
+		})
+	}
+
+	token, err := h.autenticador.EmitirToken(*id, h.validade)
+	if err != nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Emissão de tokens desativada (JWT_SECRET não configurado)",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"token":      token,
+		"tipo":       "Bearer",
+		"expiraEm":   time.Now().Add(h.validade).Format(time.RFC3339),
+		"identidade": id,
+	})
+}
+
+type criarChaveRequest struct {
+	Nome    string             `json:"nome"`
+	Papel   domain.Papel       `json:"papel"`
+	Limites *domain.LimitesUso `json:"limites"`
+}
+
+// CriarChave gera uma chave de API; a chave completa só é retornada nesta resposta
+func (h *AuthHandler) CriarChave(c echo.Context) error {
+	var req criarChaveRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Corpo da requisição inválido",
+		})
+	}
+
+	criadaPor := ""
+	if id := auth.IdentidadeDe(c); id != nil {
+		criadaPor = id.ID
+	}
+
+	valor, chave, err := h.service.Criar(c.Request().Context(), req.Nome, req.Papel, req.Limites, criadaPor)
+	if err != nil {
+		return erroChave(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"chave":    valor,
+		"registro": chave,
+	})
+}
+
+func (h *AuthHandler) ListarChaves(c echo.Context) error {
+	chaves, err := h.service.Listar(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar chaves",
+		})
+	}
+
+	return c.JSON(http.StatusOK, chaves)
+}
+
+func (h *AuthHandler) RevogarChave(c echo.Context) error {
+	chave, err := h.service.Revogar(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return erroChave(c, err)
+	}
+
+	return c.JSON(http.StatusOK, chave)
+}
+
+// erroChave converte os erros de gerenciamento de chaves no status HTTP correspondente
+func erroChave(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrIDInvalido), errors.Is(err, services.ErrPapelInvalido), errors.Is(err, services.ErrNomeChave):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrChaveNaoEncontrada):
+		status = http.StatusNotFound
+	}
+
+	if status == http.StatusInternalServerError {
+		return c.JSON(status, map[string]string{
+			"error": "Erro ao processar chave de API",
+		})
+	}
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+)
+
+// arvoreCampos são os campos pedidos em ?campos=, com os subcampos dos documentos embutidos;
+// um campo sem subcampos (nil) vai inteiro
+type arvoreCampos map[string]arvoreCampos
+
+// lerCampos monta a árvore de ?campos=voto,data,proposicao.ementa
+func lerCampos(valor string) arvoreCampos {
+	arvore := arvoreCampos{}
+	for _, campo := range strings.Split(valor, ",") {
+		campo = strings.TrimSpace(campo)
+		if campo == "" {
+			continue
+		}
+
+		no := arvore
+		partes := strings.Split(campo, ".")
+		for i, parte := range partes {
+			filho, existe := no[parte]
+			if existe && filho == nil {
+				break // O campo inteiro já foi pedido
+			}
+			if i == len(partes)-1 {
+				no[parte] = nil
+				break
+			}
+			if !existe {
+				filho = arvoreCampos{}
+				no[parte] = filho
+			}
+			no = filho
+		}
+	}
+	return arvore
+}
+
+// filtrarCampos mantém só os campos da árvore nos objetos (e nos objetos das listas) do JSON
+// decodificado. O "id" é sempre mantido e campos desconhecidos são ignorados.
+func filtrarCampos(v interface{}, arvore arvoreCampos) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		filtrado := make(map[string]interface{}, len(arvore)+1)
+		if id, ok := v["id"]; ok {
+			filtrado["id"] = id
+		}
+		for campo, subcampos := range arvore {
+			valor, ok := v[campo]
+			if !ok {
+				continue
+			}
+			if subcampos != nil {
+				valor = filtrarCampos(valor, subcampos)
+			}
+			filtrado[campo] = valor
+		}
+		return filtrado
+	case []interface{}:
+		for i := range v {
+			v[i] = filtrarCampos(v[i], arvore)
+		}
+	}
+	return v
+}
+
+// comCampos converte v no JSON genérico e aplica ?campos=
+func comCampos(v interface{}, campos string) (interface{}, error) {
+	conteudo, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// UseNumber preserva os números como vieram, sem passar por float64
+	decoder := json.NewDecoder(bytes.NewReader(conteudo))
+	decoder.UseNumber()
+	var generico interface{}
+	if err := decoder.Decode(&generico); err != nil {
+		return nil, err
+	}
+	return filtrarCampos(generico, lerCampos(campos)), nil
+}
+
+// responder responde 200 com v, reduzido aos campos de ?campos= se informado
+// (ex.: ?campos=nome,partido.sigla)
+func responder(c echo.Context, v interface{}) error {
+	campos := c.QueryParam("campos")
+	if strings.TrimSpace(campos) == "" {
+		return c.JSON(http.StatusOK, v)
+	}
+
+	filtrado, err := comCampos(v, campos)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, filtrado)
+}
+
+// responderPagina é o responder das listagens paginadas: ?campos= vale para cada item de
+// data, e o resto da resposta (total, nextCursor...) fica como está. O repositório já trouxe
+// do banco só os campos pedidos (parsePaginacao os passa adiante); aqui saem do JSON os
+// demais campos da struct, que viriam com o valor zero.
+func responderPagina[T any](c echo.Context, pagina *domain.PaginatedResponse[T]) error {
+	campos := c.QueryParam("campos")
+	if strings.TrimSpace(campos) == "" {
+		return c.JSON(http.StatusOK, pagina)
+	}
+
+	data, err := comCampos(pagina.Data, campos)
+	if err != nil {
+		return err
+	}
+	itens, _ := data.([]interface{})
+	if itens == nil {
+		itens = []interface{}{}
+	}
+	filtrada := domain.PaginatedResponse[interface{}]{
+		Data:          itens,
+		Total:         pagina.Total,
+		Pagina:        pagina.Pagina,
+		PorPagina:     pagina.PorPagina,
+		TotalPaginas:  pagina.TotalPaginas,
+		ProximoCursor: pagina.ProximoCursor,
+		PorCursor:     pagina.PorCursor,
+		SemTotal:      pagina.SemTotal,
+	}
+	return c.JSON(http.StatusOK, filtrada)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/dadosabertos"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type DadosAbertosHandler struct {
+	service *services.DadosAbertosService
+}
+
+func NewDadosAbertosHandler(service *services.DadosAbertosService) *DadosAbertosHandler {
+	return &DadosAbertosHandler{service: service}
+}
+
+// Listar retorna os dumps de dados abertos disponíveis, do mais recente para o mais antigo,
+// com o manifesto de cada um
+func (h *DadosAbertosHandler) Listar(c echo.Context) error {
+	manifestos, err := h.service.Listar()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar os dados abertos",
+		})
+	}
+
+	return c.JSON(http.StatusOK, manifestos)
+}
+
+// Buscar retorna o manifesto do dump de uma data (AAAA-MM-DD)
+func (h *DadosAbertosHandler) Buscar(c echo.Context) error {
+	manifesto, err := h.service.Buscar(c.Param("data"))
+	if err != nil {
+		return erroDadosAbertos(c, err)
+	}
+
+	return c.JSON(http.StatusOK, manifesto)
+}
+
+// Baixar envia um arquivo do dump (ex.: /dados-abertos/2024-05-01/despesas.csv.gz)
+func (h *DadosAbertosHandler) Baixar(c echo.Context) error {
+	caminho, err := h.service.Arquivo(c.Param("data"), c.Param("arquivo"))
+	if err != nil {
+		return erroDadosAbertos(c, err)
+	}
+
+	return c.Attachment(caminho, c.Param("arquivo"))
+}
+
+func erroDadosAbertos(c echo.Context, err error) error {
+	if errors.Is(err, dadosabertos.ErrDumpNaoEncontrado) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Erro ao buscar os dados abertos",
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type DespesaHandler struct {
+	service *services.DespesaService
+}
+
+func NewDespesaHandler(service *services.DespesaService) *DespesaHandler {
+	return &DespesaHandler{service: service}
+}
+
+// ResumoPorPolitico retorna os gastos de um político por categoria, fornecedor e mês
+func (h *DespesaHandler) ResumoPorPolitico(c echo.Context) error {
+	filtros := parseFiltrosDespesas(c)
+	filtros.PoliticoID = c.Param("id")
+
+	resumo, err := h.service.Resumo(c.Request().Context(), filtros)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao resumir despesas",
+		})
+	}
+
+	return c.JSON(http.StatusOK, resumo)
+}
+
+// Resumo retorna os gastos agregados de todos os políticos, de um partido ou de um estado
+func (h *DespesaHandler) Resumo(c echo.Context) error {
+	filtros := parseFiltrosDespesas(c)
+	filtros.Partido = c.QueryParam("partido")
+	filtros.Estado = c.QueryParam("estado")
+
+	resumo, err := h.service.Resumo(c.Request().Context(), filtros)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao resumir despesas",
+		})
+	}
+
+	return c.JSON(http.StatusOK, resumo)
+}
+
+// parseFiltrosDespesas lê os filtros de ano, mês e limite da query string
+func parseFiltrosDespesas(c echo.Context) domain.FiltrosDespesas {
+	var filtros domain.FiltrosDespesas
+
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		filtros.Ano = &a
+	}
+	if mesStr := c.QueryParam("mes"); mesStr != "" {
+		m, _ := strconv.Atoi(mesStr)
+		filtros.Mes = &m
+	}
+	filtros.Limite, _ = strconv.Atoi(c.QueryParam("limite"))
+
+	return filtros
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/exportacao"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type ExportacaoHandler struct {
+	service *services.ExportacaoService
+}
+
+func NewExportacaoHandler(service *services.ExportacaoService) *ExportacaoHandler {
+	return &ExportacaoHandler{service: service}
+}
+
+// Politicos exporta os políticos com os mesmos filtros de GET /politicos
+func (h *ExportacaoHandler) Politicos(c echo.Context) error {
+	filtros := parseFiltrosPoliticos(c)
+
+	return exportar(c, "politicos", func(formato exportacao.Formato, w io.Writer) error {
+		return h.service.Politicos(c.Request().Context(), filtros, formato, w)
+	})
+}
+
+// Despesas exporta as despesas com os filtros de GET /despesas/resumo, mais politicoId
+func (h *ExportacaoHandler) Despesas(c echo.Context) error {
+	filtros := parseFiltrosDespesas(c)
+	filtros.PoliticoID = c.QueryParam("politicoId")
+	filtros.Partido = c.QueryParam("partido")
+	filtros.Estado = c.QueryParam("estado")
+
+	return exportar(c, "despesas", func(formato exportacao.Formato, w io.Writer) error {
+		return h.service.Despesas(c.Request().Context(), filtros, formato, w)
+	})
+}
+
+// Votacoes exporta os votos, um por linha, filtrados por político, partido, estado, ano e voto
+func (h *ExportacaoHandler) Votacoes(c echo.Context) error {
+	filtros := domain.FiltrosVotacoes{
+		PoliticoID: c.QueryParam("politicoId"),
+		Partido:    c.QueryParam("partido"),
+		Estado:     c.QueryParam("estado"),
+		Voto:       domain.TipoVoto(c.QueryParam("voto")),
+	}
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		filtros.Ano = &a
+	}
+
+	return exportar(c, "votacoes", func(formato exportacao.Formato, w io.Writer) error {
+		return h.service.Votacoes(c.Request().Context(), filtros, formato, w)
+	})
+}
+
+// Proposicoes exporta as proposições filtradas por autor (ou coautor), tipo, ano e situação
+func (h *ExportacaoHandler) Proposicoes(c echo.Context) error {
+	filtros := domain.FiltrosProposicoes{
+		AutorID:  c.QueryParam("autorId"),
+		Tipo:     c.QueryParam("tipo"),
+		Situacao: domain.SituacaoProposicao(c.QueryParam("situacao")),
+	}
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		filtros.Ano = &a
+	}
+
+	return exportar(c, "proposicoes", func(formato exportacao.Formato, w io.Writer) error {
+		return h.service.Proposicoes(c.Request().Context(), filtros, formato, w)
+	})
+}
+
+// exportar lê o formato (?formato=csv|ndjson|parquet, CSV por padrão) e transmite o arquivo
+// direto na resposta. Erros antes do primeiro byte viram uma resposta JSON de erro; depois
+// disso o status já foi enviado, então a conexão é abortada para o cliente não confundir um
+// arquivo truncado com um completo.
+func exportar(c echo.Context, nome string, gravar func(exportacao.Formato, io.Writer) error) error {
+	formato := exportacao.Formato(c.QueryParam("formato"))
+	if formato == "" {
+		formato = exportacao.FormatoCSV
+	}
+	if !formato.Valido() {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": services.ErrFormatoInvalido.Error(),
+		})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, formato.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", nome+"."+string(formato)))
+
+	err := gravar(formato, res)
+	if err == nil {
+		return nil
+	}
+
+	if !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		if errors.Is(err, services.ErrIDInvalido) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao exportar " + nome,
+		})
+	}
+
+	log.Printf("❌ Exportação de %s interrompida: %v", nome, err)
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/services"
+	"github.com/lupa-cidada/backend/pkg/documento"
+)
+
+type FornecedorHandler struct {
+	service *services.FornecedorService
+}
+
+func NewFornecedorHandler(service *services.FornecedorService) *FornecedorHandler {
+	return &FornecedorHandler{service: service}
+}
+
+// Ranking lista os fornecedores que mais receberam da cota parlamentar
+func (h *FornecedorHandler) Ranking(c echo.Context) error {
+	var filtros domain.FiltrosFornecedores
+
+	filtros.Tipo = c.QueryParam("tipo")
+	filtros.Pagina, _ = strconv.Atoi(c.QueryParam("pagina"))
+	filtros.PorPagina, _ = strconv.Atoi(c.QueryParam("porPagina"))
+
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		filtros.Ano = &a
+	}
+	if mesStr := c.QueryParam("mes"); mesStr != "" {
+		m, _ := strconv.Atoi(mesStr)
+		filtros.Mes = &m
+	}
+
+	result, err := h.service.Ranking(c.Request().Context(), filtros)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar fornecedores",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// BuscarPorCNPJ retorna o perfil de um fornecedor (aceita CPF ou CNPJ, com ou sem pontuação).
+// Documentos com dígitos verificadores errados também são buscados: as despesas os guardam
+// assim quando a Câmara os publica errados.
+func (h *FornecedorHandler) BuscarPorCNPJ(c echo.Context) error {
+	cnpj := documento.Normalizar(c.Param("cnpj"))
+	if cnpj == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "CPF/CNPJ inválido",
+		})
+	}
+
+	perfil, err := h.service.BuscarPerfil(c.Request().Context(), cnpj)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao buscar fornecedor",
+		})
+	}
+	if perfil == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Fornecedor não encontrado",
+		})
+	}
+
+	return c.JSON(http.StatusOK, perfil)
+}
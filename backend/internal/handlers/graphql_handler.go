@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/gql"
+)
+
+// tamanhoMaximoConsulta limita o corpo das requisições POST
+const tamanhoMaximoConsulta = 1 << 20
+
+type GraphQLHandler struct {
+	esquema *gql.Esquema
+}
+
+func NewGraphQLHandler(esquema *gql.Esquema) *GraphQLHandler {
+	return &GraphQLHandler{esquema: esquema}
+}
+
+// Executar responde a uma consulta GraphQL, enviada por POST no corpo JSON
+// ({"query", "variables", "operationName"}) ou por GET em ?query= (e ?variables= em JSON).
+// As consultas rejeitadas antes de executar (sintaxe, validação, profundidade ou custo)
+// respondem 400; as demais, 200, com os erros dos campos que falharam em "errors", mesmo
+// que a falha seja do banco. Corpos maiores que 1 MiB respondem 413.
+func (h *GraphQLHandler) Executar(c echo.Context) error {
+	var requisicao gql.Requisicao
+	if c.Request().Method == http.MethodGet {
+		requisicao.Query = c.QueryParam("query")
+		requisicao.OperationName = c.QueryParam("operationName")
+		if variaveis := c.QueryParam("variables"); variaveis != "" {
+			if err := json.Unmarshal([]byte(variaveis), &requisicao.Variables); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "variables inválido",
+				})
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(c.Response(), c.Request().Body, tamanhoMaximoConsulta)).Decode(&requisicao); err != nil {
+		var grande *http.MaxBytesError
+		if errors.As(err, &grande) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"error": "Corpo da requisição grande demais",
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Corpo da requisição inválido",
+		})
+	}
+
+	if requisicao.Query == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "query é obrigatório",
+		})
+	}
+
+	resultado, rejeitada := h.esquema.Executar(c.Request().Context(), requisicao)
+	if rejeitada {
+		return c.JSON(http.StatusBadRequest, resultado)
+	}
+	return c.JSON(http.StatusOK, resultado)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type PartidoHandler struct {
+	service *services.PartidoService
+}
+
+func NewPartidoHandler(service *services.PartidoService) *PartidoHandler {
+	return &PartidoHandler{service: service}
+}
+
+// BuscarPorSigla retorna o perfil agregado de um partido (aceita siglas anteriores, ex.: PMDB)
+func (h *PartidoHandler) BuscarPorSigla(c echo.Context) error {
+	var ano *int
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		ano = &a
+	}
+
+	perfil, err := h.service.BuscarPerfil(c.Request().Context(), c.Param("sigla"), ano)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao buscar partido",
+		})
+	}
+	if perfil == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Partido não encontrado",
+		})
+	}
+
+	return c.JSON(http.StatusOK, perfil)
+}
+
+// TrocaTroca lista as trocas de partido no período (inicio e fim no formato AAAA-MM-DD).
+// Sem datas, considera os últimos 12 meses.
+func (h *PartidoHandler) TrocaTroca(c echo.Context) error {
+	fim := time.Now()
+	if fimStr := c.QueryParam("fim"); fimStr != "" {
+		data, err := time.Parse("2006-01-02", fimStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Data de fim inválida",
+			})
+		}
+		// Inclui o dia informado
+		fim = data.AddDate(0, 0, 1)
+	}
+
+	inicio := fim.AddDate(-1, 0, 0)
+	if inicioStr := c.QueryParam("inicio"); inicioStr != "" {
+		data, err := time.Parse("2006-01-02", inicioStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Data de início inválida",
+			})
+		}
+		inicio = data
+	}
+
+	if !inicio.Before(fim) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Período inválido",
+		})
+	}
+
+	relatorio, err := h.service.TrocaTroca(c.Request().Context(), inicio, fim)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar trocas de partido",
+		})
+	}
+
+	return c.JSON(http.StatusOK, relatorio)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,13 +19,12 @@ func NewPoliticoHandler(service *services.PoliticoService) *PoliticoHandler {
 	return &PoliticoHandler{service: service}
 }
 
-func (h *PoliticoHandler) Listar(c echo.Context) error {
+// parseFiltrosPoliticos lê os filtros da listagem de políticos da query string
+func parseFiltrosPoliticos(c echo.Context) domain.FiltrosPoliticos {
 	var filtros domain.FiltrosPoliticos
 
-	// Parse manual dos filtros
 	filtros.Nome = c.QueryParam("nome")
-	filtros.Pagina, _ = strconv.Atoi(c.QueryParam("pagina"))
-	filtros.PorPagina, _ = strconv.Atoi(c.QueryParam("porPagina"))
+	filtros.Paginacao = parsePaginacao(c)
 	filtros.OrdenarPor = c.QueryParam("ordenarPor")
 	filtros.Ordem = c.QueryParam("ordem")
 
@@ -59,14 +59,55 @@ func (h *PoliticoHandler) Listar(c echo.Context) error {
 		}
 	}
 
+	return filtros
+}
+
+func (h *PoliticoHandler) Listar(c echo.Context) error {
+	filtros := parseFiltrosPoliticos(c)
+
 	result, err := h.service.Listar(c.Request().Context(), filtros)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Erro ao listar políticos",
-		})
+		return erroListagem(c, err, "Erro ao listar políticos")
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return responderPagina(c, result)
+}
+
+// parsePaginacao lê a paginação das listagens: ?pagina=&porPagina= ou, por
+// cursor, ?cursor= (vazio na primeira página, depois o nextCursor da resposta) e ?total=true
+// para contar também o total. Os ?campos= vão junto, para o repositório trazer só esses.
+func parsePaginacao(c echo.Context) domain.Paginacao {
+	var paginacao domain.Paginacao
+
+	paginacao.Pagina, _ = strconv.Atoi(c.QueryParam("pagina"))
+	paginacao.PorPagina, _ = strconv.Atoi(c.QueryParam("porPagina"))
+
+	for _, campo := range strings.Split(c.QueryParam("campos"), ",") {
+		if campo = strings.TrimSpace(campo); campo != "" {
+			paginacao.Campos = append(paginacao.Campos, campo)
+		}
+	}
+
+	if c.QueryParams().Has("cursor") {
+		paginacao.PorCursor = true
+		paginacao.Cursor = c.QueryParam("cursor")
+		paginacao.ComTotal = c.QueryParam("total") == "true"
+	}
+
+	return paginacao
+}
+
+// erroListagem responde a um erro das listagens: 400 para um cursor inválido,
+// 500 para o resto
+func erroListagem(c echo.Context, err error, mensagem string) error {
+	if errors.Is(err, domain.ErrCursorInvalido) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": mensagem,
+	})
 }
 
 func (h *PoliticoHandler) BuscarPorID(c echo.Context) error {
@@ -79,7 +120,19 @@ func (h *PoliticoHandler) BuscarPorID(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusOK, politico)
+	return responder(c, politico)
+}
+
+// ListarPartidos retorna a linha do tempo de filiações partidárias do político
+func (h *PoliticoHandler) ListarPartidos(c echo.Context) error {
+	historico, err := h.service.HistoricoPartidos(c.Request().Context(), c.Param("id"))
+	if err != nil || historico == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Político não encontrado",
+		})
+	}
+
+	return c.JSON(http.StatusOK, historico)
 }
 
 func (h *PoliticoHandler) BuscarEstatisticas(c echo.Context) error {
@@ -97,23 +150,26 @@ func (h *PoliticoHandler) BuscarEstatisticas(c echo.Context) error {
 
 func (h *PoliticoHandler) ListarVotacoes(c echo.Context) error {
 	id := c.Param("id")
-	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
-	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+	paginacao := parsePaginacao(c)
 
-	result, err := h.service.ListarVotacoes(c.Request().Context(), id, pagina, porPagina)
+	incluir, err := domain.ParseInclusoes(c.QueryParam("incluir"), "proposicao", "autor")
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Erro ao listar votações",
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(http.StatusOK, result)
+	result, err := h.service.ListarVotacoes(c.Request().Context(), id, paginacao, incluir)
+	if err != nil {
+		return erroListagem(c, err, "Erro ao listar votações")
+	}
+
+	return responderPagina(c, result)
 }
 
 func (h *PoliticoHandler) ListarDespesas(c echo.Context) error {
 	id := c.Param("id")
-	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
-	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+	paginacao := parsePaginacao(c)
 
 	var ano, mes *int
 	if anoStr := c.QueryParam("ano"); anoStr != "" {
@@ -125,40 +181,53 @@ func (h *PoliticoHandler) ListarDespesas(c echo.Context) error {
 		mes = &m
 	}
 
-	result, err := h.service.ListarDespesas(c.Request().Context(), id, ano, mes, pagina, porPagina)
+	result, err := h.service.ListarDespesas(c.Request().Context(), id, ano, mes, paginacao)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Erro ao listar despesas",
-		})
+		return erroListagem(c, err, "Erro ao listar despesas")
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return responderPagina(c, result)
 }
 
 func (h *PoliticoHandler) ListarProposicoes(c echo.Context) error {
 	id := c.Param("id")
-	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
-	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+	paginacao := parsePaginacao(c)
 
-	result, err := h.service.ListarProposicoes(c.Request().Context(), id, pagina, porPagina)
+	incluir, err := domain.ParseInclusoes(c.QueryParam("incluir"), "autor")
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Erro ao listar proposições",
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(http.StatusOK, result)
+	result, err := h.service.ListarProposicoes(c.Request().Context(), id, paginacao, incluir)
+	if err != nil {
+		return erroListagem(c, err, "Erro ao listar proposições")
+	}
+
+	return responderPagina(c, result)
 }
 
 func (h *PoliticoHandler) ListarPresencas(c echo.Context) error {
-	// TODO: Implementar quando o repositório de presenças for usado
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"data":         []interface{}{},
-		"total":        0,
-		"pagina":       1,
-		"porPagina":    50,
-		"totalPaginas": 0,
-	})
+	id := c.Param("id")
+	paginacao := parsePaginacao(c)
+
+	var ano, mes *int
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		ano = &a
+	}
+	if mesStr := c.QueryParam("mes"); mesStr != "" {
+		m, _ := strconv.Atoi(mesStr)
+		mes = &m
+	}
+
+	result, err := h.service.ListarPresencas(c.Request().Context(), id, ano, mes, paginacao)
+	if err != nil {
+		return erroListagem(c, err, "Erro ao listar presenças")
+	}
+
+	return responderPagina(c, result)
 }
 
 func (h *PoliticoHandler) Comparar(c echo.Context) error {
@@ -192,6 +261,27 @@ func (h *PoliticoHandler) Comparar(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
+func (h *PoliticoHandler) BuscarSimilares(c echo.Context) error {
+	id := c.Param("id")
+	minimo, _ := strconv.Atoi(c.QueryParam("minimo"))
+	limite, _ := strconv.Atoi(c.QueryParam("limite"))
+
+	var ano *int
+	if anoStr := c.QueryParam("ano"); anoStr != "" {
+		a, _ := strconv.Atoi(anoStr)
+		ano = &a
+	}
+
+	result, err := h.service.BuscarSimilares(c.Request().Context(), id, ano, minimo, limite)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao buscar políticos similares",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 func (h *PoliticoHandler) Buscar(c echo.Context) error {
 	query := c.QueryParam("q")
 	if query == "" {
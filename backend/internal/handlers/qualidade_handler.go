@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type QualidadeHandler struct {
+	service *services.QualidadeService
+}
+
+func NewQualidadeHandler(service *services.QualidadeService) *QualidadeHandler {
+	return &QualidadeHandler{service: service}
+}
+
+// Relatorio retorna os problemas de qualidade de uma sincronização por regra e os registros
+// com mais problemas (filtros: ?execucao=<id>&regra=cpf_ausente&limite=20)
+func (h *QualidadeHandler) Relatorio(c echo.Context) error {
+	limite, _ := strconv.Atoi(c.QueryParam("limite"))
+
+	relatorio, err := h.service.Relatorio(c.Request().Context(), c.QueryParam("execucao"), domain.RegraQualidade(c.QueryParam("regra")), limite)
+	if err != nil {
+		return erroQualidade(c, err)
+	}
+
+	return c.JSON(http.StatusOK, relatorio)
+}
+
+// Problemas lista os problemas de qualidade de uma sincronização
+// (filtros: ?execucao=<id>&regra=cpf_ausente&registro=<id do político>)
+func (h *QualidadeHandler) Problemas(c echo.Context) error {
+	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
+	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+
+	result, err := h.service.Listar(c.Request().Context(), c.QueryParam("execucao"), domain.RegraQualidade(c.QueryParam("regra")), c.QueryParam("registro"), pagina, porPagina)
+	if err != nil {
+		return erroQualidade(c, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// erroQualidade converte os erros da consulta de qualidade no status HTTP correspondente
+func erroQualidade(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrIDInvalido) || errors.Is(err, services.ErrRegraQualidadeInvalida) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Erro ao consultar qualidade dos dados",
+	})
+}
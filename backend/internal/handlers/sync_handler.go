@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/auth"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/services"
+	"github.com/lupa-cidada/backend/internal/sync/orquestrador"
+)
+
+type SyncHandler struct {
+	service *services.SyncService
+}
+
+func NewSyncHandler(service *services.SyncService) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+// Iniciar dispara uma sincronização em segundo plano, ex.:
+// {"fonte": "camara", "ano": 2024, "entidades": ["votacoes", "despesas"]}
+func (h *SyncHandler) Iniciar(c echo.Context) error {
+	var pedido domain.PedidoSync
+	if err := c.Bind(&pedido); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Corpo da requisição inválido",
+		})
+	}
+
+	iniciadaPor := ""
+	if id := auth.IdentidadeDe(c); id != nil {
+		iniciadaPor = id.ID
+	}
+
+	exec, err := h.service.Iniciar(c.Request().Context(), pedido, iniciadaPor)
+	if err != nil {
+		return erroSync(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, exec)
+}
+
+// BuscarPorID retorna o andamento de uma sincronização: etapas, itens processados e erros
+func (h *SyncHandler) BuscarPorID(c echo.Context) error {
+	exec, err := h.service.BuscarPorID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return erroSync(c, err)
+	}
+
+	return c.JSON(http.StatusOK, exec)
+}
+
+// Cancelar interrompe uma sincronização em andamento
+func (h *SyncHandler) Cancelar(c echo.Context) error {
+	exec, err := h.service.Cancelar(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return erroSync(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, exec)
+}
+
+// Listar retorna o histórico de sincronizações, das mais recentes às mais antigas
+// (filtros: ?status=FALHOU&agendamento=votacoes)
+func (h *SyncHandler) Listar(c echo.Context) error {
+	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
+	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+
+	result, err := h.service.Listar(c.Request().Context(), domain.StatusExecucao(c.QueryParam("status")), c.QueryParam("agendamento"), pagina, porPagina)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar sincronizações",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Fontes lista as fontes e entidades aceitas em POST /admin/sync
+func (h *SyncHandler) Fontes(c echo.Context) error {
+	return c.JSON(http.StatusOK, orquestrador.Fontes())
+}
+
+// erroSync converte os erros de sincronização no status HTTP correspondente
+func erroSync(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrIDInvalido), errors.Is(err, services.ErrPedidoSyncInvalido):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrExecucaoNaoEncontrada):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrSyncEmAndamento), errors.Is(err, services.ErrSyncNaoExecutando):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrIndisponivelDebug):
+		status = http.StatusServiceUnavailable
+	}
+
+	if status == http.StatusInternalServerError {
+		return c.JSON(status, map[string]string{
+			"error": "Erro ao processar sincronização",
+		})
+	}
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/services"
+)
+
+type UnificacaoHandler struct {
+	service *services.UnificacaoService
+}
+
+func NewUnificacaoHandler(service *services.UnificacaoService) *UnificacaoHandler {
+	return &UnificacaoHandler{service: service}
+}
+
+type unificarRequest struct {
+	PrincipalID string `json:"principalId"`
+	DuplicadoID string `json:"duplicadoId"`
+	Motivo      string `json:"motivo"`
+}
+
+// Unificar junta dois cadastros do mesmo político; o duplicado é removido e suas
+// votações, despesas, presenças e proposições passam para o principal
+func (h *UnificacaoHandler) Unificar(c echo.Context) error {
+	var req unificarRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Corpo da requisição inválido",
+		})
+	}
+
+	unificacao, err := h.service.Unificar(c.Request().Context(), req.PrincipalID, req.DuplicadoID, req.Motivo)
+	if err != nil {
+		return erroUnificacao(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, unificacao)
+}
+
+// Desfazer restaura o cadastro duplicado e devolve a ele as referências movidas
+func (h *UnificacaoHandler) Desfazer(c echo.Context) error {
+	unificacao, err := h.service.Desfazer(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return erroUnificacao(c, err)
+	}
+
+	return c.JSON(http.StatusOK, unificacao)
+}
+
+// Listar retorna as unificações, opcionalmente filtradas por status (?status=ATIVA)
+func (h *UnificacaoHandler) Listar(c echo.Context) error {
+	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
+	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+
+	result, err := h.service.Listar(c.Request().Context(), domain.StatusUnificacao(c.QueryParam("status")), pagina, porPagina)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar unificações",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ListarRevisoes retorna os casos de identidade ambígua aguardando revisão (?status=PENDENTE por padrão)
+func (h *UnificacaoHandler) ListarRevisoes(c echo.Context) error {
+	pagina, _ := strconv.Atoi(c.QueryParam("pagina"))
+	porPagina, _ := strconv.Atoi(c.QueryParam("porPagina"))
+
+	status := domain.StatusRevisao(c.QueryParam("status"))
+	if status == "" {
+		status = domain.StatusRevisaoPendente
+	}
+
+	result, err := h.service.ListarRevisoes(c.Request().Context(), status, pagina, porPagina)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Erro ao listar revisões",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// erroUnificacao converte os erros de unificação no status HTTP correspondente
+func erroUnificacao(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrIDInvalido), errors.Is(err, services.ErrUnificacaoMesmoID):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrPoliticoNaoEncontrado), errors.Is(err, repository.ErrUnificacaoNaoEncontrada):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrUnificacaoDesfeita), errors.Is(err, repository.ErrUnificacaoDependente):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		return c.JSON(status, map[string]string{
+			"error": "Erro ao processar unificação",
+		})
+	}
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,87 @@
+package identidade
+
+import (
+	"sort"
+	"strings"
+)
+
+var semAcento = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// particulas são ignoradas na comparação de nomes
+var particulas = map[string]bool{
+	"DE": true, "DA": true, "DO": true, "DAS": true, "DOS": true, "E": true,
+}
+
+// Tokens normaliza o nome (maiúsculas, sem acentos e pontuação) e retorna suas partes,
+// sem as partículas de ligação
+func Tokens(nome string) []string {
+	nome = semAcento.Replace(strings.ToUpper(nome))
+
+	partes := strings.FieldsFunc(nome, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+	})
+
+	tokens := partes[:0]
+	for _, p := range partes {
+		if !particulas[p] {
+			tokens = append(tokens, p)
+		}
+	}
+	return tokens
+}
+
+// ChaveNome retorna uma chave que independe de acentos, caixa e ordem das partes do nome.
+// "Silva, João Pedro da" e "JOAO PEDRO SILVA" geram a mesma chave.
+func ChaveNome(nome string) string {
+	tokens := Tokens(nome)
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// Semelhanca indica o quanto dois nomes se correspondem
+type Semelhanca int
+
+const (
+	Diferentes Semelhanca = iota
+	Parcial               // Um nome contém todas as partes do outro (ex.: nome abreviado)
+	Exata                 // Mesmas partes, em qualquer ordem
+)
+
+// CompararNomes compara dois nomes ignorando acentos, caixa, partículas e ordem
+func CompararNomes(a, b string) Semelhanca {
+	ta, tb := Tokens(a), Tokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return Diferentes
+	}
+	if ChaveNome(a) == ChaveNome(b) {
+		return Exata
+	}
+
+	menor, maior := ta, tb
+	if len(menor) > len(maior) {
+		menor, maior = maior, menor
+	}
+	// Um único nome em comum não basta para sugerir a mesma pessoa
+	if len(menor) < 2 {
+		return Diferentes
+	}
+
+	partes := make(map[string]int, len(maior))
+	for _, t := range maior {
+		partes[t]++
+	}
+	for _, t := range menor {
+		if partes[t] == 0 {
+			return Diferentes
+		}
+		partes[t]--
+	}
+	return Parcial
+}
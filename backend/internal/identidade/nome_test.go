@@ -0,0 +1,59 @@
+package identidade
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	for nome, esperado := range map[string]string{
+		"João Pedro da Silva":       "JOAO PEDRO SILVA",
+		"  MARIA DAS GRAÇAS  e Sá ": "MARIA GRACAS SA",
+		"Silva, João Pedro":         "SILVA JOAO PEDRO",
+		"Zé do Caixão (Dr.) 2º":     "ZE CAIXAO DR 2",
+		"Antônio Ñúñez D'Ávila":     "ANTONIO NUNEZ D AVILA",
+		"":                          "",
+		"de da do":                  "",
+	} {
+		if tokens := strings.Join(Tokens(nome), " "); tokens != esperado {
+			t.Errorf("Tokens(%q) = %q; esperado %q", nome, tokens, esperado)
+		}
+	}
+}
+
+func TestChaveNome(t *testing.T) {
+	iguais := [][2]string{
+		{"Silva, João Pedro da", "JOAO PEDRO SILVA"},
+		{"José de Sá", "SA JOSE"},
+		{"Ana Lúcia", "ana  lucia"},
+	}
+	for _, par := range iguais {
+		if a, b := ChaveNome(par[0]), ChaveNome(par[1]); a != b {
+			t.Errorf("ChaveNome(%q) = %q, ChaveNome(%q) = %q; esperadas iguais", par[0], a, par[1], b)
+		}
+	}
+	if ChaveNome("João Silva") == ChaveNome("João Souza") {
+		t.Error("nomes diferentes geraram a mesma chave")
+	}
+}
+
+func TestCompararNomes(t *testing.T) {
+	for _, caso := range []struct {
+		a, b     string
+		esperado Semelhanca
+	}{
+		{"João Pedro da Silva", "SILVA JOAO PEDRO", Exata},
+		{"Maria das Graças Souza", "maria gracas souza", Exata},
+		{"Maria Souza", "Maria das Graças Souza", Parcial},
+		{"Maria das Graças Souza", "Maria Souza", Parcial},
+		{"Maria", "Maria Souza", Diferentes}, // Um único nome em comum não basta
+		{"Maria Souza", "Maria Santos", Diferentes},
+		{"João João", "João Silva", Diferentes}, // Cada parte só casa uma vez
+		{"", "Maria Souza", Diferentes},
+		{"de", "da", Diferentes},
+	} {
+		if s := CompararNomes(caso.a, caso.b); s != caso.esperado {
+			t.Errorf("CompararNomes(%q, %q) = %d; esperado %d", caso.a, caso.b, s, caso.esperado)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package identidade decide se um político recebido de uma fonte (Câmara, Senado, TSE...)
+// já existe no banco, para que a mesma pessoa tenha um único cadastro com todo o seu histórico.
+package identidade
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/pkg/documento"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Candidato reúne os dados de identificação de um político vindos de uma fonte
+type Candidato struct {
+	Fonte          string // Sincronizador de origem (camara, senado...)
+	IDCamara       int
+	IDSenado       string
+	IDTSE          string
+	CPF            string
+	Nome           string
+	NomeCivil      string
+	DataNascimento time.Time
+}
+
+func (c Candidato) idExterno() string {
+	switch {
+	case c.IDCamara != 0:
+		return fmt.Sprint(c.IDCamara)
+	case c.IDSenado != "":
+		return c.IDSenado
+	default:
+		return c.IDTSE
+	}
+}
+
+// Resolvedor localiza o cadastro existente de um político e registra os casos ambíguos para revisão
+type Resolvedor struct {
+	politicos   *mongo.Collection
+	revisoes    *mongo.Collection
+	unificacoes *mongo.Collection
+}
+
+func New(db *mongo.Database) *Resolvedor {
+	return &Resolvedor{
+		politicos:   db.Collection("politicos"),
+		revisoes:    db.Collection("revisoes_identidade"),
+		unificacoes: db.Collection("unificacoes"),
+	}
+}
+
+// Resolver retorna o político que corresponde ao candidato, ou nil se for um político novo.
+// A busca usa, em ordem: IDs externos, CPF, unificações manuais e nome civil com data de nascimento. Nomes que
+// coincidem só parcialmente, ou mais de um político possível, vão para a fila de revisão e
+// não são unificados automaticamente.
+func (r *Resolvedor) Resolver(ctx context.Context, c Candidato) (*domain.Politico, error) {
+	for _, filter := range filtrosExatos(c) {
+		var politico domain.Politico
+		err := r.politicos.FindOne(ctx, filter).Decode(&politico)
+		if err == nil {
+			return &politico, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+	}
+
+	// Cadastros removidos por unificação manual continuam apontando para o principal
+	if politico, err := r.buscarUnificado(ctx, c); politico != nil || err != nil {
+		return politico, err
+	}
+
+	if c.DataNascimento.IsZero() || (c.NomeCivil == "" && c.Nome == "") {
+		return nil, nil
+	}
+
+	// A data de nascimento pode variar em um dia entre fontes por causa do fuso horário
+	cursor, err := r.politicos.Find(ctx, bson.M{
+		"data_nascimento": bson.M{
+			"$gte": c.DataNascimento.AddDate(0, 0, -1),
+			"$lte": c.DataNascimento.AddDate(0, 0, 1),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var possiveis []domain.Politico
+	if err := cursor.All(ctx, &possiveis); err != nil {
+		return nil, err
+	}
+
+	var exatos, parciais []domain.Politico
+	for _, p := range possiveis {
+		switch melhorSemelhanca(c, p) {
+		case Exata:
+			exatos = append(exatos, p)
+		case Parcial:
+			parciais = append(parciais, p)
+		}
+	}
+
+	switch {
+	case len(exatos) == 1:
+		return &exatos[0], nil
+	case len(exatos) > 1:
+		r.enfileirar(ctx, c, exatos, "Mais de um político com o mesmo nome e data de nascimento")
+	case len(parciais) > 0:
+		r.enfileirar(ctx, c, parciais, "Nome parcialmente igual com a mesma data de nascimento")
+	}
+
+	return nil, nil
+}
+
+// buscarUnificado procura o candidato entre os cadastros duplicados de unificações ativas
+// e retorna o político principal correspondente
+func (r *Resolvedor) buscarUnificado(ctx context.Context, c Candidato) (*domain.Politico, error) {
+	for _, filter := range filtrosExatos(c) {
+		unificado := bson.M{"status": domain.StatusUnificacaoAtiva}
+		for campo, valor := range filter {
+			unificado["duplicado."+campo] = valor
+		}
+
+		var unificacao domain.Unificacao
+		err := r.unificacoes.FindOne(ctx, unificado).Decode(&unificacao)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var politico domain.Politico
+		err = r.politicos.FindOne(ctx, bson.M{"_id": unificacao.PrincipalID}).Decode(&politico)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &politico, nil
+	}
+	return nil, nil
+}
+
+// filtrosExatos lista as buscas por identificadores únicos, da mais para a menos confiável
+func filtrosExatos(c Candidato) []bson.M {
+	var filtros []bson.M
+	if c.IDCamara != 0 {
+		filtros = append(filtros, bson.M{"id_externo_camara": c.IDCamara})
+	}
+	if c.IDSenado != "" {
+		filtros = append(filtros, bson.M{"id_externo_senado": c.IDSenado})
+	}
+	if c.IDTSE != "" {
+		filtros = append(filtros, bson.M{"id_externo_tse": c.IDTSE})
+	}
+	if cpf := documento.Normalizar(c.CPF); len(cpf) == 11 {
+		// O CPF é gravado só com os dígitos, mas cadastros antigos podem tê-lo com pontuação
+		filtros = append(filtros, bson.M{"cpf": bson.M{"$in": bson.A{cpf, formatarCPF(cpf)}}})
+	}
+	return filtros
+}
+
+// formatarCPF retorna os 11 dígitos do CPF no formato 000.000.000-00
+func formatarCPF(cpf string) string {
+	return cpf[:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+}
+
+// melhorSemelhanca compara os nomes do candidato com o nome civil e o nome parlamentar do político
+func melhorSemelhanca(c Candidato, p domain.Politico) Semelhanca {
+	melhor := Diferentes
+	for _, a := range []string{c.NomeCivil, c.Nome} {
+		for _, b := range []string{p.NomeCivil, p.Nome} {
+			if a == "" || b == "" {
+				continue
+			}
+			if s := CompararNomes(a, b); s > melhor {
+				melhor = s
+			}
+		}
+	}
+	return melhor
+}
+
+// enfileirar registra o caso para revisão manual; a mesma pessoa da mesma fonte gera um único item
+func (r *Resolvedor) enfileirar(ctx context.Context, c Candidato, candidatos []domain.Politico, motivo string) {
+	ids := make([]primitive.ObjectID, len(candidatos))
+	for i, p := range candidatos {
+		ids[i] = p.ID
+	}
+
+	filter := bson.M{
+		"fonte":           c.Fonte,
+		"nome_civil":      c.NomeCivil,
+		"data_nascimento": c.DataNascimento,
+		"status":          domain.StatusRevisaoPendente,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"id_externo":     c.idExterno(),
+			"nome":           c.Nome,
+			"candidatos_ids": ids,
+			"motivo":         motivo,
+			"updated_at":     time.Now(),
+		},
+		"$setOnInsert": bson.M{
+			"created_at": time.Now(),
+		},
+	}
+
+	_, err := r.revisoes.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Printf("⚠️ Erro ao registrar revisão de identidade: %v", err)
+		return
+	}
+	log.Printf("🔎 Identidade ambígua enviada para revisão: %s (%s)", c.NomeCivil, motivo)
+}
+
+// FiltroNovo retorna o filtro do upsert de um político que Resolver não encontrou.
+// Usa o identificador mais forte disponível para que execuções concorrentes não dupliquem o cadastro.
+func FiltroNovo(c Candidato) bson.M {
+	if filtros := filtrosExatos(c); len(filtros) > 0 {
+		return filtros[0]
+	}
+	return bson.M{
+		"nome_civil":      c.NomeCivil,
+		"data_nascimento": c.DataNascimento,
+	}
+}
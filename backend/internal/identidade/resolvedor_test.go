@@ -0,0 +1,80 @@
+package identidade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// O CPF encontra o cadastro com ou sem pontuação, dos dois lados
+func TestResolverPorCPF(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politicos := db.Collection("politicos")
+
+	normalizado := domain.Politico{ID: primitive.NewObjectID(), CPF: "11144477735", Nome: "Helena Duarte"}
+	antigo := domain.Politico{ID: primitive.NewObjectID(), CPF: "529.982.247-25", Nome: "Rui Prado"}
+	for _, p := range []domain.Politico{normalizado, antigo} {
+		if _, err := politicos.InsertOne(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := New(db)
+	for _, caso := range []struct {
+		cpf      string
+		esperado primitive.ObjectID
+	}{
+		{"11144477735", normalizado.ID},
+		{"111.444.777-35", normalizado.ID},
+		{"52998224725", antigo.ID}, // Gravado com pontuação antes da normalização
+		{"529.982.247-25", antigo.ID},
+		{"123.456.789-09", primitive.NilObjectID},
+		{"111.444", primitive.NilObjectID}, // CPF incompleto não identifica ninguém
+	} {
+		// Sem data de nascimento, só o CPF pode encontrar o cadastro
+		politico, err := r.Resolver(ctx, Candidato{Fonte: "teste", CPF: caso.cpf, Nome: "Outro Nome"})
+		if err != nil {
+			t.Fatalf("Resolver(%s): %v", caso.cpf, err)
+		}
+		var encontrado primitive.ObjectID
+		if politico != nil {
+			encontrado = politico.ID
+		}
+		if encontrado != caso.esperado {
+			t.Errorf("Resolver(%s) = %s; esperado %s", caso.cpf, encontrado.Hex(), caso.esperado.Hex())
+		}
+	}
+}
+
+// O upsert de um político novo identificado pelo CPF não duplica o cadastro quando repetido
+func TestFiltroNovoPorCPF(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politicos := db.Collection("politicos")
+
+	c := Candidato{Fonte: "governadores", CPF: "111.444.777-35", Nome: "Helena Duarte"}
+	for i := 0; i < 3; i++ {
+		update := bson.M{
+			"$set":         bson.M{"cpf": "11144477735", "nome": c.Nome, "updated_at": time.Now()},
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+		}
+		if _, err := politicos.UpdateOne(ctx, FiltroNovo(c), update, options.Update().SetUpsert(true)); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+	}
+
+	total, err := politicos.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Errorf("%d cadastros depois de três upserts; esperado 1", total)
+	}
+}
@@ -0,0 +1,154 @@
+// Package limite controla o uso da API pública: um balde de fichas por cliente (chave de API
+// ou IP) limita as rajadas e uma cota diária limita o volume total.
+package limite
+
+import (
+	"context"
+	"log"
+	"math"
+	gosync "sync"
+	"time"
+)
+
+// fusoBrasilia define a virada do dia das cotas (Brasília não tem horário de verão desde 2019)
+var fusoBrasilia = time.FixedZone("BRT", -3*60*60)
+
+// Regra define os limites aplicados a um cliente
+type Regra struct {
+	PorMinuto  int // Fichas repostas por minuto
+	Rajada     int // Capacidade do balde; zero usa PorMinuto
+	CotaDiaria int // Requisições por dia; zero desativa a cota
+}
+
+func (r Regra) capacidade() float64 {
+	if r.Rajada > 0 {
+		return float64(r.Rajada)
+	}
+	return float64(r.PorMinuto)
+}
+
+// taxa é a reposição de fichas por milissegundo
+func (r Regra) taxa() float64 {
+	return float64(r.PorMinuto) / float64(time.Minute/time.Millisecond)
+}
+
+// Resultado é a decisão sobre uma requisição e o estado dos limites do cliente depois dela
+type Resultado struct {
+	Permitido    bool
+	Limite       int
+	Restantes    int
+	Reinicio     time.Time // Quando o balde estará cheio de novo
+	CotaDiaria   int
+	CotaRestante int
+	CotaReinicio time.Time
+	TentarEm     time.Duration // Espera sugerida quando a requisição é recusada
+}
+
+// estado é o que cada armazenamento devolve após consumir uma ficha
+type estado struct {
+	permitido bool
+	fichas    float64
+	usados    int
+}
+
+// armazem guarda os baldes e cotas. Com gastar false, só diz se haveria ficha, sem consumi-la.
+type armazem interface {
+	consumir(ctx context.Context, cliente string, regra Regra, agora time.Time, gastar bool) (estado, error)
+}
+
+// Contador aplica as regras de uso guardando os contadores no Redis. Se o Redis estiver
+// indisponível, usa contadores em memória, válidos apenas para esta instância da API.
+type Contador struct {
+	principal armazem
+	reserva   armazem
+
+	mu        gosync.Mutex
+	avisadoEm time.Time
+}
+
+// NewContadorMemoria cria um contador que guarda tudo em memória
+func NewContadorMemoria() *Contador {
+	return &Contador{principal: newMemoria()}
+}
+
+// Consumir registra uma requisição do cliente e diz se ela pode prosseguir
+func (c *Contador) Consumir(ctx context.Context, cliente string, regra Regra) Resultado {
+	return c.usar(ctx, cliente, regra, true)
+}
+
+// Consultar diz se o cliente ainda tem fichas, sem consumir nenhuma
+func (c *Contador) Consultar(ctx context.Context, cliente string, regra Regra) Resultado {
+	return c.usar(ctx, cliente, regra, false)
+}
+
+func (c *Contador) usar(ctx context.Context, cliente string, regra Regra, gastar bool) Resultado {
+	agora := time.Now()
+	if regra.PorMinuto <= 0 {
+		return Resultado{Permitido: true}
+	}
+
+	e, err := c.principal.consumir(ctx, cliente, regra, agora, gastar)
+	if err != nil && c.reserva != nil {
+		c.avisar(err)
+		e, err = c.reserva.consumir(ctx, cliente, regra, agora, gastar)
+	}
+	if err != nil {
+		// Sem como contar, não bloqueia o acesso à API
+		return Resultado{Permitido: true}
+	}
+
+	return resultado(e, regra, agora)
+}
+
+// avisar registra a falha do Redis no máximo uma vez por minuto
+func (c *Contador) avisar(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.avisadoEm) < time.Minute {
+		return
+	}
+	c.avisadoEm = time.Now()
+	log.Printf("⚠️  Limite de uso: Redis indisponível, usando contadores em memória: %v", err)
+}
+
+func resultado(e estado, regra Regra, agora time.Time) Resultado {
+	capacidade := regra.capacidade()
+	faltam := time.Duration((capacidade-e.fichas)/regra.taxa()) * time.Millisecond
+
+	r := Resultado{
+		Permitido: e.permitido,
+		Limite:    int(capacidade),
+		Restantes: int(math.Floor(e.fichas)),
+		Reinicio:  agora.Add(faltam),
+	}
+
+	if regra.CotaDiaria > 0 {
+		r.CotaDiaria = regra.CotaDiaria
+		r.CotaRestante = regra.CotaDiaria - e.usados
+		if r.CotaRestante < 0 {
+			r.CotaRestante = 0
+		}
+		r.CotaReinicio = fimDoDia(agora)
+	}
+
+	if !e.permitido {
+		if regra.CotaDiaria > 0 && e.usados >= regra.CotaDiaria {
+			r.TentarEm = r.CotaReinicio.Sub(agora)
+		} else {
+			r.TentarEm = time.Duration((1-e.fichas)/regra.taxa()) * time.Millisecond
+		}
+	}
+
+	return r
+}
+
+// fimDoDia retorna a meia-noite seguinte no horário de Brasília
+func fimDoDia(agora time.Time) time.Time {
+	local := agora.In(fusoBrasilia)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, fusoBrasilia)
+}
+
+// dia identifica o dia da cota no horário de Brasília
+func dia(agora time.Time) string {
+	return agora.In(fusoBrasilia).Format("20060102")
+}
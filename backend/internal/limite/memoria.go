@@ -0,0 +1,85 @@
+package limite
+
+import (
+	"context"
+	"math"
+	gosync "sync"
+	"time"
+)
+
+type balde struct {
+	fichas     float64
+	atualizado time.Time
+	dia        string
+	usados     int
+}
+
+// memoria guarda os contadores no processo; cada instância da API conta separadamente
+type memoria struct {
+	mu      gosync.Mutex
+	baldes  map[string]*balde
+	limpeza time.Time
+}
+
+func newMemoria() *memoria {
+	return &memoria{baldes: make(map[string]*balde)}
+}
+
+func (m *memoria) consumir(_ context.Context, cliente string, regra Regra, agora time.Time, gastar bool) (estado, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limpar(agora)
+
+	capacidade := regra.capacidade()
+	hoje := dia(agora)
+
+	b, ok := m.baldes[cliente]
+	if !ok {
+		if !gastar {
+			return estado{permitido: true, fichas: capacidade}, nil
+		}
+		b = &balde{fichas: capacidade, atualizado: agora, dia: hoje}
+		m.baldes[cliente] = b
+	}
+	if b.dia != hoje {
+		b.dia = hoje
+		b.usados = 0
+	}
+
+	decorrido := float64(agora.Sub(b.atualizado) / time.Millisecond)
+	if decorrido > 0 {
+		b.fichas = math.Min(capacidade, b.fichas+decorrido*regra.taxa())
+	}
+	b.atualizado = agora
+
+	if regra.CotaDiaria > 0 && b.usados >= regra.CotaDiaria {
+		return estado{permitido: false, fichas: b.fichas, usados: b.usados}, nil
+	}
+	if b.fichas < 1 {
+		return estado{permitido: false, fichas: b.fichas, usados: b.usados}, nil
+	}
+	if !gastar {
+		return estado{permitido: true, fichas: b.fichas, usados: b.usados}, nil
+	}
+
+	b.fichas--
+	b.usados++
+	return estado{permitido: true, fichas: b.fichas, usados: b.usados}, nil
+}
+
+// limpar descarta, no máximo uma vez por minuto, os clientes de dias anteriores.
+// Os do dia precisam ser mantidos para não zerar a cota diária.
+func (m *memoria) limpar(agora time.Time) {
+	if agora.Sub(m.limpeza) < time.Minute {
+		return
+	}
+	m.limpeza = agora
+
+	hoje := dia(agora)
+	for cliente, b := range m.baldes {
+		if b.dia != hoje {
+			delete(m.baldes, cliente)
+		}
+	}
+}
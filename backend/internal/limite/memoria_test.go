@@ -0,0 +1,86 @@
+package limite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBaldeReposicaoERajada(t *testing.T) {
+	m := newMemoria()
+	ctx := context.Background()
+	regra := Regra{PorMinuto: 60, Rajada: 3}
+	agora := time.Date(2026, 3, 10, 12, 0, 0, 0, fusoBrasilia)
+
+	// A rajada permite três requisições seguidas e recusa a quarta
+	for i := 1; i <= 4; i++ {
+		e, _ := m.consumir(ctx, "c", regra, agora, true)
+		if e.permitido != (i <= 3) {
+			t.Fatalf("requisição %d: permitido = %v", i, e.permitido)
+		}
+	}
+
+	// 60 por minuto repõem uma ficha por segundo, sem passar da capacidade
+	if e, _ := m.consumir(ctx, "c", regra, agora.Add(500*time.Millisecond), true); e.permitido {
+		t.Error("meio segundo depois ainda não há ficha")
+	}
+	if e, _ := m.consumir(ctx, "c", regra, agora.Add(time.Second), true); !e.permitido {
+		t.Error("um segundo depois deveria haver uma ficha")
+	}
+	e, _ := m.consumir(ctx, "c", regra, agora.Add(time.Hour), false)
+	if e.fichas != 3 {
+		t.Errorf("balde com %v fichas depois de uma hora; esperado cheio (3)", e.fichas)
+	}
+
+	// Os clientes são contados separadamente
+	if e, _ := m.consumir(ctx, "outro", regra, agora, true); !e.permitido {
+		t.Error("outro cliente não deveria ser afetado")
+	}
+}
+
+func TestCotaDiariaViraNaMeiaNoiteDeBrasilia(t *testing.T) {
+	m := newMemoria()
+	ctx := context.Background()
+	regra := Regra{PorMinuto: 600, CotaDiaria: 2}
+
+	// 23h59 de Brasília já é o dia seguinte em UTC
+	antes := time.Date(2026, 3, 10, 23, 59, 0, 0, fusoBrasilia)
+	for i := 1; i <= 3; i++ {
+		e, _ := m.consumir(ctx, "c", regra, antes, true)
+		if e.permitido != (i <= 2) {
+			t.Fatalf("requisição %d: permitido = %v", i, e.permitido)
+		}
+	}
+	if r := resultado(estado{usados: 2}, regra, antes); r.TentarEm != time.Minute {
+		t.Errorf("TentarEm = %v; esperado o minuto que falta para a meia-noite", r.TentarEm)
+	}
+
+	depois := antes.Add(time.Minute)
+	if e, _ := m.consumir(ctx, "c", regra, depois, true); !e.permitido || e.usados != 1 {
+		t.Errorf("depois da meia-noite: %+v; esperada a cota zerada", e)
+	}
+}
+
+func TestConsultarNaoConsome(t *testing.T) {
+	m := newMemoria()
+	ctx := context.Background()
+	regra := Regra{PorMinuto: 60, Rajada: 1}
+	agora := time.Now()
+
+	if e, _ := m.consumir(ctx, "c", regra, agora, false); !e.permitido {
+		t.Fatal("cliente novo deveria ter fichas")
+	}
+	if len(m.baldes) != 0 {
+		t.Error("a consulta não deve criar o balde do cliente")
+	}
+
+	for i := 0; i < 3; i++ {
+		if e, _ := m.consumir(ctx, "c", regra, agora, false); !e.permitido {
+			t.Fatal("consultas não deveriam gastar a ficha")
+		}
+	}
+	m.consumir(ctx, "c", regra, agora, true)
+	if e, _ := m.consumir(ctx, "c", regra, agora, false); e.permitido {
+		t.Error("sem fichas, a consulta deveria recusar")
+	}
+}
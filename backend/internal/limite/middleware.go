@@ -0,0 +1,124 @@
+package limite
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lupa-cidada/backend/internal/auth"
+)
+
+// Regras são os limites padrão para clientes anônimos (por IP) e com chave de API
+type Regras struct {
+	Anonimo Regra
+	Chave   Regra
+}
+
+// Middleware limita as requisições de cada cliente e informa o estado dos limites nos
+// cabeçalhos X-RateLimit-*. Deve vir depois de auth.Identificar, para reconhecer as chaves.
+func Middleware(contador *Contador, regras Regras) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cliente, regra := "ip:"+c.RealIP(), regras.Anonimo
+			if id := auth.IdentidadeDe(c); id != nil {
+				cliente, regra = "chave:"+id.ID, regras.Chave
+				if id.Limites != nil {
+					if id.Limites.PorMinuto > 0 {
+						regra.PorMinuto = id.Limites.PorMinuto
+						regra.Rajada = 0
+					}
+					if id.Limites.CotaDiaria > 0 {
+						regra.CotaDiaria = id.Limites.CotaDiaria
+					}
+				}
+			}
+
+			r := contador.Consumir(c.Request().Context(), cliente, regra)
+			escreverCabecalhos(c.Response().Header(), r)
+
+			if !r.Permitido {
+				return excedido(c, r)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Falhas limita por IP as credenciais recusadas (401): quem esgota as tentativas recebe 429
+// sem que a credencial seja conferida no banco. Deve vir antes de auth.Identificar, que recusa
+// as credenciais inválidas antes que Middleware conte a requisição.
+func Falhas(contador *Contador, regra Regra) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cabecalhos := c.Request().Header
+			if cabecalhos.Get(auth.HeaderChaveAPI) == "" && cabecalhos.Get(echo.HeaderAuthorization) == "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			cliente := "falhas:" + c.RealIP()
+			if r := contador.Consultar(ctx, cliente, regra); !r.Permitido {
+				return excedido(c, r)
+			}
+
+			err := next(c)
+			if c.Response().Status == http.StatusUnauthorized {
+				contador.Consumir(ctx, cliente, regra)
+			}
+			return err
+		}
+	}
+}
+
+// ExtratorIP define de onde vem o IP que identifica os clientes anônimos. Sem proxies, é o IP
+// da conexão, porque X-Forwarded-For e X-Real-IP podem ser enviados por qualquer cliente. Com
+// proxies (faixas CIDR, ex.: 10.0.0.0/8), o IP é lido do X-Forwarded-For, confiando só neles.
+func ExtratorIP(proxies []string) (echo.IPExtractor, error) {
+	var confiaveis []echo.TrustOption
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		_, faixa, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy confiável inválido %q: %w", proxy, err)
+		}
+		confiaveis = append(confiaveis, echo.TrustIPRange(faixa))
+	}
+	if len(confiaveis) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Por padrão o Echo confia em qualquer rede privada; aqui, só nas faixas configuradas
+	opcoes := append([]echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}, confiaveis...)
+	return echo.ExtractIPFromXFFHeader(opcoes...), nil
+}
+
+func excedido(c echo.Context, r Resultado) error {
+	segundos := int(math.Ceil(r.TentarEm.Seconds()))
+	c.Response().Header().Set("Retry-After", strconv.Itoa(segundos))
+	return c.JSON(http.StatusTooManyRequests, map[string]string{
+		"error": "Limite de requisições excedido",
+	})
+}
+
+func escreverCabecalhos(h http.Header, r Resultado) {
+	if r.Limite == 0 {
+		return
+	}
+	h.Set("X-RateLimit-Limit", strconv.Itoa(r.Limite))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(r.Restantes))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(r.Reinicio.Unix(), 10))
+
+	if r.CotaDiaria > 0 {
+		h.Set("X-RateLimit-Daily-Limit", strconv.Itoa(r.CotaDiaria))
+		h.Set("X-RateLimit-Daily-Remaining", strconv.Itoa(r.CotaRestante))
+		h.Set("X-RateLimit-Daily-Reset", strconv.FormatInt(r.CotaReinicio.Unix(), 10))
+	}
+}
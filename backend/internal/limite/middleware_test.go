@@ -0,0 +1,91 @@
+package limite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestExtratorIP(t *testing.T) {
+	requisicao := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.5:4000"
+		req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.9")
+		req.Header.Set(echo.HeaderXRealIP, "203.0.113.9")
+		return req
+	}
+
+	// Sem proxies configurados, os cabeçalhos enviados pelo cliente são ignorados
+	direto, err := ExtratorIP(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip := direto(requisicao()); ip != "10.0.0.5" {
+		t.Errorf("sem proxies: %s; esperado o IP da conexão", ip)
+	}
+
+	proxy, err := ExtratorIP([]string{"10.0.0.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip := proxy(requisicao()); ip != "203.0.113.9" {
+		t.Errorf("via proxy confiável: %s; esperado o IP do X-Forwarded-For", ip)
+	}
+
+	// Uma rede privada fora das faixas configuradas não é confiável
+	req := requisicao()
+	req.RemoteAddr = "192.168.0.7:4000"
+	if ip := proxy(req); ip != "192.168.0.7" {
+		t.Errorf("via proxy desconhecido: %s; esperado o IP da conexão", ip)
+	}
+
+	if _, err := ExtratorIP([]string{"10.0.0.300/8"}); err == nil {
+		t.Error("faixa inválida deveria dar erro")
+	}
+}
+
+func TestFalhasLimitaCredenciaisRecusadas(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	consultas := 0
+	e.GET("/", func(c echo.Context) error {
+		consultas++
+		if c.Request().Header.Get("X-API-Key") == "lc_valida" {
+			return c.NoContent(http.StatusOK)
+		}
+		return c.NoContent(http.StatusUnauthorized)
+	}, Falhas(NewContadorMemoria(), Regra{PorMinuto: 2}))
+
+	pedir := func(chave, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = ip + ":4000"
+		if chave != "" {
+			req.Header.Set("X-API-Key", chave)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i, esperado := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if status := pedir("lc_inventada", "198.51.100.1"); status != esperado {
+			t.Errorf("tentativa %d: status %d; esperado %d", i+1, status, esperado)
+		}
+	}
+	if consultas != 2 {
+		t.Errorf("%d credenciais conferidas; a bloqueada não deveria chegar à autenticação", consultas)
+	}
+
+	// O bloqueio vale para qualquer credencial daquele IP, mas não para os anônimos nem outros IPs
+	if status := pedir("lc_valida", "198.51.100.1"); status != http.StatusTooManyRequests {
+		t.Errorf("chave válida do IP bloqueado: status %d", status)
+	}
+	if status := pedir("", "198.51.100.1"); status != http.StatusUnauthorized {
+		t.Errorf("sem credencial: status %d; esperado passar adiante", status)
+	}
+	if status := pedir("lc_valida", "198.51.100.2"); status != http.StatusOK {
+		t.Errorf("outro IP: status %d", status)
+	}
+}
@@ -0,0 +1,119 @@
+package limite
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scriptConsumir atualiza o balde e a cota do cliente numa única operação atômica.
+// KEYS: balde, cota do dia. ARGV: capacidade, taxa (fichas/ms), agora (ms), cota, fim do dia (ms),
+// gastar (1 consome a ficha; 0 só consulta, sem alterar nada).
+var scriptConsumir = redis.NewScript(`
+local capacidade = tonumber(ARGV[1])
+local taxa = tonumber(ARGV[2])
+local agora = tonumber(ARGV[3])
+local cota = tonumber(ARGV[4])
+
+local atual = redis.call('HMGET', KEYS[1], 'fichas', 'ts')
+local fichas = tonumber(atual[1]) or capacidade
+local ts = tonumber(atual[2]) or agora
+if agora > ts then
+  fichas = math.min(capacidade, fichas + (agora - ts) * taxa)
+end
+
+local usados = tonumber(redis.call('GET', KEYS[2]) or '0')
+if ARGV[6] ~= '1' then
+  local disponivel = 0
+  if (cota <= 0 or usados < cota) and fichas >= 1 then
+    disponivel = 1
+  end
+  return {disponivel, tostring(fichas), usados}
+end
+
+local permitido = 0
+if (cota <= 0 or usados < cota) and fichas >= 1 then
+  fichas = fichas - 1
+  permitido = 1
+  if cota > 0 then
+    usados = redis.call('INCR', KEYS[2])
+    if usados == 1 then
+      redis.call('PEXPIREAT', KEYS[2], ARGV[5])
+    end
+  end
+end
+
+redis.call('HSET', KEYS[1], 'fichas', tostring(fichas), 'ts', agora)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacidade / taxa) + 1000)
+return {permitido, tostring(fichas), usados}
+`)
+
+type redisArmazem struct {
+	client *redis.Client
+}
+
+// NewContador cria um contador no Redis do endereço informado (ex.: redis://localhost:6379).
+// Se o Redis não responder, o contador começa em memória e volta a tentar o Redis a cada requisição.
+func NewContador(ctx context.Context, redisURI string) *Contador {
+	opts, err := redis.ParseURL(redisURI)
+	if err != nil {
+		log.Printf("⚠️  Limite de uso: REDIS_URI inválida, usando contadores em memória: %v", err)
+		return NewContadorMemoria()
+	}
+
+	client := redis.NewClient(opts)
+	c := &Contador{principal: &redisArmazem{client: client}, reserva: newMemoria()}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		c.avisar(err)
+	} else {
+		log.Println("🚦 Limite de uso: contadores no Redis")
+	}
+	return c
+}
+
+func (r *redisArmazem) consumir(ctx context.Context, cliente string, regra Regra, agora time.Time, gastar bool) (estado, error) {
+	chaves := []string{
+		"lupa:limite:" + cliente,
+		"lupa:cota:" + cliente + ":" + dia(agora),
+	}
+
+	flag := 0
+	if gastar {
+		flag = 1
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+
+	res, err := scriptConsumir.Run(ctx, r.client, chaves,
+		regra.capacidade(),
+		strconv.FormatFloat(regra.taxa(), 'f', -1, 64),
+		agora.UnixMilli(),
+		regra.CotaDiaria,
+		fimDoDia(agora).UnixMilli(),
+		flag,
+	).Slice()
+	if err != nil {
+		return estado{}, err
+	}
+	if len(res) != 3 {
+		return estado{}, fmt.Errorf("resposta inesperada do Redis: %v", res)
+	}
+
+	permitido, _ := res[0].(int64)
+	fichasStr, _ := res[1].(string)
+	usados, _ := res[2].(int64)
+	fichas, err := strconv.ParseFloat(fichasStr, 64)
+	if err != nil {
+		return estado{}, err
+	}
+
+	return estado{permitido: permitido == 1, fichas: fichas, usados: int(usados)}, nil
+}
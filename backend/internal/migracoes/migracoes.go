@@ -0,0 +1,163 @@
+// Package migracoes aplica as correções de dados que precisam rodar uma vez só em cada banco,
+// como apagar registros de um formato antigo. A API, o worker e o cmd/sync aplicam as
+// pendentes ao iniciar (a API sem esperar por elas), e cada migração aplicada fica
+// registrada na coleção migracoes.
+//
+// Os processos podem iniciar ao mesmo tempo, então uma migração pode rodar em dois deles
+// antes de ser registrada: as migrações devem ser idempotentes.
+package migracoes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lupa-cidada/backend/pkg/documento"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Colecao é o nome da coleção com as migrações aplicadas
+const Colecao = "migracoes"
+
+// tamanhoLote é quantas atualizações as migrações que percorrem coleções grandes enviam por vez
+const tamanhoLote = 1000
+
+// Migracao é uma correção de dados. O Nome a identifica na coleção e não deve mudar depois
+// de publicada; as novas vão no fim de Todas.
+type Migracao struct {
+	Nome      string
+	Descricao string
+	Aplicar   func(ctx context.Context, db *mongo.Database) error
+}
+
+// registro é uma migração aplicada
+type registro struct {
+	Nome       string    `bson:"_id"`
+	AplicadaEm time.Time `bson:"aplicada_em"`
+}
+
+// Todas são as migrações do projeto, na ordem em que são aplicadas
+var Todas = []Migracao{
+	{
+		Nome:      "0001_votos_sem_votacao_id_externo",
+		Descricao: "Apaga os votos gravados antes de existir o ID da votação, quando várias votações do mesmo dia e órgão eram fundidas em um só registro; a sincronização de votações os grava de novo",
+		Aplicar: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("votacoes").DeleteMany(ctx, bson.M{"votacao_id_externo": bson.M{"$exists": false}})
+			return err
+		},
+	},
+	{
+		Nome:      "0002_problemas_qualidade_por_chave",
+		Descricao: "Apaga os problemas de qualidade gravados uma vez por execução, antes da chave estável, e cria o índice único da chave; a próxima sincronização registra os que continuam",
+		Aplicar: func(ctx context.Context, db *mongo.Database) error {
+			problemas := db.Collection("problemas_qualidade")
+			if _, err := problemas.DeleteMany(ctx, bson.M{"chave": bson.M{"$exists": false}}); err != nil {
+				return err
+			}
+			_, err := problemas.Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "chave", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "visto_em", Value: -1}}},
+			})
+			return err
+		},
+	},
+	{
+		Nome:      "0003_cnpj_fornecedor_normalizado",
+		Descricao: "Grava só os dígitos do CPF/CNPJ dos fornecedores nas despesas sincronizadas antes da normalização, com o tipo do documento, para que o perfil e o ranking de fornecedores as encontrem",
+		Aplicar:   normalizarFornecedores,
+	},
+}
+
+// normalizarFornecedores corrige as despesas sem o tipo do documento do fornecedor, que são as
+// gravadas antes da normalização e as de documento inválido (estas já com os dígitos e sem
+// mudança). Rodar de novo só as confere outra vez. As atualizações vão em lotes, porque a
+// coleção de despesas tem centenas de milhares de documentos.
+func normalizarFornecedores(ctx context.Context, db *mongo.Database) error {
+	despesas := db.Collection("despesas")
+	cursor, err := despesas.Find(ctx,
+		bson.M{
+			"cnpj_fornecedor":           bson.M{"$ne": ""},
+			"tipo_documento_fornecedor": bson.M{"$exists": false},
+		},
+		options.Find().SetProjection(bson.M{"cnpj_fornecedor": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var lote []mongo.WriteModel
+	gravar := func() error {
+		if len(lote) == 0 {
+			return nil
+		}
+		_, err := despesas.BulkWrite(ctx, lote, options.BulkWrite().SetOrdered(false))
+		lote = lote[:0]
+		return err
+	}
+
+	for cursor.Next(ctx) {
+		var despesa struct {
+			ID             primitive.ObjectID `bson:"_id"`
+			CNPJFornecedor string             `bson:"cnpj_fornecedor"`
+		}
+		if err := cursor.Decode(&despesa); err != nil {
+			return err
+		}
+
+		cnpj, tipo := documento.Identificar(despesa.CNPJFornecedor)
+		set := bson.M{"cnpj_fornecedor": cnpj}
+		if tipo != documento.TipoInvalido {
+			set["tipo_documento_fornecedor"] = string(tipo)
+		} else if cnpj == despesa.CNPJFornecedor {
+			continue
+		}
+		lote = append(lote, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": despesa.ID}).SetUpdate(bson.M{"$set": set}))
+		if len(lote) == tamanhoLote {
+			if err := gravar(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return gravar()
+}
+
+// Aplicar roda, em ordem, as migrações ainda não registradas no banco e retorna os nomes das
+// aplicadas. Para na primeira que falhar, para que as seguintes não rodem sobre dados que ela
+// deveria ter corrigido.
+func Aplicar(ctx context.Context, db *mongo.Database, migracoes []Migracao) ([]string, error) {
+	colecao := db.Collection(Colecao)
+
+	var aplicadas []string
+	for _, m := range migracoes {
+		n, err := colecao.CountDocuments(ctx, bson.M{"_id": m.Nome}, options.Count().SetLimit(1))
+		if err != nil {
+			return aplicadas, err
+		}
+		if n > 0 {
+			continue
+		}
+
+		log.Printf("🔧 Migração %s: %s", m.Nome, m.Descricao)
+		if err := m.Aplicar(ctx, db); err != nil {
+			return aplicadas, fmt.Errorf("migração %s: %w", m.Nome, err)
+		}
+		_, err = colecao.ReplaceOne(ctx, bson.M{"_id": m.Nome},
+			registro{Nome: m.Nome, AplicadaEm: time.Now()},
+			options.Replace().SetUpsert(true),
+		)
+		if err != nil {
+			return aplicadas, err
+		}
+		aplicadas = append(aplicadas, m.Nome)
+	}
+
+	return aplicadas, nil
+}
@@ -0,0 +1,128 @@
+package migracoes
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Cada migração roda uma vez só; depois de uma falha, as seguintes esperam a próxima partida
+func TestAplicar(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+
+	execucoes := map[string]int{}
+	falhar := true
+	contar := func(nome string) func(context.Context, *mongo.Database) error {
+		return func(context.Context, *mongo.Database) error {
+			execucoes[nome]++
+			if nome == "b" && falhar {
+				return errors.New("falhou")
+			}
+			return nil
+		}
+	}
+	migracoes := []Migracao{
+		{Nome: "a", Aplicar: contar("a")},
+		{Nome: "b", Aplicar: contar("b")},
+		{Nome: "c", Aplicar: contar("c")},
+	}
+
+	aplicadas, err := Aplicar(ctx, db, migracoes)
+	if err == nil || !reflect.DeepEqual(aplicadas, []string{"a"}) || execucoes["c"] != 0 {
+		t.Fatalf("com b falhando: aplicadas %v, erro %v, c rodou %d vezes", aplicadas, err, execucoes["c"])
+	}
+
+	falhar = false
+	aplicadas, err = Aplicar(ctx, db, migracoes)
+	if err != nil || !reflect.DeepEqual(aplicadas, []string{"b", "c"}) {
+		t.Fatalf("aplicadas %v, erro %v; esperadas b e c", aplicadas, err)
+	}
+	if aplicadas, _ := Aplicar(ctx, db, migracoes); len(aplicadas) != 0 {
+		t.Errorf("reaplicou %v", aplicadas)
+	}
+	if execucoes["a"] != 1 || execucoes["c"] != 1 {
+		t.Errorf("execuções = %v", execucoes)
+	}
+}
+
+// Os votos sem o ID da votação são apagados; os demais ficam
+func TestVotosSemVotacaoIDExterno(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+
+	votacoes := db.Collection("votacoes")
+	if _, err := votacoes.InsertMany(ctx, []interface{}{
+		bson.M{"voto": "SIM"},
+		bson.M{"voto": "NAO", "votacao_id_externo": "2265603-43"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Aplicar(ctx, db, Todas); err != nil {
+		t.Fatalf("Aplicar: %v", err)
+	}
+	if n, _ := votacoes.CountDocuments(ctx, bson.M{}); n != 1 {
+		t.Errorf("%d votos; esperado só o que tem o ID da votação", n)
+	}
+}
+
+// Os documentos dos fornecedores ficam só com os dígitos; os válidos ganham o tipo
+func TestCNPJFornecedorNormalizado(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+
+	ids := map[string]primitive.ObjectID{}
+	for _, nome := range []string{"cnpj", "cpf", "invalido", "normalizado"} {
+		ids[nome] = primitive.NewObjectID()
+	}
+	despesas := db.Collection("despesas")
+	if _, err := despesas.InsertMany(ctx, []interface{}{
+		bson.M{"_id": ids["cnpj"], "cnpj_fornecedor": "11.222.333/0001-81"},
+		bson.M{"_id": ids["cpf"], "cnpj_fornecedor": "123.456.789-09"},
+		bson.M{"_id": ids["invalido"], "cnpj_fornecedor": "11.222.333/0001-00"},
+		bson.M{"_id": ids["normalizado"], "cnpj_fornecedor": "11222333000181", "tipo_documento_fornecedor": "CNPJ"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Mais de um lote
+	antigas := make([]interface{}, tamanhoLote+500)
+	for i := range antigas {
+		antigas[i] = bson.M{"cnpj_fornecedor": "11.222.333/0001-81"}
+	}
+	if _, err := despesas.InsertMany(ctx, antigas); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Aplicar(ctx, db, Todas); err != nil {
+		t.Fatalf("Aplicar: %v", err)
+	}
+
+	esperados := map[string][2]string{
+		"cnpj":        {"11222333000181", "CNPJ"},
+		"cpf":         {"12345678909", "CPF"},
+		"invalido":    {"11222333000100", ""},
+		"normalizado": {"11222333000181", "CNPJ"},
+	}
+	for nome, esperado := range esperados {
+		var despesa struct {
+			CNPJ string `bson:"cnpj_fornecedor"`
+			Tipo string `bson:"tipo_documento_fornecedor"`
+		}
+		if err := despesas.FindOne(ctx, bson.M{"_id": ids[nome]}).Decode(&despesa); err != nil {
+			t.Fatal(err)
+		}
+		if despesa.CNPJ != esperado[0] || despesa.Tipo != esperado[1] {
+			t.Errorf("%s: %q (%q); esperado %q (%q)", nome, despesa.CNPJ, despesa.Tipo, esperado[0], esperado[1])
+		}
+	}
+	if n, _ := despesas.CountDocuments(ctx, bson.M{"cnpj_fornecedor": "11222333000181", "tipo_documento_fornecedor": "CNPJ"}); n != int64(len(antigas))+2 {
+		t.Errorf("%d despesas com o CNPJ normalizado; esperadas %d", n, len(antigas)+2)
+	}
+}
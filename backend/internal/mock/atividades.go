@@ -0,0 +1,228 @@
+package mock
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Dados reúne os registros mockados de todas as coleções. Votos, despesas, proposições e
+// presenças são gerados a partir das estatísticas de cada político, então os números
+// calculados sobre eles batem com Estatisticas.
+type Dados struct {
+	Politicos   []domain.Politico
+	Votacoes    []domain.Votacao
+	Despesas    []domain.Despesa
+	Proposicoes []domain.Proposicao
+	Presencas   []domain.Presenca
+}
+
+// Todos retorna o conjunto completo de dados mockados. A geração usa sementes fixas, então
+// os mesmos políticos sempre recebem os mesmos registros.
+func Todos() Dados {
+	politicos := Politicos()
+	estatisticas := Estatisticas()
+
+	dados := Dados{Politicos: politicos}
+	for i, p := range politicos {
+		stats := estatisticas[p.ID.Hex()]
+		aleatorio := rand.New(rand.NewSource(int64(i + 1)))
+
+		votos := gerarVotacoes(p, stats, aleatorio)
+		dados.Votacoes = append(dados.Votacoes, votos...)
+		dados.Presencas = append(dados.Presencas, gerarPresencas(votos, primitive.NewObjectID)...)
+		dados.Despesas = append(dados.Despesas, gerarDespesas(p, stats, aleatorio)...)
+		dados.Proposicoes = append(dados.Proposicoes, gerarProposicoes(p, stats, i)...)
+	}
+	return dados
+}
+
+// casaLegislativa identifica onde o político vota; quem é da mesma casa participa das mesmas
+// votações, o que permite calcular o alinhamento entre eles
+func casaLegislativa(cargo domain.Cargo) string {
+	switch cargo {
+	case domain.CargoDeputadoFederal:
+		return "camara"
+	case domain.CargoSenador:
+		return "senado"
+	case domain.CargoDeputadoEstadual:
+		return "assembleia"
+	case domain.CargoVereador:
+		return "camara-municipal"
+	default:
+		return ""
+	}
+}
+
+// dataSessao é o n-ésimo dia útil a partir do início da legislatura, em fevereiro de 2023
+func dataSessao(n int) time.Time {
+	data := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		data = data.AddDate(0, 0, 1)
+		for data.Weekday() == time.Saturday || data.Weekday() == time.Sunday {
+			data = data.AddDate(0, 0, 1)
+		}
+	}
+	return data
+}
+
+func gerarVotacoes(p domain.Politico, stats domain.EstatisticasPolitico, aleatorio *rand.Rand) []domain.Votacao {
+	casa := casaLegislativa(p.CargoAtual.Tipo)
+	if casa == "" || stats.TotalVotacoes == 0 {
+		return nil
+	}
+
+	tipos := make([]domain.TipoVoto, 0, stats.TotalVotacoes)
+	for _, q := range []struct {
+		voto domain.TipoVoto
+		n    int
+	}{
+		{domain.VotoSim, stats.VotosSim},
+		{domain.VotoNao, stats.VotosNao},
+		{domain.VotoAbstencao, stats.Abstencoes},
+		{domain.VotoAusente, stats.Ausencias},
+	} {
+		for i := 0; i < q.n; i++ {
+			tipos = append(tipos, q.voto)
+		}
+	}
+	aleatorio.Shuffle(len(tipos), func(i, j int) { tipos[i], tipos[j] = tipos[j], tipos[i] })
+
+	votos := make([]domain.Votacao, 0, len(tipos))
+	for i, voto := range tipos {
+		votos = append(votos, domain.Votacao{
+			ID:               primitive.NewObjectID(),
+			PoliticoID:       p.ID,
+			VotacaoIDExterno: fmt.Sprintf("%s-%d", casa, i+1),
+			Voto:             voto,
+			Data:             dataSessao(i),
+			Sessao:           "Sessão Deliberativa",
+		})
+	}
+	return votos
+}
+
+// gerarPresencas registra uma presença por votação: ausente quando o voto foi AUSENTE
+func gerarPresencas(votos []domain.Votacao, novoID func() primitive.ObjectID) []domain.Presenca {
+	presencas := make([]domain.Presenca, 0, len(votos))
+	for _, v := range votos {
+		presencas = append(presencas, domain.Presenca{
+			ID:         novoID(),
+			PoliticoID: v.PoliticoID,
+			Data:       v.Data,
+			TipoSessao: "Deliberativa",
+			Presente:   v.Voto != domain.VotoAusente,
+		})
+	}
+	return presencas
+}
+
+var categoriasDespesa = []struct {
+	tipo       string
+	fornecedor string
+	cnpj       string
+	parte      int // percentual do gasto do mês
+}{
+	{"DIVULGAÇÃO DA ATIVIDADE PARLAMENTAR.", "Gráfica Horizonte Ltda", "12345678000195", 40},
+	{"LOCAÇÃO OU FRETAMENTO DE VEÍCULOS AUTOMOTORES", "Locadora Planalto S.A.", "23456789000106", 25},
+	{"COMBUSTÍVEIS E LUBRIFICANTES.", "Posto Esplanada Ltda", "34567890000117", 15},
+	{"PASSAGEM AÉREA - SIGEPA", "Companhia Aérea Nacional S.A.", "45678901000128", 20},
+}
+
+// gerarDespesas divide o total anual em 12 meses de 2024 e cada mês entre as categorias,
+// em centavos, para que a soma seja exatamente o total das estatísticas
+func gerarDespesas(p domain.Politico, stats domain.EstatisticasPolitico, aleatorio *rand.Rand) []domain.Despesa {
+	totalCentavos := int64(stats.TotalDespesas*100 + 0.5)
+	if totalCentavos == 0 {
+		return nil
+	}
+
+	var despesas []domain.Despesa
+	for mes := 1; mes <= 12; mes++ {
+		doMes := totalCentavos / 12
+		if int64(mes) <= totalCentavos%12 {
+			doMes++
+		}
+
+		restante := doMes
+		for i, c := range categoriasDespesa {
+			valor := doMes * int64(c.parte) / 100
+			if i == len(categoriasDespesa)-1 {
+				valor = restante
+			}
+			restante -= valor
+
+			despesas = append(despesas, domain.Despesa{
+				ID:                      primitive.NewObjectID(),
+				PoliticoID:              p.ID,
+				Tipo:                    c.tipo,
+				Descricao:               c.tipo,
+				Fornecedor:              c.fornecedor,
+				CNPJFornecedor:          c.cnpj,
+				TipoDocumentoFornecedor: "CNPJ",
+				Valor:                   float64(valor) / 100,
+				Data:                    time.Date(2024, time.Month(mes), 5+aleatorio.Intn(20), 0, 0, 0, 0, time.UTC),
+				MesReferencia:           mes,
+				AnoReferencia:           2024,
+				NumDocumento:            fmt.Sprintf("NF-%d%02d%d", 2024, mes, i+1),
+			})
+		}
+	}
+	return despesas
+}
+
+var ementasProposicao = []struct {
+	ementa string
+	tema   string
+}{
+	{"Dispõe sobre a transparência dos gastos públicos com publicidade.", "Administração Pública"},
+	{"Altera a legislação do ensino para ampliar a educação em tempo integral.", "Educação"},
+	{"Institui programa de atenção primária à saúde nos municípios de pequeno porte.", "Saúde"},
+	{"Estabelece incentivos à geração distribuída de energia solar.", "Meio Ambiente"},
+	{"Cria regras para a segurança de dados pessoais em serviços públicos digitais.", "Ciência, Tecnologia e Inovação"},
+	{"Amplia as penas para crimes contra a administração pública.", "Direito Penal"},
+}
+
+// situacoesNaoAprovadas são usadas em rodízio nas proposições que não foram aprovadas
+var situacoesNaoAprovadas = []domain.SituacaoProposicao{
+	domain.SituacaoEmTramitacao,
+	domain.SituacaoEmTramitacao,
+	domain.SituacaoArquivada,
+	domain.SituacaoRejeitada,
+	domain.SituacaoRetirada,
+}
+
+func gerarProposicoes(p domain.Politico, stats domain.EstatisticasPolitico, indice int) []domain.Proposicao {
+	proposicoes := make([]domain.Proposicao, 0, stats.TotalProposicoes)
+	for i := 0; i < stats.TotalProposicoes; i++ {
+		situacao := domain.SituacaoAprovada
+		if i >= stats.ProposicoesAprovadas {
+			situacao = situacoesNaoAprovadas[i%len(situacoesNaoAprovadas)]
+		}
+		ementa := ementasProposicao[(i+indice)%len(ementasProposicao)]
+		apresentacao := time.Date(2023+i%2, time.Month(1+i%12), 10, 0, 0, 0, 0, time.UTC)
+
+		proposicoes = append(proposicoes, domain.Proposicao{
+			ID:           primitive.NewObjectID(),
+			Tipo:         "PL",
+			Numero:       fmt.Sprintf("%d", (indice+1)*1000+i+1),
+			Ano:          apresentacao.Year(),
+			Ementa:       ementa.ementa,
+			AutorID:      p.ID,
+			CoautoresIDs: []primitive.ObjectID{},
+			Situacao:     situacao,
+			Tema:         []string{ementa.tema},
+			Tramitacao: []domain.TramitacaoItem{{
+				Data:      apresentacao,
+				Descricao: "Apresentação da proposição",
+				Orgao:     "Mesa Diretora",
+			}},
+			CreatedAt: apresentacao,
+			UpdatedAt: apresentacao,
+		})
+	}
+	return proposicoes
+}
@@ -204,7 +204,8 @@ func Politicos() []domain.Politico {
 	}
 }
 
-// Estatisticas retorna estatísticas mockadas para um político
+// Estatisticas retorna as estatísticas de cada político mockado, a partir das quais Todos
+// gera votos, despesas, proposições e presenças
 func Estatisticas() map[string]domain.EstatisticasPolitico {
 	return map[string]domain.EstatisticasPolitico{
 		id1.Hex(): {
@@ -281,34 +282,3 @@ func Estatisticas() map[string]domain.EstatisticasPolitico {
 		},
 	}
 }
-
-// GetPoliticoByID retorna um político pelo ID
-func GetPoliticoByID(id string) *domain.Politico {
-	for _, p := range Politicos() {
-		if p.ID.Hex() == id {
-			return &p
-		}
-	}
-	return nil
-}
-
-// GetEstatisticasByID retorna estatísticas de um político pelo ID
-func GetEstatisticasByID(id string) *domain.EstatisticasPolitico {
-	stats := Estatisticas()
-	if s, ok := stats[id]; ok {
-		return &s
-	}
-	// Retorna estatísticas padrão se não encontrar
-	return &domain.EstatisticasPolitico{
-		TotalVotacoes:        100,
-		VotosSim:             60,
-		VotosNao:             30,
-		Abstencoes:           5,
-		Ausencias:            5,
-		PercentualPresenca:   95.0,
-		TotalProposicoes:     10,
-		ProposicoesAprovadas: 3,
-		TotalDespesas:        50000.00,
-		MediaGastoMensal:     5000.00,
-	}
-}
@@ -0,0 +1,719 @@
+package mock
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"github.com/lupa-cidada/backend/pkg/documento"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Valores padrão do gerador de dados sintéticos
+const (
+	PoliticosPadrao       = 600
+	VotacoesPorCasaPadrao = 120
+)
+
+// Configuracao controla o tamanho do conjunto sintético. A mesma configuração (inclusive a
+// semente) gera sempre os mesmos dados, com os mesmos IDs.
+type Configuracao struct {
+	Semente         int64
+	Politicos       int // Total de políticos; há sempre pelo menos um por cargo
+	VotacoesPorCasa int // Votações nominais em cada casa legislativa
+}
+
+var (
+	// fimDados é o último dia coberto pelos dados sintéticos
+	fimDados = time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	// inicioDespesas limita as despesas aos dois últimos anos
+	inicioDespesas = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	// geradoEm é a data de cadastro dos registros sintéticos
+	geradoEm = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// perfilCargo descreve o mandato de cada cargo
+type perfilCargo struct {
+	cargo       domain.Cargo
+	esfera      domain.Esfera
+	fracao      float64 // fração dos políticos gerados
+	maximo      int     // tamanho real da casa ou número de vagas; 0 sem limite
+	idadeMinima int
+	salario     float64
+	cota        float64 // gasto mensal máximo com a cota parlamentar; 0 sem cota
+	proposicoes int     // máximo de proposições por político
+	inicio      time.Time
+}
+
+var perfisCargos = []perfilCargo{
+	{domain.CargoPresidente, domain.EsferaFederal, 0, 1, 35, 44008.52, 0, 30, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoGovernador, domain.EsferaEstadual, 0.04, 27, 30, 35462.22, 0, 15, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoSenador, domain.EsferaFederal, 0.08, 81, 35, 44008.52, 45000, 25, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoDeputadoFederal, domain.EsferaFederal, 0.35, 513, 21, 44008.52, 40000, 30, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoDeputadoDistrital, domain.EsferaEstadual, 0.02, 24, 21, 33006.39, 25000, 20, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoDeputadoEstadual, domain.EsferaEstadual, 0.18, 1059, 21, 33006.39, 25000, 20, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoPrefeito, domain.EsferaMunicipal, 0.08, 0, 21, 24500.00, 0, 10, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{domain.CargoVereador, domain.EsferaMunicipal, 0, 0, 18, 18991.68, 6000, 15, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+// membro é um político que vota e apresenta proposições em uma casa legislativa
+type membro struct {
+	indice    int     // posição em Dados.Politicos
+	posicao   float64 // posição no espectro político, a partir da do partido
+	ausencias float64 // probabilidade de faltar a uma votação
+}
+
+// casa é uma casa legislativa: Câmara, Senado, assembleias e câmaras municipais
+type casa struct {
+	chave       string
+	inicio      time.Time
+	membros     []membro
+	proposicoes []int // posições em Dados.Proposicoes, em ordem de apresentação
+}
+
+type partidoSorteavel struct {
+	domain.Partido
+	perfilPartido
+}
+
+type gerador struct {
+	cfg       Configuracao
+	aleatorio *rand.Rand
+	dados     Dados
+
+	partidos     []partidoSorteavel
+	pesoTotal    int
+	casas        map[string]*casa
+	ordemCasas   []string
+	numeros      map[string]int // último número de proposição por casa, tipo e ano
+	fornecedores map[string][]fornecedorSintetico
+	documento    int
+}
+
+type fornecedorSintetico struct {
+	nome      string
+	documento string
+	tipo      string
+}
+
+// Gerar produz um conjunto sintético coerente: políticos de todos os cargos e estados,
+// filiados aos partidos conforme o tamanho das bancadas, com votos orientados pela posição do
+// partido, presenças, despesas da cota parlamentar e proposições votadas nas próprias casas.
+func Gerar(cfg Configuracao) Dados {
+	if cfg.Politicos < len(perfisCargos) {
+		cfg.Politicos = len(perfisCargos)
+	}
+	if cfg.VotacoesPorCasa < 0 {
+		cfg.VotacoesPorCasa = 0
+	}
+
+	g := &gerador{
+		cfg:          cfg,
+		aleatorio:    rand.New(rand.NewSource(cfg.Semente)),
+		casas:        make(map[string]*casa),
+		numeros:      make(map[string]int),
+		fornecedores: make(map[string][]fornecedorSintetico),
+		documento:    7000000,
+	}
+	g.carregarPartidos()
+
+	for _, vaga := range g.distribuirVagas() {
+		g.gerarPolitico(vaga)
+	}
+	for _, chave := range g.ordemCasas {
+		g.gerarVotacoes(g.casas[chave])
+	}
+	for i := range g.dados.Politicos {
+		g.gerarDespesas(i)
+	}
+	g.dados.Presencas = gerarPresencas(g.dados.Votacoes, g.novoID)
+
+	return g.dados
+}
+
+func (g *gerador) carregarPartidos() {
+	for _, p := range partidos.Padrao() {
+		perfil, ok := perfisPartidos[p.Sigla]
+		if !ok || p.IncorporadoPor != "" {
+			continue
+		}
+		g.partidos = append(g.partidos, partidoSorteavel{
+			Partido:       domain.Partido{Sigla: p.Sigla, Nome: p.Nome, Cor: p.Cor},
+			perfilPartido: perfil,
+		})
+		g.pesoTotal += perfil.peso
+	}
+}
+
+// novoID cria ObjectIDs reproduzíveis: a data de geração seguida de bytes da semente
+func (g *gerador) novoID() primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(geradoEm.Unix()))
+	binary.BigEndian.PutUint64(id[4:12], g.aleatorio.Uint64())
+	return id
+}
+
+func (g *gerador) sortear(opcoes []string) string {
+	return opcoes[g.aleatorio.Intn(len(opcoes))]
+}
+
+// entre sorteia uma data no intervalo [inicio, fim)
+func (g *gerador) entre(inicio, fim time.Time) time.Time {
+	dias := int(fim.Sub(inicio).Hours() / 24)
+	if dias < 1 {
+		return inicio
+	}
+	return inicio.AddDate(0, 0, g.aleatorio.Intn(dias))
+}
+
+func (g *gerador) sortearPartido() partidoSorteavel {
+	n := g.aleatorio.Intn(g.pesoTotal)
+	for _, p := range g.partidos {
+		if n < p.peso {
+			return p
+		}
+		n -= p.peso
+	}
+	return g.partidos[len(g.partidos)-1]
+}
+
+// sortearUF escolhe um estado com peso proporcional à bancada na Câmara
+func (g *gerador) sortearUF(semDF bool) uf {
+	total := 0
+	for _, u := range ufs {
+		if !(semDF && u.sigla == "DF") {
+			total += u.bancada
+		}
+	}
+	n := g.aleatorio.Intn(total)
+	for _, u := range ufs {
+		if semDF && u.sigla == "DF" {
+			continue
+		}
+		if n < u.bancada {
+			return u
+		}
+		n -= u.bancada
+	}
+	return ufs[len(ufs)-1]
+}
+
+// vaga é um mandato a preencher
+type vaga struct {
+	perfil    perfilCargo
+	estado    string
+	municipio string
+}
+
+// distribuirVagas divide os políticos entre os cargos, respeitando o tamanho real das casas,
+// e escolhe o estado e o município de cada mandato
+func (g *gerador) distribuirVagas() []vaga {
+	var municipios []vaga
+	for _, u := range ufs {
+		if u.sigla == "DF" {
+			continue // Brasília não tem prefeitura nem câmara municipal
+		}
+		for _, m := range append([]string{u.capital}, u.municipios...) {
+			municipios = append(municipios, vaga{estado: u.sigla, municipio: m})
+		}
+	}
+
+	quantidades := make([]int, len(perfisCargos))
+	usados := 0
+	for i, p := range perfisCargos {
+		n := int(p.fracao * float64(g.cfg.Politicos))
+		if p.cargo == domain.CargoPresidente || n < 1 {
+			n = 1
+		}
+		maximo := p.maximo
+		if p.cargo == domain.CargoPrefeito {
+			maximo = len(municipios)
+		}
+		if maximo > 0 && n > maximo {
+			n = maximo
+		}
+		quantidades[i] = n
+		usados += n
+	}
+	// Vereadores completam o total
+	if resto := g.cfg.Politicos - usados; resto > 0 {
+		quantidades[len(perfisCargos)-1] += resto
+	}
+
+	var vagas []vaga
+	estados := g.aleatorio.Perm(len(ufs))
+	cidades := g.aleatorio.Perm(len(municipios))
+	for i, p := range perfisCargos {
+		for j := 0; j < quantidades[i]; j++ {
+			v := vaga{perfil: p}
+			switch p.cargo {
+			case domain.CargoPresidente:
+				v.estado = "BR"
+			case domain.CargoGovernador:
+				v.estado = ufs[estados[j%len(ufs)]].sigla
+			case domain.CargoSenador:
+				v.estado = ufs[estados[j%len(ufs)]].sigla
+			case domain.CargoDeputadoDistrital:
+				v.estado = "DF"
+			case domain.CargoDeputadoFederal:
+				v.estado = g.sortearUF(false).sigla
+			case domain.CargoDeputadoEstadual:
+				v.estado = g.sortearUF(true).sigla
+			case domain.CargoPrefeito:
+				m := municipios[cidades[j%len(municipios)]]
+				v.estado, v.municipio = m.estado, m.municipio
+			case domain.CargoVereador:
+				u := g.sortearUF(true)
+				v.estado = u.sigla
+				v.municipio = g.sortear(append([]string{u.capital}, u.municipios...))
+			}
+			vagas = append(vagas, v)
+		}
+	}
+	return vagas
+}
+
+// chaveCasa identifica a casa legislativa em que o mandato vota; executivos não votam, mas
+// apresentam proposições à casa correspondente
+func chaveCasa(v vaga) (chave string, vota bool) {
+	switch v.perfil.cargo {
+	case domain.CargoDeputadoFederal:
+		return "camara", true
+	case domain.CargoSenador:
+		return "senado", true
+	case domain.CargoPresidente:
+		return "camara", false
+	case domain.CargoDeputadoEstadual:
+		return "assembleia-" + v.estado, true
+	case domain.CargoGovernador:
+		if v.estado == "DF" {
+			return "cldf", false
+		}
+		return "assembleia-" + v.estado, false
+	case domain.CargoDeputadoDistrital:
+		return "cldf", true
+	case domain.CargoVereador:
+		return "camara-municipal-" + v.estado + "-" + slug(v.municipio), true
+	default:
+		return "camara-municipal-" + v.estado + "-" + slug(v.municipio), false
+	}
+}
+
+func (g *gerador) casa(chave string, inicio time.Time) *casa {
+	c, ok := g.casas[chave]
+	if !ok {
+		c = &casa{chave: chave, inicio: inicio}
+		g.casas[chave] = c
+		g.ordemCasas = append(g.ordemCasas, chave)
+	}
+	return c
+}
+
+var semAcentos = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c",
+)
+
+// slug converte nomes em identificadores para e-mails e chaves (ex.: São Luís → sao-luis)
+func slug(s string) string {
+	return strings.ReplaceAll(semAcentos.Replace(strings.ToLower(s)), " ", "-")
+}
+
+func (g *gerador) gerarPolitico(v vaga) {
+	p := v.perfil
+	genero := domain.GeneroMasculino
+	primeiros := nomesMasculinos
+	if g.aleatorio.Float64() < 0.22 {
+		genero = domain.GeneroFeminino
+		primeiros = nomesFemininos
+	}
+
+	primeiro := g.sortear(primeiros)
+	meio, ultimo := g.sortear(sobrenomes), g.sortear(sobrenomes)
+	nome := primeiro + " " + ultimo
+	if g.aleatorio.Float64() < 0.1 {
+		titulos := map[domain.Genero][]string{
+			domain.GeneroMasculino: {"Delegado", "Pastor", "Professor", "Dr.", "Coronel"},
+			domain.GeneroFeminino:  {"Delegada", "Professora", "Dra.", "Enfermeira"},
+		}
+		nome = g.sortear(titulos[genero]) + " " + nome
+	}
+
+	idade := p.idadeMinima + 4 + g.aleatorio.Intn(40)
+	nascimento := time.Date(p.inicio.Year()-idade, time.Month(1+g.aleatorio.Intn(12)), 1+g.aleatorio.Intn(28), 0, 0, 0, 0, time.UTC)
+
+	partido := g.sortearPartido()
+	inicio := p.inicio
+	if p.cargo == domain.CargoSenador && g.aleatorio.Intn(3) > 0 {
+		inicio = time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC) // dois terços do Senado cumprem o mandato de 2019
+	}
+
+	politico := domain.Politico{
+		ID:             g.novoID(),
+		CPF:            documento.CompletarCPF(fmt.Sprintf("%09d", g.aleatorio.Intn(1e9))),
+		Nome:           nome,
+		NomeCivil:      primeiro + " " + meio + " " + ultimo,
+		NomeEleitoral:  nome,
+		DataNascimento: nascimento,
+		Genero:         genero,
+		Partido:        partido.Partido,
+		CargoAtual: domain.CargoAtual{
+			Tipo:        p.cargo,
+			Esfera:      p.esfera,
+			Estado:      v.estado,
+			Municipio:   v.municipio,
+			DataInicio:  inicio,
+			EmExercicio: true,
+		},
+		HistoricoCargos: []domain.CargoAtual{},
+		Contato:         contato(v, primeiro, ultimo),
+		SalarioBruto:    p.salario,
+		SalarioLiquido:  math.Round(p.salario*73) / 100,
+		Escolaridade:    g.sortear(escolaridades),
+		UFNascimento:    v.estado,
+		CreatedAt:       geradoEm,
+		UpdatedAt:       geradoEm,
+	}
+	if politico.UFNascimento == "BR" {
+		politico.UFNascimento = g.sortearUF(false).sigla
+	}
+
+	switch p.cargo {
+	case domain.CargoDeputadoFederal:
+		politico.IDExternoCamara = 204000 + len(g.dados.Politicos)
+	case domain.CargoSenador:
+		politico.IDExternoSenado = fmt.Sprint(6000 + len(g.dados.Politicos))
+	}
+
+	// Parte dos políticos já teve outro mandato antes do atual
+	if g.aleatorio.Float64() < 0.3 {
+		anterior := perfisCargos[3+g.aleatorio.Intn(3)] // federal, distrital ou estadual
+		if v.estado != "DF" && anterior.cargo == domain.CargoDeputadoDistrital {
+			anterior = perfisCargos[5]
+		}
+		if anterior.cargo != p.cargo {
+			estado := v.estado
+			if estado == "BR" {
+				estado = politico.UFNascimento
+			}
+			politico.HistoricoCargos = append(politico.HistoricoCargos, domain.CargoAtual{
+				Tipo:       anterior.cargo,
+				Esfera:     anterior.esfera,
+				Estado:     estado,
+				DataInicio: inicio.AddDate(-4, 0, 0),
+				DataFim:    inicio.AddDate(0, 0, -1),
+			})
+		}
+	}
+
+	// Filiações: parte trocou de partido na última janela partidária antes do mandato
+	filiacao := inicio.AddDate(-2-g.aleatorio.Intn(10), 0, 0)
+	if g.aleatorio.Float64() < 0.15 {
+		troca := time.Date(inicio.Year()-1, 3, 20, 0, 0, 0, 0, time.UTC)
+		antigo := g.sortearPartido()
+		if antigo.Sigla != partido.Sigla {
+			politico.HistoricoPartidos = append(politico.HistoricoPartidos, domain.FiliacaoPartidaria{
+				Partido:    antigo.Partido,
+				DataInicio: filiacao,
+				DataFim:    &troca,
+			})
+			filiacao = troca
+		}
+	}
+	politico.HistoricoPartidos = append(politico.HistoricoPartidos, domain.FiliacaoPartidaria{
+		Partido:    partido.Partido,
+		DataInicio: filiacao,
+	})
+
+	indice := len(g.dados.Politicos)
+	g.dados.Politicos = append(g.dados.Politicos, politico)
+
+	chave, vota := chaveCasa(v)
+	c := g.casa(chave, p.inicio)
+	if vota {
+		c.membros = append(c.membros, membro{
+			indice:    indice,
+			posicao:   partido.posicao + g.aleatorio.NormFloat64()*0.15,
+			ausencias: 0.02 + g.aleatorio.Float64()*0.12,
+		})
+	}
+	g.gerarProposicoes(indice, c)
+}
+
+func contato(v vaga, primeiro, ultimo string) domain.Contato {
+	usuario := slug(primeiro) + "." + slug(ultimo)
+	switch v.perfil.cargo {
+	case domain.CargoDeputadoFederal:
+		return domain.Contato{Email: "dep." + usuario + "@camara.leg.br", Telefone: "(61) 3215-5000", Gabinete: "Anexo IV"}
+	case domain.CargoSenador:
+		return domain.Contato{Email: "sen." + usuario + "@senado.leg.br", Telefone: "(61) 3303-1000", Gabinete: "Anexo II"}
+	case domain.CargoDeputadoEstadual:
+		return domain.Contato{Email: "dep." + usuario + "@al." + strings.ToLower(v.estado) + ".leg.br"}
+	case domain.CargoDeputadoDistrital:
+		return domain.Contato{Email: "dep." + usuario + "@cl.df.gov.br"}
+	case domain.CargoVereador:
+		return domain.Contato{Email: "ver." + usuario + "@camara." + slug(v.municipio) + ".leg.br"}
+	default:
+		return domain.Contato{}
+	}
+}
+
+// tipoProposicao sorteia o tipo conforme a casa: emendas constitucionais, leis
+// complementares e decretos legislativos são mais raros que projetos de lei
+func (g *gerador) tipoProposicao(cargo domain.Cargo) string {
+	n := g.aleatorio.Intn(100)
+	switch {
+	case cargo == domain.CargoPresidente && n < 40:
+		return "MPV"
+	case cargo == domain.CargoDeputadoFederal || cargo == domain.CargoSenador:
+		switch {
+		case n < 5:
+			return "PEC"
+		case n < 12:
+			return "PLP"
+		case n < 22:
+			return "PDL"
+		}
+	}
+	return "PL"
+}
+
+func (g *gerador) gerarProposicoes(indice int, c *casa) {
+	politico := g.dados.Politicos[indice]
+	var perfil perfilCargo
+	for _, p := range perfisCargos {
+		if p.cargo == politico.CargoAtual.Tipo {
+			perfil = p
+		}
+	}
+
+	n := g.aleatorio.Intn(perfil.proposicoes + 1)
+	for i := 0; i < n; i++ {
+		apresentacao := g.entre(politico.CargoAtual.DataInicio, fimDados.AddDate(0, -1, 0))
+		tipo := g.tipoProposicao(politico.CargoAtual.Tipo)
+		chaveNumero := fmt.Sprintf("%s/%s/%d", c.chave, tipo, apresentacao.Year())
+		g.numeros[chaveNumero]++
+
+		tema := temasProposicao[g.aleatorio.Intn(len(temasProposicao))]
+		coautores := []primitive.ObjectID{}
+		if len(c.membros) > 1 && g.aleatorio.Float64() < 0.2 {
+			for k := 1 + g.aleatorio.Intn(3); k > 0; k-- {
+				m := c.membros[g.aleatorio.Intn(len(c.membros))]
+				if m.indice != indice {
+					coautores = append(coautores, g.dados.Politicos[m.indice].ID)
+				}
+			}
+		}
+
+		situacao := domain.SituacaoEmTramitacao
+		switch r := g.aleatorio.Float64(); {
+		case r < 0.1:
+			situacao = domain.SituacaoArquivada
+		case r < 0.13:
+			situacao = domain.SituacaoRetirada
+		}
+
+		c.proposicoes = append(c.proposicoes, len(g.dados.Proposicoes))
+		g.dados.Proposicoes = append(g.dados.Proposicoes, domain.Proposicao{
+			ID:           g.novoID(),
+			Tipo:         tipo,
+			Numero:       fmt.Sprint(g.numeros[chaveNumero]),
+			Ano:          apresentacao.Year(),
+			Ementa:       g.sortear(tema.ementas),
+			AutorID:      politico.ID,
+			CoautoresIDs: coautores,
+			Situacao:     situacao,
+			Tema:         []string{tema.tema},
+			Tramitacao: []domain.TramitacaoItem{{
+				Data:      apresentacao,
+				Descricao: "Apresentação da proposição",
+				Orgao:     "Mesa Diretora",
+			}},
+			CreatedAt: apresentacao,
+			UpdatedAt: apresentacao,
+		})
+	}
+}
+
+// diasUteis lista os dias úteis entre as datas
+func diasUteis(inicio, fim time.Time) []time.Time {
+	var dias []time.Time
+	for d := inicio; d.Before(fim); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			dias = append(dias, d)
+		}
+	}
+	return dias
+}
+
+// gerarVotacoes cria as votações nominais da casa. Cada votação tem um lado no espectro e um
+// grau de consenso; o voto de cada membro depende da distância entre a posição dele e o lado
+// da votação, então colegas de partido tendem a votar juntos.
+func (g *gerador) gerarVotacoes(c *casa) {
+	if len(c.membros) == 0 || g.cfg.VotacoesPorCasa == 0 {
+		return
+	}
+
+	// Ordem de apresentação, para votar só o que já foi apresentado
+	sort.SliceStable(c.proposicoes, func(i, j int) bool {
+		return g.dados.Proposicoes[c.proposicoes[i]].CreatedAt.Before(g.dados.Proposicoes[c.proposicoes[j]].CreatedAt)
+	})
+
+	dias := diasUteis(c.inicio, fimDados)
+	for n := 0; n < g.cfg.VotacoesPorCasa; n++ {
+		data := dias[n*len(dias)/g.cfg.VotacoesPorCasa]
+		lado := 1.0
+		if g.aleatorio.Intn(2) == 0 {
+			lado = -1
+		}
+		consenso := g.aleatorio.Float64()*1.3 - 0.5
+
+		var proposicao *domain.Proposicao
+		if apresentadas := sort.Search(len(c.proposicoes), func(i int) bool {
+			return !g.dados.Proposicoes[c.proposicoes[i]].CreatedAt.Before(data)
+		}); apresentadas > 0 {
+			proposicao = &g.dados.Proposicoes[c.proposicoes[g.aleatorio.Intn(apresentadas)]]
+		}
+
+		var proposicaoID primitive.ObjectID
+		if proposicao != nil {
+			proposicaoID = proposicao.ID
+		}
+
+		sim, nao := 0, 0
+		for _, m := range c.membros {
+			voto := domain.VotoAusente
+			if g.aleatorio.Float64() >= m.ausencias {
+				pontos := consenso + lado*m.posicao + g.aleatorio.NormFloat64()*0.35
+				switch {
+				case math.Abs(pontos) < 0.08:
+					voto = domain.VotoAbstencao
+				case pontos > 0:
+					voto = domain.VotoSim
+					sim++
+				default:
+					voto = domain.VotoNao
+					nao++
+				}
+			}
+
+			g.dados.Votacoes = append(g.dados.Votacoes, domain.Votacao{
+				ID:               g.novoID(),
+				PoliticoID:       g.dados.Politicos[m.indice].ID,
+				ProposicaoID:     proposicaoID,
+				VotacaoIDExterno: fmt.Sprintf("%s-%d", c.chave, n+1),
+				Voto:             voto,
+				Data:             data,
+				Sessao:           "Sessão Deliberativa",
+			})
+		}
+
+		if proposicao != nil {
+			proposicao.Situacao = domain.SituacaoRejeitada
+			if sim > nao {
+				proposicao.Situacao = domain.SituacaoAprovada
+			}
+			proposicao.Tramitacao = append(proposicao.Tramitacao, domain.TramitacaoItem{
+				Data:      data,
+				Descricao: fmt.Sprintf("Votação em plenário: %d votos sim, %d não", sim, nao),
+				Orgao:     "Plenário",
+			})
+			proposicao.UpdatedAt = data
+		}
+	}
+}
+
+// fornecedor escolhe um fornecedor do estado para a categoria; cada estado tem poucos
+// fornecedores por categoria, então os mesmos se repetem entre políticos, como nos dados reais
+func (g *gerador) fornecedor(estado string, categoria categoriaCota) fornecedorSintetico {
+	chave := estado + "/" + categoria.tipo
+	if len(g.fornecedores[chave]) < 4 {
+		f := fornecedorSintetico{
+			nome:      g.sortear(sobrenomes) + " " + g.sortear(ramos[categoria.tipo]) + " Ltda",
+			documento: documento.CompletarCNPJ(fmt.Sprintf("%08d0001", g.aleatorio.Intn(1e8))),
+			tipo:      string(documento.TipoCNPJ),
+		}
+		if categoria.pessoaFisica {
+			f = fornecedorSintetico{
+				nome:      g.sortear(nomesFemininos) + " " + g.sortear(sobrenomes) + " " + g.sortear(sobrenomes),
+				documento: documento.CompletarCPF(fmt.Sprintf("%09d", g.aleatorio.Intn(1e9))),
+				tipo:      string(documento.TipoCPF),
+			}
+		}
+		g.fornecedores[chave] = append(g.fornecedores[chave], f)
+		return f
+	}
+	return g.fornecedores[chave][g.aleatorio.Intn(len(g.fornecedores[chave]))]
+}
+
+// gerarDespesas lança as despesas mensais da cota parlamentar. Cada político tem um perfil de
+// gasto, e o total do mês fica entre 35% e 95% da cota.
+func (g *gerador) gerarDespesas(indice int) {
+	politico := g.dados.Politicos[indice]
+	var perfil perfilCargo
+	for _, p := range perfisCargos {
+		if p.cargo == politico.CargoAtual.Tipo {
+			perfil = p
+		}
+	}
+	if perfil.cota == 0 {
+		return
+	}
+
+	escala := perfil.cota / 40000
+	gastador := g.aleatorio.Float64()
+	inicio := politico.CargoAtual.DataInicio
+	if inicio.Before(inicioDespesas) {
+		inicio = inicioDespesas
+	}
+
+	for mes := time.Date(inicio.Year(), inicio.Month(), 1, 0, 0, 0, 0, time.UTC); mes.Before(fimDados); mes = mes.AddDate(0, 1, 0) {
+		alvo := perfil.cota * (0.35 + 0.6*gastador) * (0.85 + 0.3*g.aleatorio.Float64())
+		if alvo > perfil.cota {
+			alvo = perfil.cota
+		}
+
+		gasto := 0.0
+		for n := 0; n < 15 && gasto < alvo; n++ {
+			categoria := categoriasCota[g.aleatorio.Intn(len(categoriasCota))]
+			valor := (categoria.minimo + g.aleatorio.Float64()*(categoria.maximo-categoria.minimo)) * escala
+			valor = math.Round(math.Min(valor, alvo-gasto)*100) / 100
+			if valor < 1 {
+				break
+			}
+			gasto += valor
+
+			estado := politico.CargoAtual.Estado
+			if categoria.tipo == categoriasCota[2].tipo {
+				estado = "BR" // as companhias aéreas são as mesmas para todos
+			}
+			f := g.fornecedor(estado, categoria)
+			g.documento++
+
+			despesa := domain.Despesa{
+				ID:                      g.novoID(),
+				PoliticoID:              politico.ID,
+				Tipo:                    categoria.tipo,
+				Descricao:               categoria.tipo,
+				Fornecedor:              f.nome,
+				CNPJFornecedor:          f.documento,
+				TipoDocumentoFornecedor: f.tipo,
+				Valor:                   valor,
+				Data:                    mes.AddDate(0, 0, g.aleatorio.Intn(28)),
+				MesReferencia:           int(mes.Month()),
+				AnoReferencia:           mes.Year(),
+				NumDocumento:            fmt.Sprint(g.documento),
+			}
+			if politico.CargoAtual.Tipo == domain.CargoDeputadoFederal {
+				despesa.CodDocumento = g.documento
+			}
+			g.dados.Despesas = append(g.dados.Despesas, despesa)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package mock
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/pkg/documento"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGerarDeterministico(t *testing.T) {
+	cfg := Configuracao{Semente: 7, Politicos: 150, VotacoesPorCasa: 20}
+
+	if a, b := Gerar(cfg), Gerar(cfg); !reflect.DeepEqual(a, b) {
+		t.Fatal("a mesma configuração deveria gerar os mesmos dados")
+	}
+
+	outra := Gerar(Configuracao{Semente: 8, Politicos: 150, VotacoesPorCasa: 20})
+	if outra.Politicos[0].ID == Gerar(cfg).Politicos[0].ID {
+		t.Error("sementes diferentes deveriam gerar IDs diferentes")
+	}
+}
+
+func TestGerarCoerente(t *testing.T) {
+	dados := Gerar(Configuracao{Semente: 1, Politicos: 300, VotacoesPorCasa: 30})
+
+	if len(dados.Politicos) != 300 {
+		t.Fatalf("%d políticos; esperado 300", len(dados.Politicos))
+	}
+
+	politicos := make(map[primitive.ObjectID]domain.Politico)
+	cargos := make(map[domain.Cargo]int)
+	for _, p := range dados.Politicos {
+		politicos[p.ID] = p
+		cargos[p.CargoAtual.Tipo]++
+		if !documento.ValidarCPF(p.CPF) {
+			t.Errorf("%s: CPF inválido %q", p.Nome, p.CPF)
+		}
+		if _, ok := perfisPartidos[p.Partido.Sigla]; !ok {
+			t.Errorf("%s: partido %q fora da tabela", p.Nome, p.Partido.Sigla)
+		}
+	}
+	for _, perfil := range perfisCargos {
+		if cargos[perfil.cargo] == 0 {
+			t.Errorf("nenhum político com o cargo %s", perfil.cargo)
+		}
+	}
+	if cargos[domain.CargoPresidente] != 1 {
+		t.Errorf("%d presidentes", cargos[domain.CargoPresidente])
+	}
+
+	proposicoes := make(map[primitive.ObjectID]domain.Proposicao)
+	for _, p := range dados.Proposicoes {
+		proposicoes[p.ID] = p
+	}
+
+	for _, v := range dados.Votacoes {
+		p, ok := politicos[v.PoliticoID]
+		if !ok {
+			t.Fatalf("voto de político inexistente %s", v.PoliticoID.Hex())
+		}
+		switch p.CargoAtual.Tipo {
+		case domain.CargoPresidente, domain.CargoGovernador, domain.CargoPrefeito:
+			t.Fatalf("%s (%s) não deveria votar", p.Nome, p.CargoAtual.Tipo)
+		}
+		if !v.ProposicaoID.IsZero() {
+			if prop, ok := proposicoes[v.ProposicaoID]; !ok || prop.CreatedAt.After(v.Data) {
+				t.Fatalf("voto em %s sobre proposição inexistente ou apresentada depois", v.Data.Format("2006-01-02"))
+			}
+		}
+	}
+	if len(dados.Presencas) != len(dados.Votacoes) {
+		t.Errorf("%d presenças para %d votos", len(dados.Presencas), len(dados.Votacoes))
+	}
+
+	for _, d := range dados.Despesas {
+		if _, ok := politicos[d.PoliticoID]; !ok {
+			t.Fatalf("despesa de político inexistente %s", d.PoliticoID.Hex())
+		}
+		if _, tipo := documento.Identificar(d.CNPJFornecedor); string(tipo) != d.TipoDocumentoFornecedor {
+			t.Errorf("fornecedor %s: documento %q é %q; registrado como %q", d.Fornecedor, d.CNPJFornecedor, tipo, d.TipoDocumentoFornecedor)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package mock
+
+// Tabelas usadas pelo gerador de dados sintéticos
+
+// uf é uma unidade da federação, com o peso proporcional à bancada na Câmara
+type uf struct {
+	sigla      string
+	capital    string
+	bancada    int
+	municipios []string // além da capital, cidades com câmara municipal
+}
+
+var ufs = []uf{
+	{"AC", "Rio Branco", 8, nil},
+	{"AL", "Maceió", 9, []string{"Arapiraca"}},
+	{"AM", "Manaus", 8, []string{"Parintins"}},
+	{"AP", "Macapá", 8, nil},
+	{"BA", "Salvador", 39, []string{"Feira de Santana", "Vitória da Conquista"}},
+	{"CE", "Fortaleza", 22, []string{"Juazeiro do Norte", "Sobral"}},
+	{"DF", "Brasília", 8, nil},
+	{"ES", "Vitória", 10, []string{"Vila Velha"}},
+	{"GO", "Goiânia", 17, []string{"Anápolis", "Aparecida de Goiânia"}},
+	{"MA", "São Luís", 18, []string{"Imperatriz"}},
+	{"MG", "Belo Horizonte", 53, []string{"Uberlândia", "Juiz de Fora", "Contagem", "Montes Claros"}},
+	{"MS", "Campo Grande", 8, []string{"Dourados"}},
+	{"MT", "Cuiabá", 8, []string{"Rondonópolis"}},
+	{"PA", "Belém", 17, []string{"Ananindeua", "Santarém"}},
+	{"PB", "João Pessoa", 12, []string{"Campina Grande"}},
+	{"PE", "Recife", 25, []string{"Caruaru", "Petrolina"}},
+	{"PI", "Teresina", 10, []string{"Parnaíba"}},
+	{"PR", "Curitiba", 30, []string{"Londrina", "Maringá"}},
+	{"RJ", "Rio de Janeiro", 46, []string{"Niterói", "Duque de Caxias", "Nova Iguaçu"}},
+	{"RN", "Natal", 8, []string{"Mossoró"}},
+	{"RO", "Porto Velho", 8, nil},
+	{"RR", "Boa Vista", 8, nil},
+	{"RS", "Porto Alegre", 31, []string{"Caxias do Sul", "Pelotas"}},
+	{"SC", "Florianópolis", 16, []string{"Joinville", "Blumenau"}},
+	{"SE", "Aracaju", 8, nil},
+	{"SP", "São Paulo", 70, []string{"Campinas", "Guarulhos", "Santos", "Ribeirão Preto", "Sorocaba"}},
+	{"TO", "Palmas", 8, nil},
+}
+
+// perfilPartido dá o peso do partido no sorteio das filiações e a posição no espectro
+// político (-1 a 1), que orienta os votos da bancada
+type perfilPartido struct {
+	peso    int
+	posicao float64
+}
+
+// Partidos fora da tabela não recebem políticos
+var perfisPartidos = map[string]perfilPartido{
+	"PL":            {20, 0.8},
+	"PT":            {14, -0.7},
+	"UNIÃO":         {12, 0.4},
+	"PP":            {10, 0.5},
+	"PSD":           {9, 0.2},
+	"MDB":           {9, 0.1},
+	"REPUBLICANOS":  {8, 0.6},
+	"PDT":           {4, -0.4},
+	"PSB":           {3, -0.4},
+	"PSDB":          {3, 0.2},
+	"PODE":          {3, 0.3},
+	"PSOL":          {3, -0.9},
+	"AVANTE":        {2, 0.2},
+	"SOLIDARIEDADE": {1, 0.1},
+	"PCdoB":         {1, -0.8},
+	"PV":            {1, -0.3},
+	"CIDADANIA":     {1, -0.1},
+	"NOVO":          {1, 0.8},
+	"REDE":          {1, -0.6},
+	"PRD":           {1, 0.5},
+	"AGIR":          {1, 0.4},
+	"MOBILIZA":      {1, 0.2},
+	"DC":            {1, 0.5},
+	"PMB":           {1, 0.3},
+	"PRTB":          {1, 0.7},
+}
+
+var (
+	nomesMasculinos = []string{
+		"Antônio", "Carlos", "Eduardo", "Fernando", "Francisco", "Gabriel", "Gustavo", "Henrique",
+		"João", "José", "Lucas", "Luiz", "Marcelo", "Marcos", "Paulo", "Pedro", "Rafael", "Ricardo",
+		"Roberto", "Rodrigo", "Sérgio", "Thiago", "Vinícius", "Wellington",
+	}
+	nomesFemininos = []string{
+		"Adriana", "Aline", "Ana", "Beatriz", "Camila", "Carla", "Cláudia", "Daniela", "Fernanda",
+		"Gabriela", "Helena", "Juliana", "Luciana", "Mariana", "Maria", "Patrícia", "Renata",
+		"Simone", "Tatiana", "Vanessa",
+	}
+	sobrenomes = []string{
+		"Almeida", "Alves", "Barbosa", "Batista", "Cardoso", "Carvalho", "Castro", "Costa", "Dias",
+		"Fernandes", "Ferreira", "Gomes", "Lima", "Lopes", "Machado", "Martins", "Melo", "Mendes",
+		"Monteiro", "Moreira", "Nascimento", "Oliveira", "Pereira", "Pinto", "Ramos", "Ribeiro",
+		"Rocha", "Rodrigues", "Santos", "Silva", "Soares", "Sousa", "Teixeira", "Vieira",
+	}
+	escolaridades = []string{
+		"Superior Completo", "Superior Completo", "Superior Completo", "Pós-graduação",
+		"Superior Incompleto", "Ensino Médio Completo",
+	}
+)
+
+// categoriaCota é um tipo de gasto da cota parlamentar, com a faixa de valor por documento
+type categoriaCota struct {
+	tipo         string
+	minimo       float64
+	maximo       float64
+	pessoaFisica bool // pago a pessoa física (ex.: aluguel de escritório)
+}
+
+var categoriasCota = []categoriaCota{
+	{"MANUTENÇÃO DE ESCRITÓRIO DE APOIO À ATIVIDADE PARLAMENTAR", 800, 6000, true},
+	{"COMBUSTÍVEIS E LUBRIFICANTES.", 80, 900, false},
+	{"PASSAGEM AÉREA - SIGEPA", 600, 3500, false},
+	{"DIVULGAÇÃO DA ATIVIDADE PARLAMENTAR.", 1500, 18000, false},
+	{"LOCAÇÃO OU FRETAMENTO DE VEÍCULOS AUTOMOTORES", 2000, 12000, false},
+	{"TELEFONIA", 50, 600, false},
+	{"HOSPEDAGEM ,EXCETO DO PARLAMENTAR NO DISTRITO FEDERAL.", 200, 2500, false},
+	{"CONSULTORIAS, PESQUISAS E TRABALHOS TÉCNICOS.", 3000, 15000, false},
+	{"FORNECIMENTO DE ALIMENTAÇÃO DO PARLAMENTAR", 30, 400, false},
+}
+
+// ramos são usados para compor os nomes dos fornecedores de cada categoria
+var ramos = map[string][]string{
+	categoriasCota[0].tipo: {"Imóveis", "Administradora de Imóveis"},
+	categoriasCota[1].tipo: {"Auto Posto", "Posto de Combustíveis"},
+	categoriasCota[2].tipo: {"Linhas Aéreas", "Transportes Aéreos"},
+	categoriasCota[3].tipo: {"Gráfica e Editora", "Comunicação e Marketing", "Produções Audiovisuais"},
+	categoriasCota[4].tipo: {"Locadora de Veículos", "Locação e Transportes"},
+	categoriasCota[5].tipo: {"Telecomunicações"},
+	categoriasCota[6].tipo: {"Hotel", "Hotelaria e Turismo"},
+	categoriasCota[7].tipo: {"Consultoria", "Pesquisas e Estatística"},
+	categoriasCota[8].tipo: {"Restaurante", "Alimentação"},
+}
+
+var temasProposicao = []struct {
+	tema    string
+	ementas []string
+}{
+	{"Educação", []string{
+		"Amplia a oferta de educação em tempo integral na rede pública.",
+		"Institui programa de bolsas para estudantes do ensino médio técnico.",
+	}},
+	{"Saúde", []string{
+		"Institui programa de atenção primária à saúde em municípios de pequeno porte.",
+		"Dispõe sobre a distribuição gratuita de medicamentos para doenças raras.",
+	}},
+	{"Segurança Pública", []string{
+		"Amplia as penas para crimes cometidos contra agentes de segurança.",
+		"Cria o cadastro unificado de pessoas desaparecidas.",
+	}},
+	{"Economia", []string{
+		"Altera a tributação de micro e pequenas empresas.",
+		"Dispõe sobre a renegociação de dívidas de pessoas físicas.",
+	}},
+	{"Meio Ambiente", []string{
+		"Estabelece incentivos à geração distribuída de energia solar.",
+		"Institui a política de recuperação de nascentes e matas ciliares.",
+	}},
+	{"Administração Pública", []string{
+		"Dispõe sobre a transparência dos gastos com publicidade institucional.",
+		"Regulamenta o teletrabalho no serviço público.",
+	}},
+	{"Direitos Humanos", []string{
+		"Cria medidas de proteção às mulheres vítimas de violência doméstica.",
+		"Garante acessibilidade em prédios públicos e de uso coletivo.",
+	}},
+	{"Transporte", []string{
+		"Dispõe sobre a gratuidade no transporte coletivo para estudantes.",
+		"Altera o Código de Trânsito para endurecer as regras sobre embriaguez ao volante.",
+	}},
+}
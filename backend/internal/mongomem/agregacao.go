@@ -0,0 +1,337 @@
+package mongomem
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// agregar executa o pipeline sobre os documentos. Os estágios suportados são $match, $sort,
+// $skip, $limit, $project (inclusão/exclusão), $unwind, $group, $count e $lookup (por
+// igualdade, lendo as outras coleções do banco com colecao); as expressões aceitam só
+// referências a campos ("$campo") e valores literais.
+func agregar(docs []bson.D, estagios bson.A, colecao func(nome string) []bson.D) ([]bson.D, error) {
+	atual := make([]bson.D, len(docs))
+	copy(atual, docs)
+
+	for _, e := range estagios {
+		estagio, ok := e.(bson.D)
+		if !ok || len(estagio) != 1 {
+			return nil, fmt.Errorf("estágio de agregação inválido")
+		}
+		nome, arg := estagio[0].Key, estagio[0].Value
+
+		var err error
+		switch nome {
+		case "$match":
+			filtro, _ := arg.(bson.D)
+			atual, err = filtrar(atual, filtro)
+		case "$sort":
+			ordem, _ := arg.(bson.D)
+			err = ordenar(atual, ordem)
+		case "$skip":
+			n, _ := numero(arg)
+			if int(n) >= len(atual) {
+				atual = nil
+			} else {
+				atual = atual[int(n):]
+			}
+		case "$limit":
+			if n, _ := numero(arg); int(n) < len(atual) {
+				atual = atual[:int(n)]
+			}
+		case "$project":
+			projecao, _ := arg.(bson.D)
+			for i, d := range atual {
+				if atual[i], err = projetar(d, projecao); err != nil {
+					break
+				}
+			}
+		case "$count":
+			campo, _ := arg.(string)
+			if len(atual) == 0 {
+				atual = nil
+			} else {
+				atual = []bson.D{{{Key: campo, Value: int32(len(atual))}}}
+			}
+		case "$unwind":
+			atual, err = desenrolar(atual, arg)
+		case "$group":
+			grupo, _ := arg.(bson.D)
+			atual, err = agrupar(atual, grupo)
+		case "$lookup":
+			opcoes, _ := arg.(bson.D)
+			atual, err = juntar(atual, opcoes, colecao)
+		default:
+			err = fmt.Errorf("estágio de agregação não suportado: %s", nome)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return atual, nil
+}
+
+// avaliar resolve uma expressão: "$campo" lê o campo do documento; o resto é literal
+func avaliar(doc bson.D, expr interface{}) (interface{}, error) {
+	switch t := expr.(type) {
+	case string:
+		if strings.HasPrefix(t, "$$") {
+			return nil, fmt.Errorf("variáveis de agregação não suportadas: %s", t)
+		}
+		if strings.HasPrefix(t, "$") {
+			v, _ := obterCaminho(doc, partes(t[1:]))
+			return v, nil
+		}
+		return t, nil
+	case bson.D:
+		if _, ok := expressaoDeOperadores(t); ok {
+			return nil, fmt.Errorf("operador de expressão não suportado: %s", t[0].Key)
+		}
+		res := make(bson.D, 0, len(t))
+		for _, e := range t {
+			v, err := avaliar(doc, e.Value)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, bson.E{Key: e.Key, Value: v})
+		}
+		return res, nil
+	case bson.A:
+		res := make(bson.A, 0, len(t))
+		for _, e := range t {
+			v, err := avaliar(doc, e)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	default:
+		return expr, nil
+	}
+}
+
+func desenrolar(docs []bson.D, arg interface{}) ([]bson.D, error) {
+	caminho, _ := arg.(string)
+	preservar := false
+	if opcoes, ok := arg.(bson.D); ok {
+		p, _ := obter(opcoes, "path")
+		caminho, _ = p.(string)
+		preservar = flagDe(opcoes, "preserveNullAndEmptyArrays", false)
+	}
+	if !strings.HasPrefix(caminho, "$") {
+		return nil, fmt.Errorf("$unwind exige um caminho iniciado por $")
+	}
+	campo := partes(caminho[1:])
+
+	var res []bson.D
+	for _, d := range docs {
+		v, existe := obterCaminho(d, campo)
+		lista, ehLista := v.(bson.A)
+		switch {
+		case ehLista && len(lista) > 0:
+			for _, item := range lista {
+				novo, err := definirCaminho(copiarDoc(d), campo, item)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, novo.(bson.D))
+			}
+		case ehLista:
+			// Como no MongoDB, a lista vazia preservada some do documento
+			if preservar {
+				res = append(res, removerCaminho(copiarDoc(d), campo).(bson.D))
+			}
+		case !existe || v == nil:
+			if preservar {
+				res = append(res, d)
+			}
+		default:
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+// juntar executa o $lookup por igualdade: cada documento recebe, em as, a lista dos
+// documentos de from cujo foreignField é igual ao localField (ou a um dos seus elementos,
+// se for uma lista). A forma com pipeline e let não é suportada.
+func juntar(docs []bson.D, opcoes bson.D, colecao func(nome string) []bson.D) ([]bson.D, error) {
+	var campos [4]string
+	for i, nome := range []string{"from", "localField", "foreignField", "as"} {
+		v, _ := obter(opcoes, nome)
+		if campos[i], _ = v.(string); campos[i] == "" {
+			return nil, fmt.Errorf("$lookup exige from, localField, foreignField e as (a forma com pipeline não é suportada)")
+		}
+	}
+	outros := colecao(campos[0])
+	local, estrangeiro, como := partes(campos[1]), partes(campos[2]), partes(campos[3])
+
+	res := make([]bson.D, 0, len(docs))
+	for _, d := range docs {
+		// Sem o campo local, o MongoDB junta os documentos em que o campo estrangeiro é nulo
+		alvos := candidatos(resolver(d, local))
+		if len(alvos) == 0 {
+			alvos = []interface{}{nil}
+		}
+
+		encontrados := bson.A{}
+		for _, o := range outros {
+			valores := resolver(o, estrangeiro)
+			for _, alvo := range alvos {
+				if casaIgual(valores, alvo) {
+					encontrados = append(encontrados, copiarDoc(o))
+					break
+				}
+			}
+		}
+
+		novo, err := definirCaminho(copiarDoc(d), como, encontrados)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, novo.(bson.D))
+	}
+	return res, nil
+}
+
+// acumulador guarda o estado de um campo calculado do $group
+type acumulador struct {
+	operador string
+	expr     interface{}
+	valor    interface{}
+	soma     interface{}
+	contagem int
+	iniciado bool
+}
+
+func (a *acumulador) acumular(doc bson.D) error {
+	v, err := avaliar(doc, a.expr)
+	if err != nil {
+		return err
+	}
+
+	switch a.operador {
+	case "$sum", "$avg":
+		if _, ok := numero(v); !ok {
+			return nil
+		}
+		if a.soma == nil {
+			a.soma = int32(0)
+		}
+		if a.soma, err = somar(a.soma, v); err != nil {
+			return err
+		}
+		a.contagem++
+	case "$min", "$max":
+		if v == nil {
+			return nil
+		}
+		c := comparar(v, a.valor)
+		if !a.iniciado || (a.operador == "$min" && c < 0) || (a.operador == "$max" && c > 0) {
+			a.valor = v
+		}
+	case "$first":
+		if !a.iniciado {
+			a.valor = v
+		}
+	case "$last":
+		a.valor = v
+	case "$push":
+		lista, _ := a.valor.(bson.A)
+		a.valor = append(lista, v)
+	case "$addToSet":
+		lista, _ := a.valor.(bson.A)
+		if !contem(lista, v) {
+			lista = append(lista, v)
+		}
+		a.valor = lista
+	default:
+		return fmt.Errorf("acumulador não suportado: %s", a.operador)
+	}
+	a.iniciado = true
+	return nil
+}
+
+func (a *acumulador) resultado() interface{} {
+	switch a.operador {
+	case "$sum":
+		if a.soma == nil {
+			return int32(0)
+		}
+		return a.soma
+	case "$avg":
+		if a.contagem == 0 {
+			return nil
+		}
+		total, _ := numero(a.soma)
+		return total / float64(a.contagem)
+	case "$push", "$addToSet":
+		if a.valor == nil {
+			return bson.A{}
+		}
+	}
+	return a.valor
+}
+
+func agrupar(docs []bson.D, especificacao bson.D) ([]bson.D, error) {
+	idExpr, ok := obter(especificacao, "_id")
+	if !ok {
+		return nil, fmt.Errorf("$group exige _id")
+	}
+
+	type grupo struct {
+		chave        interface{}
+		acumuladores []*acumulador
+		campos       []string
+	}
+	var grupos []*grupo
+
+	for _, d := range docs {
+		chave, err := avaliar(d, idExpr)
+		if err != nil {
+			return nil, err
+		}
+
+		var g *grupo
+		for _, existente := range grupos {
+			if iguais(existente.chave, chave) {
+				g = existente
+				break
+			}
+		}
+		if g == nil {
+			g = &grupo{chave: chave}
+			for _, e := range especificacao {
+				if e.Key == "_id" {
+					continue
+				}
+				acc, ok := e.Value.(bson.D)
+				if !ok || len(acc) != 1 {
+					return nil, fmt.Errorf("acumulador inválido em %s", e.Key)
+				}
+				g.campos = append(g.campos, e.Key)
+				g.acumuladores = append(g.acumuladores, &acumulador{operador: acc[0].Key, expr: acc[0].Value})
+			}
+			grupos = append(grupos, g)
+		}
+
+		for _, a := range g.acumuladores {
+			if err := a.acumular(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res := make([]bson.D, 0, len(grupos))
+	for _, g := range grupos {
+		doc := bson.D{{Key: "_id", Value: g.chave}}
+		for i, a := range g.acumuladores {
+			doc = append(doc, bson.E{Key: g.campos[i], Value: a.resultado()})
+		}
+		res = append(res, doc)
+	}
+	return res, nil
+}
@@ -0,0 +1,245 @@
+package mongomem
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// atualizar aplica a atualização a uma cópia do documento e retorna o resultado.
+// Em inserindo (upsert), $setOnInsert também é aplicado.
+func atualizar(doc bson.D, atualizacao interface{}, inserindo bool) (bson.D, error) {
+	upd, ok := atualizacao.(bson.D)
+	if !ok {
+		return nil, fmt.Errorf("atualização por pipeline não suportada")
+	}
+
+	idAnterior, temID := obter(doc, "_id")
+
+	// Sem operadores, o documento é substituído, mantendo o _id
+	if len(upd) == 0 || !strings.HasPrefix(upd[0].Key, "$") {
+		novo := copiarDoc(upd)
+		if id, ok := obter(novo, "_id"); ok && temID && !iguais(id, idAnterior) {
+			return nil, erroIDImutavel
+		}
+		if temID {
+			novo = append(bson.D{{Key: "_id", Value: idAnterior}}, remover(novo, "_id")...)
+		}
+		return novo, nil
+	}
+
+	var res interface{} = copiarDoc(doc)
+	for _, op := range upd {
+		campos, ok := op.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("%s exige um documento", op.Key)
+		}
+		for _, c := range campos {
+			var err error
+			if res, err = aplicarOperador(res, op.Key, partes(c.Key), c.Value, inserindo); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	novo := res.(bson.D)
+	if temID {
+		if id, _ := obter(novo, "_id"); !iguais(id, idAnterior) {
+			return nil, erroIDImutavel
+		}
+	}
+	return novo, nil
+}
+
+func aplicarOperador(doc interface{}, operador string, caminho []string, arg interface{}, inserindo bool) (interface{}, error) {
+	atual, existe := obterCaminho(doc, caminho)
+
+	switch operador {
+	case "$set":
+		return definirCaminho(doc, caminho, copiar(arg))
+	case "$setOnInsert":
+		if !inserindo {
+			return doc, nil
+		}
+		return definirCaminho(doc, caminho, copiar(arg))
+	case "$unset":
+		return removerCaminho(doc, caminho), nil
+	case "$inc":
+		if !existe {
+			return definirCaminho(doc, caminho, arg)
+		}
+		soma, err := somar(atual, arg)
+		if err != nil {
+			return nil, fmt.Errorf("$inc em %s: %w", strings.Join(caminho, "."), err)
+		}
+		return definirCaminho(doc, caminho, soma)
+	case "$min", "$max":
+		if existe {
+			c := comparar(arg, atual)
+			if (operador == "$min" && c >= 0) || (operador == "$max" && c <= 0) {
+				return doc, nil
+			}
+		}
+		return definirCaminho(doc, caminho, copiar(arg))
+	case "$currentDate":
+		return definirCaminho(doc, caminho, primitive.NewDateTimeFromTime(time.Now()))
+	case "$push", "$addToSet":
+		lista, err := listaDoCampo(atual, existe, operador, caminho)
+		if err != nil {
+			return nil, err
+		}
+		itens := bson.A{arg}
+		if mods, ok := arg.(bson.D); ok && len(mods) > 0 && mods[0].Key == "$each" {
+			if len(mods) > 1 {
+				return nil, fmt.Errorf("modificador de %s não suportado: %s", operador, mods[1].Key)
+			}
+			if itens, ok = mods[0].Value.(bson.A); !ok {
+				return nil, fmt.Errorf("$each exige uma lista")
+			}
+		}
+		for _, item := range itens {
+			if operador == "$addToSet" && contem(lista, item) {
+				continue
+			}
+			lista = append(lista, copiar(item))
+		}
+		return definirCaminho(doc, caminho, lista)
+	case "$pull":
+		if !existe {
+			return doc, nil
+		}
+		lista, err := listaDoCampo(atual, existe, operador, caminho)
+		if err != nil {
+			return nil, err
+		}
+		restantes := bson.A{}
+		for _, elemento := range lista {
+			remove, err := casaElemento(elemento, arg)
+			if err != nil {
+				return nil, err
+			}
+			if !remove {
+				restantes = append(restantes, elemento)
+			}
+		}
+		return definirCaminho(doc, caminho, restantes)
+	default:
+		return nil, fmt.Errorf("operador de atualização não suportado: %s", operador)
+	}
+}
+
+func listaDoCampo(atual interface{}, existe bool, operador string, caminho []string) (bson.A, error) {
+	if !existe || atual == nil {
+		return bson.A{}, nil
+	}
+	lista, ok := atual.(bson.A)
+	if !ok {
+		return nil, fmt.Errorf("%s em %s: o campo não é uma lista", operador, strings.Join(caminho, "."))
+	}
+	return append(bson.A{}, lista...), nil
+}
+
+func contem(lista bson.A, item interface{}) bool {
+	for _, e := range lista {
+		if iguais(e, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// casaElemento decide se o elemento é removido por $pull
+func casaElemento(elemento, condicao interface{}) (bool, error) {
+	if ops, ok := expressaoDeOperadores(condicao); ok {
+		return casaOperadores([]interface{}{elemento}, ops)
+	}
+	if filtro, ok := condicao.(bson.D); ok {
+		if sub, ok := elemento.(bson.D); ok {
+			return casa(sub, filtro)
+		}
+		return false, nil
+	}
+	return iguais(elemento, condicao), nil
+}
+
+func somar(a, b interface{}) (interface{}, error) {
+	if _, ok := numero(a); !ok {
+		return nil, fmt.Errorf("o campo não é numérico")
+	}
+	if _, ok := numero(b); !ok {
+		return nil, fmt.Errorf("o incremento não é numérico")
+	}
+
+	x, xi := inteiro(a)
+	y, yi := inteiro(b)
+	if xi && yi {
+		soma := x + y
+		_, a64 := a.(int64)
+		_, b64 := b.(int64)
+		if !a64 && !b64 && soma == int64(int32(soma)) {
+			return int32(soma), nil
+		}
+		return soma, nil
+	}
+	fx, _ := numero(a)
+	fy, _ := numero(b)
+	return fx + fy, nil
+}
+
+// documentoUpsert monta o documento inicial de um upsert com as igualdades do filtro
+func documentoUpsert(filtro bson.D) (bson.D, error) {
+	var doc interface{} = bson.D{}
+	var err error
+	for _, e := range filtro {
+		if e.Key == "$and" {
+			subs, _ := e.Value.(bson.A)
+			for _, s := range subs {
+				sub, ok := s.(bson.D)
+				if !ok {
+					continue
+				}
+				parcial, err := documentoUpsert(sub)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range parcial {
+					if doc, err = definirCaminho(doc, partes(p.Key), p.Value); err != nil {
+						return nil, err
+					}
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(e.Key, "$") {
+			continue
+		}
+
+		v := e.Value
+		if ops, ok := expressaoDeOperadores(v); ok {
+			eq, ok := obter(ops, "$eq")
+			if !ok {
+				continue
+			}
+			v = eq
+		}
+		if _, ok := v.(primitive.Regex); ok {
+			continue
+		}
+		if doc, err = definirCaminho(doc, partes(e.Key), copiar(v)); err != nil {
+			return nil, err
+		}
+	}
+	return doc.(bson.D), nil
+}
+
+// comID garante o _id como primeiro campo, gerando um ObjectID se faltar
+func comID(doc bson.D) bson.D {
+	id, ok := obter(doc, "_id")
+	if !ok {
+		id = primitive.NewObjectID()
+	}
+	return append(bson.D{{Key: "_id", Value: id}}, remover(doc, "_id")...)
+}
@@ -0,0 +1,661 @@
+package mongomem
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// erroComando é a resposta de erro de um comando ({ok: 0, errmsg, code, codeName})
+type erroComando struct {
+	codigo int32
+	nome   string
+	msg    string
+}
+
+func (e *erroComando) Error() string {
+	return e.msg
+}
+
+var erroIDImutavel = &erroComando{codigo: 66, nome: "ImmutableField", msg: "Performing an update on the path '_id' would modify the immutable field '_id'"}
+
+// erroSemTransacoes é o que um servidor isolado (sem replica set), como o mongomem, responde
+// aos comandos de uma transação
+var erroSemTransacoes = &erroComando{codigo: 20, nome: "IllegalOperation", msg: "Transaction numbers are only allowed on a replica set member or mongos"}
+
+// erroDuplicado é o erro de chave duplicada do índice de _id
+func erroDuplicado(banco, colecao string, id interface{}) *erroComando {
+	return &erroComando{
+		codigo: 11000,
+		nome:   "DuplicateKey",
+		msg:    fmt.Sprintf("E11000 duplicate key error collection: %s.%s index: _id_ dup key: { _id: %v }", banco, colecao, id),
+	}
+}
+
+type comando func(s *Servidor, banco string, cmd bson.D) (bson.D, error)
+
+var comandos map[string]comando
+
+func init() {
+	comandos = map[string]comando{
+		"hello":           cmdHello,
+		"isMaster":        cmdHello,
+		"ismaster":        cmdHello,
+		"ping":            cmdVazio,
+		"endSessions":     cmdVazio,
+		"buildInfo":       cmdBuildInfo,
+		"buildinfo":       cmdBuildInfo,
+		"killCursors":     cmdKillCursors,
+		"getMore":         cmdGetMore,
+		"find":            cmdFind,
+		"insert":          cmdInsert,
+		"update":          cmdUpdate,
+		"delete":          cmdDelete,
+		"findAndModify":   cmdFindAndModify,
+		"findandmodify":   cmdFindAndModify,
+		"count":           cmdCount,
+		"distinct":        cmdDistinct,
+		"aggregate":       cmdAggregate,
+		"create":          cmdCreate,
+		"createIndexes":   cmdCreateIndexes,
+		"listIndexes":     cmdListIndexes,
+		"listCollections": cmdListCollections,
+		"drop":            cmdDrop,
+		"dropDatabase":    cmdDropDatabase,
+	}
+}
+
+// executar roda o comando e monta a resposta; erros viram {ok: 0}
+func (s *Servidor) executar(banco string, cmd bson.D) bson.D {
+	if len(cmd) == 0 {
+		return respostaErro(&erroComando{codigo: 59, nome: "CommandNotFound", msg: "comando vazio"})
+	}
+	if db, ok := obter(cmd, "$db"); ok {
+		banco, _ = db.(string)
+	}
+
+	if _, ok := obter(cmd, "autocommit"); ok {
+		return respostaErro(erroSemTransacoes)
+	}
+
+	fn, ok := comandos[cmd[0].Key]
+	if !ok {
+		return respostaErro(&erroComando{codigo: 59, nome: "CommandNotFound", msg: fmt.Sprintf("comando não suportado pelo mongomem: %s", cmd[0].Key)})
+	}
+
+	s.mu.Lock()
+	res, err := fn(s, banco, cmd)
+	s.mu.Unlock()
+	if err != nil {
+		return respostaErro(err)
+	}
+	return append(res, bson.E{Key: "ok", Value: 1.0})
+}
+
+func respostaErro(err error) bson.D {
+	var e *erroComando
+	if !errors.As(err, &e) {
+		e = &erroComando{codigo: 2, nome: "BadValue", msg: err.Error()}
+	}
+	return bson.D{
+		{Key: "ok", Value: 0.0},
+		{Key: "errmsg", Value: e.msg},
+		{Key: "code", Value: e.codigo},
+		{Key: "codeName", Value: e.nome},
+	}
+}
+
+// colecao retorna a coleção; com criar, cria a coleção e o banco se não existirem
+func (s *Servidor) colecao(banco, nome string, criar bool) *colecao {
+	colecoes, ok := s.bancos[banco]
+	if !ok {
+		if !criar {
+			return nil
+		}
+		colecoes = make(map[string]*colecao)
+		s.bancos[banco] = colecoes
+	}
+	c, ok := colecoes[nome]
+	if !ok && criar {
+		c = &colecao{}
+		colecoes[nome] = c
+	}
+	return c
+}
+
+// documentos retorna os documentos da coleção (a lista guardada; não altere os itens)
+func (s *Servidor) documentos(banco, nome string) []bson.D {
+	if c := s.colecao(banco, nome, false); c != nil {
+		return c.docs
+	}
+	return nil
+}
+
+func (c *colecao) posicao(id interface{}) int {
+	for i, d := range c.docs {
+		if atual, _ := obter(d, "_id"); iguais(atual, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *colecao) inserir(banco, nome string, doc bson.D) (bson.D, error) {
+	doc = comID(doc)
+	if c.posicao(doc[0].Value) >= 0 {
+		return nil, erroDuplicado(banco, nome, doc[0].Value)
+	}
+	if err := c.conferirUnicos(banco, nome, doc); err != nil {
+		return nil, err
+	}
+	c.docs = append(c.docs, doc)
+	return doc, nil
+}
+
+func (c *colecao) substituir(banco, nome string, doc bson.D) error {
+	if err := c.conferirUnicos(banco, nome, doc); err != nil {
+		return err
+	}
+	c.docs[c.posicao(doc[0].Value)] = doc
+	return nil
+}
+
+func (c *colecao) excluir(doc bson.D) {
+	id, _ := obter(doc, "_id")
+	i := c.posicao(id)
+	c.docs = append(c.docs[:i], c.docs[i+1:]...)
+}
+
+func nomeColecao(cmd bson.D) (string, error) {
+	nome, ok := cmd[0].Value.(string)
+	if !ok || nome == "" {
+		return "", fmt.Errorf("%s exige o nome da coleção", cmd[0].Key)
+	}
+	return nome, nil
+}
+
+func docDe(cmd bson.D, campo string) bson.D {
+	v, _ := obter(cmd, campo)
+	d, _ := v.(bson.D)
+	return d
+}
+
+func inteiroDe(cmd bson.D, campo string) int {
+	v, _ := obter(cmd, campo)
+	n, _ := numero(v)
+	if n < 0 {
+		n = -n
+	}
+	return int(n)
+}
+
+func flagDe(cmd bson.D, campo string, padrao bool) bool {
+	v, ok := obter(cmd, campo)
+	if !ok {
+		return padrao
+	}
+	return verdadeiro(v)
+}
+
+func respostaCursor(banco, nome string, docs []bson.D) bson.D {
+	lote := make(bson.A, len(docs))
+	for i, d := range docs {
+		lote[i] = d
+	}
+	return bson.D{{Key: "cursor", Value: bson.D{
+		{Key: "firstBatch", Value: lote},
+		{Key: "id", Value: int64(0)},
+		{Key: "ns", Value: banco + "." + nome},
+	}}}
+}
+
+func cmdHello(s *Servidor, _ string, _ bson.D) (bson.D, error) {
+	return bson.D{
+		{Key: "helloOk", Value: true},
+		{Key: "ismaster", Value: true},
+		{Key: "isWritablePrimary", Value: true},
+		{Key: "maxBsonObjectSize", Value: int32(16 * 1024 * 1024)},
+		{Key: "maxMessageSizeBytes", Value: int32(tamanhoMaximoMensagem)},
+		{Key: "maxWriteBatchSize", Value: int32(100000)},
+		{Key: "localTime", Value: primitive.NewDateTimeFromTime(time.Now())},
+		{Key: "logicalSessionTimeoutMinutes", Value: int32(30)},
+		{Key: "connectionId", Value: s.proximaConexao.Add(1)},
+		{Key: "minWireVersion", Value: int32(0)},
+		{Key: "maxWireVersion", Value: int32(17)},
+		{Key: "readOnly", Value: false},
+	}, nil
+}
+
+func cmdVazio(*Servidor, string, bson.D) (bson.D, error) {
+	return bson.D{}, nil
+}
+
+func cmdBuildInfo(*Servidor, string, bson.D) (bson.D, error) {
+	return bson.D{
+		{Key: "version", Value: "6.0.0"},
+		{Key: "versionArray", Value: bson.A{int32(6), int32(0), int32(0), int32(0)}},
+	}, nil
+}
+
+func cmdKillCursors(_ *Servidor, _ string, cmd bson.D) (bson.D, error) {
+	cursores, _ := obter(cmd, "cursors")
+	return bson.D{
+		{Key: "cursorsKilled", Value: bson.A{}},
+		{Key: "cursorsNotFound", Value: cursores},
+		{Key: "cursorsAlive", Value: bson.A{}},
+		{Key: "cursorsUnknown", Value: bson.A{}},
+	}, nil
+}
+
+// Os cursores sempre voltam completos no primeiro lote, então não há o que continuar
+func cmdGetMore(*Servidor, string, bson.D) (bson.D, error) {
+	return nil, &erroComando{codigo: 43, nome: "CursorNotFound", msg: "cursor não encontrado"}
+}
+
+// buscar aplica filtro, ordenação, salto e limite, como find e findAndModify
+func (s *Servidor) buscar(banco, nome string, filtro, ordem bson.D, salto, limite int) ([]bson.D, error) {
+	docs, err := filtrar(s.documentos(banco, nome), filtro)
+	if err != nil {
+		return nil, err
+	}
+	if len(ordem) > 0 {
+		if err := ordenar(docs, ordem); err != nil {
+			return nil, err
+		}
+	}
+	if salto >= len(docs) {
+		return nil, nil
+	}
+	docs = docs[salto:]
+	if limite > 0 && limite < len(docs) {
+		docs = docs[:limite]
+	}
+	return docs, nil
+}
+
+func cmdFind(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.buscar(banco, nome, docDe(cmd, "filter"), docDe(cmd, "sort"), inteiroDe(cmd, "skip"), inteiroDe(cmd, "limit"))
+	if err != nil {
+		return nil, err
+	}
+
+	projecao := docDe(cmd, "projection")
+	res := make([]bson.D, len(docs))
+	for i, d := range docs {
+		if res[i], err = projetar(d, projecao); err != nil {
+			return nil, err
+		}
+	}
+	return respostaCursor(banco, nome, res), nil
+}
+
+func cmdInsert(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	docs, _ := obter(cmd, "documents")
+	lista, _ := docs.(bson.A)
+	ordenado := flagDe(cmd, "ordered", true)
+
+	c := s.colecao(banco, nome, true)
+	n := int32(0)
+	var errosEscrita bson.A
+	for i, item := range lista {
+		doc, ok := item.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("insert exige documentos")
+		}
+		if _, err := c.inserir(banco, nome, doc); err != nil {
+			errosEscrita = append(errosEscrita, erroEscrita(i, err))
+			if ordenado {
+				break
+			}
+			continue
+		}
+		n++
+	}
+
+	res := bson.D{{Key: "n", Value: n}}
+	if len(errosEscrita) > 0 {
+		res = append(res, bson.E{Key: "writeErrors", Value: errosEscrita})
+	}
+	return res, nil
+}
+
+func erroEscrita(indice int, err error) bson.D {
+	e := respostaErro(err)
+	msg, _ := obter(e, "errmsg")
+	codigo, _ := obter(e, "code")
+	return bson.D{
+		{Key: "index", Value: int32(indice)},
+		{Key: "code", Value: codigo},
+		{Key: "errmsg", Value: msg},
+	}
+}
+
+func cmdUpdate(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	updates, _ := obter(cmd, "updates")
+	lista, _ := updates.(bson.A)
+	ordenado := flagDe(cmd, "ordered", true)
+
+	c := s.colecao(banco, nome, true)
+	var n, modificados int32
+	var inseridos, errosEscrita bson.A
+	for i, item := range lista {
+		upd, ok := item.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("update exige documentos")
+		}
+		if _, ok := obter(upd, "arrayFilters"); ok {
+			return nil, fmt.Errorf("arrayFilters não suportado")
+		}
+		u, _ := obter(upd, "u")
+
+		encontrados, err := filtrar(c.docs, docDe(upd, "q"))
+		if err == nil && len(encontrados) == 0 && flagDe(upd, "upsert", false) {
+			var doc bson.D
+			if doc, err = s.upsert(c, banco, nome, docDe(upd, "q"), u); err == nil {
+				n++
+				inseridos = append(inseridos, bson.D{{Key: "index", Value: int32(i)}, {Key: "_id", Value: doc[0].Value}})
+			}
+		}
+		if err == nil && len(encontrados) > 0 {
+			if !flagDe(upd, "multi", false) {
+				encontrados = encontrados[:1]
+			}
+			for _, antigo := range encontrados {
+				var novo bson.D
+				if novo, err = atualizar(antigo, u, false); err != nil {
+					break
+				}
+				if !iguais(antigo, novo) {
+					if err = c.substituir(banco, nome, novo); err != nil {
+						break
+					}
+					modificados++
+				}
+				n++
+			}
+		}
+
+		if err != nil {
+			errosEscrita = append(errosEscrita, erroEscrita(i, err))
+			if ordenado {
+				break
+			}
+		}
+	}
+
+	res := bson.D{{Key: "n", Value: n}, {Key: "nModified", Value: modificados}}
+	if len(inseridos) > 0 {
+		res = append(res, bson.E{Key: "upserted", Value: inseridos})
+	}
+	if len(errosEscrita) > 0 {
+		res = append(res, bson.E{Key: "writeErrors", Value: errosEscrita})
+	}
+	return res, nil
+}
+
+// upsert insere o documento montado com as igualdades do filtro e a atualização
+func (s *Servidor) upsert(c *colecao, banco, nome string, filtro bson.D, u interface{}) (bson.D, error) {
+	semente, err := documentoUpsert(filtro)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := atualizar(semente, u, true)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := obter(semente, "_id"); ok {
+		doc = definir(doc, "_id", id)
+	}
+	return c.inserir(banco, nome, doc)
+}
+
+func cmdDelete(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	deletes, _ := obter(cmd, "deletes")
+	lista, _ := deletes.(bson.A)
+
+	c := s.colecao(banco, nome, true)
+	n := int32(0)
+	for _, item := range lista {
+		del, ok := item.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("delete exige documentos")
+		}
+		encontrados, err := filtrar(c.docs, docDe(del, "q"))
+		if err != nil {
+			return nil, err
+		}
+		if inteiroDe(del, "limit") == 1 && len(encontrados) > 1 {
+			encontrados = encontrados[:1]
+		}
+		for _, d := range encontrados {
+			c.excluir(d)
+			n++
+		}
+	}
+	return bson.D{{Key: "n", Value: n}}, nil
+}
+
+func cmdFindAndModify(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	c := s.colecao(banco, nome, true)
+
+	encontrados, err := s.buscar(banco, nome, docDe(cmd, "query"), docDe(cmd, "sort"), 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	u, _ := obter(cmd, "update")
+	retornarNovo := flagDe(cmd, "new", false)
+
+	var valor interface{}
+	ultimoErro := bson.D{{Key: "n", Value: int32(0)}}
+	switch {
+	case len(encontrados) > 0 && flagDe(cmd, "remove", false):
+		c.excluir(encontrados[0])
+		valor = encontrados[0]
+		ultimoErro = bson.D{{Key: "n", Value: int32(1)}}
+	case len(encontrados) > 0:
+		novo, err := atualizar(encontrados[0], u, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.substituir(banco, nome, novo); err != nil {
+			return nil, err
+		}
+		valor = encontrados[0]
+		if retornarNovo {
+			valor = novo
+		}
+		ultimoErro = bson.D{{Key: "n", Value: int32(1)}, {Key: "updatedExisting", Value: true}}
+	case flagDe(cmd, "upsert", false):
+		doc, err := s.upsert(c, banco, nome, docDe(cmd, "query"), u)
+		if err != nil {
+			return nil, err
+		}
+		if retornarNovo {
+			valor = doc
+		}
+		ultimoErro = bson.D{{Key: "n", Value: int32(1)}, {Key: "updatedExisting", Value: false}, {Key: "upserted", Value: doc[0].Value}}
+	}
+
+	if doc, ok := valor.(bson.D); ok {
+		if valor, err = projetar(doc, docDe(cmd, "fields")); err != nil {
+			return nil, err
+		}
+	}
+	return bson.D{{Key: "lastErrorObject", Value: ultimoErro}, {Key: "value", Value: valor}}, nil
+}
+
+func cmdCount(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.buscar(banco, nome, docDe(cmd, "query"), nil, inteiroDe(cmd, "skip"), inteiroDe(cmd, "limit"))
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{Key: "n", Value: int32(len(docs))}}, nil
+}
+
+func cmdDistinct(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	chave, _ := obter(cmd, "key")
+	caminho, ok := chave.(string)
+	if !ok {
+		return nil, fmt.Errorf("distinct exige a chave")
+	}
+	docs, err := filtrar(s.documentos(banco, nome), docDe(cmd, "query"))
+	if err != nil {
+		return nil, err
+	}
+
+	valores := bson.A{}
+	for _, d := range docs {
+		for _, v := range resolver(d, partes(caminho)) {
+			itens := bson.A{v}
+			if lista, ok := v.(bson.A); ok {
+				itens = lista
+			}
+			for _, item := range itens {
+				if !contem(valores, item) {
+					valores = append(valores, item)
+				}
+			}
+		}
+	}
+	return bson.D{{Key: "values", Value: valores}}, nil
+}
+
+func cmdAggregate(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, _ := obter(cmd, "pipeline")
+	estagios, ok := pipeline.(bson.A)
+	if !ok {
+		return nil, fmt.Errorf("aggregate exige o pipeline")
+	}
+
+	docs, err := agregar(s.documentos(banco, nome), estagios, func(outra string) []bson.D {
+		return s.documentos(banco, outra)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return respostaCursor(banco, nome, docs), nil
+}
+
+func cmdCreate(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	s.colecao(banco, nome, true)
+	return bson.D{}, nil
+}
+
+// Os índices só são registrados; os únicos passam a ser conferidos em cada escrita
+func cmdCreateIndexes(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	indexes, _ := obter(cmd, "indexes")
+	lista, ok := indexes.(bson.A)
+	if !ok || len(lista) == 0 {
+		return nil, fmt.Errorf("createIndexes exige os índices")
+	}
+
+	c := s.colecao(banco, nome, true)
+	antes := int32(len(c.indices) + 1)
+	for _, item := range lista {
+		especificacao, ok := item.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("createIndexes exige documentos")
+		}
+		ind, err := novoIndice(especificacao)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.criarIndice(banco, nome, ind); err != nil {
+			return nil, err
+		}
+	}
+	return bson.D{
+		{Key: "numIndexesBefore", Value: antes},
+		{Key: "numIndexesAfter", Value: int32(len(c.indices) + 1)},
+	}, nil
+}
+
+func cmdListIndexes(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	indices := []bson.D{{
+		{Key: "v", Value: int32(2)},
+		{Key: "key", Value: bson.D{{Key: "_id", Value: int32(1)}}},
+		{Key: "name", Value: "_id_"},
+	}}
+	if c := s.colecao(banco, nome, false); c != nil {
+		for _, ind := range c.indices {
+			indices = append(indices, ind.descricao())
+		}
+	}
+	return respostaCursor(banco, nome, indices), nil
+}
+
+func cmdListCollections(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	var docs []bson.D
+	for nome := range s.bancos[banco] {
+		docs = append(docs, bson.D{{Key: "name", Value: nome}, {Key: "type", Value: "collection"}})
+	}
+	docs, err := filtrar(docs, docDe(cmd, "filter"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ordenar(docs, bson.D{{Key: "name", Value: int32(1)}}); err != nil {
+		return nil, err
+	}
+	return respostaCursor(banco, "$cmd.listCollections", docs), nil
+}
+
+func cmdDrop(s *Servidor, banco string, cmd bson.D) (bson.D, error) {
+	nome, err := nomeColecao(cmd)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.bancos[banco], nome)
+	return bson.D{}, nil
+}
+
+func cmdDropDatabase(s *Servidor, banco string, _ bson.D) (bson.D, error) {
+	delete(s.bancos, banco)
+	return bson.D{}, nil
+}
@@ -0,0 +1,362 @@
+package mongomem
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// casa avalia um filtro de consulta contra o documento
+func casa(doc bson.D, filtro bson.D) (bool, error) {
+	for _, e := range filtro {
+		var ok bool
+		var err error
+		switch e.Key {
+		case "$and", "$or", "$nor":
+			ok, err = casaLogico(doc, e.Key, e.Value)
+		case "$comment":
+			ok = true
+		default:
+			if strings.HasPrefix(e.Key, "$") {
+				return false, fmt.Errorf("operador de consulta não suportado: %s", e.Key)
+			}
+			ok, err = casaCondicao(resolver(doc, partes(e.Key)), e.Value)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func casaLogico(doc bson.D, operador string, arg interface{}) (bool, error) {
+	filtros, ok := arg.(bson.A)
+	if !ok || len(filtros) == 0 {
+		return false, fmt.Errorf("%s exige uma lista não vazia", operador)
+	}
+	for _, f := range filtros {
+		sub, ok := f.(bson.D)
+		if !ok {
+			return false, fmt.Errorf("%s exige uma lista de documentos", operador)
+		}
+		c, err := casa(doc, sub)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case operador == "$and" && !c:
+			return false, nil
+		case operador == "$or" && c:
+			return true, nil
+		case operador == "$nor" && c:
+			return false, nil
+		}
+	}
+	return operador != "$or", nil
+}
+
+// expressaoDeOperadores indica se a condição é do tipo {"$gte": ..., "$lte": ...}
+func expressaoDeOperadores(v interface{}) (bson.D, bool) {
+	d, ok := v.(bson.D)
+	if !ok || len(d) == 0 || !strings.HasPrefix(d[0].Key, "$") {
+		return nil, false
+	}
+	return d, true
+}
+
+// casaCondicao avalia a condição de um campo contra os valores encontrados no caminho
+func casaCondicao(valores []interface{}, condicao interface{}) (bool, error) {
+	if ops, ok := expressaoDeOperadores(condicao); ok {
+		return casaOperadores(valores, ops)
+	}
+	if re, ok := condicao.(primitive.Regex); ok {
+		return casaRegex(valores, re.Pattern, re.Options)
+	}
+	return casaIgual(valores, condicao), nil
+}
+
+func casaOperadores(valores []interface{}, ops bson.D) (bool, error) {
+	for _, op := range ops {
+		ok, err := casaOperador(valores, op.Key, op.Value, ops)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func casaOperador(valores []interface{}, operador string, arg interface{}, ops bson.D) (bool, error) {
+	switch operador {
+	case "$eq":
+		return casaIgual(valores, arg), nil
+	case "$ne":
+		return !casaIgual(valores, arg), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		return casaComparacao(valores, operador, arg), nil
+	case "$in", "$nin":
+		lista, ok := arg.(bson.A)
+		if !ok {
+			return false, fmt.Errorf("%s exige uma lista", operador)
+		}
+		algum := false
+		for _, item := range lista {
+			if re, ok := item.(primitive.Regex); ok {
+				if c, _ := casaRegex(valores, re.Pattern, re.Options); c {
+					algum = true
+					break
+				}
+			} else if casaIgual(valores, item) {
+				algum = true
+				break
+			}
+		}
+		return algum == (operador == "$in"), nil
+	case "$exists":
+		return verdadeiro(arg) == (len(valores) > 0), nil
+	case "$regex":
+		opcoes, _ := obter(ops, "$options")
+		opcoesTexto, _ := opcoes.(string)
+		switch p := arg.(type) {
+		case string:
+			return casaRegex(valores, p, opcoesTexto)
+		case primitive.Regex:
+			if opcoesTexto == "" {
+				opcoesTexto = p.Options
+			}
+			return casaRegex(valores, p.Pattern, opcoesTexto)
+		default:
+			return false, fmt.Errorf("$regex exige um texto")
+		}
+	case "$options":
+		return true, nil
+	case "$not":
+		var c bool
+		var err error
+		switch t := arg.(type) {
+		case bson.D:
+			c, err = casaOperadores(valores, t)
+		case primitive.Regex:
+			c, err = casaRegex(valores, t.Pattern, t.Options)
+		default:
+			return false, fmt.Errorf("$not exige um documento ou uma expressão regular")
+		}
+		return !c && err == nil, err
+	case "$elemMatch":
+		cond, ok := arg.(bson.D)
+		if !ok {
+			return false, fmt.Errorf("$elemMatch exige um documento")
+		}
+		for _, v := range valores {
+			lista, ok := v.(bson.A)
+			if !ok {
+				continue
+			}
+			for _, elemento := range lista {
+				var c bool
+				var err error
+				if ops, ok := expressaoDeOperadores(cond); ok {
+					c, err = casaOperadores([]interface{}{elemento}, ops)
+				} else if sub, ok := elemento.(bson.D); ok {
+					c, err = casa(sub, cond)
+				}
+				if err != nil {
+					return false, err
+				}
+				if c {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	case "$size":
+		n, ok := inteiro(arg)
+		if !ok {
+			f, ok := numero(arg)
+			if !ok {
+				return false, fmt.Errorf("$size exige um número")
+			}
+			n = int64(f)
+		}
+		for _, v := range valores {
+			if lista, ok := v.(bson.A); ok && int64(len(lista)) == n {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$all":
+		lista, ok := arg.(bson.A)
+		if !ok {
+			return false, fmt.Errorf("$all exige uma lista")
+		}
+		for _, item := range lista {
+			if !casaIgual(valores, item) {
+				return false, nil
+			}
+		}
+		return len(lista) > 0, nil
+	default:
+		return false, fmt.Errorf("operador de consulta não suportado: %s", operador)
+	}
+}
+
+// candidatos são os valores comparados: o próprio valor e, se for uma lista, cada elemento
+func candidatos(valores []interface{}) []interface{} {
+	var c []interface{}
+	for _, v := range valores {
+		c = append(c, v)
+		if lista, ok := v.(bson.A); ok {
+			c = append(c, lista...)
+		}
+	}
+	return c
+}
+
+// casaIgual segue a igualdade do MongoDB: null também casa com o campo ausente
+func casaIgual(valores []interface{}, alvo interface{}) bool {
+	if alvo == nil && len(valores) == 0 {
+		return true
+	}
+	for _, v := range candidatos(valores) {
+		if iguais(v, alvo) {
+			return true
+		}
+	}
+	return false
+}
+
+// casaComparacao só compara valores da mesma classe de tipo (números com números, datas
+// com datas...), como o MongoDB
+func casaComparacao(valores []interface{}, operador string, arg interface{}) bool {
+	for _, v := range candidatos(valores) {
+		if classe(v) != classe(arg) {
+			continue
+		}
+		c := comparar(v, arg)
+		switch {
+		case operador == "$gt" && c > 0,
+			operador == "$gte" && c >= 0,
+			operador == "$lt" && c < 0,
+			operador == "$lte" && c <= 0:
+			return true
+		}
+	}
+	return false
+}
+
+func casaRegex(valores []interface{}, padrao, opcoes string) (bool, error) {
+	flags := ""
+	for _, o := range opcoes {
+		switch o {
+		case 'i', 'm', 's':
+			flags += string(o)
+		}
+	}
+	if flags != "" {
+		padrao = "(?" + flags + ")" + padrao
+	}
+	re, err := regexp.Compile(padrao)
+	if err != nil {
+		return false, fmt.Errorf("expressão regular inválida: %w", err)
+	}
+	for _, v := range candidatos(valores) {
+		if s, ok := v.(string); ok && re.MatchString(s) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// filtrar retorna os documentos que casam com o filtro
+func filtrar(docs []bson.D, filtro bson.D) ([]bson.D, error) {
+	var res []bson.D
+	for _, d := range docs {
+		ok, err := casa(d, filtro)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+// ordenar ordena os documentos pela especificação ({campo: 1 | -1}); a ordenação é estável,
+// então empates mantêm a ordem de inserção
+func ordenar(docs []bson.D, especificacao bson.D) error {
+	direcoes := make([]int, len(especificacao))
+	for i, e := range especificacao {
+		n, ok := numero(e.Value)
+		if !ok || (n != 1 && n != -1) {
+			return fmt.Errorf("ordenação não suportada para %s", e.Key)
+		}
+		direcoes[i] = int(n)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for k, e := range especificacao {
+			a, _ := obterCaminho(docs[i], partes(e.Key))
+			b, _ := obterCaminho(docs[j], partes(e.Key))
+			if c := comparar(a, b) * direcoes[k]; c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// projetar aplica projeções simples de inclusão ({campo: 1}) ou exclusão ({campo: 0})
+func projetar(doc bson.D, projecao bson.D) (bson.D, error) {
+	if len(projecao) == 0 {
+		return doc, nil
+	}
+
+	incluir := false
+	incluirID := true
+	for _, e := range projecao {
+		switch e.Value.(type) {
+		case bool, int32, int64, float64:
+		default:
+			return nil, fmt.Errorf("projeção não suportada para %s", e.Key)
+		}
+		if e.Key == "_id" {
+			incluirID = verdadeiro(e.Value)
+		} else if verdadeiro(e.Value) {
+			incluir = true
+		}
+	}
+
+	if !incluir {
+		var res interface{} = copiarDoc(doc)
+		for _, e := range projecao {
+			if !verdadeiro(e.Value) {
+				res = removerCaminho(res, partes(e.Key))
+			}
+		}
+		return res.(bson.D), nil
+	}
+
+	var res interface{} = bson.D{}
+	if id, ok := obter(doc, "_id"); ok && incluirID {
+		res = bson.D{{Key: "_id", Value: id}}
+	}
+	for _, e := range projecao {
+		if e.Key == "_id" || !verdadeiro(e.Value) {
+			continue
+		}
+		v, ok := obterCaminho(doc, partes(e.Key))
+		if !ok {
+			continue
+		}
+		var err error
+		if res, err = definirCaminho(res, partes(e.Key), copiar(v)); err != nil {
+			return nil, err
+		}
+	}
+	return res.(bson.D), nil
+}
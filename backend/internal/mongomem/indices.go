@@ -0,0 +1,155 @@
+package mongomem
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// indiceColecao é um índice criado com createIndexes. Só os únicos mudam o comportamento: a
+// inserção ou atualização que repetir a chave de outro documento falha com E11000, como
+// no MongoDB. Um campo ausente conta como null.
+type indiceColecao struct {
+	nome  string
+	chave bson.D
+	unico bool
+}
+
+// opcoesIndice são as opções aceitas em createIndexes; as outras (parciais, esparsos, TTL,
+// collation...) mudariam o que o índice garante e respondem com erro
+var opcoesIndice = map[string]bool{"key": true, "name": true, "unique": true, "background": true, "v": true}
+
+func novoIndice(especificacao bson.D) (indiceColecao, error) {
+	for _, e := range especificacao {
+		if !opcoesIndice[e.Key] {
+			return indiceColecao{}, fmt.Errorf("opção de índice não suportada pelo mongomem: %s", e.Key)
+		}
+	}
+	chave := docDe(especificacao, "key")
+	if len(chave) == 0 {
+		return indiceColecao{}, fmt.Errorf("índice sem chave")
+	}
+	for _, c := range chave {
+		if n, ok := numero(c.Value); !ok || (n != 1 && n != -1) {
+			return indiceColecao{}, fmt.Errorf("tipo de índice não suportado pelo mongomem: %s: %v", c.Key, c.Value)
+		}
+	}
+	nome, _ := obter(especificacao, "name")
+	texto, _ := nome.(string)
+	if texto == "" {
+		return indiceColecao{}, fmt.Errorf("índice sem nome")
+	}
+	return indiceColecao{nome: texto, chave: chave, unico: flagDe(especificacao, "unique", false)}, nil
+}
+
+// mesmaDefinicao diz se os dois índices têm a mesma chave e unicidade
+func (i indiceColecao) mesmaDefinicao(outro indiceColecao) bool {
+	if i.unico != outro.unico || len(i.chave) != len(outro.chave) {
+		return false
+	}
+	for n, c := range i.chave {
+		if c.Key != outro.chave[n].Key || !iguais(c.Value, outro.chave[n].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// valores monta a chave do documento no índice; listas (índices multichave) não são suportadas
+func (i indiceColecao) valores(doc bson.D) (bson.A, error) {
+	valores := make(bson.A, len(i.chave))
+	for n, c := range i.chave {
+		v, _ := obterCaminho(doc, partes(c.Key))
+		if _, ok := v.(bson.A); ok {
+			return nil, fmt.Errorf("índice único sobre lista não suportado pelo mongomem: %s", c.Key)
+		}
+		valores[n] = v
+	}
+	return valores, nil
+}
+
+func (i indiceColecao) erroDuplicado(banco, colecao string, valores bson.A) *erroComando {
+	campos := make([]string, len(i.chave))
+	for n, c := range i.chave {
+		campos[n] = fmt.Sprintf("%s: %v", c.Key, valores[n])
+	}
+	return &erroComando{
+		codigo: 11000,
+		nome:   "DuplicateKey",
+		msg:    fmt.Sprintf("E11000 duplicate key error collection: %s.%s index: %s dup key: { %s }", banco, colecao, i.nome, strings.Join(campos, ", ")),
+	}
+}
+
+// conferirUnicos falha se doc repetir, em algum índice único, a chave de outro documento
+// (um documento com o mesmo _id é a versão anterior dele mesmo e não conta)
+func (c *colecao) conferirUnicos(banco, nome string, doc bson.D) error {
+	id, _ := obter(doc, "_id")
+	for _, ind := range c.indices {
+		if !ind.unico {
+			continue
+		}
+		valores, err := ind.valores(doc)
+		if err != nil {
+			return err
+		}
+		for _, outro := range c.docs {
+			if outroID, _ := obter(outro, "_id"); iguais(outroID, id) {
+				continue
+			}
+			existentes, err := ind.valores(outro)
+			if err != nil {
+				return err
+			}
+			if iguais(existentes, valores) {
+				return ind.erroDuplicado(banco, nome, valores)
+			}
+		}
+	}
+	return nil
+}
+
+// criarIndice registra o índice; repetir um índice existente não faz nada, e um índice
+// único só é criado se os documentos atuais não repetirem a chave
+func (c *colecao) criarIndice(banco, nome string, novo indiceColecao) error {
+	for _, ind := range c.indices {
+		if ind.nome == novo.nome {
+			if ind.mesmaDefinicao(novo) {
+				return nil
+			}
+			return &erroComando{codigo: 86, nome: "IndexKeySpecsConflict", msg: fmt.Sprintf("já existe um índice %s com outra definição", novo.nome)}
+		}
+	}
+	if novo.unico {
+		for i, doc := range c.docs {
+			valores, err := novo.valores(doc)
+			if err != nil {
+				return err
+			}
+			for _, outro := range c.docs[:i] {
+				existentes, err := novo.valores(outro)
+				if err != nil {
+					return err
+				}
+				if iguais(existentes, valores) {
+					return novo.erroDuplicado(banco, nome, valores)
+				}
+			}
+		}
+	}
+	c.indices = append(c.indices, novo)
+	return nil
+}
+
+// descricao é o documento do índice em listIndexes
+func (i indiceColecao) descricao() bson.D {
+	d := bson.D{
+		{Key: "v", Value: int32(2)},
+		{Key: "key", Value: i.chave},
+		{Key: "name", Value: i.nome},
+	}
+	if i.unico {
+		d = append(d, bson.E{Key: "unique", Value: true})
+	}
+	return d
+}
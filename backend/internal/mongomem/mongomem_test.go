@@ -0,0 +1,224 @@
+package mongomem_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+)
+
+type politicoTeste struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Nome       string             `bson:"nome"`
+	Partido    string             `bson:"partido"`
+	Nascimento time.Time          `bson:"nascimento"`
+	Cargo      struct {
+		Tipo        string `bson:"tipo"`
+		EmExercicio bool   `bson:"em_exercicio"`
+	} `bson:"cargo"`
+	Fontes map[string]string `bson:"fontes,omitempty"`
+	Votos  int               `bson:"votos"`
+}
+
+func TestConsultasEAtualizacoes(t *testing.T) {
+	ctx := context.Background()
+	coll := mongomemtest.Banco(t).Collection("politicos")
+
+	nascimento := time.Date(1970, 5, 1, 0, 0, 0, 0, time.UTC)
+	docs := []interface{}{
+		bson.M{"nome": "Ana", "partido": "PT", "nascimento": nascimento, "cargo": bson.M{"tipo": "deputado_federal", "em_exercicio": true}, "votos": 3},
+		bson.M{"nome": "Bruno", "partido": "PL", "nascimento": nascimento.AddDate(1, 0, 0), "cargo": bson.M{"tipo": "senador", "em_exercicio": true}, "votos": 1},
+		bson.M{"nome": "Carla", "partido": "PT", "cargo": bson.M{"tipo": "deputado_federal", "em_exercicio": false}, "votos": 2},
+	}
+	if _, err := coll.InsertMany(ctx, docs); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+
+	n, err := coll.CountDocuments(ctx, bson.M{"cargo.tipo": "deputado_federal", "cargo.em_exercicio": true})
+	if err != nil || n != 1 {
+		t.Fatalf("CountDocuments = %d, %v; esperado 1", n, err)
+	}
+
+	cursor, err := coll.Find(ctx, bson.M{
+		"nascimento": bson.M{"$gte": nascimento.AddDate(0, 0, -1), "$lte": nascimento.AddDate(0, 0, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	var porData []politicoTeste
+	if err := cursor.All(ctx, &porData); err != nil {
+		t.Fatalf("cursor.All: %v", err)
+	}
+	if len(porData) != 1 || porData[0].Nome != "Ana" || !porData[0].Nascimento.Equal(nascimento) {
+		t.Fatalf("busca por data = %+v; esperado só Ana", porData)
+	}
+
+	cursor, err = coll.Find(ctx, bson.M{"partido": bson.M{"$ne": "PL"}, "nascimento": bson.M{"$exists": false}},
+		options.Find().SetSort(bson.D{{Key: "votos", Value: -1}}))
+	if err != nil {
+		t.Fatalf("Find com $ne/$exists: %v", err)
+	}
+	var semData []politicoTeste
+	if err := cursor.All(ctx, &semData); err != nil {
+		t.Fatalf("cursor.All: %v", err)
+	}
+	if len(semData) != 1 || semData[0].Nome != "Carla" {
+		t.Fatalf("busca com $ne/$exists = %+v; esperado só Carla", semData)
+	}
+
+	// findAndModify retorna o documento anterior e aplica $set com caminho e $inc
+	var anterior politicoTeste
+	err = coll.FindOneAndUpdate(ctx, bson.M{"nome": "Ana"}, bson.M{
+		"$set": bson.M{"fontes.camara": "https://exemplo/1"},
+		"$inc": bson.M{"votos": 2},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&anterior)
+	if err != nil {
+		t.Fatalf("FindOneAndUpdate: %v", err)
+	}
+	if anterior.Votos != 3 || anterior.Fontes != nil {
+		t.Fatalf("documento anterior = %+v", anterior)
+	}
+	var ana politicoTeste
+	if err := coll.FindOne(ctx, bson.M{"_id": anterior.ID}).Decode(&ana); err != nil {
+		t.Fatalf("FindOne: %v", err)
+	}
+	if ana.Votos != 5 || ana.Fontes["camara"] != "https://exemplo/1" {
+		t.Fatalf("documento atualizado = %+v", ana)
+	}
+
+	// Upsert: o documento novo junta as igualdades do filtro e o $setOnInsert
+	id := primitive.NewObjectID()
+	res, err := coll.UpdateOne(ctx, bson.M{"nome": "Diego"}, bson.M{
+		"$set":         bson.M{"partido": "PSB"},
+		"$setOnInsert": bson.M{"_id": id, "votos": 0},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		t.Fatalf("UpdateOne com upsert: %v", err)
+	}
+	if res.UpsertedID != id {
+		t.Fatalf("UpsertedID = %v; esperado %v", res.UpsertedID, id)
+	}
+	var diego politicoTeste
+	if err := coll.FindOne(ctx, bson.M{"_id": id}).Decode(&diego); err != nil {
+		t.Fatalf("FindOne: %v", err)
+	}
+	if diego.Nome != "Diego" || diego.Partido != "PSB" {
+		t.Fatalf("documento do upsert = %+v", diego)
+	}
+
+	if err := coll.FindOne(ctx, bson.M{"nome": "Ninguém"}).Err(); err != mongo.ErrNoDocuments {
+		t.Fatalf("FindOne sem resultado = %v; esperado ErrNoDocuments", err)
+	}
+
+	if _, err := coll.InsertOne(ctx, bson.M{"_id": id}); !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("InsertOne com _id repetido = %v; esperado chave duplicada", err)
+	}
+
+	// Agregação por grupo, como nos relatórios
+	cursor, err = coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$partido", "total": bson.M{"$sum": "$votos"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	var grupos []struct {
+		Partido string `bson:"_id"`
+		Total   int    `bson:"total"`
+	}
+	if err := cursor.All(ctx, &grupos); err != nil {
+		t.Fatalf("cursor.All: %v", err)
+	}
+	if len(grupos) != 3 || grupos[0].Partido != "PL" || grupos[2].Partido != "PT" || grupos[2].Total != 7 {
+		t.Fatalf("grupos = %+v", grupos)
+	}
+
+	// $lookup por igualdade, como na inclusão das proposições nas votações
+	votos := coll.Database().Collection("votos")
+	if _, err := votos.InsertMany(ctx, []interface{}{
+		bson.M{"politico_id": id, "voto": "sim"},
+		bson.M{"politico_id": primitive.NewObjectID(), "voto": "não"},
+	}); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+	cursor, err = votos.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{"from": "politicos", "localField": "politico_id", "foreignField": "_id", "as": "politico"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$politico", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$sort", Value: bson.D{{Key: "voto", Value: -1}}}},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate com $lookup: %v", err)
+	}
+	var juntados []struct {
+		Voto     string         `bson:"voto"`
+		Politico *politicoTeste `bson:"politico"`
+	}
+	if err := cursor.All(ctx, &juntados); err != nil {
+		t.Fatalf("cursor.All: %v", err)
+	}
+	if len(juntados) != 2 || juntados[0].Politico == nil || juntados[0].Politico.Nome != "Diego" || juntados[1].Politico != nil {
+		t.Fatalf("juntados = %+v", juntados)
+	}
+
+	if _, err := coll.Find(ctx, bson.M{"$where": "true"}); err == nil {
+		t.Fatal("operador não suportado deveria falhar")
+	}
+}
+
+func TestIndiceUnico(t *testing.T) {
+	ctx := context.Background()
+	coll := mongomemtest.Banco(t).Collection("alertas")
+
+	if _, err := coll.InsertMany(ctx, []interface{}{bson.M{"chave": "a"}, bson.M{"chave": "b"}}); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+	nome, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "chave", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil || nome != "chave_1" {
+		t.Fatalf("CreateOne = %q, %v", nome, err)
+	}
+
+	if _, err := coll.InsertOne(ctx, bson.M{"chave": "a"}); !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("InsertOne com chave repetida = %v; esperado chave duplicada", err)
+	}
+	if _, err := coll.UpdateOne(ctx, bson.M{"chave": "b"}, bson.M{"$set": bson.M{"chave": "a"}}); !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("UpdateOne para chave repetida = %v; esperado chave duplicada", err)
+	}
+	if _, err := coll.UpdateOne(ctx, bson.M{"chave": "c"}, bson.M{"$set": bson.M{"chave": "a"}}, options.Update().SetUpsert(true)); !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("upsert com chave repetida = %v; esperado chave duplicada", err)
+	}
+	// Regravar o próprio documento não conflita com ele mesmo
+	if _, err := coll.UpdateOne(ctx, bson.M{"chave": "a"}, bson.M{"$set": bson.M{"chave": "a", "visto": true}}); err != nil {
+		t.Fatalf("UpdateOne do próprio documento: %v", err)
+	}
+	if n, _ := coll.CountDocuments(ctx, bson.M{}); n != 2 {
+		t.Fatalf("documentos = %d; esperado 2", n)
+	}
+
+	// O índice único não é criado sobre dados que já repetem a chave
+	repetidos := coll.Database().Collection("repetidos")
+	if _, err := repetidos.InsertMany(ctx, []interface{}{bson.M{"hash": "x"}, bson.M{"hash": "x"}}); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+	if _, err := repetidos.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("CreateOne sobre chaves repetidas = %v; esperado chave duplicada", err)
+	}
+
+	if _, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "chave", Value: 1}},
+		Options: options.Index().SetName("parcial").SetUnique(true).SetPartialFilterExpression(bson.M{"visto": true}),
+	}); err == nil {
+		t.Fatal("índice parcial deveria falhar")
+	}
+}
@@ -0,0 +1,42 @@
+// Package mongomemtest liga o mongomem aos testes: Banco sobe um servidor em memória para
+// o teste e o encerra ao fim dele.
+package mongomemtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/lupa-cidada/backend/internal/mongomem"
+)
+
+// Banco sobe um servidor e retorna um banco conectado a ele; o servidor e a conexão são
+// encerrados ao fim do teste
+func Banco(t testing.TB) *mongo.Database {
+	t.Helper()
+
+	s, err := mongomem.Iniciar()
+	if err != nil {
+		t.Fatalf("erro ao iniciar o MongoDB em memória: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().
+		ApplyURI(s.URI()).
+		SetServerSelectionTimeout(5*time.Second))
+	if err != nil {
+		s.Encerrar()
+		t.Fatalf("erro ao conectar ao MongoDB em memória: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+		s.Encerrar()
+	})
+
+	return client.Database("lupa_cidada")
+}
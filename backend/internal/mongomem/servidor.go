@@ -0,0 +1,287 @@
+// Package mongomem é um substituto do MongoDB em memória para os testes. O servidor atende
+// o protocolo do MongoDB em uma porta local, então o código testado usa o driver oficial sem
+// alterações. Só o subconjunto de comandos, filtros, operadores de atualização e estágios de
+// agregação usado pelo backend é suportado; o resto responde com erro, para que um teste
+// nunca passe por acaso.
+package mongomem
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	gosync "sync"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Códigos de operação do protocolo
+const (
+	opReply = 1
+	opQuery = 2004
+	opMsg   = 2013
+)
+
+// tamanhoMaximoMensagem é o limite anunciado no hello (o mesmo do MongoDB)
+const tamanhoMaximoMensagem = 48_000_000
+
+// Servidor é um MongoDB em memória escutando em uma porta local
+type Servidor struct {
+	listener net.Listener
+
+	// mu serializa os comandos: cada comando vê e altera os dados de forma atômica
+	mu     gosync.Mutex
+	bancos map[string]map[string]*colecao
+
+	conexoesMu gosync.Mutex
+	conexoes   map[net.Conn]struct{}
+	wg         gosync.WaitGroup
+
+	proximaMensagem atomic.Int32
+	proximaConexao  atomic.Int32
+}
+
+// colecao guarda os documentos na ordem de inserção e os índices criados
+type colecao struct {
+	docs    []bson.D
+	indices []indiceColecao
+}
+
+// Iniciar sobe um servidor vazio em uma porta livre de 127.0.0.1
+func Iniciar() (*Servidor, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Servidor{
+		listener: listener,
+		bancos:   make(map[string]map[string]*colecao),
+		conexoes: make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.aceitar()
+	return s, nil
+}
+
+// URI é o endereço de conexão do servidor
+func (s *Servidor) URI() string {
+	return fmt.Sprintf("mongodb://%s/?directConnection=true", s.listener.Addr())
+}
+
+// Encerrar fecha a porta e as conexões abertas
+func (s *Servidor) Encerrar() {
+	s.listener.Close()
+	s.conexoesMu.Lock()
+	for conn := range s.conexoes {
+		conn.Close()
+	}
+	s.conexoesMu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Servidor) aceitar() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.conexoesMu.Lock()
+		s.conexoes[conn] = struct{}{}
+		s.conexoesMu.Unlock()
+
+		s.wg.Add(1)
+		go s.atender(conn)
+	}
+}
+
+// atender lê as mensagens da conexão e responde cada uma até o cliente desconectar
+func (s *Servidor) atender(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.conexoesMu.Lock()
+		delete(s.conexoes, conn)
+		s.conexoesMu.Unlock()
+		conn.Close()
+	}()
+
+	leitor := bufio.NewReader(conn)
+	for {
+		var cabecalho [16]byte
+		if _, err := io.ReadFull(leitor, cabecalho[:]); err != nil {
+			return
+		}
+		tamanho := int32(binary.LittleEndian.Uint32(cabecalho[0:4]))
+		requestID := int32(binary.LittleEndian.Uint32(cabecalho[4:8]))
+		opcode := int32(binary.LittleEndian.Uint32(cabecalho[12:16]))
+		if tamanho < 16 || tamanho > tamanhoMaximoMensagem {
+			return
+		}
+
+		corpo := make([]byte, tamanho-16)
+		if _, err := io.ReadFull(leitor, corpo); err != nil {
+			return
+		}
+
+		var resposta []byte
+		var err error
+		switch opcode {
+		case opMsg:
+			resposta, err = s.opMsg(requestID, corpo)
+		case opQuery:
+			resposta, err = s.opQuery(requestID, corpo)
+		default:
+			return
+		}
+		if err != nil {
+			return
+		}
+		if resposta != nil {
+			if _, err := conn.Write(resposta); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// opMsg atende um OP_MSG: a seção 0 traz o comando e as seções 1, as listas de documentos
+// (ex.: os documentos de um insert), que são juntadas ao comando
+func (s *Servidor) opMsg(requestID int32, corpo []byte) ([]byte, error) {
+	if len(corpo) < 5 {
+		return nil, errors.New("OP_MSG curto demais")
+	}
+	flags := binary.LittleEndian.Uint32(corpo[0:4])
+	secoes := corpo[4:]
+	if flags&1 != 0 { // checksumPresent
+		secoes = secoes[:len(secoes)-4]
+	}
+
+	var comando bson.D
+	var listas bson.D
+	for len(secoes) > 0 {
+		tipo := secoes[0]
+		secoes = secoes[1:]
+		if len(secoes) < 4 {
+			return nil, errors.New("seção do OP_MSG truncada")
+		}
+		tamanho := int(binary.LittleEndian.Uint32(secoes[0:4]))
+		if tamanho > len(secoes) {
+			return nil, errors.New("seção do OP_MSG truncada")
+		}
+
+		switch tipo {
+		case 0:
+			doc, err := documentoBruto(secoes[:tamanho])
+			if err != nil {
+				return nil, err
+			}
+			comando = doc
+		case 1:
+			seq := secoes[4:tamanho]
+			fim := strings.IndexByte(string(seq), 0)
+			if fim < 0 {
+				return nil, errors.New("identificador da seção do OP_MSG sem terminador")
+			}
+			identificador := string(seq[:fim])
+			seq = seq[fim+1:]
+
+			var docs bson.A
+			for len(seq) > 0 {
+				n := int(binary.LittleEndian.Uint32(seq[0:4]))
+				doc, err := documentoBruto(seq[:n])
+				if err != nil {
+					return nil, err
+				}
+				docs = append(docs, doc)
+				seq = seq[n:]
+			}
+			listas = append(listas, bson.E{Key: identificador, Value: docs})
+		default:
+			return nil, fmt.Errorf("tipo de seção do OP_MSG desconhecido: %d", tipo)
+		}
+		secoes = secoes[tamanho:]
+	}
+	comando = append(comando, listas...)
+
+	resultado := s.executar("", comando)
+	if flags&2 != 0 { // moreToCome: o cliente não espera resposta
+		return nil, nil
+	}
+
+	doc, err := bson.Marshal(resultado)
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, 0, 5+len(doc))
+	payload = binary.LittleEndian.AppendUint32(payload, 0)
+	payload = append(payload, 0)
+	payload = append(payload, doc...)
+	return s.mensagem(requestID, opMsg, payload), nil
+}
+
+// opQuery atende o OP_QUERY legado, usado pelo driver só no primeiro hello da conexão
+func (s *Servidor) opQuery(requestID int32, corpo []byte) ([]byte, error) {
+	if len(corpo) < 4 {
+		return nil, errors.New("OP_QUERY curto demais")
+	}
+	resto := corpo[4:]
+	fim := strings.IndexByte(string(resto), 0)
+	if fim < 0 {
+		return nil, errors.New("nome da coleção do OP_QUERY sem terminador")
+	}
+	banco, _, _ := strings.Cut(string(resto[:fim]), ".")
+	resto = resto[fim+1:]
+	if len(resto) < 12 {
+		return nil, errors.New("OP_QUERY truncado")
+	}
+	resto = resto[8:] // numberToSkip e numberToReturn
+	n := int(binary.LittleEndian.Uint32(resto[0:4]))
+	if n > len(resto) {
+		return nil, errors.New("OP_QUERY truncado")
+	}
+
+	comando, err := documentoBruto(resto[:n])
+	if err != nil {
+		return nil, err
+	}
+	if len(comando) > 0 && comando[0].Key == "$query" {
+		if interno, ok := comando[0].Value.(bson.D); ok {
+			comando = interno
+		}
+	}
+
+	doc, err := bson.Marshal(s.executar(banco, comando))
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, 0, 20+len(doc))
+	payload = binary.LittleEndian.AppendUint32(payload, 0) // responseFlags
+	payload = binary.LittleEndian.AppendUint64(payload, 0) // cursorID
+	payload = binary.LittleEndian.AppendUint32(payload, 0) // startingFrom
+	payload = binary.LittleEndian.AppendUint32(payload, 1) // numberReturned
+	payload = append(payload, doc...)
+	return s.mensagem(requestID, opReply, payload), nil
+}
+
+func (s *Servidor) mensagem(responseTo, opcode int32, payload []byte) []byte {
+	msg := make([]byte, 0, 16+len(payload))
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(16+len(payload)))
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(s.proximaMensagem.Add(1)))
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(responseTo))
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(opcode))
+	return append(msg, payload...)
+}
+
+// documentoBruto valida e converte um documento BSON recebido
+func documentoBruto(b []byte) (bson.D, error) {
+	raw := bson.Raw(b)
+	if err := raw.Validate(); err != nil {
+		return nil, err
+	}
+	return documento(raw), nil
+}
@@ -0,0 +1,430 @@
+package mongomem
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Os documentos são guardados como bson.D, com subdocumentos bson.D, listas bson.A e os
+// tipos de primitive; datas ficam como primitive.DateTime, como chegam pelo protocolo.
+
+func documento(raw bson.Raw) bson.D {
+	elementos, _ := raw.Elements()
+	doc := make(bson.D, 0, len(elementos))
+	for _, e := range elementos {
+		doc = append(doc, bson.E{Key: e.Key(), Value: valor(e.Value())})
+	}
+	return doc
+}
+
+func valor(rv bson.RawValue) interface{} {
+	switch rv.Type {
+	case bsontype.Double:
+		return rv.Double()
+	case bsontype.String:
+		return rv.StringValue()
+	case bsontype.EmbeddedDocument:
+		return documento(rv.Document())
+	case bsontype.Array:
+		valores, _ := rv.Array().Values()
+		lista := make(bson.A, 0, len(valores))
+		for _, v := range valores {
+			lista = append(lista, valor(v))
+		}
+		return lista
+	case bsontype.Binary:
+		subtipo, dados := rv.Binary()
+		return primitive.Binary{Subtype: subtipo, Data: dados}
+	case bsontype.Null, bsontype.Undefined:
+		return nil
+	case bsontype.ObjectID:
+		return rv.ObjectID()
+	case bsontype.Boolean:
+		return rv.Boolean()
+	case bsontype.DateTime:
+		return primitive.DateTime(rv.DateTime())
+	case bsontype.Regex:
+		padrao, opcoes := rv.Regex()
+		return primitive.Regex{Pattern: padrao, Options: opcoes}
+	case bsontype.Int32:
+		return rv.Int32()
+	case bsontype.Timestamp:
+		t, i := rv.Timestamp()
+		return primitive.Timestamp{T: t, I: i}
+	case bsontype.Int64:
+		return rv.Int64()
+	case bsontype.Decimal128:
+		return rv.Decimal128()
+	case bsontype.MinKey:
+		return primitive.MinKey{}
+	case bsontype.MaxKey:
+		return primitive.MaxKey{}
+	default:
+		return rv
+	}
+}
+
+// copiar duplica subdocumentos e listas, para que alterações não vazem para o original
+func copiar(v interface{}) interface{} {
+	switch t := v.(type) {
+	case bson.D:
+		c := make(bson.D, len(t))
+		for i, e := range t {
+			c[i] = bson.E{Key: e.Key, Value: copiar(e.Value)}
+		}
+		return c
+	case bson.A:
+		c := make(bson.A, len(t))
+		for i, e := range t {
+			c[i] = copiar(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+func copiarDoc(d bson.D) bson.D {
+	return copiar(d).(bson.D)
+}
+
+func obter(d bson.D, chave string) (interface{}, bool) {
+	for _, e := range d {
+		if e.Key == chave {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+func definir(d bson.D, chave string, v interface{}) bson.D {
+	for i, e := range d {
+		if e.Key == chave {
+			d[i].Value = v
+			return d
+		}
+	}
+	return append(d, bson.E{Key: chave, Value: v})
+}
+
+func remover(d bson.D, chave string) bson.D {
+	for i, e := range d {
+		if e.Key == chave {
+			return append(d[:i:i], d[i+1:]...)
+		}
+	}
+	return d
+}
+
+func partes(caminho string) []string {
+	return strings.Split(caminho, ".")
+}
+
+func indice(parte string) (int, bool) {
+	i, err := strconv.Atoi(parte)
+	return i, err == nil && i >= 0
+}
+
+// obterCaminho segue um caminho com pontos sem expandir listas (usado nas atualizações e
+// na ordenação); índices numéricos acessam elementos de listas
+func obterCaminho(v interface{}, caminho []string) (interface{}, bool) {
+	for _, parte := range caminho {
+		switch t := v.(type) {
+		case bson.D:
+			var ok bool
+			if v, ok = obter(t, parte); !ok {
+				return nil, false
+			}
+		case bson.A:
+			i, ok := indice(parte)
+			if !ok || i >= len(t) {
+				return nil, false
+			}
+			v = t[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// definirCaminho grava novo no caminho, criando os subdocumentos que faltarem
+func definirCaminho(v interface{}, caminho []string, novo interface{}) (interface{}, error) {
+	if len(caminho) == 0 {
+		return novo, nil
+	}
+	switch t := v.(type) {
+	case nil:
+		return definirCaminho(bson.D{}, caminho, novo)
+	case bson.D:
+		filho, _ := obter(t, caminho[0])
+		res, err := definirCaminho(filho, caminho[1:], novo)
+		if err != nil {
+			return nil, err
+		}
+		return definir(t, caminho[0], res), nil
+	case bson.A:
+		i, ok := indice(caminho[0])
+		if !ok {
+			return nil, fmt.Errorf("não é possível criar o campo '%s' em uma lista", caminho[0])
+		}
+		for len(t) <= i {
+			t = append(t, nil)
+		}
+		res, err := definirCaminho(t[i], caminho[1:], novo)
+		if err != nil {
+			return nil, err
+		}
+		t[i] = res
+		return t, nil
+	default:
+		return nil, fmt.Errorf("não é possível criar o campo '%s' em um valor %T", caminho[0], v)
+	}
+}
+
+// removerCaminho apaga o campo do caminho; em listas, o elemento vira null, como no MongoDB
+func removerCaminho(v interface{}, caminho []string) interface{} {
+	switch t := v.(type) {
+	case bson.D:
+		if len(caminho) == 1 {
+			return remover(t, caminho[0])
+		}
+		if filho, ok := obter(t, caminho[0]); ok {
+			return definir(t, caminho[0], removerCaminho(filho, caminho[1:]))
+		}
+	case bson.A:
+		if i, ok := indice(caminho[0]); ok && i < len(t) {
+			if len(caminho) == 1 {
+				t[i] = nil
+			} else {
+				t[i] = removerCaminho(t[i], caminho[1:])
+			}
+		}
+	}
+	return v
+}
+
+// resolver retorna os valores do caminho para as consultas: ao passar por uma lista, o
+// restante do caminho é seguido em cada elemento (ex.: "votos.tipo" em uma lista de votos)
+func resolver(v interface{}, caminho []string) []interface{} {
+	if len(caminho) == 0 {
+		return []interface{}{v}
+	}
+	switch t := v.(type) {
+	case bson.D:
+		filho, ok := obter(t, caminho[0])
+		if !ok {
+			return nil
+		}
+		return resolver(filho, caminho[1:])
+	case bson.A:
+		if i, ok := indice(caminho[0]); ok {
+			if i >= len(t) {
+				return nil
+			}
+			return resolver(t[i], caminho[1:])
+		}
+		var valores []interface{}
+		for _, e := range t {
+			if _, ok := e.(bson.D); ok {
+				valores = append(valores, resolver(e, caminho)...)
+			}
+		}
+		return valores
+	default:
+		return nil
+	}
+}
+
+// classe ordena os tipos como o MongoDB faz ao comparar valores de tipos diferentes
+func classe(v interface{}) int {
+	switch v.(type) {
+	case primitive.MinKey:
+		return 1
+	case nil:
+		return 2
+	case int32, int64, float64, primitive.Decimal128:
+		return 3
+	case string, primitive.Symbol:
+		return 4
+	case bson.D:
+		return 5
+	case bson.A:
+		return 6
+	case primitive.Binary:
+		return 7
+	case primitive.ObjectID:
+		return 8
+	case bool:
+		return 9
+	case primitive.DateTime:
+		return 10
+	case primitive.Timestamp:
+		return 11
+	case primitive.Regex:
+		return 12
+	case primitive.MaxKey:
+		return 14
+	default:
+		return 13
+	}
+}
+
+func numero(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float64:
+		return t, true
+	case primitive.Decimal128:
+		f, err := strconv.ParseFloat(t.String(), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func inteiro(v interface{}) (int64, bool) {
+	switch t := v.(type) {
+	case int32:
+		return int64(t), true
+	case int64:
+		return t, true
+	default:
+		return 0, false
+	}
+}
+
+// verdadeiro interpreta flags de comandos e projeções (true, 1, 1.0)
+func verdadeiro(v interface{}) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	if n, ok := numero(v); ok {
+		return n != 0
+	}
+	return v != nil
+}
+
+func sinal(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparar segue a ordem de valores do MongoDB: primeiro pela classe do tipo, depois pelo valor
+func comparar(a, b interface{}) int {
+	ca, cb := classe(a), classe(b)
+	if ca != cb {
+		return sinal(ca - cb)
+	}
+
+	switch ca {
+	case 3:
+		if x, ok := inteiro(a); ok {
+			if y, ok := inteiro(b); ok {
+				switch {
+				case x < y:
+					return -1
+				case x > y:
+					return 1
+				}
+				return 0
+			}
+		}
+		x, _ := numero(a)
+		y, _ := numero(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case 4:
+		return strings.Compare(texto(a), texto(b))
+	case 5:
+		x, y := a.(bson.D), b.(bson.D)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := strings.Compare(x[i].Key, y[i].Key); c != 0 {
+				return c
+			}
+			if c := comparar(x[i].Value, y[i].Value); c != 0 {
+				return c
+			}
+		}
+		return sinal(len(x) - len(y))
+	case 6:
+		x, y := a.(bson.A), b.(bson.A)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := comparar(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return sinal(len(x) - len(y))
+	case 7:
+		x, y := a.(primitive.Binary), b.(primitive.Binary)
+		if x.Subtype != y.Subtype {
+			return sinal(int(x.Subtype) - int(y.Subtype))
+		}
+		return bytes.Compare(x.Data, y.Data)
+	case 8:
+		x, y := a.(primitive.ObjectID), b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:])
+	case 9:
+		x, y := a.(bool), b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case 10:
+		x, y := a.(primitive.DateTime), b.(primitive.DateTime)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case 11:
+		x, y := a.(primitive.Timestamp), b.(primitive.Timestamp)
+		return primitive.CompareTimestamp(x, y)
+	case 12:
+		x, y := a.(primitive.Regex), b.(primitive.Regex)
+		if c := strings.Compare(x.Pattern, y.Pattern); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Options, y.Options)
+	case 13:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	default:
+		return 0
+	}
+}
+
+func texto(v interface{}) string {
+	if s, ok := v.(primitive.Symbol); ok {
+		return string(s)
+	}
+	return v.(string)
+}
+
+func iguais(a, b interface{}) bool {
+	return comparar(a, b) == 0
+}
@@ -0,0 +1,170 @@
+// Package partidos centraliza os metadados dos partidos (nome, cor, renomeações e fusões)
+// a partir da coleção de partidos, usada como fonte única pela API e pelas sincronizações.
+package partidos
+
+import (
+	"context"
+	"log"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CorPadrao é usada para partidos que ainda não estão cadastrados
+const CorPadrao = "#666666"
+
+// Colecao é o nome da coleção de partidos
+const Colecao = "partidos"
+
+// Catalogo resolve siglas de partidos para os dados cadastrados na coleção de partidos.
+// Os dados são carregados uma única vez e podem ser compartilhados entre goroutines.
+type Catalogo struct {
+	db       *mongo.Database
+	once     gosync.Once
+	porSigla map[string]domain.RegistroPartido
+}
+
+// NewCatalogo cria um catálogo de partidos. Com db nil, usa apenas os partidos padrão.
+func NewCatalogo(db *mongo.Database) *Catalogo {
+	return &Catalogo{db: db}
+}
+
+// Buscar retorna o cadastro do partido com a sigla informada, considerando siglas anteriores
+func (c *Catalogo) Buscar(ctx context.Context, sigla string) (domain.RegistroPartido, bool) {
+	c.once.Do(func() { c.carregar(ctx) })
+
+	registro, ok := c.porSigla[normalizarSigla(sigla)]
+	return registro, ok
+}
+
+// Resolver retorna o partido atual correspondente à sigla, seguindo renomeações e
+// incorporações (ex.: DEM e PSL → UNIÃO). Siglas desconhecidas são mantidas com a cor padrão.
+func (c *Catalogo) Resolver(ctx context.Context, sigla string) domain.Partido {
+	sigla = strings.TrimSpace(sigla)
+	registro, ok := c.Buscar(ctx, sigla)
+	if !ok {
+		return domain.Partido{Sigla: sigla, Cor: CorPadrao}
+	}
+
+	// Limita os saltos para não entrar em laço caso o cadastro tenha ciclos
+	for i := 0; i < 5 && registro.IncorporadoPor != ""; i++ {
+		sucessor, ok := c.Buscar(ctx, registro.IncorporadoPor)
+		if !ok {
+			break
+		}
+		registro = sucessor
+	}
+
+	return registro.Partido()
+}
+
+func (c *Catalogo) carregar(ctx context.Context) {
+	registros := Padrao()
+
+	if c.db != nil {
+		cursor, err := c.db.Collection(Colecao).Find(ctx, bson.M{})
+		if err == nil {
+			var doBanco []domain.RegistroPartido
+			if err = cursor.All(ctx, &doBanco); err == nil && len(doBanco) > 0 {
+				registros = doBanco
+			}
+		}
+		if err != nil {
+			log.Printf("⚠️ Erro ao carregar partidos, usando cadastro padrão: %v", err)
+		}
+	}
+
+	c.porSigla = indexar(registros)
+}
+
+func indexar(registros []domain.RegistroPartido) map[string]domain.RegistroPartido {
+	porSigla := make(map[string]domain.RegistroPartido, len(registros))
+	for _, r := range registros {
+		porSigla[normalizarSigla(r.Sigla)] = r
+	}
+	// Siglas anteriores só entram se não colidirem com um partido existente
+	for _, r := range registros {
+		for _, anterior := range r.SiglasAnteriores {
+			chave := normalizarSigla(anterior)
+			if _, existe := porSigla[chave]; !existe {
+				porSigla[chave] = r
+			}
+		}
+	}
+	return porSigla
+}
+
+func normalizarSigla(sigla string) string {
+	return strings.ToUpper(strings.TrimSpace(sigla))
+}
+
+// Semear grava os partidos padrão que ainda não existem na coleção. Nos já cadastrados, só
+// completa as siglas anteriores e a incorporação, preservando nome e cor editados no banco.
+func Semear(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(Colecao)
+
+	var models []mongo.WriteModel
+	for _, p := range Padrao() {
+		novo := bson.M{"sigla": p.Sigla, "nome": p.Nome, "cor": p.Cor}
+		if p.Observacao != "" {
+			novo["observacao"] = p.Observacao
+		}
+		update := bson.M{"$setOnInsert": novo}
+		if len(p.SiglasAnteriores) > 0 {
+			update["$addToSet"] = bson.M{"siglas_anteriores": bson.M{"$each": p.SiglasAnteriores}}
+		}
+		if p.IncorporadoPor != "" {
+			update["$set"] = bson.M{"incorporado_por": p.IncorporadoPor}
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"sigla": p.Sigla}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	_, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// AtualizarHistorico registra a filiação ao partido novo no histórico do político.
+// Se o histórico está vazio, a filiação armazenada (anterior) é registrada desde inicioAnterior.
+// Quando o partido muda, a filiação aberta é encerrada em agora; se o partido anterior foi
+// renomeado ou incorporado pelo novo, a mudança é marcada como fusão e não conta como troca.
+func (c *Catalogo) AtualizarHistorico(ctx context.Context, historico []domain.FiliacaoPartidaria, anterior, novo domain.Partido, inicioAnterior, agora time.Time) []domain.FiliacaoPartidaria {
+	if len(historico) == 0 && anterior.Sigla != "" {
+		historico = append(historico, domain.FiliacaoPartidaria{
+			Partido:    anterior,
+			DataInicio: inicioAnterior,
+		})
+	}
+	if novo.Sigla == "" {
+		return historico
+	}
+
+	if n := len(historico); n > 0 && historico[n-1].DataFim == nil {
+		aberta := &historico[n-1]
+		if normalizarSigla(aberta.Partido.Sigla) == normalizarSigla(novo.Sigla) {
+			aberta.Partido = novo
+			return historico
+		}
+		fim := agora
+		aberta.DataFim = &fim
+	}
+
+	porFusao := false
+	if n := len(historico); n > 0 {
+		porFusao = c.Resolver(ctx, historico[n-1].Partido.Sigla).Sigla == novo.Sigla
+	}
+
+	return append(historico, domain.FiliacaoPartidaria{
+		Partido:    novo,
+		DataInicio: agora,
+		PorFusao:   porFusao,
+	})
+}
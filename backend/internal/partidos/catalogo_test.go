@@ -0,0 +1,54 @@
+package partidos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Os partidos já cadastrados (por versões anteriores) recebem as siglas
+// anteriores e a incorporação, sem perder o nome e a cor editados
+func TestSemearCompletaPartidosExistentes(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	colecao := db.Collection(Colecao)
+
+	_, err := colecao.InsertMany(ctx, []interface{}{
+		bson.M{"sigla": "MDB", "nome": "MDB", "cor": "#123456"},
+		bson.M{"sigla": "DEM", "nome": "Democratas", "cor": "#0066CC", "siglas_anteriores": bson.A{"PFL", "ARENA"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := Semear(ctx, db); err != nil {
+			t.Fatalf("Semear: %v", err)
+		}
+	}
+
+	if n, _ := colecao.CountDocuments(ctx, bson.M{}); n != int64(len(Padrao())) {
+		t.Errorf("%d partidos; esperados %d, sem repetições", n, len(Padrao()))
+	}
+
+	var mdb, dem domain.RegistroPartido
+	colecao.FindOne(ctx, bson.M{"sigla": "MDB"}).Decode(&mdb)
+	colecao.FindOne(ctx, bson.M{"sigla": "DEM"}).Decode(&dem)
+	if mdb.Cor != "#123456" || mdb.Nome != "MDB" {
+		t.Errorf("MDB editado foi sobrescrito: %+v", mdb)
+	}
+	if len(mdb.SiglasAnteriores) != 1 || mdb.SiglasAnteriores[0] != "PMDB" {
+		t.Errorf("siglas anteriores do MDB: %v; esperado [PMDB]", mdb.SiglasAnteriores)
+	}
+	if dem.IncorporadoPor != "UNIÃO" || len(dem.SiglasAnteriores) != 2 {
+		t.Errorf("DEM: %+v; esperado incorporado pelo UNIÃO, mantendo as siglas cadastradas", dem)
+	}
+
+	// O catálogo passa a seguir a incorporação
+	if p := NewCatalogo(db).Resolver(ctx, "PFL"); p.Sigla != "UNIÃO" {
+		t.Errorf("PFL resolvido para %s; esperado UNIÃO", p.Sigla)
+	}
+}
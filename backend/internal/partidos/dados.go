@@ -0,0 +1,51 @@
+package partidos
+
+import "github.com/lupa-cidada/backend/internal/domain"
+
+// Padrao retorna os partidos usados para popular a coleção de partidos, que Semear grava ao
+// iniciar a API. Nome e cor editados no banco prevalecem; as siglas anteriores e as
+// incorporações são completadas por Semear.
+func Padrao() []domain.RegistroPartido {
+	return []domain.RegistroPartido{
+		{Sigla: "PT", Nome: "Partido dos Trabalhadores", Cor: "#CC0000"},
+		{Sigla: "PL", Nome: "Partido Liberal", Cor: "#003366", SiglasAnteriores: []string{"PR"}},
+		{Sigla: "UNIÃO", Nome: "União Brasil", Cor: "#2E3092", SiglasAnteriores: []string{"UNIAO"}, Observacao: "Fusão de DEM e PSL (2022)"},
+		{Sigla: "PP", Nome: "Progressistas", Cor: "#0066CC", SiglasAnteriores: []string{"PPB"}},
+		{Sigla: "MDB", Nome: "Movimento Democrático Brasileiro", Cor: "#00AA00", SiglasAnteriores: []string{"PMDB"}},
+		{Sigla: "PSD", Nome: "Partido Social Democrático", Cor: "#FF6600"},
+		{Sigla: "REPUBLICANOS", Nome: "Republicanos", Cor: "#0033CC", SiglasAnteriores: []string{"PRB"}},
+		{Sigla: "PDT", Nome: "Partido Democrático Trabalhista", Cor: "#FF0000"},
+		{Sigla: "PSDB", Nome: "Partido da Social Democracia Brasileira", Cor: "#003399"},
+		{Sigla: "PSOL", Nome: "Partido Socialismo e Liberdade", Cor: "#FFD700"},
+		{Sigla: "PSB", Nome: "Partido Socialista Brasileiro", Cor: "#FF6347"},
+		{Sigla: "PODE", Nome: "Podemos", Cor: "#00CED1", SiglasAnteriores: []string{"PTN", "PODEMOS"}},
+		{Sigla: "CIDADANIA", Nome: "Cidadania", Cor: "#9932CC", SiglasAnteriores: []string{"PPS"}},
+		{Sigla: "AVANTE", Nome: "Avante", Cor: "#FF8C00", SiglasAnteriores: []string{"PTDOB"}},
+		{Sigla: "SOLIDARIEDADE", Nome: "Solidariedade", Cor: "#FF4500", SiglasAnteriores: []string{"SD"}},
+		{Sigla: "PCdoB", Nome: "Partido Comunista do Brasil", Cor: "#8B0000"},
+		{Sigla: "PV", Nome: "Partido Verde", Cor: "#228B22"},
+		{Sigla: "NOVO", Nome: "Partido Novo", Cor: "#FF6600"},
+		{Sigla: "REDE", Nome: "Rede Sustentabilidade", Cor: "#00AA66"},
+		{Sigla: "PRD", Nome: "Partido Renovação Democrática", Cor: "#1E90FF", Observacao: "Fusão de PATRIOTA e PTB (2023)"},
+		{Sigla: "AGIR", Nome: "Agir", Cor: "#4169E1", SiglasAnteriores: []string{"PTC"}},
+		{Sigla: "DC", Nome: "Democracia Cristã", Cor: "#1C3F94", SiglasAnteriores: []string{"PSDC"}},
+		{Sigla: "PMB", Nome: "Partido da Mulher Brasileira", Cor: "#C71585"},
+		{Sigla: "PRTB", Nome: "Partido Renovador Trabalhista Brasileiro", Cor: "#006400"},
+		{Sigla: "MOBILIZA", Nome: "Mobiliza", Cor: "#DAA520", SiglasAnteriores: []string{"PMN"}},
+		{Sigla: "PCO", Nome: "Partido da Causa Operária", Cor: "#B22222"},
+		{Sigla: "PSTU", Nome: "Partido Socialista dos Trabalhadores Unificado", Cor: "#DC143C"},
+		{Sigla: "PCB", Nome: "Partido Comunista Brasileiro", Cor: "#A52A2A"},
+		{Sigla: "UP", Nome: "Unidade Popular", Cor: "#8B0000"},
+
+		// Partidos extintos por fusão ou incorporação
+		{Sigla: "DEM", Nome: "Democratas", Cor: "#0066CC", IncorporadoPor: "UNIÃO", SiglasAnteriores: []string{"PFL"}},
+		{Sigla: "PSL", Nome: "Partido Social Liberal", Cor: "#0066CC", IncorporadoPor: "UNIÃO"},
+		{Sigla: "PATRIOTA", Nome: "Patriota", Cor: "#0066CC", IncorporadoPor: "PRD", SiglasAnteriores: []string{"PEN"}},
+		{Sigla: "PTB", Nome: "Partido Trabalhista Brasileiro", Cor: "#0066CC", IncorporadoPor: "PRD"},
+		{Sigla: "PROS", Nome: "Partido Republicano da Ordem Social", Cor: "#FF8C00", IncorporadoPor: "SOLIDARIEDADE"},
+		{Sigla: "PSC", Nome: "Partido Social Cristão", Cor: "#0066CC", IncorporadoPor: "PODE"},
+		{Sigla: "PHS", Nome: "Partido Humanista da Solidariedade", Cor: "#0066CC", IncorporadoPor: "PODE"},
+		{Sigla: "PRP", Nome: "Partido Republicano Progressista", Cor: "#0066CC", IncorporadoPor: "PATRIOTA"},
+		{Sigla: "PPL", Nome: "Partido Pátria Livre", Cor: "#E31E24", IncorporadoPor: "PCdoB"},
+	}
+}
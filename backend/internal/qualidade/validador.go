@@ -0,0 +1,256 @@
+// Package qualidade verifica os registros gravados pelas sincronizações e monta o relatório
+// de problemas de cada execução.
+package qualidade
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type chaveValidador struct{}
+
+// ComValidador associa o validador ao contexto; as sincronizações validam o que gravam
+// apenas quando há um validador no contexto
+func ComValidador(ctx context.Context, v *Validador) context.Context {
+	return context.WithValue(ctx, chaveValidador{}, v)
+}
+
+// De retorna o validador associado ao contexto, ou nil se não houver
+func De(ctx context.Context) *Validador {
+	v, _ := ctx.Value(chaveValidador{}).(*Validador)
+	return v
+}
+
+// Validador aplica as regras de qualidade, grava os problemas encontrados e os acumula
+// para o relatório da execução. Pode ser usado por várias goroutines; um validador nil não
+// valida nada, para que as sincronizações possam usar De(ctx) diretamente.
+type Validador struct {
+	execucaoID string
+	problemas  *mongo.Collection
+	partidos   *partidos.Catalogo
+
+	mu        sync.Mutex
+	vistos    map[string]bool
+	total     int
+	porRegra  map[domain.RegraQualidade]int
+	ofensores map[string]*ofensor
+}
+
+type ofensor struct {
+	domain.OfensorQualidade
+	regras map[domain.RegraQualidade]bool
+}
+
+// NewValidador cria o validador de uma execução. Com db nil, os problemas ficam só em memória.
+func NewValidador(db *mongo.Database, execucaoID string) *Validador {
+	v := &Validador{
+		execucaoID: execucaoID,
+		partidos:   partidos.NewCatalogo(db),
+		vistos:     make(map[string]bool),
+		porRegra:   make(map[domain.RegraQualidade]int),
+		ofensores:  make(map[string]*ofensor),
+	}
+	if db != nil {
+		v.problemas = db.Collection("problemas_qualidade")
+	}
+	return v
+}
+
+// ValidarPolitico verifica o cadastro do político como ficou após a gravação
+func (v *Validador) ValidarPolitico(ctx context.Context, fonte string, p domain.Politico) {
+	if v == nil {
+		return
+	}
+
+	registrar := func(regra domain.RegraQualidade, detalhe string) {
+		id := p.ID
+		v.registrar(ctx, domain.ProblemaQualidade{
+			Regra:      regra,
+			Fonte:      fonte,
+			Entidade:   "politico",
+			Registro:   id.Hex(),
+			PoliticoID: &id,
+			Nome:       p.Nome,
+			Detalhe:    detalhe,
+		})
+	}
+
+	if strings.TrimSpace(p.CPF) == "" {
+		registrar(domain.RegraCPFAusente, "")
+	}
+	if p.DataNascimento.IsZero() {
+		registrar(domain.RegraDataNascimentoAusente, "")
+	}
+	// Sem partido é uma situação válida; só a sigla fora do cadastro é problema
+	if sigla := strings.TrimSpace(p.Partido.Sigla); sigla != "" {
+		if _, ok := v.partidos.Buscar(ctx, sigla); !ok {
+			registrar(domain.RegraPartidoDesconhecido, "sigla "+sigla)
+		}
+	}
+}
+
+// ValidarDespesa verifica uma despesa do político; referencia identifica o documento na fonte
+func (v *Validador) ValidarDespesa(ctx context.Context, fonte string, politico domain.Politico, despesa domain.Despesa, referencia string) {
+	if v == nil || despesa.Valor >= 0 {
+		return
+	}
+
+	id := politico.ID
+	v.registrar(ctx, domain.ProblemaQualidade{
+		Regra:      domain.RegraDespesaValorNegativo,
+		Fonte:      fonte,
+		Entidade:   "despesa",
+		Registro:   id.Hex(),
+		PoliticoID: &id,
+		Nome:       politico.Nome,
+		Detalhe:    fmt.Sprintf("%s: R$ %.2f", referencia, despesa.Valor),
+	})
+}
+
+// VotoPoliticoDesconhecido registra um voto de alguém que não está no cadastro de políticos.
+// idExterno é o ID do parlamentar na fonte e votacao, o ID da votação.
+func (v *Validador) VotoPoliticoDesconhecido(ctx context.Context, fonte, tipo string, idExterno int, nome, votacao string) {
+	if v == nil {
+		return
+	}
+	v.registrar(ctx, domain.ProblemaQualidade{
+		Regra:    domain.RegraVotoPoliticoDesconhecido,
+		Fonte:    fonte,
+		Entidade: "voto",
+		Registro: fmt.Sprintf("%s:%s:%d", fonte, tipo, idExterno),
+		Nome:     nome,
+		Detalhe:  "votação " + votacao,
+	})
+}
+
+func (v *Validador) registrar(ctx context.Context, p domain.ProblemaQualidade) {
+	p.Chave = domain.ChaveProblema(p.Regra, p.Registro, p.Detalhe)
+	p.ExecucaoID = v.execucaoID
+	p.VistoEm = time.Now()
+
+	v.mu.Lock()
+	// O mesmo político pode ser gravado por mais de uma etapa; cada problema conta uma vez
+	if v.vistos[p.Chave] {
+		v.mu.Unlock()
+		return
+	}
+	v.vistos[p.Chave] = true
+	v.total++
+	v.porRegra[p.Regra]++
+	o, ok := v.ofensores[p.Registro]
+	if !ok {
+		o = &ofensor{
+			OfensorQualidade: domain.OfensorQualidade{
+				Registro:   p.Registro,
+				PoliticoID: p.PoliticoID,
+				Nome:       p.Nome,
+				Fonte:      p.Fonte,
+			},
+			regras: make(map[domain.RegraQualidade]bool),
+		}
+		v.ofensores[p.Registro] = o
+	}
+	o.Problemas++
+	o.regras[p.Regra] = true
+	v.mu.Unlock()
+
+	if v.problemas == nil {
+		return
+	}
+	// As execuções periódicas encontram os mesmos problemas de novo: o documento do problema
+	// passa para a execução atual em vez de repetido
+	campos := bson.M{
+		"execucao_id": p.ExecucaoID,
+		"regra":       p.Regra,
+		"fonte":       p.Fonte,
+		"entidade":    p.Entidade,
+		"registro":    p.Registro,
+		"nome":        p.Nome,
+		"detalhe":     p.Detalhe,
+		"visto_em":    p.VistoEm,
+	}
+	if p.PoliticoID != nil {
+		campos["politico_id"] = p.PoliticoID
+	}
+	_, err := v.problemas.UpdateOne(ctx,
+		bson.M{"chave": p.Chave},
+		bson.M{
+			"$set":         campos,
+			"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "created_at": p.VistoEm},
+		},
+		options.Update().SetUpsert(true),
+	)
+	// Falhar ao gravar o problema não deve interromper a sincronização
+	if err != nil {
+		log.Printf("⚠️  Erro ao registrar problema de qualidade: %v", err)
+	}
+}
+
+// Contagem retorna quantos problemas cada regra encontrou até agora
+func (v *Validador) Contagem() map[domain.RegraQualidade]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	contagem := make(map[domain.RegraQualidade]int, len(v.porRegra))
+	for regra, n := range v.porRegra {
+		contagem[regra] = n
+	}
+	return contagem
+}
+
+// Relatorio monta o relatório da execução com os limite piores registros (todos, se limite <= 0)
+func (v *Validador) Relatorio(limite int) domain.RelatorioQualidade {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	piores := make([]domain.OfensorQualidade, 0, len(v.ofensores))
+	for _, o := range v.ofensores {
+		item := o.OfensorQualidade
+		item.Regras = make([]domain.RegraQualidade, 0, len(o.regras))
+		for _, regra := range domain.RegrasQualidade {
+			if o.regras[regra] {
+				item.Regras = append(item.Regras, regra)
+			}
+		}
+		piores = append(piores, item)
+	}
+	ordenarOfensores(piores)
+	if limite > 0 && len(piores) > limite {
+		piores = piores[:limite]
+	}
+
+	porRegra := make(map[domain.RegraQualidade]int, len(v.porRegra))
+	for regra, n := range v.porRegra {
+		porRegra[regra] = n
+	}
+
+	return domain.RelatorioQualidade{
+		ExecucaoID: v.execucaoID,
+		Total:      v.total,
+		PorRegra:   porRegra,
+		Piores:     piores,
+		GeradoEm:   time.Now(),
+	}
+}
+
+// ordenarOfensores ordena do registro com mais problemas para o com menos
+func ordenarOfensores(ofensores []domain.OfensorQualidade) {
+	sort.Slice(ofensores, func(i, j int) bool {
+		if ofensores[i].Problemas != ofensores[j].Problemas {
+			return ofensores[i].Problemas > ofensores[j].Problemas
+		}
+		return ofensores[i].Registro < ofensores[j].Registro
+	})
+}
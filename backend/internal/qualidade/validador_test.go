@@ -0,0 +1,43 @@
+package qualidade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Uma execução que encontra de novo o mesmo problema atualiza o documento, sem repeti-lo
+func TestProblemaRepetidoEntreExecucoes(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politico := domain.Politico{ID: primitive.NewObjectID(), Nome: "Fulano", CPF: "12345678909"}
+
+	for _, execucao := range []string{"primeira", "segunda"} {
+		v := NewValidador(db, execucao)
+		v.ValidarPolitico(ctx, "camara", politico)
+		v.VotoPoliticoDesconhecido(ctx, "camara", "deputado", 204554, "Beltrano", "2265603-43")
+	}
+
+	var problemas []domain.ProblemaQualidade
+	cursor, err := db.Collection("problemas_qualidade").Find(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.All(ctx, &problemas); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem data de nascimento e o voto desconhecido, uma vez cada
+	if len(problemas) != 2 {
+		t.Fatalf("%d problemas gravados; esperados 2", len(problemas))
+	}
+	for _, p := range problemas {
+		if p.ExecucaoID != "segunda" || p.VistoEm.Before(p.CreatedAt) {
+			t.Errorf("problema %s: execução %q, criado em %v e visto em %v", p.Regra, p.ExecucaoID, p.CreatedAt, p.VistoEm)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AlertaRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAlertaRepository(db *mongo.Database) *AlertaRepository {
+	return &AlertaRepository{
+		collection: db.Collection("alertas"),
+	}
+}
+
+func (r *AlertaRepository) Listar(ctx context.Context, filtros domain.FiltrosAlertas) (*domain.PaginatedResponse[domain.Alerta], error) {
+	filter := bson.M{}
+
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return nil, err
+		}
+		filter["politico_id"] = objectID
+	}
+
+	if len(filtros.Tipo) > 0 {
+		filter["tipo"] = bson.M{"$in": filtros.Tipo}
+	}
+
+	if len(filtros.Severidade) > 0 {
+		filter["severidade"] = bson.M{"$in": filtros.Severidade}
+	}
+
+	if filtros.Ano != nil {
+		filter["ano_referencia"] = *filtros.Ano
+	}
+
+	ordenacao := bson.D{
+		{Key: "ano_referencia", Value: -1},
+		{Key: "mes_referencia", Value: -1},
+		{Key: "valor", Value: -1},
+		{Key: "_id", Value: -1},
+	}
+	return listarPaginado(ctx, r.collection, filter, ordenacao, filtros.Paginacao, 20, nil, chaveAlerta)
+}
+
+// chaveAlerta são os valores da ordenação da listagem de alertas, que formam o cursor
+func chaveAlerta(a domain.Alerta) bson.D {
+	return bson.D{
+		{Key: "ano_referencia", Value: a.AnoReferencia},
+		{Key: "mes_referencia", Value: a.MesReferencia},
+		{Key: "valor", Value: a.Valor},
+		{Key: "_id", Value: a.ID},
+	}
+}
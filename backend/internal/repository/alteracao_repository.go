@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AlteracaoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAlteracaoRepository(db *mongo.Database) *AlteracaoRepository {
+	// Os valores anteriores e novos são livres; subdocumentos são lidos como mapas
+	// para que o JSON da resposta tenha o mesmo formato do documento original
+	registry := bson.NewRegistry()
+	registry.RegisterTypeMapEntry(bsontype.EmbeddedDocument, reflect.TypeOf(bson.M{}))
+
+	return &AlteracaoRepository{
+		collection: db.Collection("alteracoes", options.Collection().SetRegistry(registry)),
+	}
+}
+
+func (r *AlteracaoRepository) ListarPorPolitico(ctx context.Context, politicoID, campo string, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Alteracao], error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"politico_id": objectID}
+	if campo != "" {
+		filter["campo"] = campo
+	}
+
+	ordenacao := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(ctx, r.collection, filter, ordenacao, paginacao, 20, nil, func(a domain.Alteracao) bson.D {
+		return bson.D{{Key: "created_at", Value: a.CreatedAt}, {Key: "_id", Value: a.ID}}
+	})
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrChaveNaoEncontrada = errors.New("chave de API não encontrada")
+
+// ChaveRepository guarda as chaves de API usadas pelas rotas protegidas
+type ChaveRepository struct {
+	collection *mongo.Collection
+}
+
+func NewChaveRepository(db *mongo.Database) *ChaveRepository {
+	return &ChaveRepository{
+		collection: db.Collection("chaves_api"),
+	}
+}
+
+func (r *ChaveRepository) Criar(ctx context.Context, chave *domain.ChaveAPI) error {
+	if chave.ID.IsZero() {
+		chave.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, chave)
+	return err
+}
+
+// BuscarPorHash retorna a chave ativa com o hash informado, ou nil se não existir ou estiver revogada
+func (r *ChaveRepository) BuscarPorHash(ctx context.Context, hash string) (*domain.ChaveAPI, error) {
+	var chave domain.ChaveAPI
+	err := r.collection.FindOne(ctx, bson.M{"hash": hash, "revogada_em": nil}).Decode(&chave)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &chave, nil
+}
+
+func (r *ChaveRepository) RegistrarUso(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{"ultimo_uso_em": time.Now()}})
+	return err
+}
+
+func (r *ChaveRepository) Listar(ctx context.Context) ([]domain.ChaveAPI, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var chaves []domain.ChaveAPI
+	if err := cursor.All(ctx, &chaves); err != nil {
+		return nil, err
+	}
+	return chaves, nil
+}
+
+// Revogar desativa a chave; ela continua listada para fins de auditoria
+func (r *ChaveRepository) Revogar(ctx context.Context, id primitive.ObjectID) (*domain.ChaveAPI, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var chave domain.ChaveAPI
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "revogada_em": nil},
+		bson.M{"$set": bson.M{"revogada_em": time.Now()}},
+		opts,
+	).Decode(&chave)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrChaveNaoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &chave, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// percorrer decodifica os documentos do cursor um a um e os entrega a fn, sem carregar o
+// resultado inteiro na memória. Para no primeiro erro, do cursor ou de fn.
+func percorrer[T any](ctx context.Context, cursor *mongo.Cursor, fn func(T) error) error {
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var item T
+		if err := cursor.Decode(&item); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// idsPoliticos retorna os IDs dos políticos do partido e do estado atuais, para filtrar as
+// coleções de atividades, que guardam só o politico_id
+func idsPoliticos(ctx context.Context, db *mongo.Database, partido, estado string) ([]primitive.ObjectID, error) {
+	filter := bson.M{}
+	if partido != "" {
+		filter["partido.sigla"] = partido
+	}
+	if estado != "" {
+		filter["cargo_atual.estado"] = estado
+	}
+
+	cursor, err := db.Collection("politicos").Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{}
+	err = percorrer(ctx, cursor, func(doc struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}) error {
+		ids = append(ids, doc.ID)
+		return nil
+	})
+	return ids, err
+}
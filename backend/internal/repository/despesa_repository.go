@@ -20,7 +20,8 @@ func NewDespesaRepository(db *mongo.Database) *DespesaRepository {
 	}
 }
 
-func (r *DespesaRepository) ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, pagina, porPagina int) (*domain.PaginatedResponse[domain.Despesa], error) {
+// ListarPorPolitico lista as despesas do político, das mais recentes para as mais antigas
+func (r *DespesaRepository) ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Despesa], error) {
 	objectID, err := primitive.ObjectIDFromHex(politicoID)
 	if err != nil {
 		return nil, err
@@ -35,49 +36,10 @@ func (r *DespesaRepository) ListarPorPolitico(ctx context.Context, politicoID st
 		filter["mes_referencia"] = *mes
 	}
 
-	if pagina < 1 {
-		pagina = 1
-	}
-	if porPagina < 1 || porPagina > 100 {
-		porPagina = 20
-	}
-
-	skip := int64((pagina - 1) * porPagina)
-	limit := int64(porPagina)
-
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(limit).
-		SetSort(bson.D{{Key: "data", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var despesas []domain.Despesa
-	if err := cursor.All(ctx, &despesas); err != nil {
-		return nil, err
-	}
-
-	totalPaginas := int(total) / porPagina
-	if int(total)%porPagina > 0 {
-		totalPaginas++
-	}
-
-	return &domain.PaginatedResponse[domain.Despesa]{
-		Data:         despesas,
-		Total:        total,
-		Pagina:       pagina,
-		PorPagina:    porPagina,
-		TotalPaginas: totalPaginas,
-	}, nil
+	ordenacao := bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(ctx, r.collection, filter, ordenacao, paginacao, 20, nil, func(d domain.Despesa) bson.D {
+		return bson.D{{Key: "data", Value: d.Data}, {Key: "_id", Value: d.ID}}
+	})
 }
 
 func (r *DespesaRepository) TotalPorPolitico(ctx context.Context, politicoID string) (float64, error) {
@@ -177,3 +139,172 @@ func (r *DespesaRepository) TotalGeral(ctx context.Context) (float64, error) {
 	return result.Total, nil
 }
 
+// Resumo agrega as despesas por categoria, fornecedor e mês.
+// Os filtros de partido e estado usam os dados atuais do político.
+func (r *DespesaRepository) Resumo(ctx context.Context, filtros domain.FiltrosDespesas) (*domain.ResumoDespesas, error) {
+	match := bson.M{}
+
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return nil, err
+		}
+		match["politico_id"] = objectID
+	}
+	if filtros.Ano != nil {
+		match["ano_referencia"] = *filtros.Ano
+	}
+	if filtros.Mes != nil {
+		match["mes_referencia"] = *filtros.Mes
+	}
+
+	limite := filtros.Limite
+	if limite < 1 || limite > 100 {
+		limite = 10
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+	}
+
+	// Partido e estado ficam no político, então é preciso juntar as coleções
+	if filtros.Partido != "" || filtros.Estado != "" {
+		matchPolitico := bson.M{}
+		if filtros.Partido != "" {
+			matchPolitico["politico.partido.sigla"] = filtros.Partido
+		}
+		if filtros.Estado != "" {
+			matchPolitico["politico.cargo_atual.estado"] = filtros.Estado
+		}
+
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         "politicos",
+				"localField":   "politico_id",
+				"foreignField": "_id",
+				"as":           "politico",
+			}}},
+			bson.D{{Key: "$match", Value: matchPolitico}},
+			bson.D{{Key: "$project", Value: bson.M{"politico": 0}}},
+		)
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"totais": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":        nil,
+				"total":      bson.M{"$sum": "$valor"},
+				"quantidade": bson.M{"$sum": 1},
+			}},
+		},
+		"por_tipo": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":        "$tipo",
+				"total":      bson.M{"$sum": "$valor"},
+				"quantidade": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.D{{Key: "total", Value: -1}}},
+		},
+		"fornecedores": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":        "$cnpj_fornecedor",
+				"nome":       bson.M{"$first": "$fornecedor"},
+				"total":      bson.M{"$sum": "$valor"},
+				"quantidade": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.D{{Key: "total", Value: -1}}},
+			bson.M{"$limit": limite},
+			bson.M{"$project": bson.M{
+				"_id":        0,
+				"cnpj":       "$_id",
+				"nome":       1,
+				"total":      1,
+				"quantidade": 1,
+			}},
+		},
+		"serie_mensal": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":        bson.M{"ano": "$ano_referencia", "mes": "$mes_referencia"},
+				"total":      bson.M{"$sum": "$valor"},
+				"quantidade": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.D{{Key: "_id.ano", Value: 1}, {Key: "_id.mes", Value: 1}}},
+			bson.M{"$project": bson.M{
+				"_id":        0,
+				"ano":        "$_id.ano",
+				"mes":        "$_id.mes",
+				"total":      1,
+				"quantidade": 1,
+			}},
+		},
+	}}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		Totais []struct {
+			Total      float64 `bson:"total"`
+			Quantidade int     `bson:"quantidade"`
+		} `bson:"totais"`
+		PorTipo      []domain.TotalPorTipo       `bson:"por_tipo"`
+		Fornecedores []domain.TotalPorFornecedor `bson:"fornecedores"`
+		SerieMensal  []domain.TotalMensal        `bson:"serie_mensal"`
+	}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+
+	resumo := &domain.ResumoDespesas{
+		PorTipo:         result.PorTipo,
+		TopFornecedores: result.Fornecedores,
+		SerieMensal:     result.SerieMensal,
+	}
+	if len(result.Totais) > 0 {
+		resumo.Total = result.Totais[0].Total
+		resumo.Quantidade = result.Totais[0].Quantidade
+	}
+
+	return resumo, nil
+}
+
+// Exportar percorre todas as despesas que atendem aos filtros, em ordem de _id.
+// O limite dos filtros não se aplica.
+func (r *DespesaRepository) Exportar(ctx context.Context, filtros domain.FiltrosDespesas, fn func(domain.Despesa) error) error {
+	filter := bson.M{}
+
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return err
+		}
+		filter["politico_id"] = objectID
+	}
+	if filtros.Ano != nil {
+		filter["ano_referencia"] = *filtros.Ano
+	}
+	if filtros.Mes != nil {
+		filter["mes_referencia"] = *filtros.Mes
+	}
+
+	// Partido e estado ficam no político; o $and preserva o filtro por politico_id, se houver
+	if filtros.Partido != "" || filtros.Estado != "" {
+		ids, err := idsPoliticos(ctx, r.collection.Database(), filtros.Partido, filtros.Estado)
+		if err != nil {
+			return err
+		}
+		filter["$and"] = bson.A{bson.M{"politico_id": bson.M{"$in": ids}}}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	return percorrer(ctx, cursor, fn)
+}
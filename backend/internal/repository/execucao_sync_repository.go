@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrExecucaoNaoEncontrada = errors.New("execução de sincronização não encontrada")
+
+// ExecucaoSyncRepository guarda os jobs de sincronização e o seu andamento
+type ExecucaoSyncRepository struct {
+	collection *mongo.Collection
+}
+
+func NewExecucaoSyncRepository(db *mongo.Database) *ExecucaoSyncRepository {
+	return &ExecucaoSyncRepository{
+		collection: db.Collection("execucoes_sync"),
+	}
+}
+
+// Salvar grava o estado atual da execução, criando o registro se necessário. Os campos são
+// definidos um a um, para não apagar um pedido de cancelamento feito por outra instância.
+func (r *ExecucaoSyncRepository) Salvar(ctx context.Context, exec domain.ExecucaoSync) error {
+	opts := options.Update().SetUpsert(true)
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": exec.ID}, bson.M{"$set": exec}, opts)
+	return err
+}
+
+// SolicitarCancelamento registra o pedido de cancelamento de uma execução em andamento.
+// Retorna ErrExecucaoNaoEncontrada se não houver execução em andamento com o ID.
+func (r *ExecucaoSyncRepository) SolicitarCancelamento(ctx context.Context, id primitive.ObjectID) (*domain.ExecucaoSync, error) {
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$in": bson.A{domain.StatusExecucaoPendente, domain.StatusExecucaoExecutando}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var exec domain.ExecucaoSync
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"cancelamento_solicitado": true}}, opts).Decode(&exec)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrExecucaoNaoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &exec, nil
+}
+
+// CancelamentoSolicitado diz se foi pedido o cancelamento da execução
+func (r *ExecucaoSyncRepository) CancelamentoSolicitado(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	exec, err := r.BuscarPorID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return exec.CancelamentoSolicitado, nil
+}
+
+// MarcarInterrompidas marca como interrompidas as execuções em andamento sem batimento desde
+// antesDe, deixadas assim por um processo que parou sem registrar o fim
+func (r *ExecucaoSyncRepository) MarcarInterrompidas(ctx context.Context, antesDe time.Time) (int64, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": bson.A{domain.StatusExecucaoPendente, domain.StatusExecucaoExecutando}},
+		"$or": bson.A{
+			bson.M{"batimento_em": bson.M{"$lt": antesDe}},
+			bson.M{"batimento_em": bson.M{"$exists": false}, "created_at": bson.M{"$lt": antesDe}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status": domain.StatusExecucaoInterrompida,
+		"fim_em": time.Now(),
+	}}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *ExecucaoSyncRepository) BuscarPorID(ctx context.Context, id primitive.ObjectID) (*domain.ExecucaoSync, error) {
+	var exec domain.ExecucaoSync
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&exec)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrExecucaoNaoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &exec, nil
+}
+
+func (r *ExecucaoSyncRepository) Listar(ctx context.Context, status domain.StatusExecucao, agendamento string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ExecucaoSync], error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if agendamento != "" {
+		filter["agendamento"] = agendamento
+	}
+
+	return paginar[domain.ExecucaoSync](ctx, r.collection, filter, bson.D{{Key: "created_at", Value: -1}}, pagina, porPagina)
+}
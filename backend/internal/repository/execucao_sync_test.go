@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExecucaoSyncCancelamentoEInterrompidas(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	repo := repository.NewExecucaoSyncRepository(db)
+	ctx := context.Background()
+	agora := time.Now().Truncate(time.Millisecond)
+	antigo := agora.Add(-time.Hour)
+
+	nova := func(status domain.StatusExecucao, batimento *time.Time, criada time.Time) domain.ExecucaoSync {
+		exec := domain.ExecucaoSync{ID: primitive.NewObjectID(), Status: status, Etapas: []domain.EtapaSync{}, Erros: []string{}, CreatedAt: criada, BatimentoEm: batimento}
+		if err := repo.Salvar(ctx, exec); err != nil {
+			t.Fatalf("Salvar: %v", err)
+		}
+		return exec
+	}
+	viva := nova(domain.StatusExecucaoExecutando, &agora, antigo)
+	parada := nova(domain.StatusExecucaoExecutando, &antigo, antigo)
+	pendente := nova(domain.StatusExecucaoPendente, nil, antigo)
+	concluida := nova(domain.StatusExecucaoConcluida, &antigo, antigo)
+
+	// O pedido de cancelamento sobrevive às gravações do andamento feitas pelo processo que executa
+	if _, err := repo.SolicitarCancelamento(ctx, viva.ID); err != nil {
+		t.Fatalf("SolicitarCancelamento: %v", err)
+	}
+	viva.Erros = append(viva.Erros, "etapa com erro")
+	if err := repo.Salvar(ctx, viva); err != nil {
+		t.Fatal(err)
+	}
+	if solicitado, _ := repo.CancelamentoSolicitado(ctx, viva.ID); !solicitado {
+		t.Error("a gravação do andamento apagou o pedido de cancelamento")
+	}
+	if _, err := repo.SolicitarCancelamento(ctx, concluida.ID); !errors.Is(err, repository.ErrExecucaoNaoEncontrada) {
+		t.Errorf("cancelar execução concluída: %v; esperado ErrExecucaoNaoEncontrada", err)
+	}
+
+	n, err := repo.MarcarInterrompidas(ctx, agora.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("MarcarInterrompidas: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("%d execuções interrompidas; esperadas 2 (sem batimento recente)", n)
+	}
+	for exec, esperado := range map[primitive.ObjectID]domain.StatusExecucao{
+		viva.ID:      domain.StatusExecucaoExecutando,
+		parada.ID:    domain.StatusExecucaoInterrompida,
+		pendente.ID:  domain.StatusExecucaoInterrompida,
+		concluida.ID: domain.StatusExecucaoConcluida,
+	} {
+		salva, _ := repo.BuscarPorID(ctx, exec)
+		if salva.Status != esperado {
+			t.Errorf("execução %s: %s; esperado %s", exec.Hex(), salva.Status, esperado)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package repository_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func inserir[T any](t *testing.T, collection *mongo.Collection, itens []T) {
+	t.Helper()
+
+	docs := make([]interface{}, 0, len(itens))
+	for _, item := range itens {
+		docs = append(docs, item)
+	}
+	if _, err := collection.InsertMany(context.Background(), docs); err != nil {
+		t.Fatalf("erro ao inserir em %s: %v", collection.Name(), err)
+	}
+}
+
+// ids percorre a exportação e retorna os IDs na ordem em que foram entregues
+func ids[T any](t *testing.T, exportar func(func(T) error) error, id func(T) primitive.ObjectID) []string {
+	t.Helper()
+
+	resultado := []string{}
+	if err := exportar(func(item T) error {
+		resultado = append(resultado, id(item).Hex())
+		return nil
+	}); err != nil {
+		t.Fatalf("Exportar: %v", err)
+	}
+	return resultado
+}
+
+// As exportações com MongoDB e em memória devem entregar os mesmos registros, na mesma ordem
+func TestExportarComoEmMemoria(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 3, Politicos: 60, VotacoesPorCasa: 8})
+	ctx := context.Background()
+
+	db := mongomemtest.Banco(t)
+	inserir(t, db.Collection("politicos"), dados.Politicos)
+	inserir(t, db.Collection("votacoes"), dados.Votacoes)
+	inserir(t, db.Collection("despesas"), dados.Despesas)
+	inserir(t, db.Collection("proposicoes"), dados.Proposicoes)
+
+	banco := memoria.NewBanco(dados)
+	politicoID := dados.Politicos[3].ID.Hex()
+	partido := dados.Politicos[3].Partido.Sigla
+	ano := 2024
+
+	politicos := []repository.Politicos{repository.NewPoliticoRepository(db), memoria.NewPoliticoRepository(banco)}
+	despesas := []repository.Despesas{repository.NewDespesaRepository(db), memoria.NewDespesaRepository(banco)}
+	votacoes := []repository.Votacoes{repository.NewVotacaoRepository(db), memoria.NewVotacaoRepository(banco)}
+	proposicoes := []repository.Proposicoes{repository.NewProposicaoRepository(db), memoria.NewProposicaoRepository(banco)}
+
+	casos := []struct {
+		nome string
+		ids  func(i int) []string
+	}{
+		{"políticos de um partido", func(i int) []string {
+			filtros := domain.FiltrosPoliticos{Partido: []string{partido}, Paginacao: domain.Paginacao{Pagina: 2, PorPagina: 1}}
+			return ids(t, func(fn func(domain.Politico) error) error { return politicos[i].Exportar(ctx, filtros, fn) },
+				func(p domain.Politico) primitive.ObjectID { return p.ID })
+		}},
+		{"despesas do partido no ano", func(i int) []string {
+			filtros := domain.FiltrosDespesas{Partido: partido, Ano: &ano}
+			return ids(t, func(fn func(domain.Despesa) error) error { return despesas[i].Exportar(ctx, filtros, fn) },
+				func(d domain.Despesa) primitive.ObjectID { return d.ID })
+		}},
+		{"despesas de um político", func(i int) []string {
+			filtros := domain.FiltrosDespesas{PoliticoID: politicoID, Estado: "XX"}
+			return ids(t, func(fn func(domain.Despesa) error) error { return despesas[i].Exportar(ctx, filtros, fn) },
+				func(d domain.Despesa) primitive.ObjectID { return d.ID })
+		}},
+		{"votos SIM no ano", func(i int) []string {
+			filtros := domain.FiltrosVotacoes{Ano: &ano, Voto: domain.VotoSim}
+			return ids(t, func(fn func(domain.Votacao) error) error { return votacoes[i].Exportar(ctx, filtros, fn) },
+				func(v domain.Votacao) primitive.ObjectID { return v.ID })
+		}},
+		{"proposições de um autor ou coautor", func(i int) []string {
+			filtros := domain.FiltrosProposicoes{AutorID: politicoID}
+			return ids(t, func(fn func(domain.Proposicao) error) error { return proposicoes[i].Exportar(ctx, filtros, fn) },
+				func(p domain.Proposicao) primitive.ObjectID { return p.ID })
+		}},
+	}
+
+	for _, caso := range casos {
+		doMongo, daMemoria := caso.ids(0), caso.ids(1)
+		if !reflect.DeepEqual(doMongo, daMemoria) {
+			t.Errorf("%s: MongoDB entregou %d registros e a memória %d, ou em outra ordem", caso.nome, len(doMongo), len(daMemoria))
+		}
+		if caso.nome != "despesas de um político" && len(doMongo) == 0 {
+			t.Errorf("%s: nenhum registro; o caso não testa nada", caso.nome)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FornecedorRepository agrega as despesas pelo CPF/CNPJ do fornecedor
+type FornecedorRepository struct {
+	despesas  *mongo.Collection
+	cadastros *mongo.Collection
+}
+
+func NewFornecedorRepository(db *mongo.Database) *FornecedorRepository {
+	return &FornecedorRepository{
+		despesas:  db.Collection("despesas"),
+		cadastros: db.Collection("cadastro_fornecedores"),
+	}
+}
+
+func (r *FornecedorRepository) Ranking(ctx context.Context, filtros domain.FiltrosFornecedores) (*domain.PaginatedResponse[domain.TotalPorFornecedor], error) {
+	match := bson.M{"cnpj_fornecedor": bson.M{"$ne": ""}}
+
+	if filtros.Tipo != "" {
+		match["tipo"] = filtros.Tipo
+	}
+	if filtros.Ano != nil {
+		match["ano_referencia"] = *filtros.Ano
+	}
+	if filtros.Mes != nil {
+		match["mes_referencia"] = *filtros.Mes
+	}
+
+	pagina := filtros.Pagina
+	if pagina < 1 {
+		pagina = 1
+	}
+
+	porPagina := filtros.PorPagina
+	if porPagina < 1 || porPagina > 100 {
+		porPagina = 20
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$cnpj_fornecedor",
+			"nome":       bson.M{"$first": "$fornecedor"},
+			"total":      bson.M{"$sum": "$valor"},
+			"quantidade": bson.M{"$sum": 1},
+			"politicos":  bson.M{"$addToSet": "$politico_id"},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "n"}},
+			"dados": bson.A{
+				bson.M{"$sort": bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$skip": (pagina - 1) * porPagina},
+				bson.M{"$limit": porPagina},
+				bson.M{"$project": bson.M{
+					"_id":             0,
+					"cnpj":            "$_id",
+					"nome":            1,
+					"total":           1,
+					"quantidade":      1,
+					"total_politicos": bson.M{"$size": "$politicos"},
+				}},
+			},
+		}}},
+	}
+
+	// O agrupamento percorre todas as despesas do período e pode passar do limite de memória
+	opts := options.Aggregate().SetAllowDiskUse(true)
+	cursor, err := r.despesas.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		Total []struct {
+			N int64 `bson:"n"`
+		} `bson:"total"`
+		Dados []domain.TotalPorFornecedor `bson:"dados"`
+	}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+
+	var total int64
+	if len(result.Total) > 0 {
+		total = result.Total[0].N
+	}
+
+	totalPaginas := int(total) / porPagina
+	if int(total)%porPagina > 0 {
+		totalPaginas++
+	}
+
+	return &domain.PaginatedResponse[domain.TotalPorFornecedor]{
+		Data:         result.Dados,
+		Total:        total,
+		Pagina:       pagina,
+		PorPagina:    porPagina,
+		TotalPaginas: totalPaginas,
+	}, nil
+}
+
+// BuscarPerfil retorna o perfil de um fornecedor pelo documento já normalizado.
+// Retorna nil se o fornecedor não recebeu nenhum pagamento.
+func (r *FornecedorRepository) BuscarPerfil(ctx context.Context, cnpj string) (*domain.PerfilFornecedor, error) {
+	grupo := func(id interface{}) bson.M {
+		return bson.M{"$group": bson.M{
+			"_id":        id,
+			"total":      bson.M{"$sum": "$valor"},
+			"quantidade": bson.M{"$sum": 1},
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"cnpj_fornecedor": cnpj}}},
+		{{Key: "$facet", Value: bson.M{
+			"totais": bson.A{
+				bson.M{"$sort": bson.D{{Key: "data", Value: -1}}},
+				bson.M{"$group": bson.M{
+					"_id":                nil,
+					"nome":               bson.M{"$first": "$fornecedor"},
+					"tipo_documento":     bson.M{"$first": "$tipo_documento_fornecedor"},
+					"total":              bson.M{"$sum": "$valor"},
+					"quantidade":         bson.M{"$sum": 1},
+					"primeiro_pagamento": bson.M{"$min": "$data"},
+					"ultimo_pagamento":   bson.M{"$max": "$data"},
+				}},
+			},
+			"politicos": bson.A{
+				grupo("$politico_id"),
+				bson.M{"$sort": bson.D{{Key: "total", Value: -1}}},
+				bson.M{"$lookup": bson.M{
+					"from":         "politicos",
+					"localField":   "_id",
+					"foreignField": "_id",
+					"as":           "politico",
+				}},
+				bson.M{"$unwind": bson.M{"path": "$politico", "preserveNullAndEmptyArrays": true}},
+				bson.M{"$project": bson.M{
+					"_id":         0,
+					"politico_id": "$_id",
+					"nome":        "$politico.nome",
+					"partido":     "$politico.partido.sigla",
+					"estado":      "$politico.cargo_atual.estado",
+					"total":       1,
+					"quantidade":  1,
+				}},
+			},
+			"por_tipo": bson.A{
+				grupo("$tipo"),
+				bson.M{"$sort": bson.D{{Key: "total", Value: -1}}},
+			},
+			"serie_mensal": bson.A{
+				grupo(bson.M{"ano": "$ano_referencia", "mes": "$mes_referencia"}),
+				bson.M{"$sort": bson.D{{Key: "_id.ano", Value: 1}, {Key: "_id.mes", Value: 1}}},
+				bson.M{"$project": bson.M{
+					"_id":        0,
+					"ano":        "$_id.ano",
+					"mes":        "$_id.mes",
+					"total":      1,
+					"quantidade": 1,
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.despesas.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		Totais []struct {
+			Nome              string    `bson:"nome"`
+			TipoDocumento     string    `bson:"tipo_documento"`
+			Total             float64   `bson:"total"`
+			Quantidade        int       `bson:"quantidade"`
+			PrimeiroPagamento time.Time `bson:"primeiro_pagamento"`
+			UltimoPagamento   time.Time `bson:"ultimo_pagamento"`
+		} `bson:"totais"`
+		Politicos   []domain.PagamentoPorPolitico `bson:"politicos"`
+		PorTipo     []domain.TotalPorTipo         `bson:"por_tipo"`
+		SerieMensal []domain.TotalMensal          `bson:"serie_mensal"`
+	}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(result.Totais) == 0 {
+		return nil, nil
+	}
+
+	totais := result.Totais[0]
+	perfil := &domain.PerfilFornecedor{
+		CNPJ:              cnpj,
+		TipoDocumento:     totais.TipoDocumento,
+		Nome:              totais.Nome,
+		Total:             totais.Total,
+		Quantidade:        totais.Quantidade,
+		PrimeiroPagamento: totais.PrimeiroPagamento,
+		UltimoPagamento:   totais.UltimoPagamento,
+		Politicos:         result.Politicos,
+		PorTipo:           result.PorTipo,
+		SerieMensal:       result.SerieMensal,
+	}
+
+	// Dados cadastrais são opcionais (só existem para CNPJs já consultados pela análise de despesas)
+	var cadastro struct {
+		RazaoSocial  string    `bson:"razao_social"`
+		DataAbertura time.Time `bson:"data_abertura"`
+	}
+	err = r.cadastros.FindOne(ctx, bson.M{"cnpj": cnpj}).Decode(&cadastro)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if err == nil {
+		perfil.RazaoSocial = cadastro.RazaoSocial
+		if !cadastro.DataAbertura.IsZero() {
+			perfil.DataAbertura = &cadastro.DataAbertura
+		}
+	}
+
+	return perfil, nil
+}
@@ -0,0 +1,159 @@
+package repository_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Os documentos embutidos por ?incluir= devem ser os mesmos com MongoDB ($lookup) e em memória
+func TestInclusoes(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 3, Politicos: 20, VotacoesPorCasa: 10})
+	ctx := context.Background()
+
+	// Um voto numa proposição que não está na base fica sem a proposição, sem falhar
+	politico := dados.Votacoes[0].PoliticoID
+	orfao := domain.Votacao{ID: primitive.NewObjectID(), PoliticoID: politico, ProposicaoID: primitive.NewObjectID(), Voto: domain.VotoSim, Data: time.Now()}
+	dados.Votacoes = append(dados.Votacoes, orfao)
+
+	db := mongomemtest.Banco(t)
+	inserir(t, db.Collection("votacoes"), dados.Votacoes)
+	inserir(t, db.Collection("proposicoes"), dados.Proposicoes)
+	inserir(t, db.Collection("politicos"), dados.Politicos)
+
+	banco := memoria.NewBanco(dados)
+	votacoes := []repository.Votacoes{repository.NewVotacaoRepository(db), memoria.NewVotacaoRepository(banco)}
+	proposicoes := []repository.Proposicoes{repository.NewProposicaoRepository(db), memoria.NewProposicaoRepository(banco)}
+
+	// resumoVotos descreve o que veio embutido em cada voto: a proposição e o autor
+	resumoVotos := func(i int, incluir domain.Inclusoes) []string {
+		t.Helper()
+		pagina, err := votacoes[i].ListarPorPolitico(ctx, politico.Hex(), domain.Paginacao{PorPagina: 100}, incluir)
+		if err != nil {
+			t.Fatalf("ListarPorPolitico: %v", err)
+		}
+		var resumo []string
+		for _, v := range pagina.Data {
+			switch {
+			case v.Proposicao == nil:
+				resumo = append(resumo, v.ID.Hex()+" sem proposição")
+			case v.Proposicao.Autor == nil:
+				resumo = append(resumo, v.ID.Hex()+" "+v.Proposicao.Ementa)
+			default:
+				resumo = append(resumo, v.ID.Hex()+" "+v.Proposicao.Ementa+" | autor: "+v.Proposicao.Autor.Nome)
+			}
+		}
+		return resumo
+	}
+
+	for _, caso := range []struct {
+		nome    string
+		incluir domain.Inclusoes
+	}{
+		{"sem inclusões", domain.Inclusoes{}},
+		{"com a proposição", domain.Inclusoes{Proposicao: true}},
+		{"com o autor", domain.Inclusoes{Autor: true}},
+	} {
+		mongo, memoria := resumoVotos(0, caso.incluir), resumoVotos(1, caso.incluir)
+		if !reflect.DeepEqual(mongo, memoria) {
+			t.Errorf("votações %s: MongoDB e memória divergem\n%v\n%v", caso.nome, mongo, memoria)
+		}
+
+		incluidas := 0
+		for _, v := range mongo {
+			if !strings.HasSuffix(v, " sem proposição") {
+				incluidas++
+				if caso.incluir.Autor && !strings.Contains(v, " | autor: ") {
+					t.Errorf("votações %s: voto sem o autor da proposição: %s", caso.nome, v)
+				}
+			}
+		}
+		esperadas := 0
+		if caso.incluir.Proposicao || caso.incluir.Autor {
+			// Os votos com proposição, menos o órfão
+			for _, v := range dados.Votacoes {
+				if v.PoliticoID == politico && !v.ProposicaoID.IsZero() && v.ID != orfao.ID {
+					esperadas++
+				}
+			}
+		}
+		if incluidas != esperadas {
+			t.Errorf("votações %s: %d votos com a proposição embutida, esperados %d", caso.nome, incluidas, esperadas)
+		}
+	}
+
+	// Nas proposições, o autor embutido é o do autor_id, mesmo para quem é só coautor
+	var autor primitive.ObjectID
+	for _, p := range dados.Proposicoes {
+		if len(p.CoautoresIDs) > 0 {
+			autor = p.CoautoresIDs[0]
+			break
+		}
+	}
+	for i, origem := range []string{"MongoDB", "memória"} {
+		pagina, err := proposicoes[i].ListarPorAutor(ctx, autor.Hex(), domain.Paginacao{PorPagina: 100}, domain.Inclusoes{Autor: true})
+		if err != nil {
+			t.Fatalf("ListarPorAutor: %v", err)
+		}
+		if len(pagina.Data) == 0 {
+			t.Fatalf("%s: nenhuma proposição do coautor", origem)
+		}
+		for _, p := range pagina.Data {
+			if p.Autor == nil || p.Autor.ID != p.AutorID {
+				t.Errorf("%s: proposição %s sem o autor %s embutido", origem, p.ID.Hex(), p.AutorID.Hex())
+			}
+		}
+	}
+}
+
+// ?campos= vira projeção: só os campos pedidos (e o cursor) vêm do banco, também dos embutidos
+func TestCamposProjetados(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 3, Politicos: 20, VotacoesPorCasa: 10})
+	ctx := context.Background()
+
+	db := mongomemtest.Banco(t)
+	inserir(t, db.Collection("votacoes"), dados.Votacoes)
+	inserir(t, db.Collection("proposicoes"), dados.Proposicoes)
+	inserir(t, db.Collection("politicos"), dados.Politicos)
+	repo := repository.NewVotacaoRepository(db)
+
+	politico := dados.Votacoes[0].PoliticoID.Hex()
+	paginacao := domain.Paginacao{PorCursor: true, PorPagina: 4, Campos: []string{"voto", "proposicao.ementa", "desconhecido"}}
+	pagina, err := repo.ListarPorPolitico(ctx, politico, paginacao, domain.Inclusoes{Proposicao: true})
+	if err != nil {
+		t.Fatalf("ListarPorPolitico: %v", err)
+	}
+	comProposicao := 0
+	for _, v := range pagina.Data {
+		if v.ID.IsZero() || v.Voto == "" || v.Data.IsZero() {
+			t.Errorf("voto sem id, voto ou data (a data forma o cursor): %+v", v.Votacao)
+		}
+		if !v.PoliticoID.IsZero() || v.Sessao != "" {
+			t.Errorf("campos não pedidos vieram do banco: %+v", v.Votacao)
+		}
+		if v.Proposicao != nil {
+			comProposicao++
+			if v.Proposicao.Ementa == "" || v.Proposicao.Tipo != "" {
+				t.Errorf("proposição embutida com os campos errados: %+v", v.Proposicao.Proposicao)
+			}
+		}
+	}
+	if comProposicao == 0 {
+		t.Error("nenhum voto com a ementa da proposição")
+	}
+
+	// O cursor continua funcionando com a projeção
+	paginacao.Cursor = pagina.ProximoCursor
+	if _, err := repo.ListarPorPolitico(ctx, politico, paginacao, domain.Inclusoes{Proposicao: true}); err != nil || pagina.ProximoCursor == "" {
+		t.Errorf("próxima página com campos: cursor %q, erro %v", pagina.ProximoCursor, err)
+	}
+}
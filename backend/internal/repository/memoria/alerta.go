@@ -0,0 +1,76 @@
+package memoria
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AlertaRepository struct {
+	banco *Banco
+}
+
+var _ repository.Alertas = (*AlertaRepository)(nil)
+
+func NewAlertaRepository(banco *Banco) *AlertaRepository {
+	return &AlertaRepository{banco: banco}
+}
+
+func (r *AlertaRepository) Listar(ctx context.Context, filtros domain.FiltrosAlertas) (*domain.PaginatedResponse[domain.Alerta], error) {
+	var politicoID primitive.ObjectID
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return nil, err
+		}
+		politicoID = objectID
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var alertas []domain.Alerta
+	for _, a := range r.banco.alertas {
+		if !politicoID.IsZero() && a.PoliticoID != politicoID {
+			continue
+		}
+		if len(filtros.Tipo) > 0 && !entre(filtros.Tipo, a.Tipo) {
+			continue
+		}
+		if len(filtros.Severidade) > 0 && !entre(filtros.Severidade, a.Severidade) {
+			continue
+		}
+		if filtros.Ano != nil && a.AnoReferencia != *filtros.Ano {
+			continue
+		}
+		alertas = append(alertas, a)
+	}
+
+	ordenacao := bson.D{
+		{Key: "ano_referencia", Value: -1},
+		{Key: "mes_referencia", Value: -1},
+		{Key: "valor", Value: -1},
+		{Key: "_id", Value: -1},
+	}
+	return listarPaginado(alertas, ordenacao, filtros.Paginacao, 20, func(a domain.Alerta) bson.D {
+		return bson.D{
+			{Key: "ano_referencia", Value: a.AnoReferencia},
+			{Key: "mes_referencia", Value: a.MesReferencia},
+			{Key: "valor", Value: a.Valor},
+			{Key: "_id", Value: a.ID},
+		}
+	})
+}
+
+// entre indica se o valor está na lista, como um $in
+func entre[T comparable](lista []T, valor T) bool {
+	for _, v := range lista {
+		if v == valor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package memoria
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AlteracaoRepository struct {
+	banco *Banco
+}
+
+var _ repository.Alteracoes = (*AlteracaoRepository)(nil)
+
+func NewAlteracaoRepository(banco *Banco) *AlteracaoRepository {
+	return &AlteracaoRepository{banco: banco}
+}
+
+func (r *AlteracaoRepository) ListarPorPolitico(ctx context.Context, politicoID, campo string, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Alteracao], error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var alteracoes []domain.Alteracao
+	for _, a := range r.banco.alteracoes {
+		if a.PoliticoID == objectID && (campo == "" || a.Campo == campo) {
+			alteracoes = append(alteracoes, a)
+		}
+	}
+
+	ordenacao := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(alteracoes, ordenacao, paginacao, 20, func(a domain.Alteracao) bson.D {
+		return bson.D{{Key: "created_at", Value: a.CreatedAt}, {Key: "_id", Value: a.ID}}
+	})
+}
@@ -0,0 +1,146 @@
+// Package memoria implementa os repositórios de repository em memória. É usado no modo debug,
+// com os dados de internal/mock, e nos testes dos serviços, que assim não dependem do MongoDB.
+// As consultas reproduzem o comportamento das implementações com MongoDB: mesmos filtros,
+// ordenação, paginação e erros (IDs inválidos e mongo.ErrNoDocuments).
+package memoria
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Banco guarda as coleções compartilhadas pelos repositórios, como o *mongo.Database
+type Banco struct {
+	mu          sync.RWMutex
+	politicos   []domain.Politico
+	votacoes    []domain.Votacao
+	despesas    []domain.Despesa
+	proposicoes []domain.Proposicao
+	presencas   []domain.Presenca
+
+	// Coleções preenchidas pela API, pelas sincronizações e pela análise; começam vazias,
+	// exceto os partidos, que vêm do cadastro padrão
+	partidos    []domain.RegistroPartido
+	alertas     []domain.Alerta
+	alteracoes  []domain.Alteracao
+	unificacoes []domain.Unificacao
+	revisoes    []domain.RevisaoIdentidade
+	chaves      []domain.ChaveAPI
+	problemas   []domain.ProblemaQualidade
+	execucoes   []domain.ExecucaoSync
+}
+
+// NewBanco cria um banco com uma cópia dos dados informados
+func NewBanco(dados mock.Dados) *Banco {
+	banco := &Banco{
+		politicos:   append([]domain.Politico(nil), dados.Politicos...),
+		votacoes:    append([]domain.Votacao(nil), dados.Votacoes...),
+		despesas:    append([]domain.Despesa(nil), dados.Despesas...),
+		proposicoes: append([]domain.Proposicao(nil), dados.Proposicoes...),
+		presencas:   append([]domain.Presenca(nil), dados.Presencas...),
+		partidos:    partidos.Padrao(),
+	}
+	for i := range banco.partidos {
+		banco.partidos[i].ID = primitive.NewObjectID()
+	}
+	return banco
+}
+
+// paginar recorta a página pedida, com os mesmos limites das implementações com MongoDB
+func paginar[T any](itens []T, pagina, porPagina, porPaginaPadrao int) *domain.PaginatedResponse[T] {
+	if pagina < 1 {
+		pagina = 1
+	}
+	if porPagina < 1 || porPagina > 100 {
+		porPagina = porPaginaPadrao
+	}
+
+	total := len(itens)
+	inicio := (pagina - 1) * porPagina
+	if inicio > total {
+		inicio = total
+	}
+	fim := inicio + porPagina
+	if fim > total {
+		fim = total
+	}
+
+	totalPaginas := total / porPagina
+	if total%porPagina > 0 {
+		totalPaginas++
+	}
+
+	return &domain.PaginatedResponse[T]{
+		Data:         append([]T{}, itens[inicio:fim]...),
+		Total:        int64(total),
+		Pagina:       pagina,
+		PorPagina:    porPagina,
+		TotalPaginas: totalPaginas,
+	}
+}
+
+// contem indica se o ID está na lista
+func contem(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// percorrer entrega os itens a fn em ordem de _id, como os cursores das exportações no
+// MongoDB. Recebe uma cópia filtrada, para que fn rode sem a trava do banco.
+func percorrer[T any](itens []T, id func(T) primitive.ObjectID, fn func(T) error) error {
+	sort.SliceStable(itens, func(i, j int) bool {
+		a, b := id(itens[i]), id(itens[j])
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	for _, item := range itens {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// politicosDe retorna os IDs dos políticos com o partido e o estado atuais informados;
+// o chamador deve segurar a trava de leitura
+func (b *Banco) politicosDe(partido, estado string) map[primitive.ObjectID]bool {
+	ids := make(map[primitive.ObjectID]bool)
+	for _, p := range b.politicos {
+		if (partido == "" || p.Partido.Sigla == partido) && (estado == "" || p.CargoAtual.Estado == estado) {
+			ids[p.ID] = true
+		}
+	}
+	return ids
+}
+
+// proposicaoPorID retorna uma cópia da proposição, como um $lookup, ou nil se não existir;
+// o chamador deve segurar a trava de leitura
+func (b *Banco) proposicaoPorID(id primitive.ObjectID) *domain.ProposicaoComAutor {
+	for _, p := range b.proposicoes {
+		if p.ID == id {
+			return &domain.ProposicaoComAutor{Proposicao: p}
+		}
+	}
+	return nil
+}
+
+// politicoPorID retorna uma cópia do político, ou nil se não existir; o chamador deve
+// segurar a trava de leitura
+func (b *Banco) politicoPorID(id primitive.ObjectID) *domain.Politico {
+	for _, p := range b.politicos {
+		if p.ID == id {
+			politico := p
+			return &politico
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package memoria
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ChaveRepository struct {
+	banco *Banco
+}
+
+var _ repository.Chaves = (*ChaveRepository)(nil)
+
+func NewChaveRepository(banco *Banco) *ChaveRepository {
+	return &ChaveRepository{banco: banco}
+}
+
+func (r *ChaveRepository) Criar(ctx context.Context, chave *domain.ChaveAPI) error {
+	if chave.ID.IsZero() {
+		chave.ID = primitive.NewObjectID()
+	}
+
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	r.banco.chaves = append(r.banco.chaves, *chave)
+	return nil
+}
+
+// BuscarPorHash retorna a chave ativa com o hash informado, ou nil se não existir ou estiver revogada
+func (r *ChaveRepository) BuscarPorHash(ctx context.Context, hash string) (*domain.ChaveAPI, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	for _, c := range r.banco.chaves {
+		if c.Hash == hash && c.RevogadaEm == nil {
+			chave := c
+			return &chave, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *ChaveRepository) RegistrarUso(ctx context.Context, id primitive.ObjectID) error {
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	for i := range r.banco.chaves {
+		if r.banco.chaves[i].ID == id {
+			agora := time.Now()
+			r.banco.chaves[i].UltimoUsoEm = &agora
+		}
+	}
+	return nil
+}
+
+func (r *ChaveRepository) Listar(ctx context.Context) ([]domain.ChaveAPI, error) {
+	r.banco.mu.RLock()
+	chaves := append([]domain.ChaveAPI(nil), r.banco.chaves...)
+	r.banco.mu.RUnlock()
+
+	sort.SliceStable(chaves, func(i, j int) bool {
+		return chaves[i].CreatedAt.After(chaves[j].CreatedAt)
+	})
+	return chaves, nil
+}
+
+// Revogar desativa a chave; ela continua listada para fins de auditoria
+func (r *ChaveRepository) Revogar(ctx context.Context, id primitive.ObjectID) (*domain.ChaveAPI, error) {
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	for i := range r.banco.chaves {
+		if r.banco.chaves[i].ID == id && r.banco.chaves[i].RevogadaEm == nil {
+			agora := time.Now()
+			r.banco.chaves[i].RevogadaEm = &agora
+			chave := r.banco.chaves[i]
+			return &chave, nil
+		}
+	}
+	return nil, repository.ErrChaveNaoEncontrada
+}
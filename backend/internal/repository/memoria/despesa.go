@@ -0,0 +1,247 @@
+package memoria
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DespesaRepository struct {
+	banco *Banco
+}
+
+var _ repository.Despesas = (*DespesaRepository)(nil)
+
+func NewDespesaRepository(banco *Banco) *DespesaRepository {
+	return &DespesaRepository{banco: banco}
+}
+
+// doPolitico retorna as despesas do político; o chamador deve segurar a trava de leitura
+func (r *DespesaRepository) doPolitico(politicoID primitive.ObjectID) []domain.Despesa {
+	var despesas []domain.Despesa
+	for _, d := range r.banco.despesas {
+		if d.PoliticoID == politicoID {
+			despesas = append(despesas, d)
+		}
+	}
+	return despesas
+}
+
+func (r *DespesaRepository) ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Despesa], error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var despesas []domain.Despesa
+	for _, d := range r.doPolitico(objectID) {
+		if ano != nil && d.AnoReferencia != *ano || mes != nil && d.MesReferencia != *mes {
+			continue
+		}
+		despesas = append(despesas, d)
+	}
+
+	ordenacao := bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(despesas, ordenacao, paginacao, 20, func(d domain.Despesa) bson.D {
+		return bson.D{{Key: "data", Value: d.Data}, {Key: "_id", Value: d.ID}}
+	})
+}
+
+func (r *DespesaRepository) TotalPorPolitico(ctx context.Context, politicoID string) (float64, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var total float64
+	for _, d := range r.doPolitico(objectID) {
+		total += d.Valor
+	}
+	return total, nil
+}
+
+// MediaMensalPorPolitico é a média dos totais de cada mês com despesas
+func (r *DespesaRepository) MediaMensalPorPolitico(ctx context.Context, politicoID string) (float64, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	porMes := make(map[[2]int]float64)
+	for _, d := range r.doPolitico(objectID) {
+		porMes[[2]int{d.AnoReferencia, d.MesReferencia}] += d.Valor
+	}
+	if len(porMes) == 0 {
+		return 0, nil
+	}
+
+	var soma float64
+	for _, total := range porMes {
+		soma += total
+	}
+	return soma / float64(len(porMes)), nil
+}
+
+func (r *DespesaRepository) TotalGeral(ctx context.Context) (float64, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var total float64
+	for _, d := range r.banco.despesas {
+		total += d.Valor
+	}
+	return total, nil
+}
+
+// Resumo agrega as despesas por categoria, fornecedor e mês.
+// Os filtros de partido e estado usam os dados atuais do político.
+func (r *DespesaRepository) Resumo(ctx context.Context, filtros domain.FiltrosDespesas) (*domain.ResumoDespesas, error) {
+	var politicoID primitive.ObjectID
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return nil, err
+		}
+		politicoID = objectID
+	}
+
+	limite := filtros.Limite
+	if limite < 1 || limite > 100 {
+		limite = 10
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	politicos := make(map[primitive.ObjectID]domain.Politico, len(r.banco.politicos))
+	for _, p := range r.banco.politicos {
+		politicos[p.ID] = p
+	}
+
+	resumo := &domain.ResumoDespesas{}
+	porTipo := make(map[string]*domain.TotalPorTipo)
+	porFornecedor := make(map[string]*domain.TotalPorFornecedor)
+	porMes := make(map[[2]int]*domain.TotalMensal)
+
+	for _, d := range r.banco.despesas {
+		if !politicoID.IsZero() && d.PoliticoID != politicoID ||
+			filtros.Ano != nil && d.AnoReferencia != *filtros.Ano ||
+			filtros.Mes != nil && d.MesReferencia != *filtros.Mes {
+			continue
+		}
+		if filtros.Partido != "" || filtros.Estado != "" {
+			p, ok := politicos[d.PoliticoID]
+			if !ok ||
+				filtros.Partido != "" && p.Partido.Sigla != filtros.Partido ||
+				filtros.Estado != "" && p.CargoAtual.Estado != filtros.Estado {
+				continue
+			}
+		}
+
+		resumo.Total += d.Valor
+		resumo.Quantidade++
+
+		t, ok := porTipo[d.Tipo]
+		if !ok {
+			t = &domain.TotalPorTipo{Tipo: d.Tipo}
+			porTipo[d.Tipo] = t
+		}
+		t.Total += d.Valor
+		t.Quantidade++
+
+		f, ok := porFornecedor[d.CNPJFornecedor]
+		if !ok {
+			f = &domain.TotalPorFornecedor{CNPJ: d.CNPJFornecedor, Nome: d.Fornecedor}
+			porFornecedor[d.CNPJFornecedor] = f
+		}
+		f.Total += d.Valor
+		f.Quantidade++
+
+		chave := [2]int{d.AnoReferencia, d.MesReferencia}
+		m, ok := porMes[chave]
+		if !ok {
+			m = &domain.TotalMensal{Ano: d.AnoReferencia, Mes: d.MesReferencia}
+			porMes[chave] = m
+		}
+		m.Total += d.Valor
+		m.Quantidade++
+	}
+
+	for _, t := range porTipo {
+		resumo.PorTipo = append(resumo.PorTipo, *t)
+	}
+	sort.Slice(resumo.PorTipo, func(i, j int) bool {
+		if resumo.PorTipo[i].Total != resumo.PorTipo[j].Total {
+			return resumo.PorTipo[i].Total > resumo.PorTipo[j].Total
+		}
+		return resumo.PorTipo[i].Tipo < resumo.PorTipo[j].Tipo
+	})
+
+	for _, f := range porFornecedor {
+		resumo.TopFornecedores = append(resumo.TopFornecedores, *f)
+	}
+	sort.Slice(resumo.TopFornecedores, func(i, j int) bool {
+		if resumo.TopFornecedores[i].Total != resumo.TopFornecedores[j].Total {
+			return resumo.TopFornecedores[i].Total > resumo.TopFornecedores[j].Total
+		}
+		return resumo.TopFornecedores[i].CNPJ < resumo.TopFornecedores[j].CNPJ
+	})
+	if len(resumo.TopFornecedores) > limite {
+		resumo.TopFornecedores = resumo.TopFornecedores[:limite]
+	}
+
+	for _, m := range porMes {
+		resumo.SerieMensal = append(resumo.SerieMensal, *m)
+	}
+	sort.Slice(resumo.SerieMensal, func(i, j int) bool {
+		if resumo.SerieMensal[i].Ano != resumo.SerieMensal[j].Ano {
+			return resumo.SerieMensal[i].Ano < resumo.SerieMensal[j].Ano
+		}
+		return resumo.SerieMensal[i].Mes < resumo.SerieMensal[j].Mes
+	})
+
+	return resumo, nil
+}
+
+func (r *DespesaRepository) Exportar(ctx context.Context, filtros domain.FiltrosDespesas, fn func(domain.Despesa) error) error {
+	var politicoID primitive.ObjectID
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return err
+		}
+		politicoID = objectID
+	}
+
+	r.banco.mu.RLock()
+	var politicos map[primitive.ObjectID]bool
+	if filtros.Partido != "" || filtros.Estado != "" {
+		politicos = r.banco.politicosDe(filtros.Partido, filtros.Estado)
+	}
+	var despesas []domain.Despesa
+	for _, d := range r.banco.despesas {
+		if !politicoID.IsZero() && d.PoliticoID != politicoID ||
+			politicos != nil && !politicos[d.PoliticoID] ||
+			filtros.Ano != nil && d.AnoReferencia != *filtros.Ano ||
+			filtros.Mes != nil && d.MesReferencia != *filtros.Mes {
+			continue
+		}
+		despesas = append(despesas, d)
+	}
+	r.banco.mu.RUnlock()
+
+	return percorrer(despesas, func(d domain.Despesa) primitive.ObjectID { return d.ID }, fn)
+}
@@ -0,0 +1,131 @@
+package memoria
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExecucaoSyncRepository struct {
+	banco *Banco
+}
+
+var _ repository.ExecucoesSync = (*ExecucaoSyncRepository)(nil)
+
+func NewExecucaoSyncRepository(banco *Banco) *ExecucaoSyncRepository {
+	return &ExecucaoSyncRepository{banco: banco}
+}
+
+// Salvar grava o estado atual da execução, criando o registro se necessário. Como o $set da
+// implementação com MongoDB, não apaga um pedido de cancelamento já registrado.
+func (r *ExecucaoSyncRepository) Salvar(ctx context.Context, exec domain.ExecucaoSync) error {
+	exec.Etapas = append([]domain.EtapaSync(nil), exec.Etapas...)
+	exec.Erros = append([]string(nil), exec.Erros...)
+
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	if i := r.posicao(exec.ID); i >= 0 {
+		exec.CancelamentoSolicitado = exec.CancelamentoSolicitado || r.banco.execucoes[i].CancelamentoSolicitado
+		r.banco.execucoes[i] = exec
+		return nil
+	}
+	r.banco.execucoes = append(r.banco.execucoes, exec)
+	return nil
+}
+
+// posicao retorna o índice da execução em r.banco.execucoes, ou -1; exige o banco travado
+func (r *ExecucaoSyncRepository) posicao(id primitive.ObjectID) int {
+	for i, e := range r.banco.execucoes {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// SolicitarCancelamento registra o pedido de cancelamento de uma execução em andamento.
+// Retorna ErrExecucaoNaoEncontrada se não houver execução em andamento com o ID.
+func (r *ExecucaoSyncRepository) SolicitarCancelamento(ctx context.Context, id primitive.ObjectID) (*domain.ExecucaoSync, error) {
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	i := r.posicao(id)
+	if i < 0 || !emAndamento(r.banco.execucoes[i].Status) {
+		return nil, repository.ErrExecucaoNaoEncontrada
+	}
+	r.banco.execucoes[i].CancelamentoSolicitado = true
+	exec := r.banco.execucoes[i]
+	return &exec, nil
+}
+
+// CancelamentoSolicitado diz se foi pedido o cancelamento da execução
+func (r *ExecucaoSyncRepository) CancelamentoSolicitado(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	exec, err := r.BuscarPorID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return exec.CancelamentoSolicitado, nil
+}
+
+// MarcarInterrompidas marca como interrompidas as execuções em andamento sem batimento desde
+// antesDe, deixadas assim por um processo que parou sem registrar o fim
+func (r *ExecucaoSyncRepository) MarcarInterrompidas(ctx context.Context, antesDe time.Time) (int64, error) {
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	var n int64
+	for i := range r.banco.execucoes {
+		e := &r.banco.execucoes[i]
+		ultimo := e.CreatedAt
+		if e.BatimentoEm != nil {
+			ultimo = *e.BatimentoEm
+		}
+		if !emAndamento(e.Status) || !ultimo.Before(antesDe) {
+			continue
+		}
+		agora := time.Now()
+		e.Status = domain.StatusExecucaoInterrompida
+		e.FimEm = &agora
+		n++
+	}
+	return n, nil
+}
+
+func (r *ExecucaoSyncRepository) BuscarPorID(ctx context.Context, id primitive.ObjectID) (*domain.ExecucaoSync, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	i := r.posicao(id)
+	if i < 0 {
+		return nil, repository.ErrExecucaoNaoEncontrada
+	}
+	exec := r.banco.execucoes[i]
+	return &exec, nil
+}
+
+func (r *ExecucaoSyncRepository) Listar(ctx context.Context, status domain.StatusExecucao, agendamento string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ExecucaoSync], error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var execucoes []domain.ExecucaoSync
+	for _, e := range r.banco.execucoes {
+		if status != "" && e.Status != status || agendamento != "" && e.Agendamento != agendamento {
+			continue
+		}
+		execucoes = append(execucoes, e)
+	}
+
+	sort.SliceStable(execucoes, func(i, j int) bool {
+		return execucoes[i].CreatedAt.After(execucoes[j].CreatedAt)
+	})
+	return paginar(execucoes, pagina, porPagina, 20), nil
+}
+
+func emAndamento(status domain.StatusExecucao) bool {
+	return status == domain.StatusExecucaoPendente || status == domain.StatusExecucaoExecutando
+}
@@ -0,0 +1,152 @@
+package memoria
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FornecedorRepository struct {
+	banco *Banco
+}
+
+var _ repository.Fornecedores = (*FornecedorRepository)(nil)
+
+func NewFornecedorRepository(banco *Banco) *FornecedorRepository {
+	return &FornecedorRepository{banco: banco}
+}
+
+func (r *FornecedorRepository) Ranking(ctx context.Context, filtros domain.FiltrosFornecedores) (*domain.PaginatedResponse[domain.TotalPorFornecedor], error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	porFornecedor := make(map[string]*domain.TotalPorFornecedor)
+	politicos := make(map[string]map[primitive.ObjectID]bool)
+	for _, d := range r.banco.despesas {
+		if d.CNPJFornecedor == "" ||
+			filtros.Tipo != "" && d.Tipo != filtros.Tipo ||
+			filtros.Ano != nil && d.AnoReferencia != *filtros.Ano ||
+			filtros.Mes != nil && d.MesReferencia != *filtros.Mes {
+			continue
+		}
+
+		f, ok := porFornecedor[d.CNPJFornecedor]
+		if !ok {
+			f = &domain.TotalPorFornecedor{CNPJ: d.CNPJFornecedor, Nome: d.Fornecedor}
+			porFornecedor[d.CNPJFornecedor] = f
+			politicos[d.CNPJFornecedor] = make(map[primitive.ObjectID]bool)
+		}
+		f.Total += d.Valor
+		f.Quantidade++
+		politicos[d.CNPJFornecedor][d.PoliticoID] = true
+	}
+
+	fornecedores := make([]domain.TotalPorFornecedor, 0, len(porFornecedor))
+	for cnpj, f := range porFornecedor {
+		f.TotalPoliticos = len(politicos[cnpj])
+		fornecedores = append(fornecedores, *f)
+	}
+	sort.Slice(fornecedores, func(i, j int) bool {
+		if fornecedores[i].Total != fornecedores[j].Total {
+			return fornecedores[i].Total > fornecedores[j].Total
+		}
+		return fornecedores[i].CNPJ < fornecedores[j].CNPJ
+	})
+
+	return paginar(fornecedores, filtros.Pagina, filtros.PorPagina, 20), nil
+}
+
+// BuscarPerfil retorna o perfil de um fornecedor pelo documento já normalizado, sem os dados
+// cadastrais, que só existem no MongoDB. Retorna nil se o fornecedor não recebeu nenhum pagamento.
+func (r *FornecedorRepository) BuscarPerfil(ctx context.Context, cnpj string) (*domain.PerfilFornecedor, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var perfil *domain.PerfilFornecedor
+	porPolitico := make(map[primitive.ObjectID]*domain.PagamentoPorPolitico)
+	porTipo := make(map[string]*domain.TotalPorTipo)
+	porMes := make(map[[2]int]*domain.TotalMensal)
+
+	for _, d := range r.banco.despesas {
+		if d.CNPJFornecedor != cnpj {
+			continue
+		}
+
+		// Nome e tipo do documento vêm do pagamento mais recente
+		if perfil == nil {
+			perfil = &domain.PerfilFornecedor{CNPJ: cnpj, PrimeiroPagamento: d.Data, UltimoPagamento: d.Data}
+		}
+		if !d.Data.Before(perfil.UltimoPagamento) {
+			perfil.UltimoPagamento = d.Data
+			perfil.Nome = d.Fornecedor
+			perfil.TipoDocumento = d.TipoDocumentoFornecedor
+		}
+		if d.Data.Before(perfil.PrimeiroPagamento) {
+			perfil.PrimeiroPagamento = d.Data
+		}
+		perfil.Total += d.Valor
+		perfil.Quantidade++
+
+		p, ok := porPolitico[d.PoliticoID]
+		if !ok {
+			p = &domain.PagamentoPorPolitico{PoliticoID: d.PoliticoID}
+			if politico := r.banco.politicoPorID(d.PoliticoID); politico != nil {
+				p.Nome = politico.Nome
+				p.Partido = politico.Partido.Sigla
+				p.Estado = politico.CargoAtual.Estado
+			}
+			porPolitico[d.PoliticoID] = p
+		}
+		p.Total += d.Valor
+		p.Quantidade++
+
+		t, ok := porTipo[d.Tipo]
+		if !ok {
+			t = &domain.TotalPorTipo{Tipo: d.Tipo}
+			porTipo[d.Tipo] = t
+		}
+		t.Total += d.Valor
+		t.Quantidade++
+
+		chave := [2]int{d.AnoReferencia, d.MesReferencia}
+		m, ok := porMes[chave]
+		if !ok {
+			m = &domain.TotalMensal{Ano: d.AnoReferencia, Mes: d.MesReferencia}
+			porMes[chave] = m
+		}
+		m.Total += d.Valor
+		m.Quantidade++
+	}
+	if perfil == nil {
+		return nil, nil
+	}
+
+	for _, p := range porPolitico {
+		perfil.Politicos = append(perfil.Politicos, *p)
+	}
+	sort.Slice(perfil.Politicos, func(i, j int) bool {
+		return perfil.Politicos[i].Total > perfil.Politicos[j].Total
+	})
+
+	for _, t := range porTipo {
+		perfil.PorTipo = append(perfil.PorTipo, *t)
+	}
+	sort.Slice(perfil.PorTipo, func(i, j int) bool {
+		return perfil.PorTipo[i].Total > perfil.PorTipo[j].Total
+	})
+
+	for _, m := range porMes {
+		perfil.SerieMensal = append(perfil.SerieMensal, *m)
+	}
+	sort.Slice(perfil.SerieMensal, func(i, j int) bool {
+		if perfil.SerieMensal[i].Ano != perfil.SerieMensal[j].Ano {
+			return perfil.SerieMensal[i].Ano < perfil.SerieMensal[j].Ano
+		}
+		return perfil.SerieMensal[i].Mes < perfil.SerieMensal[j].Mes
+	})
+
+	return perfil, nil
+}
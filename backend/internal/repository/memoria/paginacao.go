@@ -0,0 +1,152 @@
+package memoria
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// listarPaginado ordena os itens e recorta a página pedida, por número ou por cursor, como o
+// listarPaginado das implementações com MongoDB: mesma ordenação (terminando no _id), mesmo
+// formato de cursor e mesma detecção da última página
+func listarPaginado[T any](itens []T, ordenacao bson.D, paginacao domain.Paginacao, porPaginaPadrao int, chave func(T) bson.D) (*domain.PaginatedResponse[T], error) {
+	sort.SliceStable(itens, func(i, j int) bool {
+		c, _ := compararChaves(chave(itens[i]), chave(itens[j]), ordenacao)
+		return c < 0
+	})
+
+	if !paginacao.PorCursor {
+		return paginar(itens, paginacao.Pagina, paginacao.PorPagina, porPaginaPadrao), nil
+	}
+
+	porPagina := paginacao.PorPagina
+	if porPagina < 1 || porPagina > 100 {
+		porPagina = porPaginaPadrao
+	}
+	resposta := &domain.PaginatedResponse[T]{PorPagina: porPagina, PorCursor: true, SemTotal: !paginacao.ComTotal}
+	if paginacao.ComTotal {
+		resposta.Total = int64(len(itens))
+	}
+
+	if paginacao.Cursor != "" {
+		var posicao bson.D
+		if err := repository.DecodificarCursor(paginacao.Cursor, &posicao); err != nil {
+			return nil, err
+		}
+		if len(posicao) != len(ordenacao) {
+			return nil, domain.ErrCursorInvalido
+		}
+		for i, campo := range ordenacao {
+			if posicao[i].Key != campo.Key {
+				return nil, domain.ErrCursorInvalido
+			}
+		}
+
+		inicio := 0
+		for ; inicio < len(itens); inicio++ {
+			c, ok := compararChaves(chave(itens[inicio]), posicao, ordenacao)
+			if !ok {
+				return nil, domain.ErrCursorInvalido
+			}
+			if c > 0 {
+				break
+			}
+		}
+		itens = itens[inicio:]
+	}
+
+	if len(itens) > porPagina {
+		itens = itens[:porPagina]
+		resposta.ProximoCursor = repository.CodificarCursor(chave(itens[porPagina-1]))
+	}
+	resposta.Data = append([]T{}, itens...)
+
+	return resposta, nil
+}
+
+// compararChaves compara as chaves de ordenação de dois itens na direção de cada campo;
+// retorna false se os valores não forem comparáveis
+func compararChaves(a, b, ordenacao bson.D) (int, bool) {
+	for i, campo := range ordenacao {
+		c, ok := comparar(a[i].Value, b[i].Value)
+		if !ok {
+			return 0, false
+		}
+		if campo.Value == -1 {
+			c = -c
+		}
+		if c != 0 {
+			return c, true
+		}
+	}
+	return 0, true
+}
+
+// comparar compara dois valores como o MongoDB: datas com a precisão de milissegundos do BSON,
+// números pelo valor, e textos e ObjectIDs byte a byte
+func comparar(a, b interface{}) (int, bool) {
+	x, y := normalizar(a), normalizar(b)
+
+	switch x := x.(type) {
+	case int64:
+		y, ok := y.(int64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case float64:
+		y, ok := y.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := y.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+	return 0, false
+}
+
+// normalizar converte os valores de uma chave, do item ou lidos do cursor, em int64, float64
+// ou string
+func normalizar(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UnixMilli()
+	case primitive.DateTime:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return v
+	case string:
+		return v
+	case primitive.ObjectID:
+		return v.Hex()
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package memoria
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PartidoRepository struct {
+	banco *Banco
+}
+
+var _ repository.Partidos = (*PartidoRepository)(nil)
+
+func NewPartidoRepository(banco *Banco) *PartidoRepository {
+	return &PartidoRepository{banco: banco}
+}
+
+// Listar retorna os partidos em atividade, ou seja, que não foram incorporados por outro
+func (r *PartidoRepository) Listar(ctx context.Context) ([]domain.RegistroPartido, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var ativos []domain.RegistroPartido
+	for _, p := range r.banco.partidos {
+		if p.IncorporadoPor == "" {
+			ativos = append(ativos, p)
+		}
+	}
+	sort.Slice(ativos, func(i, j int) bool { return ativos[i].Sigla < ativos[j].Sigla })
+	return ativos, nil
+}
+
+// BuscarPorSigla busca o partido pela sigla atual ou por uma sigla anterior, sem diferenciar
+// maiúsculas, como a collation do MongoDB
+func (r *PartidoRepository) BuscarPorSigla(ctx context.Context, sigla string) (*domain.RegistroPartido, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	for _, p := range r.banco.partidos {
+		if strings.EqualFold(p.Sigla, sigla) {
+			partido := p
+			return &partido, nil
+		}
+	}
+	for _, p := range r.banco.partidos {
+		for _, anterior := range p.SiglasAnteriores {
+			if strings.EqualFold(anterior, sigla) {
+				partido := p
+				return &partido, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Perfil agrega os membros do partido e a atuação deles. O ano, quando informado,
+// restringe votações, despesas e proposições.
+func (r *PartidoRepository) Perfil(ctx context.Context, partido domain.RegistroPartido, ano *int) (*domain.PerfilPartido, error) {
+	perfil := &domain.PerfilPartido{
+		Partido:          partido,
+		MembrosPorCargo:  make(map[domain.Cargo]int),
+		MembrosPorEstado: make(map[string]int),
+		MembrosPorGenero: make(map[domain.Genero]int),
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	membros := make(map[primitive.ObjectID]bool)
+	for _, p := range r.banco.politicos {
+		if p.Partido.Sigla != partido.Sigla {
+			continue
+		}
+		membros[p.ID] = true
+		perfil.MembrosPorCargo[p.CargoAtual.Tipo]++
+		if p.CargoAtual.Estado != "" {
+			perfil.MembrosPorEstado[p.CargoAtual.Estado]++
+		}
+		if p.Genero != "" {
+			perfil.MembrosPorGenero[p.Genero]++
+		}
+	}
+	perfil.TotalMembros = len(membros)
+	if len(membros) == 0 {
+		return perfil, nil
+	}
+
+	// Média, entre os membros, do percentual de sessões em que estiveram presentes
+	sessoes := make(map[primitive.ObjectID][2]int)
+	for _, p := range r.banco.presencas {
+		if !membros[p.PoliticoID] || ano != nil && p.Data.UTC().Year() != *ano {
+			continue
+		}
+		s := sessoes[p.PoliticoID]
+		s[0]++
+		if p.Presente {
+			s[1]++
+		}
+		sessoes[p.PoliticoID] = s
+	}
+	if len(sessoes) > 0 {
+		var soma float64
+		for _, s := range sessoes {
+			soma += float64(s[1]) / float64(s[0])
+		}
+		perfil.PercentualPresenca = soma / float64(len(sessoes)) * 100
+	}
+
+	for _, d := range r.banco.despesas {
+		if membros[d.PoliticoID] && (ano == nil || d.AnoReferencia == *ano) {
+			perfil.TotalDespesas += d.Valor
+		}
+	}
+
+	for _, p := range r.banco.proposicoes {
+		if ano != nil && p.Ano != *ano {
+			continue
+		}
+		deMembro := membros[p.AutorID]
+		for _, id := range p.CoautoresIDs {
+			deMembro = deMembro || membros[id]
+		}
+		if !deMembro {
+			continue
+		}
+		perfil.TotalProposicoes++
+		if p.Situacao == domain.SituacaoAprovada {
+			perfil.ProposicoesAprovadas++
+		}
+	}
+
+	// Coesão: fração da bancada que votou com a maioria, nas votações com ao menos dois membros
+	votos := make(map[string]map[domain.TipoVoto]int)
+	for _, v := range r.banco.votacoes {
+		if !membros[v.PoliticoID] || !participou(v, ano) {
+			continue
+		}
+		if votos[v.VotacaoIDExterno] == nil {
+			votos[v.VotacaoIDExterno] = make(map[domain.TipoVoto]int)
+		}
+		votos[v.VotacaoIDExterno][v.Voto]++
+	}
+	var soma float64
+	for _, contagem := range votos {
+		total, maioria := 0, 0
+		for _, n := range contagem {
+			total += n
+			if n > maioria {
+				maioria = n
+			}
+		}
+		if total < 2 {
+			continue
+		}
+		soma += float64(maioria) / float64(total)
+		perfil.VotacoesAnalisadas++
+	}
+	if perfil.VotacoesAnalisadas > 0 {
+		perfil.Coesao = soma / float64(perfil.VotacoesAnalisadas) * 100
+	}
+
+	return perfil, nil
+}
+
+// Trocas lista as mudanças de partido iniciadas no período [inicio, fim), ignorando
+// as causadas por renomeação ou fusão de partidos
+func (r *PartidoRepository) Trocas(ctx context.Context, inicio, fim time.Time) ([]domain.TrocaPartido, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var trocas []domain.TrocaPartido
+	for _, p := range r.banco.politicos {
+		for i := 1; i < len(p.HistoricoPartidos); i++ {
+			filiacao := p.HistoricoPartidos[i]
+			if filiacao.PorFusao || filiacao.DataInicio.Before(inicio) || !filiacao.DataInicio.Before(fim) {
+				continue
+			}
+			trocas = append(trocas, domain.TrocaPartido{
+				PoliticoID: p.ID,
+				Nome:       p.Nome,
+				Cargo:      p.CargoAtual.Tipo,
+				Estado:     p.CargoAtual.Estado,
+				De:         p.HistoricoPartidos[i-1].Partido,
+				Para:       filiacao.Partido,
+				Data:       filiacao.DataInicio,
+			})
+		}
+	}
+
+	sort.SliceStable(trocas, func(i, j int) bool {
+		if !trocas[i].Data.Equal(trocas[j].Data) {
+			return trocas[i].Data.After(trocas[j].Data)
+		}
+		return trocas[i].Nome < trocas[j].Nome
+	})
+	return trocas, nil
+}
@@ -0,0 +1,182 @@
+package memoria
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PoliticoRepository struct {
+	banco *Banco
+}
+
+var _ repository.Politicos = (*PoliticoRepository)(nil)
+
+func NewPoliticoRepository(banco *Banco) *PoliticoRepository {
+	return &PoliticoRepository{banco: banco}
+}
+
+// algumDe indica se o valor está entre os aceitos; sem valores aceitos, o filtro não se aplica
+func algumDe[T comparable](aceitos []T, valor T) bool {
+	if len(aceitos) == 0 {
+		return true
+	}
+	for _, a := range aceitos {
+		if a == valor {
+			return true
+		}
+	}
+	return false
+}
+
+// casaNome substitui a busca textual do MongoDB: procura o termo no nome e no nome civil
+func casaNome(p domain.Politico, termo string) bool {
+	termo = strings.ToLower(termo)
+	return strings.Contains(strings.ToLower(p.Nome), termo) ||
+		strings.Contains(strings.ToLower(p.NomeCivil), termo)
+}
+
+// atende aplica os filtros da listagem, exceto paginação e ordenação
+func atende(p domain.Politico, filtros domain.FiltrosPoliticos) bool {
+	if filtros.Nome != "" && !casaNome(p, filtros.Nome) {
+		return false
+	}
+	if filtros.EmExercicio != nil && p.CargoAtual.EmExercicio != *filtros.EmExercicio {
+		return false
+	}
+	return algumDe(filtros.Partido, p.Partido.Sigla) &&
+		algumDe(filtros.Cargo, p.CargoAtual.Tipo) &&
+		algumDe(filtros.Esfera, p.CargoAtual.Esfera) &&
+		algumDe(filtros.Estado, p.CargoAtual.Estado) &&
+		algumDe(filtros.Municipio, p.CargoAtual.Municipio) &&
+		algumDe(filtros.Genero, p.Genero)
+}
+
+func (r *PoliticoRepository) Listar(ctx context.Context, filtros domain.FiltrosPoliticos) (*domain.PaginatedResponse[domain.Politico], error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var politicos []domain.Politico
+	for _, p := range r.banco.politicos {
+		if atende(p, filtros) {
+			politicos = append(politicos, p)
+		}
+	}
+
+	ordenacao, chave := repository.OrdenacaoPoliticos(filtros)
+	return listarPaginado(politicos, ordenacao, filtros.Paginacao, 12, chave)
+}
+
+func (r *PoliticoRepository) Exportar(ctx context.Context, filtros domain.FiltrosPoliticos, fn func(domain.Politico) error) error {
+	r.banco.mu.RLock()
+	var politicos []domain.Politico
+	for _, p := range r.banco.politicos {
+		if atende(p, filtros) {
+			politicos = append(politicos, p)
+		}
+	}
+	r.banco.mu.RUnlock()
+
+	return percorrer(politicos, func(p domain.Politico) primitive.ObjectID { return p.ID }, fn)
+}
+
+func (r *PoliticoRepository) BuscarPorID(ctx context.Context, id string) (*domain.Politico, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	for _, p := range r.banco.politicos {
+		if p.ID == objectID {
+			return &p, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *PoliticoRepository) BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Politico, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var politicos []domain.Politico
+	for _, p := range r.banco.politicos {
+		if contem(objectIDs, p.ID) {
+			politicos = append(politicos, p)
+		}
+	}
+	return politicos, nil
+}
+
+func (r *PoliticoRepository) Buscar(ctx context.Context, query string, limite int) ([]domain.Politico, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var politicos []domain.Politico
+	for _, p := range r.banco.politicos {
+		if len(politicos) >= limite {
+			break
+		}
+		if casaNome(p, query) {
+			politicos = append(politicos, p)
+		}
+	}
+	return politicos, nil
+}
+
+func (r *PoliticoRepository) Criar(ctx context.Context, politico *domain.Politico) error {
+	politico.CreatedAt = time.Now()
+	politico.UpdatedAt = time.Now()
+	if politico.ID.IsZero() {
+		politico.ID = primitive.NewObjectID()
+	}
+
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	for _, p := range r.banco.politicos {
+		if p.ID == politico.ID {
+			return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key error"}}}
+		}
+	}
+	r.banco.politicos = append(r.banco.politicos, *politico)
+	return nil
+}
+
+func (r *PoliticoRepository) Atualizar(ctx context.Context, politico *domain.Politico) error {
+	politico.UpdatedAt = time.Now()
+
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	for i, p := range r.banco.politicos {
+		if p.ID == politico.ID {
+			r.banco.politicos[i] = *politico
+			break
+		}
+	}
+	return nil
+}
+
+func (r *PoliticoRepository) Contar(ctx context.Context) (int64, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	return int64(len(r.banco.politicos)), nil
+}
@@ -0,0 +1,81 @@
+package memoria
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PresencaRepository struct {
+	banco *Banco
+}
+
+var _ repository.Presencas = (*PresencaRepository)(nil)
+
+func NewPresencaRepository(banco *Banco) *PresencaRepository {
+	return &PresencaRepository{banco: banco}
+}
+
+func (r *PresencaRepository) ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Presenca], error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var presencas []domain.Presenca
+	for _, p := range r.banco.presencas {
+		if p.PoliticoID != objectID {
+			continue
+		}
+		data := p.Data.UTC()
+		if ano != nil && (data.Year() != *ano || mes != nil && int(data.Month()) != *mes) {
+			continue
+		}
+		presencas = append(presencas, p)
+	}
+
+	ordenacao := bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(presencas, ordenacao, paginacao, 50, func(p domain.Presenca) bson.D {
+		return bson.D{{Key: "data", Value: p.Data}, {Key: "_id", Value: p.ID}}
+	})
+}
+
+func (r *PresencaRepository) CalcularPercentual(ctx context.Context, politicoID string) (float64, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var total, presentes int
+	for _, p := range r.banco.presencas {
+		if p.PoliticoID != objectID {
+			continue
+		}
+		total++
+		if p.Presente {
+			presentes++
+		}
+	}
+
+	if total == 0 {
+		return 0, nil
+	}
+	return float64(presentes) / float64(total) * 100, nil
+}
+
+func (r *PresencaRepository) Exportar(ctx context.Context, fn func(domain.Presenca) error) error {
+	r.banco.mu.RLock()
+	presencas := append([]domain.Presenca(nil), r.banco.presencas...)
+	r.banco.mu.RUnlock()
+
+	return percorrer(presencas, func(p domain.Presenca) primitive.ObjectID { return p.ID }, fn)
+}
@@ -0,0 +1,154 @@
+package memoria
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ProposicaoRepository struct {
+	banco *Banco
+}
+
+var _ repository.Proposicoes = (*ProposicaoRepository)(nil)
+
+func NewProposicaoRepository(banco *Banco) *ProposicaoRepository {
+	return &ProposicaoRepository{banco: banco}
+}
+
+// doAutor retorna as proposições de que o político é autor ou coautor; o chamador deve
+// segurar a trava de leitura
+func (r *ProposicaoRepository) doAutor(autorID primitive.ObjectID) []domain.Proposicao {
+	var proposicoes []domain.Proposicao
+	for _, p := range r.banco.proposicoes {
+		if p.AutorID == autorID || contem(p.CoautoresIDs, autorID) {
+			proposicoes = append(proposicoes, p)
+		}
+	}
+	return proposicoes
+}
+
+func (r *ProposicaoRepository) ListarPorAutor(ctx context.Context, autorID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.ProposicaoComAutor], error) {
+	objectID, err := primitive.ObjectIDFromHex(autorID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var proposicoes []domain.ProposicaoComAutor
+	for _, p := range r.doAutor(objectID) {
+		proposicoes = append(proposicoes, domain.ProposicaoComAutor{Proposicao: p})
+	}
+
+	// Como no MongoDB, o número é comparado como texto
+	ordenacao := bson.D{{Key: "ano", Value: -1}, {Key: "numero", Value: -1}, {Key: "_id", Value: -1}}
+	result, err := listarPaginado(proposicoes, ordenacao, paginacao, 20, func(p domain.ProposicaoComAutor) bson.D {
+		return bson.D{{Key: "ano", Value: p.Ano}, {Key: "numero", Value: p.Numero}, {Key: "_id", Value: p.ID}}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if incluir.Autor {
+		for i := range result.Data {
+			result.Data[i].Autor = r.banco.politicoPorID(result.Data[i].AutorID)
+		}
+	}
+	return result, nil
+}
+
+func (r *ProposicaoRepository) ContarPorAutor(ctx context.Context, autorID string) (total, aprovadas int64, err error) {
+	objectID, err := primitive.ObjectIDFromHex(autorID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	for _, p := range r.doAutor(objectID) {
+		total++
+		if p.Situacao == domain.SituacaoAprovada {
+			aprovadas++
+		}
+	}
+	return total, aprovadas, nil
+}
+
+func (r *ProposicaoRepository) Contar(ctx context.Context) (int64, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	return int64(len(r.banco.proposicoes)), nil
+}
+
+func (r *ProposicaoRepository) BuscarPorID(ctx context.Context, id string) (*domain.Proposicao, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	for _, p := range r.banco.proposicoes {
+		if p.ID == objectID {
+			return &p, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *ProposicaoRepository) BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Proposicao, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var proposicoes []domain.Proposicao
+	for _, p := range r.banco.proposicoes {
+		if contem(objectIDs, p.ID) {
+			proposicoes = append(proposicoes, p)
+		}
+	}
+	return proposicoes, nil
+}
+
+func (r *ProposicaoRepository) Exportar(ctx context.Context, filtros domain.FiltrosProposicoes, fn func(domain.Proposicao) error) error {
+	var autorID primitive.ObjectID
+	if filtros.AutorID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.AutorID)
+		if err != nil {
+			return err
+		}
+		autorID = objectID
+	}
+
+	r.banco.mu.RLock()
+	var proposicoes []domain.Proposicao
+	for _, p := range r.banco.proposicoes {
+		if !autorID.IsZero() && p.AutorID != autorID && !contem(p.CoautoresIDs, autorID) ||
+			filtros.Tipo != "" && p.Tipo != filtros.Tipo ||
+			filtros.Ano != nil && p.Ano != *filtros.Ano ||
+			filtros.Situacao != "" && p.Situacao != filtros.Situacao {
+			continue
+		}
+		proposicoes = append(proposicoes, p)
+	}
+	r.banco.mu.RUnlock()
+
+	return percorrer(proposicoes, func(p domain.Proposicao) primitive.ObjectID { return p.ID }, fn)
+}
@@ -0,0 +1,116 @@
+package memoria
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+type QualidadeRepository struct {
+	banco *Banco
+}
+
+var _ repository.Qualidade = (*QualidadeRepository)(nil)
+
+func NewQualidadeRepository(banco *Banco) *QualidadeRepository {
+	return &QualidadeRepository{banco: banco}
+}
+
+// UltimaExecucao retorna a execução do problema encontrado mais recentemente, ou "" se não houver
+func (r *QualidadeRepository) UltimaExecucao(ctx context.Context) (string, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var ultimo *domain.ProblemaQualidade
+	for i, p := range r.banco.problemas {
+		if ultimo == nil || p.VistoEm.After(ultimo.VistoEm) {
+			ultimo = &r.banco.problemas[i]
+		}
+	}
+	if ultimo == nil {
+		return "", nil
+	}
+	return ultimo.ExecucaoID, nil
+}
+
+// Relatorio conta os problemas da execução por regra e agrupa os limite registros com mais
+// problemas. Com regra, os piores registros consideram apenas essa regra.
+func (r *QualidadeRepository) Relatorio(ctx context.Context, execucaoID string, regra domain.RegraQualidade, limite int) (*domain.RelatorioQualidade, error) {
+	relatorio := &domain.RelatorioQualidade{
+		ExecucaoID: execucaoID,
+		PorRegra:   make(map[domain.RegraQualidade]int),
+		Piores:     []domain.OfensorQualidade{},
+		GeradoEm:   time.Now(),
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	porRegistro := make(map[string]*domain.OfensorQualidade)
+	regras := make(map[string]map[domain.RegraQualidade]bool)
+	var registros []string
+	for _, p := range r.banco.problemas {
+		if p.ExecucaoID != execucaoID {
+			continue
+		}
+		relatorio.PorRegra[p.Regra]++
+		relatorio.Total++
+
+		if regra != "" && p.Regra != regra {
+			continue
+		}
+		ofensor, ok := porRegistro[p.Registro]
+		if !ok {
+			ofensor = &domain.OfensorQualidade{Registro: p.Registro, PoliticoID: p.PoliticoID, Nome: p.Nome, Fonte: p.Fonte}
+			porRegistro[p.Registro] = ofensor
+			regras[p.Registro] = make(map[domain.RegraQualidade]bool)
+			registros = append(registros, p.Registro)
+		}
+		ofensor.Problemas++
+		regras[p.Registro][p.Regra] = true
+	}
+
+	sort.Slice(registros, func(i, j int) bool {
+		a, b := porRegistro[registros[i]], porRegistro[registros[j]]
+		if a.Problemas != b.Problemas {
+			return a.Problemas > b.Problemas
+		}
+		return a.Registro < b.Registro
+	})
+	if len(registros) > limite {
+		registros = registros[:limite]
+	}
+	for _, registro := range registros {
+		ofensor := porRegistro[registro]
+		for _, rg := range domain.RegrasQualidade {
+			if regras[registro][rg] {
+				ofensor.Regras = append(ofensor.Regras, rg)
+			}
+		}
+		relatorio.Piores = append(relatorio.Piores, *ofensor)
+	}
+
+	return relatorio, nil
+}
+
+// Listar retorna os problemas da execução, opcionalmente de uma regra ou de um registro
+func (r *QualidadeRepository) Listar(ctx context.Context, execucaoID string, regra domain.RegraQualidade, registro string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ProblemaQualidade], error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var problemas []domain.ProblemaQualidade
+	for _, p := range r.banco.problemas {
+		if p.ExecucaoID != execucaoID || regra != "" && p.Regra != regra || registro != "" && p.Registro != registro {
+			continue
+		}
+		problemas = append(problemas, p)
+	}
+
+	sort.SliceStable(problemas, func(i, j int) bool {
+		return problemas[i].CreatedAt.Before(problemas[j].CreatedAt)
+	})
+	return paginar(problemas, pagina, porPagina, 20), nil
+}
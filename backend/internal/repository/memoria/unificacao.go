@@ -0,0 +1,437 @@
+package memoria
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UnificacaoRepository struct {
+	banco *Banco
+}
+
+var _ repository.Unificacoes = (*UnificacaoRepository)(nil)
+
+func NewUnificacaoRepository(banco *Banco) *UnificacaoRepository {
+	return &UnificacaoRepository{banco: banco}
+}
+
+// campoIdentificador é um identificador copiado do duplicado para o principal quando o
+// principal não o tem, como os camposIdentificadores do repositório com MongoDB
+type campoIdentificador struct {
+	nome    string
+	vazio   func(p *domain.Politico) bool
+	copiar  func(de, para *domain.Politico)
+	remover func(p *domain.Politico)
+}
+
+var camposIdentificadores = []campoIdentificador{
+	{"cpf", func(p *domain.Politico) bool { return p.CPF == "" },
+		func(de, para *domain.Politico) { para.CPF = de.CPF }, func(p *domain.Politico) { p.CPF = "" }},
+	{"id_externo_camara", func(p *domain.Politico) bool { return p.IDExternoCamara == 0 },
+		func(de, para *domain.Politico) { para.IDExternoCamara = de.IDExternoCamara }, func(p *domain.Politico) { p.IDExternoCamara = 0 }},
+	{"id_externo_senado", func(p *domain.Politico) bool { return p.IDExternoSenado == "" },
+		func(de, para *domain.Politico) { para.IDExternoSenado = de.IDExternoSenado }, func(p *domain.Politico) { p.IDExternoSenado = "" }},
+	{"id_externo_tse", func(p *domain.Politico) bool { return p.IDExternoTSE == "" },
+		func(de, para *domain.Politico) { para.IDExternoTSE = de.IDExternoTSE }, func(p *domain.Politico) { p.IDExternoTSE = "" }},
+}
+
+// Unificar incorpora o político duplicado ao principal, como no repositório com MongoDB: as
+// referências passam para o principal, os registros repetidos são removidos e o duplicado sai
+func (r *UnificacaoRepository) Unificar(ctx context.Context, principalID, duplicadoID primitive.ObjectID, motivo string) (*domain.Unificacao, error) {
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	principal, duplicado := r.indicePolitico(principalID), r.indicePolitico(duplicadoID)
+	if principal < 0 || duplicado < 0 {
+		return nil, repository.ErrPoliticoNaoEncontrado
+	}
+
+	unificacao := domain.Unificacao{
+		ID:          primitive.NewObjectID(),
+		PrincipalID: principalID,
+		DuplicadoID: duplicadoID,
+		Duplicado:   r.banco.politicos[duplicado],
+		Motivo:      motivo,
+		Status:      domain.StatusUnificacaoAtiva,
+		CreatedAt:   time.Now(),
+	}
+	refs := &unificacao.Referencias
+
+	var err error
+	r.banco.votacoes, refs.Votacoes, refs.Repetidos.Votacoes, err = separar(r.banco.votacoes, principalID, duplicadoID,
+		func(v domain.Votacao) primitive.ObjectID { return v.PoliticoID },
+		func(v *domain.Votacao) { v.PoliticoID = principalID },
+		func(v domain.Votacao) primitive.ObjectID { return v.ID }, chaveVotacao)
+	if err != nil {
+		return nil, err
+	}
+	r.banco.despesas, refs.Despesas, refs.Repetidos.Despesas, err = separar(r.banco.despesas, principalID, duplicadoID,
+		func(d domain.Despesa) primitive.ObjectID { return d.PoliticoID },
+		func(d *domain.Despesa) { d.PoliticoID = principalID },
+		func(d domain.Despesa) primitive.ObjectID { return d.ID }, chaveDespesa)
+	if err != nil {
+		return nil, err
+	}
+	r.banco.presencas, refs.Presencas, refs.Repetidos.Presencas, err = separar(r.banco.presencas, principalID, duplicadoID,
+		func(p domain.Presenca) primitive.ObjectID { return p.PoliticoID },
+		func(p *domain.Presenca) { p.PoliticoID = principalID },
+		func(p domain.Presenca) primitive.ObjectID { return p.ID }, chavePresenca)
+	if err != nil {
+		return nil, err
+	}
+
+	refs.Alteracoes, refs.Alertas = []primitive.ObjectID{}, []primitive.ObjectID{}
+	for i := range r.banco.alteracoes {
+		if a := &r.banco.alteracoes[i]; a.PoliticoID == duplicadoID {
+			refs.Alteracoes = append(refs.Alteracoes, a.ID)
+			a.PoliticoID = principalID
+		}
+	}
+	for i := range r.banco.alertas {
+		if a := &r.banco.alertas[i]; a.PoliticoID == duplicadoID {
+			refs.Alertas = append(refs.Alertas, a.ID)
+			a.PoliticoID = principalID
+		}
+	}
+
+	refs.ProposicoesAutor, refs.ProposicoesCoautor, refs.CoautoriasCompartilhadas = []primitive.ObjectID{}, []primitive.ObjectID{}, []primitive.ObjectID{}
+	for i := range r.banco.proposicoes {
+		p := &r.banco.proposicoes[i]
+		if p.AutorID == duplicadoID {
+			refs.ProposicoesAutor = append(refs.ProposicoesAutor, p.ID)
+			p.AutorID = principalID
+		}
+		if !contem(p.CoautoresIDs, duplicadoID) {
+			continue
+		}
+		refs.ProposicoesCoautor = append(refs.ProposicoesCoautor, p.ID)
+		if contem(p.CoautoresIDs, principalID) {
+			refs.CoautoriasCompartilhadas = append(refs.CoautoriasCompartilhadas, p.ID)
+		}
+		p.CoautoresIDs = trocarCoautor(p.CoautoresIDs, duplicadoID, principalID)
+	}
+
+	p, d := &r.banco.politicos[principal], &unificacao.Duplicado
+	for _, campo := range camposIdentificadores {
+		if !campo.vazio(d) && campo.vazio(p) {
+			campo.copiar(d, p)
+			refs.CamposCopiados = append(refs.CamposCopiados, campo.nome)
+		}
+	}
+	refs.CargosAdicionados = cargosNovos(p, d)
+	p.HistoricoCargos = append(p.HistoricoCargos, refs.CargosAdicionados...)
+	p.UpdatedAt = time.Now()
+
+	r.banco.politicos = append(r.banco.politicos[:duplicado], r.banco.politicos[duplicado+1:]...)
+
+	// Revisões de identidade que envolviam o duplicado deixam de estar pendentes
+	for i := range r.banco.revisoes {
+		revisao := &r.banco.revisoes[i]
+		if revisao.Status == domain.StatusRevisaoPendente && contem(revisao.CandidatosIDs, duplicadoID) {
+			revisao.Status = domain.StatusRevisaoResolvida
+			revisao.UpdatedAt = time.Now()
+		}
+	}
+
+	r.banco.unificacoes = append(r.banco.unificacoes, unificacao)
+	return &unificacao, nil
+}
+
+// Desfazer restaura o cadastro duplicado, as referências movidas e os registros repetidos.
+// Só a unificação mais recente de cada cadastro pode ser desfeita.
+func (r *UnificacaoRepository) Desfazer(ctx context.Context, id primitive.ObjectID) (*domain.Unificacao, error) {
+	r.banco.mu.Lock()
+	defer r.banco.mu.Unlock()
+
+	indice := -1
+	for i, u := range r.banco.unificacoes {
+		if u.ID == id {
+			indice = i
+		}
+	}
+	if indice < 0 {
+		return nil, repository.ErrUnificacaoNaoEncontrada
+	}
+	unificacao := &r.banco.unificacoes[indice]
+	if unificacao.Status == domain.StatusUnificacaoDesfeita {
+		return nil, repository.ErrUnificacaoDesfeita
+	}
+
+	envolvidos := []primitive.ObjectID{unificacao.PrincipalID, unificacao.DuplicadoID}
+	for _, u := range r.banco.unificacoes {
+		if u.ID != id && u.Status == domain.StatusUnificacaoAtiva && !u.CreatedAt.Before(unificacao.CreatedAt) &&
+			(contem(envolvidos, u.PrincipalID) || contem(envolvidos, u.DuplicadoID)) {
+			return nil, repository.ErrUnificacaoDependente
+		}
+	}
+
+	refs := unificacao.Referencias
+	principalID, duplicadoID := unificacao.PrincipalID, unificacao.DuplicadoID
+
+	// Decodifica os repetidos antes de alterar o banco, para não parar no meio
+	var votacoes []domain.Votacao
+	var despesas []domain.Despesa
+	var presencas []domain.Presenca
+	if err := decodificar(refs.Repetidos.Votacoes, &votacoes); err != nil {
+		return nil, err
+	}
+	if err := decodificar(refs.Repetidos.Despesas, &despesas); err != nil {
+		return nil, err
+	}
+	if err := decodificar(refs.Repetidos.Presencas, &presencas); err != nil {
+		return nil, err
+	}
+
+	if principal := r.indicePolitico(principalID); principal >= 0 {
+		p := &r.banco.politicos[principal]
+		for _, campo := range camposIdentificadores {
+			for _, copiado := range refs.CamposCopiados {
+				if campo.nome == copiado {
+					campo.remover(p)
+				}
+			}
+		}
+		var cargos []domain.CargoAtual
+		for _, c := range p.HistoricoCargos {
+			adicionado := false
+			for _, a := range refs.CargosAdicionados {
+				adicionado = adicionado || mesmoCargo(c, a)
+			}
+			if !adicionado {
+				cargos = append(cargos, c)
+			}
+		}
+		p.HistoricoCargos = cargos
+		p.UpdatedAt = time.Now()
+	}
+	if r.indicePolitico(duplicadoID) < 0 {
+		r.banco.politicos = append(r.banco.politicos, unificacao.Duplicado)
+	}
+
+	for i := range r.banco.votacoes {
+		if v := &r.banco.votacoes[i]; v.PoliticoID == principalID && contem(refs.Votacoes, v.ID) {
+			v.PoliticoID = duplicadoID
+		}
+	}
+	for i := range r.banco.despesas {
+		if d := &r.banco.despesas[i]; d.PoliticoID == principalID && contem(refs.Despesas, d.ID) {
+			d.PoliticoID = duplicadoID
+		}
+	}
+	for i := range r.banco.presencas {
+		if p := &r.banco.presencas[i]; p.PoliticoID == principalID && contem(refs.Presencas, p.ID) {
+			p.PoliticoID = duplicadoID
+		}
+	}
+	for i := range r.banco.alteracoes {
+		if a := &r.banco.alteracoes[i]; a.PoliticoID == principalID && contem(refs.Alteracoes, a.ID) {
+			a.PoliticoID = duplicadoID
+		}
+	}
+	for i := range r.banco.alertas {
+		if a := &r.banco.alertas[i]; a.PoliticoID == principalID && contem(refs.Alertas, a.ID) {
+			a.PoliticoID = duplicadoID
+		}
+	}
+	for i := range r.banco.proposicoes {
+		p := &r.banco.proposicoes[i]
+		if p.AutorID == principalID && contem(refs.ProposicoesAutor, p.ID) {
+			p.AutorID = duplicadoID
+		}
+		if !contem(refs.ProposicoesCoautor, p.ID) {
+			continue
+		}
+		// Nas coautorias compartilhadas, os dois continuam coautores
+		if contem(refs.CoautoriasCompartilhadas, p.ID) {
+			if !contem(p.CoautoresIDs, duplicadoID) {
+				p.CoautoresIDs = append(p.CoautoresIDs, duplicadoID)
+			}
+			continue
+		}
+		p.CoautoresIDs = trocarCoautor(p.CoautoresIDs, principalID, duplicadoID)
+	}
+
+	r.banco.votacoes = restaurar(r.banco.votacoes, votacoes, func(v domain.Votacao) primitive.ObjectID { return v.ID })
+	r.banco.despesas = restaurar(r.banco.despesas, despesas, func(d domain.Despesa) primitive.ObjectID { return d.ID })
+	r.banco.presencas = restaurar(r.banco.presencas, presencas, func(p domain.Presenca) primitive.ObjectID { return p.ID })
+
+	agora := time.Now()
+	unificacao.Status = domain.StatusUnificacaoDesfeita
+	unificacao.DesfeitaEm = &agora
+
+	resultado := *unificacao
+	return &resultado, nil
+}
+
+func (r *UnificacaoRepository) Listar(ctx context.Context, status domain.StatusUnificacao, pagina, porPagina int) (*domain.PaginatedResponse[domain.Unificacao], error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var unificacoes []domain.Unificacao
+	for _, u := range r.banco.unificacoes {
+		if status == "" || u.Status == status {
+			unificacoes = append(unificacoes, u)
+		}
+	}
+
+	ordenacao := bson.D{{Key: "created_at", Value: -1}}
+	return listarPaginado(unificacoes, ordenacao, domain.Paginacao{Pagina: pagina, PorPagina: porPagina}, 20, func(u domain.Unificacao) bson.D {
+		return bson.D{{Key: "created_at", Value: u.CreatedAt}}
+	})
+}
+
+// ListarRevisoes lista a fila de identidades ambíguas encontradas pelas sincronizações
+func (r *UnificacaoRepository) ListarRevisoes(ctx context.Context, status domain.StatusRevisao, pagina, porPagina int) (*domain.PaginatedResponse[domain.RevisaoIdentidade], error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var revisoes []domain.RevisaoIdentidade
+	for _, rv := range r.banco.revisoes {
+		if status == "" || rv.Status == status {
+			revisoes = append(revisoes, rv)
+		}
+	}
+
+	ordenacao := bson.D{{Key: "created_at", Value: -1}}
+	return listarPaginado(revisoes, ordenacao, domain.Paginacao{Pagina: pagina, PorPagina: porPagina}, 20, func(rv domain.RevisaoIdentidade) bson.D {
+		return bson.D{{Key: "created_at", Value: rv.CreatedAt}}
+	})
+}
+
+// indicePolitico retorna a posição do político, ou -1; o chamador deve segurar a trava
+func (r *UnificacaoRepository) indicePolitico(id primitive.ObjectID) int {
+	for i, p := range r.banco.politicos {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Chaves dos registros da fonte, como as do repositório com MongoDB; vazias quando o
+// documento não tem os campos que identificam o registro
+func chaveVotacao(v domain.Votacao) string { return v.VotacaoIDExterno }
+
+func chaveDespesa(d domain.Despesa) string {
+	if d.CodDocumento == 0 {
+		return ""
+	}
+	return fmt.Sprint(d.CodDocumento, d.NumDocumento, d.CodLote, d.Parcela)
+}
+
+func chavePresenca(p domain.Presenca) string {
+	return fmt.Sprint(p.Data.UnixMilli(), p.TipoSessao)
+}
+
+// separar passa os registros do duplicado para o principal e remove os que repetem um registro
+// que o principal já tem. Retorna a coleção resultante, os IDs movidos e os repetidos como
+// documentos, no formato guardado na unificação.
+func separar[T any](itens []T, principalID, duplicadoID primitive.ObjectID, politico func(T) primitive.ObjectID, mover func(*T), id func(T) primitive.ObjectID, chave func(T) string) ([]T, []primitive.ObjectID, []primitive.M, error) {
+	doPrincipal := make(map[string]bool)
+	for _, item := range itens {
+		if c := chave(item); c != "" && politico(item) == principalID {
+			doPrincipal[c] = true
+		}
+	}
+
+	movidos := []primitive.ObjectID{}
+	var repetidos []primitive.M
+	resultado := itens[:0]
+	for _, item := range itens {
+		if politico(item) == duplicadoID {
+			if c := chave(item); c != "" && doPrincipal[c] {
+				doc, err := documento(item)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				repetidos = append(repetidos, doc)
+				continue
+			}
+			mover(&item)
+			movidos = append(movidos, id(item))
+		}
+		resultado = append(resultado, item)
+	}
+	return resultado, movidos, repetidos, nil
+}
+
+// restaurar devolve à coleção os registros repetidos que ainda não estão nela
+func restaurar[T any](itens, repetidos []T, id func(T) primitive.ObjectID) []T {
+	for _, r := range repetidos {
+		existe := false
+		for _, item := range itens {
+			existe = existe || id(item) == id(r)
+		}
+		if !existe {
+			itens = append(itens, r)
+		}
+	}
+	return itens
+}
+
+func documento(v interface{}) (primitive.M, error) {
+	dados, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc primitive.M
+	return doc, bson.Unmarshal(dados, &doc)
+}
+
+func decodificar[T any](docs []primitive.M, destino *[]T) error {
+	for _, doc := range docs {
+		dados, err := bson.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		var item T
+		if err := bson.Unmarshal(dados, &item); err != nil {
+			return err
+		}
+		*destino = append(*destino, item)
+	}
+	return nil
+}
+
+// trocarCoautor substitui "de" por "para" na lista de coautores, sem repetir "para"
+func trocarCoautor(coautores []primitive.ObjectID, de, para primitive.ObjectID) []primitive.ObjectID {
+	resultado := make([]primitive.ObjectID, 0, len(coautores))
+	for _, id := range coautores {
+		if id != de && id != para {
+			resultado = append(resultado, id)
+		}
+	}
+	return append(resultado, para)
+}
+
+// cargosNovos retorna o cargo atual e o histórico do duplicado que ainda não estão no principal
+func cargosNovos(principal, duplicado *domain.Politico) []domain.CargoAtual {
+	existentes := append([]domain.CargoAtual{principal.CargoAtual}, principal.HistoricoCargos...)
+	candidatos := append([]domain.CargoAtual{duplicado.CargoAtual}, duplicado.HistoricoCargos...)
+
+	var novos []domain.CargoAtual
+	for _, c := range candidatos {
+		if c.Tipo == "" {
+			continue
+		}
+		repetido := false
+		for _, e := range append(existentes, novos...) {
+			repetido = repetido || e.Tipo == c.Tipo && e.Estado == c.Estado && e.DataInicio.Equal(c.DataInicio)
+		}
+		if !repetido {
+			novos = append(novos, c)
+		}
+	}
+	return novos
+}
+
+func mesmoCargo(a, b domain.CargoAtual) bool {
+	return a.Tipo == b.Tipo && a.Esfera == b.Esfera && a.Estado == b.Estado && a.Municipio == b.Municipio &&
+		a.DataInicio.Equal(b.DataInicio) && a.DataFim.Equal(b.DataFim) && a.EmExercicio == b.EmExercicio
+}
@@ -0,0 +1,186 @@
+package memoria
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type VotacaoRepository struct {
+	banco *Banco
+}
+
+var _ repository.Votacoes = (*VotacaoRepository)(nil)
+
+func NewVotacaoRepository(banco *Banco) *VotacaoRepository {
+	return &VotacaoRepository{banco: banco}
+}
+
+// doPolitico retorna os votos do político; o chamador deve segurar a trava de leitura
+func (r *VotacaoRepository) doPolitico(politicoID primitive.ObjectID) []domain.Votacao {
+	var votos []domain.Votacao
+	for _, v := range r.banco.votacoes {
+		if v.PoliticoID == politicoID {
+			votos = append(votos, v)
+		}
+	}
+	return votos
+}
+
+func (r *VotacaoRepository) ListarPorPolitico(ctx context.Context, politicoID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.VotacaoComProposicao], error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	var votos []domain.VotacaoComProposicao
+	for _, v := range r.doPolitico(objectID) {
+		votos = append(votos, domain.VotacaoComProposicao{Votacao: v})
+	}
+
+	ordenacao := bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}
+	result, err := listarPaginado(votos, ordenacao, paginacao, 20, func(v domain.VotacaoComProposicao) bson.D {
+		return bson.D{{Key: "data", Value: v.Data}, {Key: "_id", Value: v.ID}}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Como os $lookup, só nos itens da página
+	if incluir.Proposicao || incluir.Autor {
+		for i := range result.Data {
+			proposicao := r.banco.proposicaoPorID(result.Data[i].ProposicaoID)
+			if proposicao != nil && incluir.Autor {
+				proposicao.Autor = r.banco.politicoPorID(proposicao.AutorID)
+			}
+			result.Data[i].Proposicao = proposicao
+		}
+	}
+	return result, nil
+}
+
+func (r *VotacaoRepository) ContarPorPolitico(ctx context.Context, politicoID string) (map[domain.TipoVoto]int, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	result := make(map[domain.TipoVoto]int)
+	for _, v := range r.doPolitico(objectID) {
+		result[v.Voto]++
+	}
+	return result, nil
+}
+
+func (r *VotacaoRepository) Contar(ctx context.Context) (int64, error) {
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	return int64(len(r.banco.votacoes)), nil
+}
+
+// participou equivale ao filtroParticipacao do repositório com MongoDB: votos não ausentes,
+// com ID externo da votação e, se informado, do ano
+func participou(v domain.Votacao, ano *int) bool {
+	if v.Voto == domain.VotoAusente || v.VotacaoIDExterno == "" {
+		return false
+	}
+	return ano == nil || v.Data.UTC().Year() == *ano
+}
+
+// VotosPorPolitico retorna o voto do político em cada votação de que participou, indexado pelo ID externo da votação
+func (r *VotacaoRepository) VotosPorPolitico(ctx context.Context, politicoID string, ano *int) (map[string]domain.TipoVoto, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	votos := make(map[string]domain.TipoVoto)
+	for _, v := range r.doPolitico(objectID) {
+		if participou(v, ano) {
+			votos[v.VotacaoIDExterno] = v.Voto
+		}
+	}
+	return votos, nil
+}
+
+// Concordancia conta, para cada outro político, as votações de que ambos participaram e em
+// quantas delas o voto foi o mesmo
+func (r *VotacaoRepository) Concordancia(ctx context.Context, politicoID string, ano *int) ([]domain.AlinhamentoPolitico, error) {
+	votos, err := r.VotosPorPolitico(ctx, politicoID, ano)
+	if err != nil {
+		return nil, err
+	}
+	objectID, _ := primitive.ObjectIDFromHex(politicoID)
+
+	r.banco.mu.RLock()
+	defer r.banco.mu.RUnlock()
+
+	porPolitico := make(map[primitive.ObjectID]*domain.AlinhamentoPolitico)
+	var ordem []primitive.ObjectID
+	for _, v := range r.banco.votacoes {
+		voto, ok := votos[v.VotacaoIDExterno]
+		if v.PoliticoID == objectID || !ok || v.Voto == domain.VotoAusente {
+			continue
+		}
+
+		a, ok := porPolitico[v.PoliticoID]
+		if !ok {
+			a = &domain.AlinhamentoPolitico{PoliticoID: v.PoliticoID}
+			porPolitico[v.PoliticoID] = a
+			ordem = append(ordem, v.PoliticoID)
+		}
+		a.VotacoesEmComum++
+		if v.Voto == voto {
+			a.Concordancias++
+		}
+	}
+
+	resultado := make([]domain.AlinhamentoPolitico, 0, len(ordem))
+	for _, id := range ordem {
+		resultado = append(resultado, *porPolitico[id])
+	}
+	return resultado, nil
+}
+
+func (r *VotacaoRepository) Exportar(ctx context.Context, filtros domain.FiltrosVotacoes, fn func(domain.Votacao) error) error {
+	var politicoID primitive.ObjectID
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return err
+		}
+		politicoID = objectID
+	}
+
+	r.banco.mu.RLock()
+	var politicos map[primitive.ObjectID]bool
+	if filtros.Partido != "" || filtros.Estado != "" {
+		politicos = r.banco.politicosDe(filtros.Partido, filtros.Estado)
+	}
+	var votos []domain.Votacao
+	for _, v := range r.banco.votacoes {
+		if !politicoID.IsZero() && v.PoliticoID != politicoID ||
+			politicos != nil && !politicos[v.PoliticoID] ||
+			filtros.Ano != nil && v.Data.UTC().Year() != *filtros.Ano ||
+			filtros.Voto != "" && v.Voto != filtros.Voto {
+			continue
+		}
+		votos = append(votos, v)
+	}
+	r.banco.mu.RUnlock()
+
+	return percorrer(votos, func(v domain.Votacao) primitive.ObjectID { return v.ID }, fn)
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CodificarCursor gera o cursor opaco de uma posição: os campos de ordenação do último item
+// entregue, na ordem da ordenação e terminando no _id. Os valores vão em BSON, para que datas e
+// ObjectIDs voltem com o mesmo tipo.
+func CodificarCursor(posicao bson.D) string {
+	conteudo, err := bson.Marshal(posicao)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(conteudo)
+}
+
+// DecodificarCursor lê a posição de um cursor gerado por CodificarCursor em posicao (um
+// bson.D ou uma struct com as tags bson dos campos)
+func DecodificarCursor(cursor string, posicao interface{}) error {
+	conteudo, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return domain.ErrCursorInvalido
+	}
+	if err := bson.Unmarshal(conteudo, posicao); err != nil {
+		return domain.ErrCursorInvalido
+	}
+	return nil
+}
+
+// depoisDe monta o filtro dos itens que vêm depois da posição na ordenação: os com o primeiro
+// campo depois do da posição, ou com o primeiro igual e o segundo depois, e assim por diante
+func depoisDe(ordenacao, posicao bson.D) (bson.M, error) {
+	if len(posicao) != len(ordenacao) {
+		return nil, domain.ErrCursorInvalido
+	}
+
+	alternativas := bson.A{}
+	for i, campo := range ordenacao {
+		if posicao[i].Key != campo.Key {
+			return nil, domain.ErrCursorInvalido
+		}
+		// Só valores simples: um documento na igualdade seria lido como operador
+		switch posicao[i].Value.(type) {
+		case bson.D, bson.A:
+			return nil, domain.ErrCursorInvalido
+		}
+
+		operador := "$gt"
+		if campo.Value == -1 {
+			operador = "$lt"
+		}
+		alternativa := bson.M{campo.Key: bson.M{operador: posicao[i].Value}}
+		for _, anterior := range posicao[:i] {
+			alternativa[anterior.Key] = anterior.Value
+		}
+		alternativas = append(alternativas, alternativa)
+	}
+	return bson.M{"$or": alternativas}, nil
+}
+
+// buscar retorna os itens do filtro na ordenação, pulando salto e até limite (0 é sem limite).
+// Com estágios em juntar (os $lookup dos documentos relacionados), a busca vira uma agregação
+// que junta só os itens já recortados. Com projetar (ver projecao), só esses campos vêm do
+// banco; na agregação, a projeção é o último estágio, depois das junções que dependem dos
+// outros campos.
+func buscar[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, ordenacao bson.D, salto, limite int64, juntar mongo.Pipeline, projetar bson.D) ([]T, error) {
+	var cursor *mongo.Cursor
+	var err error
+	if len(juntar) == 0 {
+		opts := options.Find().SetSort(ordenacao).SetSkip(salto).SetLimit(limite)
+		if projetar != nil {
+			opts.SetProjection(projetar)
+		}
+		cursor, err = collection.Find(ctx, filter, opts)
+	} else {
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$sort", Value: ordenacao}},
+		}
+		if salto > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$skip", Value: salto}})
+		}
+		if limite > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limite}})
+		}
+		pipeline = append(pipeline, juntar...)
+		if projetar != nil {
+			pipeline = append(pipeline, bson.D{{Key: "$project", Value: projetar}})
+		}
+		cursor, err = collection.Aggregate(ctx, pipeline)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	data := []T{}
+	if err := cursor.All(ctx, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// juntarUm monta os estágios que embutem em como o documento de from cujo _id é o valor de
+// local; sem documento correspondente, o campo fica ausente
+func juntarUm(from, local, como string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: from},
+			{Key: "localField", Value: local},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: como},
+		}}},
+		{{Key: "$unwind", Value: bson.D{
+			{Key: "path", Value: "$" + como},
+			{Key: "preserveNullAndEmptyArrays", Value: true},
+		}}},
+	}
+}
+
+// listarPaginado busca a página pedida, por número ou por cursor. A ordenação deve terminar
+// no _id, para que a posição de cada item seja única, e chave retorna os valores dos campos
+// da ordenação de um item, que viram o cursor da próxima página. Os estágios de juntar
+// embutem documentos relacionados em cada item, e paginacao.Campos limita os campos trazidos
+// do banco (veja buscar).
+func listarPaginado[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, ordenacao bson.D, paginacao domain.Paginacao, porPaginaPadrao int, juntar mongo.Pipeline, chave func(T) bson.D) (*domain.PaginatedResponse[T], error) {
+	porPagina := paginacao.PorPagina
+	if porPagina < 1 || porPagina > 100 {
+		porPagina = porPaginaPadrao
+	}
+
+	projetar := projecao[T](paginacao.Campos, ordenacao)
+	if !paginacao.PorCursor {
+		return paginarJuntando[T](ctx, collection, filter, ordenacao, paginacao.Pagina, porPagina, juntar, projetar)
+	}
+
+	resposta := &domain.PaginatedResponse[T]{PorPagina: porPagina, PorCursor: true, SemTotal: !paginacao.ComTotal}
+	if paginacao.ComTotal {
+		total, err := collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		resposta.Total = total
+	}
+
+	busca := filter
+	if paginacao.Cursor != "" {
+		var posicao bson.D
+		if err := DecodificarCursor(paginacao.Cursor, &posicao); err != nil {
+			return nil, err
+		}
+		depois, err := depoisDe(ordenacao, posicao)
+		if err != nil {
+			return nil, err
+		}
+		// O $and preserva um $or que o próprio filtro já tenha
+		busca = bson.M{"$and": bson.A{filter, depois}}
+	}
+
+	// Um item a mais diz se há próxima página, sem contar
+	data, err := buscar[T](ctx, collection, busca, ordenacao, 0, int64(porPagina+1), juntar, projetar)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > porPagina {
+		data = data[:porPagina]
+		resposta.ProximoCursor = CodificarCursor(chave(data[porPagina-1]))
+	}
+	resposta.Data = data
+
+	return resposta, nil
+}
+
+// paginar executa uma busca paginada simples ordenada por sort
+func paginar[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, sort bson.D, pagina, porPagina int) (*domain.PaginatedResponse[T], error) {
+	return paginarJuntando[T](ctx, collection, filter, sort, pagina, porPagina, nil, nil)
+}
+
+// paginarJuntando é o paginar com os estágios de junção e a projeção de buscar
+func paginarJuntando[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, sort bson.D, pagina, porPagina int, juntar mongo.Pipeline, projetar bson.D) (*domain.PaginatedResponse[T], error) {
+	if pagina < 1 {
+		pagina = 1
+	}
+	if porPagina < 1 || porPagina > 100 {
+		porPagina = 20
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := buscar[T](ctx, collection, filter, sort, int64((pagina-1)*porPagina), int64(porPagina), juntar, projetar)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPaginas := int(total) / porPagina
+	if int(total)%porPagina > 0 {
+		totalPaginas++
+	}
+
+	return &domain.PaginatedResponse[T]{
+		Data:         data,
+		Total:        total,
+		Pagina:       pagina,
+		PorPagina:    porPagina,
+		TotalPaginas: totalPaginas,
+	}, nil
+}
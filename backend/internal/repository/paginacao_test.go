@@ -0,0 +1,210 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// porCursor percorre todas as páginas seguindo o nextCursor e retorna os IDs em ordem
+func porCursor[T any](t *testing.T, listar func(domain.Paginacao) (*domain.PaginatedResponse[T], error), id func(T) primitive.ObjectID) []string {
+	t.Helper()
+
+	resultado := []string{}
+	paginacao := domain.Paginacao{PorCursor: true, PorPagina: 7}
+	for paginas := 0; ; paginas++ {
+		if paginas > 1000 {
+			t.Fatal("o cursor não chega ao fim")
+		}
+		pagina, err := listar(paginacao)
+		if err != nil {
+			t.Fatalf("página por cursor: %v", err)
+		}
+		for _, item := range pagina.Data {
+			resultado = append(resultado, id(item).Hex())
+		}
+		if pagina.ProximoCursor == "" {
+			return resultado
+		}
+		paginacao.Cursor = pagina.ProximoCursor
+	}
+}
+
+// porNumero lista tudo página a página, por número, para comparar com o percurso por cursor
+func porNumero[T any](t *testing.T, listar func(domain.Paginacao) (*domain.PaginatedResponse[T], error), id func(T) primitive.ObjectID) []string {
+	t.Helper()
+
+	resultado := []string{}
+	for pagina := 1; ; pagina++ {
+		resposta, err := listar(domain.Paginacao{Pagina: pagina, PorPagina: 100})
+		if err != nil {
+			t.Fatalf("página %d: %v", pagina, err)
+		}
+		for _, item := range resposta.Data {
+			resultado = append(resultado, id(item).Hex())
+		}
+		if pagina >= resposta.TotalPaginas {
+			return resultado
+		}
+	}
+}
+
+// A paginação por cursor deve entregar os mesmos itens, na mesma ordem, que a por número, e
+// igual com MongoDB e em memória
+func TestPaginacaoPorCursor(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 7, Politicos: 30, VotacoesPorCasa: 40})
+	ctx := context.Background()
+
+	db := mongomemtest.Banco(t)
+	inserir(t, db.Collection("politicos"), dados.Politicos)
+	inserir(t, db.Collection("votacoes"), dados.Votacoes)
+	inserir(t, db.Collection("despesas"), dados.Despesas)
+	inserir(t, db.Collection("proposicoes"), dados.Proposicoes)
+	inserir(t, db.Collection("presencas"), dados.Presencas)
+
+	banco := memoria.NewBanco(dados)
+	politicos := []repository.Politicos{repository.NewPoliticoRepository(db), memoria.NewPoliticoRepository(banco)}
+	votacoes := []repository.Votacoes{repository.NewVotacaoRepository(db), memoria.NewVotacaoRepository(banco)}
+	despesas := []repository.Despesas{repository.NewDespesaRepository(db), memoria.NewDespesaRepository(banco)}
+	proposicoes := []repository.Proposicoes{repository.NewProposicaoRepository(db), memoria.NewProposicaoRepository(banco)}
+	presencas := []repository.Presencas{repository.NewPresencaRepository(db), memoria.NewPresencaRepository(banco)}
+
+	// O político com mais votos, e o ano da sua despesa mais recente
+	votos := map[primitive.ObjectID]int{}
+	var maisVotos primitive.ObjectID
+	for _, v := range dados.Votacoes {
+		votos[v.PoliticoID]++
+		if votos[v.PoliticoID] > votos[maisVotos] {
+			maisVotos = v.PoliticoID
+		}
+	}
+	politicoID := maisVotos.Hex()
+	ano := 0
+	for _, d := range dados.Despesas {
+		if d.PoliticoID == maisVotos && d.AnoReferencia > ano {
+			ano = d.AnoReferencia
+		}
+	}
+
+	casos := []struct {
+		nome string
+		ids  func(i int, cursor bool) []string
+	}{
+		{"políticos por partido", func(i int, cursor bool) []string {
+			listar := func(p domain.Paginacao) (*domain.PaginatedResponse[domain.Politico], error) {
+				return politicos[i].Listar(ctx, domain.FiltrosPoliticos{OrdenarPor: "partido", Ordem: "desc", Paginacao: p})
+			}
+			id := func(p domain.Politico) primitive.ObjectID { return p.ID }
+			if cursor {
+				return porCursor(t, listar, id)
+			}
+			return porNumero(t, listar, id)
+		}},
+		{"votações", func(i int, cursor bool) []string {
+			listar := func(p domain.Paginacao) (*domain.PaginatedResponse[domain.VotacaoComProposicao], error) {
+				return votacoes[i].ListarPorPolitico(ctx, politicoID, p, domain.Inclusoes{})
+			}
+			id := func(v domain.VotacaoComProposicao) primitive.ObjectID { return v.ID }
+			if cursor {
+				return porCursor(t, listar, id)
+			}
+			return porNumero(t, listar, id)
+		}},
+		{"despesas do ano", func(i int, cursor bool) []string {
+			listar := func(p domain.Paginacao) (*domain.PaginatedResponse[domain.Despesa], error) {
+				return despesas[i].ListarPorPolitico(ctx, politicoID, &ano, nil, p)
+			}
+			id := func(d domain.Despesa) primitive.ObjectID { return d.ID }
+			if cursor {
+				return porCursor(t, listar, id)
+			}
+			return porNumero(t, listar, id)
+		}},
+		{"proposições", func(i int, cursor bool) []string {
+			listar := func(p domain.Paginacao) (*domain.PaginatedResponse[domain.ProposicaoComAutor], error) {
+				return proposicoes[i].ListarPorAutor(ctx, politicoID, p, domain.Inclusoes{})
+			}
+			id := func(p domain.ProposicaoComAutor) primitive.ObjectID { return p.ID }
+			if cursor {
+				return porCursor(t, listar, id)
+			}
+			return porNumero(t, listar, id)
+		}},
+		{"presenças", func(i int, cursor bool) []string {
+			listar := func(p domain.Paginacao) (*domain.PaginatedResponse[domain.Presenca], error) {
+				return presencas[i].ListarPorPolitico(ctx, politicoID, nil, nil, p)
+			}
+			id := func(p domain.Presenca) primitive.ObjectID { return p.ID }
+			if cursor {
+				return porCursor(t, listar, id)
+			}
+			return porNumero(t, listar, id)
+		}},
+	}
+
+	for _, caso := range casos {
+		referencia := caso.ids(0, false)
+		if len(referencia) <= 7 {
+			t.Errorf("%s: só %d itens; o caso não passa da primeira página", caso.nome, len(referencia))
+		}
+		for i, origem := range []string{"MongoDB", "memória"} {
+			if ids := caso.ids(i, true); !reflect.DeepEqual(ids, referencia) {
+				t.Errorf("%s: por cursor (%s) entregou %d itens e por número %d, ou em outra ordem", caso.nome, origem, len(ids), len(referencia))
+			}
+			if i == 1 && !reflect.DeepEqual(caso.ids(1, false), referencia) {
+				t.Errorf("%s: por número, a memória diverge do MongoDB", caso.nome)
+			}
+		}
+	}
+
+	// Alertas só existem depois da análise: um por despesa do político, com valores repetidos
+	var alertas []domain.Alerta
+	for _, d := range dados.Despesas {
+		if d.PoliticoID == maisVotos {
+			alertas = append(alertas, domain.Alerta{
+				ID: primitive.NewObjectID(), PoliticoID: d.PoliticoID, Tipo: domain.AlertaPicoHistorico,
+				Valor: float64(int(d.Valor) % 3), MesReferencia: d.MesReferencia, AnoReferencia: d.AnoReferencia,
+			})
+		}
+	}
+	inserir(t, db.Collection("alertas"), alertas)
+	listarAlertas := func(p domain.Paginacao) (*domain.PaginatedResponse[domain.Alerta], error) {
+		return repository.NewAlertaRepository(db).Listar(ctx, domain.FiltrosAlertas{PoliticoID: politicoID, Paginacao: p})
+	}
+	idAlerta := func(a domain.Alerta) primitive.ObjectID { return a.ID }
+	if ids, referencia := porCursor(t, listarAlertas, idAlerta), porNumero(t, listarAlertas, idAlerta); len(referencia) <= 7 || !reflect.DeepEqual(ids, referencia) {
+		t.Errorf("alertas: por cursor entregou %d itens e por número %d, ou em outra ordem", len(ids), len(referencia))
+	}
+
+	// Cursores adulterados ou de outra listagem são recusados
+	deOutraListagem := repository.CodificarCursor(bson.D{{Key: "ano", Value: 2024}, {Key: "_id", Value: maisVotos}})
+	for _, cursor := range []string{"nao-e-base64!", deOutraListagem} {
+		for i := range votacoes {
+			_, err := votacoes[i].ListarPorPolitico(ctx, politicoID, domain.Paginacao{PorCursor: true, Cursor: cursor}, domain.Inclusoes{})
+			if !errors.Is(err, domain.ErrCursorInvalido) {
+				t.Errorf("cursor %q: esperado ErrCursorInvalido, veio %v", cursor, err)
+			}
+		}
+	}
+
+	// Sem ?total=true, a resposta por cursor não tem total nem número de página
+	pagina, err := votacoes[0].ListarPorPolitico(ctx, politicoID, domain.Paginacao{PorCursor: true}, domain.Inclusoes{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conteudo, _ := json.Marshal(pagina)
+	if strings.Contains(string(conteudo), `"total"`) || strings.Contains(string(conteudo), `"pagina"`) || !strings.Contains(string(conteudo), `"nextCursor"`) {
+		t.Errorf("resposta por cursor inesperada: %.200s", conteudo)
+	}
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PartidoRepository lê o cadastro de partidos e agrega os dados dos seus membros
+type PartidoRepository struct {
+	partidos    *mongo.Collection
+	politicos   *mongo.Collection
+	votacoes    *mongo.Collection
+	presencas   *mongo.Collection
+	despesas    *mongo.Collection
+	proposicoes *mongo.Collection
+}
+
+func NewPartidoRepository(db *mongo.Database) *PartidoRepository {
+	return &PartidoRepository{
+		partidos:    db.Collection("partidos"),
+		politicos:   db.Collection("politicos"),
+		votacoes:    db.Collection("votacoes"),
+		presencas:   db.Collection("presencas"),
+		despesas:    db.Collection("despesas"),
+		proposicoes: db.Collection("proposicoes"),
+	}
+}
+
+// Listar retorna os partidos em atividade, ou seja, que não foram incorporados por outro
+func (r *PartidoRepository) Listar(ctx context.Context) ([]domain.RegistroPartido, error) {
+	filter := bson.M{"incorporado_por": bson.M{"$in": bson.A{nil, ""}}}
+	opts := options.Find().SetSort(bson.D{{Key: "sigla", Value: 1}})
+
+	cursor, err := r.partidos.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var partidos []domain.RegistroPartido
+	if err := cursor.All(ctx, &partidos); err != nil {
+		return nil, err
+	}
+
+	return partidos, nil
+}
+
+// BuscarPorSigla busca o partido pela sigla atual ou por uma sigla anterior (ex.: PMDB → MDB)
+func (r *PartidoRepository) BuscarPorSigla(ctx context.Context, sigla string) (*domain.RegistroPartido, error) {
+	collation := options.Collation{Locale: "pt", Strength: 1}
+
+	var partido domain.RegistroPartido
+	err := r.partidos.FindOne(ctx, bson.M{"sigla": sigla}, options.FindOne().SetCollation(&collation)).Decode(&partido)
+	if err == mongo.ErrNoDocuments {
+		err = r.partidos.FindOne(ctx, bson.M{"siglas_anteriores": sigla}, options.FindOne().SetCollation(&collation)).Decode(&partido)
+	}
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &partido, nil
+}
+
+// Perfil agrega os membros do partido e a atuação deles. O ano, quando informado,
+// restringe votações, despesas e proposições.
+func (r *PartidoRepository) Perfil(ctx context.Context, partido domain.RegistroPartido, ano *int) (*domain.PerfilPartido, error) {
+	perfil := &domain.PerfilPartido{
+		Partido:          partido,
+		MembrosPorCargo:  make(map[domain.Cargo]int),
+		MembrosPorEstado: make(map[string]int),
+		MembrosPorGenero: make(map[domain.Genero]int),
+	}
+
+	ids, err := r.membros(ctx, partido.Sigla, perfil)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return perfil, nil
+	}
+
+	if perfil.PercentualPresenca, err = r.presencaMedia(ctx, ids, ano); err != nil {
+		return nil, err
+	}
+	if perfil.TotalDespesas, err = r.totalDespesas(ctx, ids, ano); err != nil {
+		return nil, err
+	}
+	if err := r.contarProposicoes(ctx, ids, ano, perfil); err != nil {
+		return nil, err
+	}
+	if err := r.coesao(ctx, ids, ano, perfil); err != nil {
+		return nil, err
+	}
+
+	return perfil, nil
+}
+
+// membros preenche as contagens por cargo, estado e gênero e retorna os IDs dos membros
+func (r *PartidoRepository) membros(ctx context.Context, sigla string, perfil *domain.PerfilPartido) ([]primitive.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{
+		"_id":                1,
+		"genero":             1,
+		"cargo_atual.tipo":   1,
+		"cargo_atual.estado": 1,
+	})
+
+	cursor, err := r.politicos.Find(ctx, bson.M{"partido.sigla": sigla}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var p domain.Politico
+		if err := cursor.Decode(&p); err != nil {
+			continue
+		}
+		ids = append(ids, p.ID)
+		perfil.MembrosPorCargo[p.CargoAtual.Tipo]++
+		if p.CargoAtual.Estado != "" {
+			perfil.MembrosPorEstado[p.CargoAtual.Estado]++
+		}
+		if p.Genero != "" {
+			perfil.MembrosPorGenero[p.Genero]++
+		}
+	}
+	perfil.TotalMembros = len(ids)
+
+	return ids, cursor.Err()
+}
+
+func filtroAnoData(filter bson.M, ano *int) bson.M {
+	if ano != nil {
+		filter["data"] = bson.M{
+			"$gte": time.Date(*ano, 1, 1, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(*ano+1, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	return filter
+}
+
+// presencaMedia calcula a média, entre os membros, do percentual de sessões em que estiveram
+// presentes, como o percentual de presença de cada político
+func (r *PartidoRepository) presencaMedia(ctx context.Context, ids []primitive.ObjectID, ano *int) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filtroAnoData(bson.M{"politico_id": bson.M{"$in": ids}}, ano)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$politico_id",
+			"total":     bson.M{"$sum": 1},
+			"presentes": bson.M{"$sum": bson.M{"$cond": bson.A{"$presente", 1, 0}}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"media": bson.M{"$avg": bson.M{"$divide": bson.A{"$presentes", "$total"}}},
+		}}},
+	}
+
+	var result []struct {
+		Media float64 `bson:"media"`
+	}
+	if err := r.aggregate(ctx, r.presencas, pipeline, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+
+	return result[0].Media * 100, nil
+}
+
+func (r *PartidoRepository) totalDespesas(ctx context.Context, ids []primitive.ObjectID, ano *int) (float64, error) {
+	match := bson.M{"politico_id": bson.M{"$in": ids}}
+	if ano != nil {
+		match["ano_referencia"] = *ano
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": "$valor"},
+		}}},
+	}
+
+	var result []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := r.aggregate(ctx, r.despesas, pipeline, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+
+	return result[0].Total, nil
+}
+
+// contarProposicoes conta as proposições de autoria ou coautoria de algum membro
+func (r *PartidoRepository) contarProposicoes(ctx context.Context, ids []primitive.ObjectID, ano *int, perfil *domain.PerfilPartido) error {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"autor_id": bson.M{"$in": ids}},
+			{"coautores_ids": bson.M{"$in": ids}},
+		},
+	}
+	if ano != nil {
+		filter["ano"] = *ano
+	}
+
+	total, err := r.proposicoes.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	filter["situacao"] = domain.SituacaoAprovada
+	aprovadas, err := r.proposicoes.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	perfil.TotalProposicoes = int(total)
+	perfil.ProposicoesAprovadas = int(aprovadas)
+	return nil
+}
+
+// coesao calcula, para cada votação com ao menos dois membros presentes, a fração da bancada
+// que votou com a maioria, e retorna a média dessas frações
+func (r *PartidoRepository) coesao(ctx context.Context, ids []primitive.ObjectID, ano *int, perfil *domain.PerfilPartido) error {
+	match := filtroParticipacao(ano)
+	match["politico_id"] = bson.M{"$in": ids}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"votacao": "$votacao_id_externo", "voto": "$voto"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$_id.votacao",
+			"total":   bson.M{"$sum": "$count"},
+			"maioria": bson.M{"$max": "$count"},
+		}}},
+		{{Key: "$match", Value: bson.M{"total": bson.M{"$gte": 2}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      nil,
+			"media":    bson.M{"$avg": bson.M{"$divide": bson.A{"$maioria", "$total"}}},
+			"votacoes": bson.M{"$sum": 1},
+		}}},
+	}
+
+	var result []struct {
+		Media    float64 `bson:"media"`
+		Votacoes int     `bson:"votacoes"`
+	}
+	if err := r.aggregate(ctx, r.votacoes, pipeline, &result, options.Aggregate().SetAllowDiskUse(true)); err != nil {
+		return err
+	}
+	if len(result) > 0 {
+		perfil.Coesao = result[0].Media * 100
+		perfil.VotacoesAnalisadas = result[0].Votacoes
+	}
+
+	return nil
+}
+
+func (r *PartidoRepository) aggregate(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, result interface{}, opts ...*options.AggregateOptions) error {
+	cursor, err := collection.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, result)
+}
+
+// Trocas lista as mudanças de partido iniciadas no período [inicio, fim), ignorando
+// as causadas por renomeação ou fusão de partidos
+func (r *PartidoRepository) Trocas(ctx context.Context, inicio, fim time.Time) ([]domain.TrocaPartido, error) {
+	periodo := bson.M{"$gte": inicio, "$lt": fim}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"historico_partidos.data_inicio": periodo}}},
+		{{Key: "$addFields", Value: bson.M{"filiacao": "$historico_partidos"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$filiacao", "includeArrayIndex": "indice"}}},
+		{{Key: "$match", Value: bson.M{
+			"indice":               bson.M{"$gt": 0},
+			"filiacao.data_inicio": periodo,
+			"filiacao.por_fusao":   bson.M{"$ne": true},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"politico_id": "$_id",
+			"nome":        1,
+			"cargo":       "$cargo_atual.tipo",
+			"estado":      "$cargo_atual.estado",
+			"de": bson.M{"$arrayElemAt": bson.A{
+				"$historico_partidos.partido",
+				bson.M{"$subtract": bson.A{"$indice", 1}},
+			}},
+			"para": "$filiacao.partido",
+			"data": "$filiacao.data_inicio",
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "data", Value: -1}, {Key: "nome", Value: 1}}}},
+	}
+
+	var trocas []domain.TrocaPartido
+	if err := r.aggregate(ctx, r.politicos, pipeline, &trocas); err != nil {
+		return nil, err
+	}
+
+	return trocas, nil
+}
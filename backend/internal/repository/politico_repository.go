@@ -22,9 +22,38 @@ func NewPoliticoRepository(db *mongo.Database) *PoliticoRepository {
 }
 
 func (r *PoliticoRepository) Listar(ctx context.Context, filtros domain.FiltrosPoliticos) (*domain.PaginatedResponse[domain.Politico], error) {
+	ordenacao, chave := OrdenacaoPoliticos(filtros)
+	return listarPaginado(ctx, r.collection, filtroPoliticos(filtros), ordenacao, filtros.Paginacao, 12, nil, chave)
+}
+
+// OrdenacaoPoliticos retorna a ordenação da listagem de políticos, terminando no _id, e os
+// valores dela em cada político, que formam o cursor. Presença, proposições e gastos não ficam
+// no documento do político: ordenar por eles mantém a ordem de inserção.
+func OrdenacaoPoliticos(filtros domain.FiltrosPoliticos) (bson.D, func(domain.Politico) bson.D) {
+	ordem := 1
+	if filtros.OrdenarPor != "" && filtros.Ordem == "desc" {
+		ordem = -1
+	}
+
+	switch filtros.OrdenarPor {
+	case "partido":
+		return bson.D{{Key: "partido.sigla", Value: ordem}, {Key: "_id", Value: ordem}}, func(p domain.Politico) bson.D {
+			return bson.D{{Key: "partido.sigla", Value: p.Partido.Sigla}, {Key: "_id", Value: p.ID}}
+		}
+	case "presenca", "proposicoes", "gastos":
+		return bson.D{{Key: "_id", Value: 1}}, func(p domain.Politico) bson.D {
+			return bson.D{{Key: "_id", Value: p.ID}}
+		}
+	}
+	return bson.D{{Key: "nome", Value: ordem}, {Key: "_id", Value: ordem}}, func(p domain.Politico) bson.D {
+		return bson.D{{Key: "nome", Value: p.Nome}, {Key: "_id", Value: p.ID}}
+	}
+}
+
+// filtroPoliticos monta o filtro do MongoDB a partir dos filtros da listagem
+func filtroPoliticos(filtros domain.FiltrosPoliticos) bson.M {
 	filter := bson.M{}
 
-	// Aplicar filtros
 	if filtros.Nome != "" {
 		filter["$text"] = bson.M{"$search": filtros.Nome}
 	}
@@ -57,77 +86,17 @@ func (r *PoliticoRepository) Listar(ctx context.Context, filtros domain.FiltrosP
 		filter["genero"] = bson.M{"$in": filtros.Genero}
 	}
 
-	// Configurar paginação
-	pagina := filtros.Pagina
-	if pagina < 1 {
-		pagina = 1
-	}
-
-	porPagina := filtros.PorPagina
-	if porPagina < 1 || porPagina > 100 {
-		porPagina = 12
-	}
-
-	skip := int64((pagina - 1) * porPagina)
-	limit := int64(porPagina)
-
-	// Configurar ordenação
-	sort := bson.D{{Key: "nome", Value: 1}}
-	if filtros.OrdenarPor != "" {
-		ordem := 1
-		if filtros.Ordem == "desc" {
-			ordem = -1
-		}
-
-		sortField := "nome"
-		switch filtros.OrdenarPor {
-		case "partido":
-			sortField = "partido.sigla"
-		case "presenca":
-			sortField = "presenca_percentual"
-		case "proposicoes":
-			sortField = "total_proposicoes"
-		case "gastos":
-			sortField = "gasto_mensal"
-		}
-		sort = bson.D{{Key: sortField, Value: ordem}}
-	}
-
-	// Contar total
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	// Buscar documentos
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(limit).
-		SetSort(sort)
+	return filter
+}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+// Exportar percorre todos os políticos que atendem aos filtros, em ordem de _id, ignorando a
+// paginação e a ordenação da listagem
+func (r *PoliticoRepository) Exportar(ctx context.Context, filtros domain.FiltrosPoliticos, fn func(domain.Politico) error) error {
+	cursor, err := r.collection.Find(ctx, filtroPoliticos(filtros), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
 	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var politicos []domain.Politico
-	if err := cursor.All(ctx, &politicos); err != nil {
-		return nil, err
-	}
-
-	totalPaginas := int(total) / porPagina
-	if int(total)%porPagina > 0 {
-		totalPaginas++
+		return err
 	}
-
-	return &domain.PaginatedResponse[domain.Politico]{
-		Data:         politicos,
-		Total:        total,
-		Pagina:       pagina,
-		PorPagina:    porPagina,
-		TotalPaginas: totalPaginas,
-	}, nil
+	return percorrer(ctx, cursor, fn)
 }
 
 func (r *PoliticoRepository) BuscarPorID(ctx context.Context, id string) (*domain.Politico, error) {
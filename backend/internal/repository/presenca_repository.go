@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/lupa-cidada/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,59 +21,33 @@ func NewPresencaRepository(db *mongo.Database) *PresencaRepository {
 	}
 }
 
-func (r *PresencaRepository) ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, pagina, porPagina int) (*domain.PaginatedResponse[domain.Presenca], error) {
+// ListarPorPolitico lista as presenças do político, das mais recentes para as mais antigas
+func (r *PresencaRepository) ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Presenca], error) {
 	objectID, err := primitive.ObjectIDFromHex(politicoID)
 	if err != nil {
 		return nil, err
 	}
 
 	filter := bson.M{"politico_id": objectID}
-
-	// TODO: Adicionar filtros de data quando necessário
-
-	if pagina < 1 {
-		pagina = 1
-	}
-	if porPagina < 1 || porPagina > 100 {
-		porPagina = 50
-	}
-
-	skip := int64((pagina - 1) * porPagina)
-	limit := int64(porPagina)
-
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(limit).
-		SetSort(bson.D{{Key: "data", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
+	if ano != nil {
+		filter["data"] = filtroPeriodo(*ano, mes)
 	}
-	defer cursor.Close(ctx)
 
-	var presencas []domain.Presenca
-	if err := cursor.All(ctx, &presencas); err != nil {
-		return nil, err
-	}
+	ordenacao := bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(ctx, r.collection, filter, ordenacao, paginacao, 50, nil, func(p domain.Presenca) bson.D {
+		return bson.D{{Key: "data", Value: p.Data}, {Key: "_id", Value: p.ID}}
+	})
+}
 
-	totalPaginas := int(total) / porPagina
-	if int(total)%porPagina > 0 {
-		totalPaginas++
+// filtroPeriodo restringe as datas ao ano informado, ou só ao mês se também houver mês
+func filtroPeriodo(ano int, mes *int) bson.M {
+	inicio := time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC)
+	fim := inicio.AddDate(1, 0, 0)
+	if mes != nil {
+		inicio = time.Date(ano, time.Month(*mes), 1, 0, 0, 0, 0, time.UTC)
+		fim = inicio.AddDate(0, 1, 0)
 	}
-
-	return &domain.PaginatedResponse[domain.Presenca]{
-		Data:         presencas,
-		Total:        total,
-		Pagina:       pagina,
-		PorPagina:    porPagina,
-		TotalPaginas: totalPaginas,
-	}, nil
+	return bson.M{"$gte": inicio, "$lt": fim}
 }
 
 func (r *PresencaRepository) CalcularPercentual(ctx context.Context, politicoID string) (float64, error) {
@@ -114,3 +89,12 @@ func (r *PresencaRepository) CalcularPercentual(ctx context.Context, politicoID
 	return float64(result.Presentes) / float64(result.Total) * 100, nil
 }
 
+// Exportar percorre todas as presenças, em ordem de _id
+func (r *PresencaRepository) Exportar(ctx context.Context, fn func(domain.Presenca) error) error {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	return percorrer(ctx, cursor, fn)
+}
+
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// projecao converte os campos pedidos em ?campos= (nomes do JSON, com ponto nos documentos
+// embutidos: "proposicao.ementa") na projeção do MongoDB sobre os campos BSON de T. O _id de T
+// e dos documentos embutidos sempre vem, como o "id" na resposta, e os campos da ordenação
+// também, porque formam o cursor. Campos desconhecidos são ignorados. Sem campos, retorna nil
+// (documento inteiro).
+func projecao[T any](campos []string, ordenacao bson.D) bson.D {
+	if len(campos) == 0 {
+		return nil
+	}
+
+	caminhos := map[string]bool{}
+	tipo := reflect.TypeOf((*T)(nil)).Elem()
+	for _, campo := range campos {
+		if caminho := caminhoBSON(tipo, strings.Split(strings.TrimSpace(campo), "."), "", caminhos); caminho != "" {
+			caminhos[caminho] = true
+		}
+	}
+	for _, campo := range ordenacao {
+		caminhos[campo.Key] = true
+	}
+
+	// Um caminho dentro de outro já projetado colidiria ("partido" e "partido.sigla")
+	ordenados := make([]string, 0, len(caminhos))
+	for caminho := range caminhos {
+		ordenados = append(ordenados, caminho)
+	}
+	sort.Strings(ordenados)
+
+	resultado := bson.D{}
+	for _, caminho := range ordenados {
+		if len(resultado) > 0 {
+			if anterior := resultado[len(resultado)-1].Key; strings.HasPrefix(caminho, anterior+".") {
+				continue
+			}
+		}
+		resultado = append(resultado, bson.E{Key: caminho, Value: 1})
+	}
+	return resultado
+}
+
+// caminhoBSON segue as partes do nome JSON pelos campos do tipo e retorna o caminho BSON
+// correspondente, ou "" se alguma parte não existe. Ao entrar em um documento embutido que tem
+// _id, marca também o _id dele em caminhos.
+func caminhoBSON(tipo reflect.Type, partes []string, prefixo string, caminhos map[string]bool) string {
+	tipo = elemento(tipo)
+	if tipo.Kind() != reflect.Struct || len(partes) == 0 || partes[0] == "" {
+		return ""
+	}
+
+	for i := 0; i < tipo.NumField(); i++ {
+		campo := tipo.Field(i)
+		nomeBSON, inline := nomeBSON(campo)
+		if inline {
+			if caminho := caminhoBSON(campo.Type, partes, prefixo, caminhos); caminho != "" {
+				return caminho
+			}
+			continue
+		}
+		if nomeJSON(campo) != partes[0] || nomeBSON == "-" {
+			continue
+		}
+
+		caminho := prefixo + nomeBSON
+		if len(partes) == 1 || !embutido(campo.Type) {
+			return caminho
+		}
+		if temID(campo.Type) {
+			caminhos[caminho+"._id"] = true
+		}
+		return caminhoBSON(campo.Type, partes[1:], caminho+".", caminhos)
+	}
+	return ""
+}
+
+// elemento tira ponteiros e listas do tipo
+func elemento(tipo reflect.Type) reflect.Type {
+	for tipo.Kind() == reflect.Ptr || tipo.Kind() == reflect.Slice {
+		tipo = tipo.Elem()
+	}
+	return tipo
+}
+
+// embutido indica se o tipo é um documento com campos próprios, e não um valor como datas e IDs
+func embutido(tipo reflect.Type) bool {
+	tipo = elemento(tipo)
+	return tipo.Kind() == reflect.Struct && tipo != reflect.TypeOf(time.Time{}) && tipo != reflect.TypeOf(primitive.ObjectID{})
+}
+
+func temID(tipo reflect.Type) bool {
+	tipo = elemento(tipo)
+	for i := 0; i < tipo.NumField(); i++ {
+		nome, inline := nomeBSON(tipo.Field(i))
+		if nome == "_id" || inline && temID(tipo.Field(i).Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// nomeBSON é o nome do campo no documento, como o driver o grava, e se o campo é inline
+func nomeBSON(campo reflect.StructField) (string, bool) {
+	tag := campo.Tag.Get("bson")
+	nome, opcoes, _ := strings.Cut(tag, ",")
+	if strings.Contains(opcoes, "inline") || campo.Anonymous && tag == "" {
+		return "", true
+	}
+	if nome == "" {
+		nome = strings.ToLower(campo.Name)
+	}
+	return nome, false
+}
+
+func nomeJSON(campo reflect.StructField) string {
+	nome, _, _ := strings.Cut(campo.Tag.Get("json"), ",")
+	if nome == "" {
+		return campo.Name
+	}
+	return nome
+}
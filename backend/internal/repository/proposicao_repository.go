@@ -20,7 +20,9 @@ func NewProposicaoRepository(db *mongo.Database) *ProposicaoRepository {
 	}
 }
 
-func (r *ProposicaoRepository) ListarPorAutor(ctx context.Context, autorID string, pagina, porPagina int) (*domain.PaginatedResponse[domain.Proposicao], error) {
+// ListarPorAutor lista as proposições de que o político é autor ou coautor, das mais recentes
+// para as mais antigas, com o autor embutido se pedido em incluir
+func (r *ProposicaoRepository) ListarPorAutor(ctx context.Context, autorID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.ProposicaoComAutor], error) {
 	objectID, err := primitive.ObjectIDFromHex(autorID)
 	if err != nil {
 		return nil, err
@@ -34,49 +36,15 @@ func (r *ProposicaoRepository) ListarPorAutor(ctx context.Context, autorID strin
 		},
 	}
 
-	if pagina < 1 {
-		pagina = 1
-	}
-	if porPagina < 1 || porPagina > 100 {
-		porPagina = 20
-	}
-
-	skip := int64((pagina - 1) * porPagina)
-	limit := int64(porPagina)
-
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
-
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(limit).
-		SetSort(bson.D{{Key: "ano", Value: -1}, {Key: "numero", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var proposicoes []domain.Proposicao
-	if err := cursor.All(ctx, &proposicoes); err != nil {
-		return nil, err
+	var juntar mongo.Pipeline
+	if incluir.Autor {
+		juntar = juntarUm("politicos", "autor_id", "autor")
 	}
 
-	totalPaginas := int(total) / porPagina
-	if int(total)%porPagina > 0 {
-		totalPaginas++
-	}
-
-	return &domain.PaginatedResponse[domain.Proposicao]{
-		Data:         proposicoes,
-		Total:        total,
-		Pagina:       pagina,
-		PorPagina:    porPagina,
-		TotalPaginas: totalPaginas,
-	}, nil
+	ordenacao := bson.D{{Key: "ano", Value: -1}, {Key: "numero", Value: -1}, {Key: "_id", Value: -1}}
+	return listarPaginado(ctx, r.collection, filter, ordenacao, paginacao, 20, juntar, func(p domain.ProposicaoComAutor) bson.D {
+		return bson.D{{Key: "ano", Value: p.Ano}, {Key: "numero", Value: p.Numero}, {Key: "_id", Value: p.ID}}
+	})
 }
 
 func (r *ProposicaoRepository) ContarPorAutor(ctx context.Context, autorID string) (total, aprovadas int64, err error) {
@@ -134,3 +102,59 @@ func (r *ProposicaoRepository) BuscarPorID(ctx context.Context, id string) (*dom
 	return &proposicao, nil
 }
 
+// BuscarPorIDs busca várias proposições de uma vez; IDs inválidos ou inexistentes são ignorados
+func (r *ProposicaoRepository) BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Proposicao, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var proposicoes []domain.Proposicao
+	if err := cursor.All(ctx, &proposicoes); err != nil {
+		return nil, err
+	}
+
+	return proposicoes, nil
+}
+
+// Exportar percorre todas as proposições que atendem aos filtros, em ordem de _id.
+// O filtro de autor inclui as proposições em que o político é coautor, como em ListarPorAutor.
+func (r *ProposicaoRepository) Exportar(ctx context.Context, filtros domain.FiltrosProposicoes, fn func(domain.Proposicao) error) error {
+	filter := bson.M{}
+
+	if filtros.AutorID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.AutorID)
+		if err != nil {
+			return err
+		}
+		filter["$or"] = []bson.M{
+			{"autor_id": objectID},
+			{"coautores_ids": objectID},
+		}
+	}
+	if filtros.Tipo != "" {
+		filter["tipo"] = filtros.Tipo
+	}
+	if filtros.Ano != nil {
+		filter["ano"] = *filtros.Ano
+	}
+	if filtros.Situacao != "" {
+		filter["situacao"] = filtros.Situacao
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	return percorrer(ctx, cursor, fn)
+}
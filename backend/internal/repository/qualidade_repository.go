@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// QualidadeRepository consulta os problemas de qualidade registrados pelas sincronizações
+type QualidadeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewQualidadeRepository(db *mongo.Database) *QualidadeRepository {
+	return &QualidadeRepository{
+		collection: db.Collection("problemas_qualidade"),
+	}
+}
+
+// UltimaExecucao retorna a execução do problema encontrado mais recentemente, ou "" se não houver
+func (r *QualidadeRepository) UltimaExecucao(ctx context.Context) (string, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "visto_em", Value: -1}}).
+		SetProjection(bson.M{"execucao_id": 1})
+
+	var problema domain.ProblemaQualidade
+	err := r.collection.FindOne(ctx, bson.M{}, opts).Decode(&problema)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return problema.ExecucaoID, nil
+}
+
+// Relatorio conta os problemas da execução por regra e agrupa os limite registros com mais
+// problemas. Com regra, os piores registros consideram apenas essa regra. Um problema
+// encontrado de novo por uma execução posterior passa a contar só para ela.
+func (r *QualidadeRepository) Relatorio(ctx context.Context, execucaoID string, regra domain.RegraQualidade, limite int) (*domain.RelatorioQualidade, error) {
+	relatorio := &domain.RelatorioQualidade{
+		ExecucaoID: execucaoID,
+		PorRegra:   make(map[domain.RegraQualidade]int),
+		Piores:     []domain.OfensorQualidade{},
+		GeradoEm:   time.Now(),
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"execucao_id": execucaoID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$regra", "total": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var contagens []struct {
+		Regra domain.RegraQualidade `bson:"_id"`
+		Total int                   `bson:"total"`
+	}
+	if err := cursor.All(ctx, &contagens); err != nil {
+		return nil, err
+	}
+	for _, c := range contagens {
+		relatorio.PorRegra[c.Regra] = c.Total
+		relatorio.Total += c.Total
+	}
+
+	match := bson.M{"execucao_id": execucaoID}
+	if regra != "" {
+		match["regra"] = regra
+	}
+	cursor, err = r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$registro",
+			"politico_id": bson.M{"$first": "$politico_id"},
+			"nome":        bson.M{"$first": "$nome"},
+			"fonte":       bson.M{"$first": "$fonte"},
+			"problemas":   bson.M{"$sum": 1},
+			"regras":      bson.M{"$addToSet": "$regra"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "problemas", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limite}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &relatorio.Piores); err != nil {
+		return nil, err
+	}
+	for i := range relatorio.Piores {
+		relatorio.Piores[i].Regras = ordenarRegras(relatorio.Piores[i].Regras)
+	}
+
+	return relatorio, nil
+}
+
+// Listar retorna os problemas da execução, opcionalmente de uma regra ou de um registro
+func (r *QualidadeRepository) Listar(ctx context.Context, execucaoID string, regra domain.RegraQualidade, registro string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ProblemaQualidade], error) {
+	filter := bson.M{"execucao_id": execucaoID}
+	if regra != "" {
+		filter["regra"] = regra
+	}
+	if registro != "" {
+		filter["registro"] = registro
+	}
+
+	return paginar[domain.ProblemaQualidade](ctx, r.collection, filter, bson.D{{Key: "created_at", Value: 1}}, pagina, porPagina)
+}
+
+// ordenarRegras devolve as regras na ordem de domain.RegrasQualidade
+func ordenarRegras(regras []domain.RegraQualidade) []domain.RegraQualidade {
+	presentes := make(map[domain.RegraQualidade]bool, len(regras))
+	for _, regra := range regras {
+		presentes[regra] = true
+	}
+
+	ordenadas := make([]domain.RegraQualidade, 0, len(regras))
+	for _, regra := range domain.RegrasQualidade {
+		if presentes[regra] {
+			ordenadas = append(ordenadas, regra)
+		}
+	}
+	return ordenadas
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Interfaces dos repositórios usados pelos serviços. As implementações com MongoDB ficam neste
+// pacote; as em memória, usadas no modo debug e nos testes, em repository/memoria.
+
+// Politicos é o repositório de políticos
+type Politicos interface {
+	Listar(ctx context.Context, filtros domain.FiltrosPoliticos) (*domain.PaginatedResponse[domain.Politico], error)
+	BuscarPorID(ctx context.Context, id string) (*domain.Politico, error)
+	BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Politico, error)
+	Buscar(ctx context.Context, query string, limite int) ([]domain.Politico, error)
+	Criar(ctx context.Context, politico *domain.Politico) error
+	Atualizar(ctx context.Context, politico *domain.Politico) error
+	Contar(ctx context.Context) (int64, error)
+	Exportar(ctx context.Context, filtros domain.FiltrosPoliticos, fn func(domain.Politico) error) error
+}
+
+// Votacoes é o repositório dos votos dos políticos
+type Votacoes interface {
+	ListarPorPolitico(ctx context.Context, politicoID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.VotacaoComProposicao], error)
+	ContarPorPolitico(ctx context.Context, politicoID string) (map[domain.TipoVoto]int, error)
+	Contar(ctx context.Context) (int64, error)
+	VotosPorPolitico(ctx context.Context, politicoID string, ano *int) (map[string]domain.TipoVoto, error)
+	Concordancia(ctx context.Context, politicoID string, ano *int) ([]domain.AlinhamentoPolitico, error)
+	Exportar(ctx context.Context, filtros domain.FiltrosVotacoes, fn func(domain.Votacao) error) error
+}
+
+// Despesas é o repositório das despesas da cota parlamentar
+type Despesas interface {
+	ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Despesa], error)
+	TotalPorPolitico(ctx context.Context, politicoID string) (float64, error)
+	MediaMensalPorPolitico(ctx context.Context, politicoID string) (float64, error)
+	TotalGeral(ctx context.Context) (float64, error)
+	Resumo(ctx context.Context, filtros domain.FiltrosDespesas) (*domain.ResumoDespesas, error)
+	Exportar(ctx context.Context, filtros domain.FiltrosDespesas, fn func(domain.Despesa) error) error
+}
+
+// Proposicoes é o repositório das proposições legislativas
+type Proposicoes interface {
+	ListarPorAutor(ctx context.Context, autorID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.ProposicaoComAutor], error)
+	ContarPorAutor(ctx context.Context, autorID string) (total, aprovadas int64, err error)
+	Contar(ctx context.Context) (int64, error)
+	BuscarPorID(ctx context.Context, id string) (*domain.Proposicao, error)
+	BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Proposicao, error)
+	Exportar(ctx context.Context, filtros domain.FiltrosProposicoes, fn func(domain.Proposicao) error) error
+}
+
+// Presencas é o repositório das presenças em sessões
+type Presencas interface {
+	ListarPorPolitico(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Presenca], error)
+	CalcularPercentual(ctx context.Context, politicoID string) (float64, error)
+	Exportar(ctx context.Context, fn func(domain.Presenca) error) error
+}
+
+// Alertas é o repositório dos alertas gerados pela análise das despesas
+type Alertas interface {
+	Listar(ctx context.Context, filtros domain.FiltrosAlertas) (*domain.PaginatedResponse[domain.Alerta], error)
+}
+
+// Fornecedores agrega as despesas pelo documento do fornecedor
+type Fornecedores interface {
+	Ranking(ctx context.Context, filtros domain.FiltrosFornecedores) (*domain.PaginatedResponse[domain.TotalPorFornecedor], error)
+	BuscarPerfil(ctx context.Context, cnpj string) (*domain.PerfilFornecedor, error)
+}
+
+// Partidos é o repositório do cadastro de partidos e das estatísticas dos seus membros
+type Partidos interface {
+	Listar(ctx context.Context) ([]domain.RegistroPartido, error)
+	BuscarPorSigla(ctx context.Context, sigla string) (*domain.RegistroPartido, error)
+	Perfil(ctx context.Context, partido domain.RegistroPartido, ano *int) (*domain.PerfilPartido, error)
+	Trocas(ctx context.Context, inicio, fim time.Time) ([]domain.TrocaPartido, error)
+}
+
+// Alteracoes é o histórico de alterações feitas pelas sincronizações nos cadastros
+type Alteracoes interface {
+	ListarPorPolitico(ctx context.Context, politicoID, campo string, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Alteracao], error)
+}
+
+// Unificacoes junta e separa cadastros duplicados de políticos
+type Unificacoes interface {
+	Unificar(ctx context.Context, principalID, duplicadoID primitive.ObjectID, motivo string) (*domain.Unificacao, error)
+	Desfazer(ctx context.Context, id primitive.ObjectID) (*domain.Unificacao, error)
+	Listar(ctx context.Context, status domain.StatusUnificacao, pagina, porPagina int) (*domain.PaginatedResponse[domain.Unificacao], error)
+	ListarRevisoes(ctx context.Context, status domain.StatusRevisao, pagina, porPagina int) (*domain.PaginatedResponse[domain.RevisaoIdentidade], error)
+}
+
+// Chaves é o repositório das chaves de API
+type Chaves interface {
+	Criar(ctx context.Context, chave *domain.ChaveAPI) error
+	BuscarPorHash(ctx context.Context, hash string) (*domain.ChaveAPI, error)
+	RegistrarUso(ctx context.Context, id primitive.ObjectID) error
+	Listar(ctx context.Context) ([]domain.ChaveAPI, error)
+	Revogar(ctx context.Context, id primitive.ObjectID) (*domain.ChaveAPI, error)
+}
+
+// Qualidade é o repositório dos problemas de qualidade encontrados nas sincronizações
+type Qualidade interface {
+	UltimaExecucao(ctx context.Context) (string, error)
+	Relatorio(ctx context.Context, execucaoID string, regra domain.RegraQualidade, limite int) (*domain.RelatorioQualidade, error)
+	Listar(ctx context.Context, execucaoID string, regra domain.RegraQualidade, registro string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ProblemaQualidade], error)
+}
+
+// ExecucoesSync é o histórico das sincronizações e o seu andamento
+type ExecucoesSync interface {
+	Salvar(ctx context.Context, exec domain.ExecucaoSync) error
+	SolicitarCancelamento(ctx context.Context, id primitive.ObjectID) (*domain.ExecucaoSync, error)
+	CancelamentoSolicitado(ctx context.Context, id primitive.ObjectID) (bool, error)
+	MarcarInterrompidas(ctx context.Context, antesDe time.Time) (int64, error)
+	BuscarPorID(ctx context.Context, id primitive.ObjectID) (*domain.ExecucaoSync, error)
+	Listar(ctx context.Context, status domain.StatusExecucao, agendamento string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ExecucaoSync], error)
+}
+
+var (
+	_ Politicos    = (*PoliticoRepository)(nil)
+	_ Votacoes     = (*VotacaoRepository)(nil)
+	_ Despesas     = (*DespesaRepository)(nil)
+	_ Proposicoes  = (*ProposicaoRepository)(nil)
+	_ Presencas    = (*PresencaRepository)(nil)
+	_ Alertas      = (*AlertaRepository)(nil)
+	_ Fornecedores = (*FornecedorRepository)(nil)
+	_ Partidos     = (*PartidoRepository)(nil)
+	_ Alteracoes   = (*AlteracaoRepository)(nil)
+	_ Unificacoes  = (*UnificacaoRepository)(nil)
+	_ Chaves       = (*ChaveRepository)(nil)
+	_ Qualidade    = (*QualidadeRepository)(nil)
+
+	_ ExecucoesSync = (*ExecucaoSyncRepository)(nil)
+)
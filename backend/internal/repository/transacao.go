@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	gosync "sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// codigoSemTransacoes é o erro IllegalOperation dos servidores que não aceitam transações
+const codigoSemTransacoes = 20
+
+var avisoSemTransacoes gosync.Once
+
+// emTransacao executa fn em uma transação. Transações exigem um replica set (o docker-compose
+// sobe um de um nó só); em um servidor isolado, fn é executada sem transação, com um aviso no log.
+func emTransacao(ctx context.Context, client *mongo.Client, fn func(ctx context.Context) error) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+
+	var erroComando mongo.CommandError
+	if errors.As(err, &erroComando) && erroComando.Code == codigoSemTransacoes {
+		avisoSemTransacoes.Do(func() {
+			log.Println("⚠️  O MongoDB não aceita transações (sem replica set); alterações em várias coleções não são atômicas")
+		})
+		return fn(ctx)
+	}
+	return err
+}
@@ -0,0 +1,531 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrPoliticoNaoEncontrado   = errors.New("político não encontrado")
+	ErrUnificacaoNaoEncontrada = errors.New("unificação não encontrada")
+	ErrUnificacaoDesfeita      = errors.New("unificação já foi desfeita")
+	ErrUnificacaoDependente    = errors.New("há uma unificação posterior dos mesmos cadastros; desfaça-a antes")
+)
+
+// camposIdentificadores são copiados do duplicado para o principal quando o principal não os tem,
+// para que as sincronizações passem a encontrar o cadastro unificado
+var camposIdentificadores = []string{"cpf", "id_externo_camara", "id_externo_senado", "id_externo_tse"}
+
+// UnificacaoRepository junta e separa cadastros de políticos, movendo as referências entre eles
+type UnificacaoRepository struct {
+	unificacoes *mongo.Collection
+	revisoes    *mongo.Collection
+	politicos   *mongo.Collection
+	votacoes    *mongo.Collection
+	despesas    *mongo.Collection
+	presencas   *mongo.Collection
+	proposicoes *mongo.Collection
+	alteracoes  *mongo.Collection
+	alertas     *mongo.Collection
+}
+
+func NewUnificacaoRepository(db *mongo.Database) *UnificacaoRepository {
+	return &UnificacaoRepository{
+		unificacoes: db.Collection("unificacoes"),
+		revisoes:    db.Collection("revisoes_identidade"),
+		politicos:   db.Collection("politicos"),
+		votacoes:    db.Collection("votacoes"),
+		despesas:    db.Collection("despesas"),
+		presencas:   db.Collection("presencas"),
+		proposicoes: db.Collection("proposicoes"),
+		alteracoes:  db.Collection("alteracoes"),
+		alertas:     db.Collection("alertas"),
+	}
+}
+
+// Unificar incorpora o político duplicado ao principal: as votações, despesas, presenças,
+// proposições, alterações e alertas passam para o principal e o cadastro duplicado é removido. Os registros que o
+// principal já tinha (o mesmo voto, por exemplo) são removidos do duplicado em vez de movidos.
+// A unificação é gravada com tudo o que é necessário para desfazê-la, na mesma transação.
+func (r *UnificacaoRepository) Unificar(ctx context.Context, principalID, duplicadoID primitive.ObjectID, motivo string) (*domain.Unificacao, error) {
+	var unificacao *domain.Unificacao
+	err := emTransacao(ctx, r.politicos.Database().Client(), func(ctx context.Context) error {
+		var err error
+		unificacao, err = r.unificar(ctx, principalID, duplicadoID, motivo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unificacao, nil
+}
+
+func (r *UnificacaoRepository) unificar(ctx context.Context, principalID, duplicadoID primitive.ObjectID, motivo string) (*domain.Unificacao, error) {
+	principal, err := r.buscarPolitico(ctx, principalID)
+	if err != nil {
+		return nil, err
+	}
+	duplicado, err := r.buscarPolitico(ctx, duplicadoID)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := r.coletarReferencias(ctx, principalID, duplicadoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Identificadores que só o duplicado tem
+	principalDoc, duplicadoDoc, err := r.documentos(ctx, principalID, duplicadoID)
+	if err != nil {
+		return nil, err
+	}
+	copiar := bson.M{}
+	for _, campo := range camposIdentificadores {
+		valor, ok := duplicadoDoc[campo]
+		if !ok || valor == "" {
+			continue
+		}
+		if atual, ok := principalDoc[campo]; ok && atual != "" {
+			continue
+		}
+		copiar[campo] = valor
+		refs.CamposCopiados = append(refs.CamposCopiados, campo)
+	}
+
+	refs.CargosAdicionados = cargosNovos(principal, duplicado)
+
+	unificacao := &domain.Unificacao{
+		ID:          primitive.NewObjectID(),
+		PrincipalID: principalID,
+		DuplicadoID: duplicadoID,
+		Duplicado:   *duplicado,
+		Referencias: refs,
+		Motivo:      motivo,
+		Status:      domain.StatusUnificacaoAtiva,
+		CreatedAt:   time.Now(),
+	}
+	if _, err := r.unificacoes.InsertOne(ctx, unificacao); err != nil {
+		return nil, err
+	}
+
+	for _, m := range r.repetidos(refs.Repetidos) {
+		if len(m.docs) == 0 {
+			continue
+		}
+		if _, err := m.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": idsDe(m.docs)}}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.moverReferencias(ctx, refs, duplicadoID, principalID); err != nil {
+		return nil, err
+	}
+
+	// O duplicado sai antes de o principal receber os identificadores únicos dele
+	if _, err := r.politicos.DeleteOne(ctx, bson.M{"_id": duplicadoID}); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	for campo, valor := range copiar {
+		update["$set"].(bson.M)[campo] = valor
+	}
+	if len(refs.CargosAdicionados) > 0 {
+		update["$push"] = bson.M{"historico_cargos": bson.M{"$each": refs.CargosAdicionados}}
+	}
+	if _, err := r.politicos.UpdateOne(ctx, bson.M{"_id": principalID}, update); err != nil {
+		return nil, err
+	}
+
+	// Revisões de identidade que envolviam o duplicado deixam de estar pendentes
+	_, err = r.revisoes.UpdateMany(ctx, bson.M{
+		"candidatos_ids": duplicadoID,
+		"status":         domain.StatusRevisaoPendente,
+	}, bson.M{"$set": bson.M{"status": domain.StatusRevisaoResolvida, "updated_at": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+
+	return unificacao, nil
+}
+
+// Desfazer restaura o cadastro duplicado e devolve a ele as referências movidas na unificação
+// e os registros repetidos removidos. Só a unificação mais recente de cada cadastro pode ser
+// desfeita: as posteriores moveram as mesmas referências de novo.
+func (r *UnificacaoRepository) Desfazer(ctx context.Context, id primitive.ObjectID) (*domain.Unificacao, error) {
+	var unificacao *domain.Unificacao
+	err := emTransacao(ctx, r.politicos.Database().Client(), func(ctx context.Context) error {
+		var err error
+		unificacao, err = r.desfazer(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unificacao, nil
+}
+
+func (r *UnificacaoRepository) desfazer(ctx context.Context, id primitive.ObjectID) (*domain.Unificacao, error) {
+	var unificacao domain.Unificacao
+	err := r.unificacoes.FindOne(ctx, bson.M{"_id": id}).Decode(&unificacao)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUnificacaoNaoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	if unificacao.Status == domain.StatusUnificacaoDesfeita {
+		return nil, ErrUnificacaoDesfeita
+	}
+
+	envolvidos := bson.A{unificacao.PrincipalID, unificacao.DuplicadoID}
+	posteriores, err := r.unificacoes.CountDocuments(ctx, bson.M{
+		"_id":        bson.M{"$ne": id},
+		"status":     domain.StatusUnificacaoAtiva,
+		"created_at": bson.M{"$gte": unificacao.CreatedAt},
+		"$or": bson.A{
+			bson.M{"principal_id": bson.M{"$in": envolvidos}},
+			bson.M{"duplicado_id": bson.M{"$in": envolvidos}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if posteriores > 0 {
+		return nil, ErrUnificacaoDependente
+	}
+
+	refs := unificacao.Referencias
+
+	// O principal devolve os identificadores antes de o duplicado voltar a existir
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if len(refs.CamposCopiados) > 0 {
+		unset := bson.M{}
+		for _, campo := range refs.CamposCopiados {
+			unset[campo] = ""
+		}
+		update["$unset"] = unset
+	}
+	if len(refs.CargosAdicionados) > 0 {
+		update["$pull"] = bson.M{"historico_cargos": bson.M{"$in": refs.CargosAdicionados}}
+	}
+	if _, err := r.politicos.UpdateOne(ctx, bson.M{"_id": unificacao.PrincipalID}, update); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.politicos.InsertOne(ctx, unificacao.Duplicado); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	if err := r.moverReferencias(ctx, refs, unificacao.PrincipalID, unificacao.DuplicadoID); err != nil {
+		return nil, err
+	}
+
+	for _, m := range r.repetidos(refs.Repetidos) {
+		if len(m.docs) == 0 {
+			continue
+		}
+		docs := make([]interface{}, len(m.docs))
+		for i, doc := range m.docs {
+			docs[i] = doc
+		}
+		_, err := m.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+	}
+
+	agora := time.Now()
+	_, err = r.unificacoes.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":      domain.StatusUnificacaoDesfeita,
+		"desfeita_em": agora,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	unificacao.Status = domain.StatusUnificacaoDesfeita
+	unificacao.DesfeitaEm = &agora
+	return &unificacao, nil
+}
+
+func (r *UnificacaoRepository) Listar(ctx context.Context, status domain.StatusUnificacao, pagina, porPagina int) (*domain.PaginatedResponse[domain.Unificacao], error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return paginar[domain.Unificacao](ctx, r.unificacoes, filter, bson.D{{Key: "created_at", Value: -1}}, pagina, porPagina)
+}
+
+// ListarRevisoes lista a fila de identidades ambíguas encontradas pelas sincronizações
+func (r *UnificacaoRepository) ListarRevisoes(ctx context.Context, status domain.StatusRevisao, pagina, porPagina int) (*domain.PaginatedResponse[domain.RevisaoIdentidade], error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return paginar[domain.RevisaoIdentidade](ctx, r.revisoes, filter, bson.D{{Key: "created_at", Value: -1}}, pagina, porPagina)
+}
+
+func (r *UnificacaoRepository) buscarPolitico(ctx context.Context, id primitive.ObjectID) (*domain.Politico, error) {
+	var politico domain.Politico
+	err := r.politicos.FindOne(ctx, bson.M{"_id": id}).Decode(&politico)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPoliticoNaoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &politico, nil
+}
+
+// documentos lê os cadastros como documentos brutos, para distinguir campos ausentes de vazios
+func (r *UnificacaoRepository) documentos(ctx context.Context, principalID, duplicadoID primitive.ObjectID) (bson.M, bson.M, error) {
+	var principal, duplicado bson.M
+	if err := r.politicos.FindOne(ctx, bson.M{"_id": principalID}).Decode(&principal); err != nil {
+		return nil, nil, err
+	}
+	if err := r.politicos.FindOne(ctx, bson.M{"_id": duplicadoID}).Decode(&duplicado); err != nil {
+		return nil, nil, err
+	}
+	return principal, duplicado, nil
+}
+
+func (r *UnificacaoRepository) coletarReferencias(ctx context.Context, principalID, duplicadoID primitive.ObjectID) (domain.ReferenciasMovidas, error) {
+	var refs domain.ReferenciasMovidas
+	var err error
+
+	if refs.Votacoes, refs.Repetidos.Votacoes, err = separarRepetidos(ctx, r.votacoes, chaveVotacao, principalID, duplicadoID); err != nil {
+		return refs, err
+	}
+	if refs.Despesas, refs.Repetidos.Despesas, err = separarRepetidos(ctx, r.despesas, chaveDespesa, principalID, duplicadoID); err != nil {
+		return refs, err
+	}
+	if refs.Presencas, refs.Repetidos.Presencas, err = separarRepetidos(ctx, r.presencas, chavePresenca, principalID, duplicadoID); err != nil {
+		return refs, err
+	}
+	if refs.Alteracoes, err = ids(ctx, r.alteracoes, bson.M{"politico_id": duplicadoID}); err != nil {
+		return refs, err
+	}
+	if refs.Alertas, err = ids(ctx, r.alertas, bson.M{"politico_id": duplicadoID}); err != nil {
+		return refs, err
+	}
+	if refs.ProposicoesAutor, err = ids(ctx, r.proposicoes, bson.M{"autor_id": duplicadoID}); err != nil {
+		return refs, err
+	}
+	if refs.ProposicoesCoautor, err = ids(ctx, r.proposicoes, bson.M{"coautores_ids": duplicadoID}); err != nil {
+		return refs, err
+	}
+	refs.CoautoriasCompartilhadas, err = ids(ctx, r.proposicoes, bson.M{
+		"coautores_ids": bson.M{"$all": bson.A{duplicadoID, principalID}},
+	})
+
+	return refs, err
+}
+
+// chaveRegistro identifica o registro da fonte de um documento entre os de um mesmo político,
+// pelos campos usados nas sincronizações. Vazia quando o documento não tem esses campos.
+type chaveRegistro func(doc bson.M) string
+
+func chaveVotacao(doc bson.M) string {
+	if id, ok := doc["votacao_id_externo"]; ok && id != "" {
+		return fmt.Sprint(id)
+	}
+	return ""
+}
+
+// chaveDespesa segue despesaFilter da sincronização da Câmara: sem código do documento, o
+// registro é identificado também pelos dados da despesa e pela ocorrência entre as idênticas
+func chaveDespesa(doc bson.M) string {
+	if _, ok := doc["cod_documento"]; !ok {
+		return ""
+	}
+	chave := fmt.Sprint(doc["cod_documento"], doc["num_documento"], doc["cod_lote"], doc["parcela"])
+	if fmt.Sprint(doc["cod_documento"]) == "0" {
+		chave += fmt.Sprint(doc["ano_referencia"], doc["mes_referencia"], doc["tipo"], doc["cnpj_fornecedor"], doc["data"], doc["valor"], doc["ocorrencia"])
+	}
+	return chave
+}
+
+func chavePresenca(doc bson.M) string {
+	if _, ok := doc["data"]; !ok {
+		return ""
+	}
+	return fmt.Sprint(doc["data"], doc["tipo_sessao"])
+}
+
+// separarRepetidos divide os documentos do duplicado entre os que passam para o principal
+// (retornados pelo ID) e os que repetem um registro que o principal já tem (completos)
+func separarRepetidos(ctx context.Context, collection *mongo.Collection, chave chaveRegistro, principalID, duplicadoID primitive.ObjectID) ([]primitive.ObjectID, []bson.M, error) {
+	doPrincipal := map[string]bool{}
+	cursor, err := collection.Find(ctx, bson.M{"politico_id": principalID})
+	if err != nil {
+		return nil, nil, err
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, nil, err
+	}
+	for _, doc := range docs {
+		if c := chave(doc); c != "" {
+			doPrincipal[c] = true
+		}
+	}
+
+	cursor, err = collection.Find(ctx, bson.M{"politico_id": duplicadoID})
+	if err != nil {
+		return nil, nil, err
+	}
+	docs = nil
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, nil, err
+	}
+
+	mover := []primitive.ObjectID{}
+	var repetidos []bson.M
+	for _, doc := range docs {
+		if c := chave(doc); c != "" && doPrincipal[c] {
+			repetidos = append(repetidos, doc)
+			continue
+		}
+		id, _ := doc["_id"].(primitive.ObjectID)
+		mover = append(mover, id)
+	}
+	return mover, repetidos, nil
+}
+
+type documentosRepetidos struct {
+	collection *mongo.Collection
+	docs       []bson.M
+}
+
+func (r *UnificacaoRepository) repetidos(rep domain.RegistrosRepetidos) []documentosRepetidos {
+	return []documentosRepetidos{
+		{r.votacoes, rep.Votacoes},
+		{r.despesas, rep.Despesas},
+		{r.presencas, rep.Presencas},
+	}
+}
+
+func idsDe(docs []bson.M) bson.A {
+	ids := bson.A{}
+	for _, doc := range docs {
+		ids = append(ids, doc["_id"])
+	}
+	return ids
+}
+
+// moverReferencias troca o político "de" pelo político "para" nos documentos listados.
+// Só altera documentos que ainda apontam para "de".
+func (r *UnificacaoRepository) moverReferencias(ctx context.Context, refs domain.ReferenciasMovidas, de, para primitive.ObjectID) error {
+	for _, m := range []struct {
+		collection *mongo.Collection
+		ids        []primitive.ObjectID
+	}{
+		{r.votacoes, refs.Votacoes},
+		{r.despesas, refs.Despesas},
+		{r.presencas, refs.Presencas},
+		{r.alteracoes, refs.Alteracoes},
+		{r.alertas, refs.Alertas},
+	} {
+		if len(m.ids) == 0 {
+			continue
+		}
+		_, err := m.collection.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": m.ids}, "politico_id": de},
+			bson.M{"$set": bson.M{"politico_id": para}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(refs.ProposicoesAutor) > 0 {
+		_, err := r.proposicoes.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": refs.ProposicoesAutor}, "autor_id": de},
+			bson.M{"$set": bson.M{"autor_id": para}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(refs.ProposicoesCoautor) > 0 {
+		_, err := r.proposicoes.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": refs.ProposicoesCoautor}},
+			bson.M{"$addToSet": bson.M{"coautores_ids": para}})
+		if err != nil {
+			return err
+		}
+
+		// Nas coautorias compartilhadas, os dois continuam coautores ao desfazer
+		remover := bson.M{"_id": bson.M{"$in": refs.ProposicoesCoautor}}
+		if len(refs.CoautoriasCompartilhadas) > 0 {
+			remover["_id"] = bson.M{"$in": refs.ProposicoesCoautor, "$nin": refs.CoautoriasCompartilhadas}
+			_, err = r.proposicoes.UpdateMany(ctx,
+				bson.M{"_id": bson.M{"$in": refs.CoautoriasCompartilhadas}},
+				bson.M{"$addToSet": bson.M{"coautores_ids": de}})
+			if err != nil {
+				return err
+			}
+		}
+		_, err = r.proposicoes.UpdateMany(ctx, remover, bson.M{"$pull": bson.M{"coautores_ids": de}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cargosNovos retorna o cargo atual e o histórico do duplicado que ainda não estão no principal
+func cargosNovos(principal, duplicado *domain.Politico) []domain.CargoAtual {
+	existentes := append([]domain.CargoAtual{principal.CargoAtual}, principal.HistoricoCargos...)
+	candidatos := append([]domain.CargoAtual{duplicado.CargoAtual}, duplicado.HistoricoCargos...)
+
+	var novos []domain.CargoAtual
+	for _, c := range candidatos {
+		if c.Tipo == "" {
+			continue
+		}
+		repetido := false
+		for _, e := range append(existentes, novos...) {
+			if e.Tipo == c.Tipo && e.Estado == c.Estado && e.DataInicio.Equal(c.DataInicio) {
+				repetido = true
+				break
+			}
+		}
+		if !repetido {
+			novos = append(novos, c)
+		}
+	}
+	return novos
+}
+
+func ids(ctx context.Context, collection *mongo.Collection, filter bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := []primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result = append(result, doc.ID)
+	}
+	return result, cursor.Err()
+}
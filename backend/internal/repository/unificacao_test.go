@@ -0,0 +1,139 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestUnificarRemoveRepetidosEDesfaz(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	repo := repository.NewUnificacaoRepository(db)
+	ctx := context.Background()
+	dia := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	principal, duplicado := novoPolitico(t, db, "Ana Lima"), novoPolitico(t, db, "Ana Lima")
+	inserir(t, db.Collection("votacoes"), []bson.M{
+		{"politico_id": principal, "votacao_id_externo": "v1", "voto": "SIM"},
+		{"politico_id": duplicado, "votacao_id_externo": "v1", "voto": "SIM"},
+		{"politico_id": duplicado, "votacao_id_externo": "v2", "voto": "NAO"},
+	})
+	inserir(t, db.Collection("despesas"), []bson.M{
+		{"politico_id": principal, "cod_documento": int32(10), "num_documento": "A", "cod_lote": int32(1), "parcela": int32(0), "valor": 50.0},
+		{"politico_id": duplicado, "cod_documento": int32(10), "num_documento": "A", "cod_lote": int32(1), "parcela": int32(0), "valor": 50.0},
+		{"politico_id": duplicado, "cod_documento": int32(11), "num_documento": "B", "cod_lote": int32(1), "parcela": int32(0), "valor": 80.0},
+	})
+	inserir(t, db.Collection("presencas"), []bson.M{
+		{"politico_id": principal, "data": dia, "tipo_sessao": "Plenário"},
+		{"politico_id": duplicado, "data": dia, "tipo_sessao": "Plenário"},
+		{"politico_id": duplicado, "data": dia, "tipo_sessao": "Comissão"},
+	})
+	inserir(t, db.Collection("alteracoes"), []bson.M{
+		{"politico_id": principal, "operacao": "CRIACAO"},
+		{"politico_id": duplicado, "operacao": "CRIACAO"},
+	})
+	inserir(t, db.Collection("alertas"), []bson.M{
+		{"politico_id": principal, "chave": "a"},
+		{"politico_id": duplicado, "chave": "b"},
+	})
+
+	unificacao, err := repo.Unificar(ctx, principal, duplicado, "mesma pessoa")
+	if err != nil {
+		t.Fatalf("Unificar: %v", err)
+	}
+	// O histórico de alterações e os alertas do duplicado passam para o principal
+	for _, colecao := range []string{"alteracoes", "alertas"} {
+		if n := contar(t, db, colecao, bson.M{"politico_id": principal}); n != 2 {
+			t.Errorf("%s do principal: %d; esperados 2", colecao, n)
+		}
+	}
+	for _, colecao := range []string{"votacoes", "despesas", "presencas"} {
+		if n := contar(t, db, colecao, bson.M{"politico_id": principal}); n != 2 {
+			t.Errorf("%s do principal: %d; esperados 2, sem o registro repetido", colecao, n)
+		}
+		if n := contar(t, db, colecao, bson.M{}); n != 2 {
+			t.Errorf("%s: %d documentos; o repetido do duplicado deveria ter sido removido", colecao, n)
+		}
+	}
+	if n := len(unificacao.Referencias.Repetidos.Votacoes); n != 1 {
+		t.Errorf("%d votos repetidos registrados; esperado 1", n)
+	}
+
+	if _, err := repo.Desfazer(ctx, unificacao.ID); err != nil {
+		t.Fatalf("Desfazer: %v", err)
+	}
+	for _, colecao := range []string{"votacoes", "despesas", "presencas"} {
+		if n := contar(t, db, colecao, bson.M{"politico_id": duplicado}); n != 2 {
+			t.Errorf("%s do duplicado depois de desfazer: %d; esperados 2", colecao, n)
+		}
+		if n := contar(t, db, colecao, bson.M{"politico_id": principal}); n != 1 {
+			t.Errorf("%s do principal depois de desfazer: %d; esperado 1", colecao, n)
+		}
+	}
+	for _, colecao := range []string{"alteracoes", "alertas"} {
+		if n := contar(t, db, colecao, bson.M{"politico_id": duplicado}); n != 1 {
+			t.Errorf("%s do duplicado depois de desfazer: %d; esperado 1", colecao, n)
+		}
+	}
+}
+
+func TestDesfazerRecusaUnificacaoComPosteriores(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	repo := repository.NewUnificacaoRepository(db)
+	ctx := context.Background()
+
+	a, b, c := novoPolitico(t, db, "Rui Prado"), novoPolitico(t, db, "Rui Prado"), novoPolitico(t, db, "Rui Prado")
+	inserir(t, db.Collection("votacoes"), []bson.M{{"politico_id": a, "votacao_id_externo": "v1", "voto": "SIM"}})
+
+	// a → b e depois b → c: desfazer a primeira antes da segunda deixaria o voto em c
+	primeira, err := repo.Unificar(ctx, b, a, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	segunda, err := repo.Unificar(ctx, c, b, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Desfazer(ctx, primeira.ID); !errors.Is(err, repository.ErrUnificacaoDependente) {
+		t.Fatalf("desfazer a primeira: %v; esperado ErrUnificacaoDependente", err)
+	}
+	if _, err := repo.Desfazer(ctx, segunda.ID); err != nil {
+		t.Fatalf("desfazer a segunda: %v", err)
+	}
+	if _, err := repo.Desfazer(ctx, primeira.ID); err != nil {
+		t.Fatalf("desfazer a primeira depois da segunda: %v", err)
+	}
+	if n := contar(t, db, "votacoes", bson.M{"politico_id": a}); n != 1 {
+		t.Errorf("o voto não voltou ao cadastro original (%d)", n)
+	}
+	if n := contar(t, db, "politicos", bson.M{}); n != 3 {
+		t.Errorf("%d cadastros; esperados os 3 restaurados", n)
+	}
+}
+
+func novoPolitico(t *testing.T, db *mongo.Database, nome string) primitive.ObjectID {
+	t.Helper()
+	politico := domain.Politico{ID: primitive.NewObjectID(), Nome: nome, CreatedAt: time.Now()}
+	if _, err := db.Collection("politicos").InsertOne(context.Background(), politico); err != nil {
+		t.Fatal(err)
+	}
+	return politico.ID
+}
+
+func contar(t *testing.T, db *mongo.Database, colecao string, filtro bson.M) int64 {
+	t.Helper()
+	n, err := db.Collection(colecao).CountDocuments(context.Background(), filtro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
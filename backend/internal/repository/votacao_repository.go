@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/lupa-cidada/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,57 +21,37 @@ func NewVotacaoRepository(db *mongo.Database) *VotacaoRepository {
 	}
 }
 
-func (r *VotacaoRepository) ListarPorPolitico(ctx context.Context, politicoID string, pagina, porPagina int) (*domain.PaginatedResponse[domain.Votacao], error) {
+// ListarPorPolitico lista os votos do político, dos mais recentes para os mais antigos, com a
+// proposição votada (e o autor dela) embutida se pedido em incluir
+func (r *VotacaoRepository) ListarPorPolitico(ctx context.Context, politicoID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.VotacaoComProposicao], error) {
 	objectID, err := primitive.ObjectIDFromHex(politicoID)
 	if err != nil {
 		return nil, err
 	}
 
-	filter := bson.M{"politico_id": objectID}
-
-	if pagina < 1 {
-		pagina = 1
+	var juntar mongo.Pipeline
+	if incluir.Proposicao || incluir.Autor {
+		juntar = juntarUm("proposicoes", "proposicao_id", "proposicao")
 	}
-	if porPagina < 1 || porPagina > 100 {
-		porPagina = 20
+	if incluir.Autor {
+		juntar = append(juntar, juntarUm("politicos", "proposicao.autor_id", "proposicao.autor")...)
 	}
 
-	skip := int64((pagina - 1) * porPagina)
-	limit := int64(porPagina)
-
-	total, err := r.collection.CountDocuments(ctx, filter)
+	ordenacao := bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}
+	result, err := listarPaginado(ctx, r.collection, bson.M{"politico_id": objectID}, ordenacao, paginacao, 20, juntar, func(v domain.VotacaoComProposicao) bson.D {
+		return bson.D{{Key: "data", Value: v.Data}, {Key: "_id", Value: v.ID}}
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(limit).
-		SetSort(bson.D{{Key: "data", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var votacoes []domain.Votacao
-	if err := cursor.All(ctx, &votacoes); err != nil {
-		return nil, err
-	}
-
-	totalPaginas := int(total) / porPagina
-	if int(total)%porPagina > 0 {
-		totalPaginas++
+	// Sem a proposição, o $lookup do autor ainda cria o documento "proposicao", vazio
+	for i := range result.Data {
+		if p := result.Data[i].Proposicao; p != nil && p.ID.IsZero() {
+			result.Data[i].Proposicao = nil
+		}
 	}
-
-	return &domain.PaginatedResponse[domain.Votacao]{
-		Data:         votacoes,
-		Total:        total,
-		Pagina:       pagina,
-		PorPagina:    porPagina,
-		TotalPaginas: totalPaginas,
-	}, nil
+	return result, nil
 }
 
 func (r *VotacaoRepository) ContarPorPolitico(ctx context.Context, politicoID string) (map[domain.TipoVoto]int, error) {
@@ -112,3 +93,153 @@ func (r *VotacaoRepository) Contar(ctx context.Context) (int64, error) {
 	return r.collection.CountDocuments(ctx, bson.M{})
 }
 
+// filtroParticipacao restringe aos votos em que o político participou da votação (não ausente)
+// e que têm o ID externo da votação, necessário para cruzar votos de políticos diferentes
+func filtroParticipacao(ano *int) bson.M {
+	filter := bson.M{
+		"voto":               bson.M{"$ne": domain.VotoAusente},
+		"votacao_id_externo": bson.M{"$exists": true, "$ne": ""},
+	}
+	if ano != nil {
+		filter["data"] = bson.M{
+			"$gte": time.Date(*ano, 1, 1, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(*ano+1, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	return filter
+}
+
+// VotosPorPolitico retorna o voto do político em cada votação de que participou, indexado pelo ID externo da votação
+func (r *VotacaoRepository) VotosPorPolitico(ctx context.Context, politicoID string, ano *int) (map[string]domain.TipoVoto, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := filtroParticipacao(ano)
+	filter["politico_id"] = objectID
+
+	opts := options.Find().SetProjection(bson.M{"votacao_id_externo": 1, "voto": 1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	votos := make(map[string]domain.TipoVoto)
+	for cursor.Next(ctx) {
+		var v domain.Votacao
+		if err := cursor.Decode(&v); err != nil {
+			continue
+		}
+		votos[v.VotacaoIDExterno] = v.Voto
+	}
+
+	return votos, cursor.Err()
+}
+
+// Concordancia conta, para cada outro político, as votações de que ambos participaram e em
+// quantas delas o voto foi o mesmo. O pipeline parte dos votos do político (pelo índice de
+// politico_id) e junta os dos colegas na mesma votação; cada colega volta agrupado pelo par
+// de votos, que é somado aqui.
+func (r *VotacaoRepository) Concordancia(ctx context.Context, politicoID string, ano *int) ([]domain.AlinhamentoPolitico, error) {
+	objectID, err := primitive.ObjectIDFromHex(politicoID)
+	if err != nil {
+		return nil, err
+	}
+
+	match := filtroParticipacao(ano)
+	match["politico_id"] = objectID
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$project", Value: bson.M{"votacao_id_externo": 1, "voto": 1}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: r.collection.Name()},
+			{Key: "localField", Value: "votacao_id_externo"},
+			{Key: "foreignField", Value: "votacao_id_externo"},
+			{Key: "as", Value: "outros"},
+		}}},
+		{{Key: "$unwind", Value: "$outros"}},
+		{{Key: "$match", Value: bson.M{
+			"outros.politico_id": bson.M{"$ne": objectID},
+			"outros.voto":        bson.M{"$ne": domain.VotoAusente},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"politico_id": "$outros.politico_id", "voto": "$voto", "outro": "$outros.voto"},
+			"total": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var pares []struct {
+		ID struct {
+			PoliticoID primitive.ObjectID `bson:"politico_id"`
+			Voto       domain.TipoVoto    `bson:"voto"`
+			Outro      domain.TipoVoto    `bson:"outro"`
+		} `bson:"_id"`
+		Total int `bson:"total"`
+	}
+	if err := cursor.All(ctx, &pares); err != nil {
+		return nil, err
+	}
+
+	indice := make(map[primitive.ObjectID]int)
+	resultado := []domain.AlinhamentoPolitico{}
+	for _, p := range pares {
+		i, ok := indice[p.ID.PoliticoID]
+		if !ok {
+			i = len(resultado)
+			indice[p.ID.PoliticoID] = i
+			resultado = append(resultado, domain.AlinhamentoPolitico{PoliticoID: p.ID.PoliticoID})
+		}
+		resultado[i].VotacoesEmComum += p.Total
+		if p.ID.Voto == p.ID.Outro {
+			resultado[i].Concordancias += p.Total
+		}
+	}
+
+	return resultado, nil
+}
+
+// Exportar percorre todos os votos que atendem aos filtros, em ordem de _id
+func (r *VotacaoRepository) Exportar(ctx context.Context, filtros domain.FiltrosVotacoes, fn func(domain.Votacao) error) error {
+	filter := bson.M{}
+
+	if filtros.PoliticoID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filtros.PoliticoID)
+		if err != nil {
+			return err
+		}
+		filter["politico_id"] = objectID
+	}
+	if filtros.Ano != nil {
+		filter["data"] = bson.M{
+			"$gte": time.Date(*filtros.Ano, 1, 1, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(*filtros.Ano+1, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	if filtros.Voto != "" {
+		filter["voto"] = filtros.Voto
+	}
+
+	// Partido e estado ficam no político; o $and preserva o filtro por politico_id, se houver
+	if filtros.Partido != "" || filtros.Estado != "" {
+		ids, err := idsPoliticos(ctx, r.collection.Database(), filtros.Partido, filtros.Estado)
+		if err != nil {
+			return err
+		}
+		filter["$and"] = bson.A{bson.M{"politico_id": bson.M{"$in": ids}}}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	return percorrer(ctx, cursor, fn)
+}
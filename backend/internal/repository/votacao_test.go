@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+)
+
+// A concordância calculada pelo pipeline é a mesma da memória
+func TestConcordancia(t *testing.T) {
+	dados := mock.Gerar(mock.Configuracao{Semente: 11, Politicos: 20, VotacoesPorCasa: 15})
+	ctx := context.Background()
+
+	db := mongomemtest.Banco(t)
+	inserir(t, db.Collection("votacoes"), dados.Votacoes)
+
+	repos := []repository.Votacoes{repository.NewVotacaoRepository(db), memoria.NewVotacaoRepository(memoria.NewBanco(dados))}
+	ano := dados.Votacoes[0].Data.UTC().Year()
+	politicoID := dados.Votacoes[0].PoliticoID.Hex()
+
+	for _, filtro := range []*int{nil, &ano} {
+		var resultados [2]map[string]domain.AlinhamentoPolitico
+		for i, repo := range repos {
+			alinhamentos, err := repo.Concordancia(ctx, politicoID, filtro)
+			if err != nil {
+				t.Fatalf("Concordancia: %v", err)
+			}
+			resultados[i] = map[string]domain.AlinhamentoPolitico{}
+			for _, a := range alinhamentos {
+				resultados[i][a.PoliticoID.Hex()] = a
+			}
+		}
+
+		if len(resultados[0]) == 0 || len(resultados[0]) != len(resultados[1]) {
+			t.Fatalf("%d colegas no MongoDB e %d em memória", len(resultados[0]), len(resultados[1]))
+		}
+		ids := make([]string, 0, len(resultados[0]))
+		for id := range resultados[0] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if a, b := resultados[0][id], resultados[1][id]; a.VotacoesEmComum != b.VotacoesEmComum || a.Concordancias != b.Concordancias {
+				t.Errorf("colega %s: MongoDB %d/%d, memória %d/%d", id, a.Concordancias, a.VotacoesEmComum, b.Concordancias, b.VotacoesEmComum)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+type AlertaService struct {
+	alertaRepo repository.Alertas
+}
+
+func NewAlertaService(alertaRepo repository.Alertas) *AlertaService {
+	return &AlertaService{
+		alertaRepo: alertaRepo,
+	}
+}
+
+func (s *AlertaService) Listar(ctx context.Context, filtros domain.FiltrosAlertas) (*domain.PaginatedResponse[domain.Alerta], error) {
+	result, err := s.alertaRepo.Listar(ctx, filtros)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.Alerta{}
+	}
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+type AlteracaoService struct {
+	alteracaoRepo repository.Alteracoes
+}
+
+func NewAlteracaoService(alteracaoRepo repository.Alteracoes) *AlteracaoService {
+	return &AlteracaoService{
+		alteracaoRepo: alteracaoRepo,
+	}
+}
+
+func (s *AlteracaoService) ListarPorPolitico(ctx context.Context, politicoID, campo string, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Alteracao], error) {
+	result, err := s.alteracaoRepo.ListarPorPolitico(ctx, politicoID, campo, paginacao)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.Alteracao{}
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/auth"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrPapelInvalido = errors.New("papel inválido; use CONSUMIDOR, LEITOR, EDITOR ou ADMIN")
+	ErrNomeChave     = errors.New("nome da chave é obrigatório")
+)
+
+type ChaveService struct {
+	chaveRepo repository.Chaves
+}
+
+func NewChaveService(chaveRepo repository.Chaves) *ChaveService {
+	return &ChaveService{
+		chaveRepo: chaveRepo,
+	}
+}
+
+// Criar gera uma nova chave de API, com limites de uso próprios opcionais. O valor retornado
+// é o único momento em que a chave completa fica disponível.
+func (s *ChaveService) Criar(ctx context.Context, nome string, papel domain.Papel, limites *domain.LimitesUso, criadaPor string) (string, *domain.ChaveAPI, error) {
+	nome = strings.TrimSpace(nome)
+	if nome == "" {
+		return "", nil, ErrNomeChave
+	}
+	papel = domain.Papel(strings.ToUpper(string(papel)))
+	if !papel.Valido() {
+		return "", nil, ErrPapelInvalido
+	}
+
+	if limites != nil && *limites == (domain.LimitesUso{}) {
+		limites = nil
+	}
+
+	valor, prefixo, hash, err := auth.GerarChave()
+	if err != nil {
+		return "", nil, err
+	}
+
+	chave := &domain.ChaveAPI{
+		Nome:      nome,
+		Prefixo:   prefixo,
+		Hash:      hash,
+		Papel:     papel,
+		Limites:   limites,
+		CriadaPor: criadaPor,
+		CreatedAt: time.Now(),
+	}
+	if err := s.chaveRepo.Criar(ctx, chave); err != nil {
+		return "", nil, err
+	}
+
+	return valor, chave, nil
+}
+
+func (s *ChaveService) Listar(ctx context.Context) ([]domain.ChaveAPI, error) {
+	chaves, err := s.chaveRepo.Listar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chaves == nil {
+		chaves = []domain.ChaveAPI{}
+	}
+	return chaves, nil
+}
+
+func (s *ChaveService) Revogar(ctx context.Context, id string) (*domain.ChaveAPI, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrIDInvalido
+	}
+
+	return s.chaveRepo.Revogar(ctx, objectID)
+}
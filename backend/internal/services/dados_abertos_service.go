@@ -0,0 +1,30 @@
+package services
+
+import (
+	"github.com/lupa-cidada/backend/internal/dadosabertos"
+)
+
+// DadosAbertosService lista os dumps de dados abertos gravados por cmd/dump. Os dumps ficam
+// em disco, não no banco, então o serviço funciona igual em modo debug.
+type DadosAbertosService struct {
+	diretorio string
+}
+
+func NewDadosAbertosService(diretorio string) *DadosAbertosService {
+	return &DadosAbertosService{diretorio: diretorio}
+}
+
+// Listar retorna os manifestos dos dumps disponíveis, do mais recente para o mais antigo
+func (s *DadosAbertosService) Listar() ([]dadosabertos.Manifesto, error) {
+	return dadosabertos.Listar(s.diretorio)
+}
+
+// Buscar retorna o manifesto do dump da data (AAAA-MM-DD)
+func (s *DadosAbertosService) Buscar(data string) (*dadosabertos.Manifesto, error) {
+	return dadosabertos.Buscar(s.diretorio, data)
+}
+
+// Arquivo retorna o caminho em disco de um arquivo listado no manifesto do dump
+func (s *DadosAbertosService) Arquivo(data, nome string) (string, error) {
+	return dadosabertos.Caminho(s.diretorio, data, nome)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+type DespesaService struct {
+	despesaRepo repository.Despesas
+}
+
+func NewDespesaService(despesaRepo repository.Despesas) *DespesaService {
+	return &DespesaService{
+		despesaRepo: despesaRepo,
+	}
+}
+
+// Resumo retorna os gastos agregados por categoria, fornecedor e mês
+func (s *DespesaService) Resumo(ctx context.Context, filtros domain.FiltrosDespesas) (*domain.ResumoDespesas, error) {
+	resumo, err := s.despesaRepo.Resumo(ctx, filtros)
+	if err != nil {
+		return nil, err
+	}
+
+	// Garante arrays vazios no JSON em vez de null
+	if resumo.PorTipo == nil {
+		resumo.PorTipo = []domain.TotalPorTipo{}
+	}
+	if resumo.TopFornecedores == nil {
+		resumo.TopFornecedores = []domain.TotalPorFornecedor{}
+	}
+	if resumo.SerieMensal == nil {
+		resumo.SerieMensal = []domain.TotalMensal{}
+	}
+
+	return resumo, nil
+}
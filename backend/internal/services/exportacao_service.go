@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/exportacao"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrFormatoInvalido = errors.New("formato inválido: use csv, ndjson ou parquet")
+
+// ExportacaoService grava conjuntos completos de registros, lidos do cursor do repositório e
+// escritos linha a linha, sem passar pela paginação das listagens
+type ExportacaoService struct {
+	politicoRepo   repository.Politicos
+	votacaoRepo    repository.Votacoes
+	despesaRepo    repository.Despesas
+	proposicaoRepo repository.Proposicoes
+}
+
+func NewExportacaoService(
+	politicoRepo repository.Politicos,
+	votacaoRepo repository.Votacoes,
+	despesaRepo repository.Despesas,
+	proposicaoRepo repository.Proposicoes,
+) *ExportacaoService {
+	return &ExportacaoService{
+		politicoRepo:   politicoRepo,
+		votacaoRepo:    votacaoRepo,
+		despesaRepo:    despesaRepo,
+		proposicaoRepo: proposicaoRepo,
+	}
+}
+
+func (s *ExportacaoService) Politicos(ctx context.Context, filtros domain.FiltrosPoliticos, formato exportacao.Formato, w io.Writer) error {
+	return exportar(w, formato, func(fn func(domain.Politico) error) error {
+		return s.politicoRepo.Exportar(ctx, filtros, fn)
+	}, exportacao.NovaLinhaPolitico)
+}
+
+func (s *ExportacaoService) Despesas(ctx context.Context, filtros domain.FiltrosDespesas, formato exportacao.Formato, w io.Writer) error {
+	if !idValido(filtros.PoliticoID) {
+		return ErrIDInvalido
+	}
+	return exportar(w, formato, func(fn func(domain.Despesa) error) error {
+		return s.despesaRepo.Exportar(ctx, filtros, fn)
+	}, exportacao.NovaLinhaDespesa)
+}
+
+func (s *ExportacaoService) Votacoes(ctx context.Context, filtros domain.FiltrosVotacoes, formato exportacao.Formato, w io.Writer) error {
+	if !idValido(filtros.PoliticoID) {
+		return ErrIDInvalido
+	}
+	return exportar(w, formato, func(fn func(domain.Votacao) error) error {
+		return s.votacaoRepo.Exportar(ctx, filtros, fn)
+	}, exportacao.NovaLinhaVotacao)
+}
+
+func (s *ExportacaoService) Proposicoes(ctx context.Context, filtros domain.FiltrosProposicoes, formato exportacao.Formato, w io.Writer) error {
+	if !idValido(filtros.AutorID) {
+		return ErrIDInvalido
+	}
+	return exportar(w, formato, func(fn func(domain.Proposicao) error) error {
+		return s.proposicaoRepo.Exportar(ctx, filtros, fn)
+	}, exportacao.NovaLinhaProposicao)
+}
+
+// idValido aceita filtros de ID vazios (sem filtro) ou ObjectIDs em hexadecimal
+func idValido(id string) bool {
+	return id == "" || primitive.IsValidObjectID(id)
+}
+
+// exportar grava os registros no formato pedido. Como nada é escrito em w antes do primeiro
+// registro, um erro na consulta ainda pode ser respondido com um status HTTP de erro.
+func exportar[D, L any](w io.Writer, formato exportacao.Formato, percorrer func(func(D) error) error, linha func(D) L) error {
+	if !formato.Valido() {
+		return ErrFormatoInvalido
+	}
+
+	_, err := exportacao.Gravar(w, formato, percorrer, linha)
+	return err
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+type FornecedorService struct {
+	fornecedorRepo repository.Fornecedores
+}
+
+func NewFornecedorService(fornecedorRepo repository.Fornecedores) *FornecedorService {
+	return &FornecedorService{
+		fornecedorRepo: fornecedorRepo,
+	}
+}
+
+func (s *FornecedorService) Ranking(ctx context.Context, filtros domain.FiltrosFornecedores) (*domain.PaginatedResponse[domain.TotalPorFornecedor], error) {
+	result, err := s.fornecedorRepo.Ranking(ctx, filtros)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.TotalPorFornecedor{}
+	}
+	return result, nil
+}
+
+// BuscarPerfil retorna nil se o fornecedor não recebeu pagamentos
+func (s *FornecedorService) BuscarPerfil(ctx context.Context, cnpj string) (*domain.PerfilFornecedor, error) {
+	return s.fornecedorRepo.BuscarPerfil(ctx, cnpj)
+}
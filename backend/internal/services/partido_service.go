@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+)
+
+type PartidoService struct {
+	partidoRepo repository.Partidos
+}
+
+func NewPartidoService(partidoRepo repository.Partidos) *PartidoService {
+	return &PartidoService{
+		partidoRepo: partidoRepo,
+	}
+}
+
+// Listar retorna os partidos em atividade
+func (s *PartidoService) Listar(ctx context.Context) ([]domain.RegistroPartido, error) {
+	result, err := s.partidoRepo.Listar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []domain.RegistroPartido{}
+	}
+	return result, nil
+}
+
+// BuscarPerfil retorna nil se a sigla não corresponder a nenhum partido cadastrado
+func (s *PartidoService) BuscarPerfil(ctx context.Context, sigla string, ano *int) (*domain.PerfilPartido, error) {
+	partido, err := s.partidoRepo.BuscarPorSigla(ctx, sigla)
+	if err != nil || partido == nil {
+		return nil, err
+	}
+
+	return s.partidoRepo.Perfil(ctx, *partido, ano)
+}
+
+// TrocaTroca lista as trocas de partido no período e o saldo de entradas e saídas de cada partido
+func (s *PartidoService) TrocaTroca(ctx context.Context, inicio, fim time.Time) (*domain.RelatorioTrocas, error) {
+	relatorio := &domain.RelatorioTrocas{
+		Inicio: inicio,
+		Fim:    fim,
+		Trocas: []domain.TrocaPartido{},
+		Saldos: []domain.SaldoPartido{},
+	}
+	trocas, err := s.partidoRepo.Trocas(ctx, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	if trocas != nil {
+		relatorio.Trocas = trocas
+	}
+	relatorio.Total = len(relatorio.Trocas)
+	relatorio.Saldos = saldosPorPartido(relatorio.Trocas)
+
+	return relatorio, nil
+}
+
+func saldosPorPartido(trocas []domain.TrocaPartido) []domain.SaldoPartido {
+	porSigla := make(map[string]*domain.SaldoPartido)
+	saldo := func(sigla string) *domain.SaldoPartido {
+		if _, ok := porSigla[sigla]; !ok {
+			porSigla[sigla] = &domain.SaldoPartido{Sigla: sigla}
+		}
+		return porSigla[sigla]
+	}
+
+	for _, t := range trocas {
+		saldo(t.De.Sigla).Saidas++
+		saldo(t.Para.Sigla).Entradas++
+	}
+
+	saldos := make([]domain.SaldoPartido, 0, len(porSigla))
+	for _, s := range porSigla {
+		s.Saldo = s.Entradas - s.Saidas
+		saldos = append(saldos, *s)
+	}
+	sort.Slice(saldos, func(i, j int) bool {
+		if saldos[i].Saldo != saldos[j].Saldo {
+			return saldos[i].Saldo > saldos[j].Saldo
+		}
+		return saldos[i].Sigla < saldos[j].Sigla
+	})
+
+	return saldos
+}
@@ -2,197 +2,67 @@ package services
 
 import (
 	"context"
-	"strings"
+	"sort"
 
 	"github.com/lupa-cidada/backend/internal/domain"
-	"github.com/lupa-cidada/backend/internal/mock"
 	"github.com/lupa-cidada/backend/internal/repository"
 )
 
+// PoliticoService depende só das interfaces dos repositórios: em produção recebe os
+// repositórios com MongoDB e, no modo debug e nos testes, os de repository/memoria.
 type PoliticoService struct {
-	debug          bool
-	politicoRepo   *repository.PoliticoRepository
-	votacaoRepo    *repository.VotacaoRepository
-	despesaRepo    *repository.DespesaRepository
-	proposicaoRepo *repository.ProposicaoRepository
+	politicoRepo   repository.Politicos
+	votacaoRepo    repository.Votacoes
+	despesaRepo    repository.Despesas
+	proposicaoRepo repository.Proposicoes
+	presencaRepo   repository.Presencas
 }
 
 func NewPoliticoService(
-	debug bool,
-	politicoRepo *repository.PoliticoRepository,
-	votacaoRepo *repository.VotacaoRepository,
-	despesaRepo *repository.DespesaRepository,
-	proposicaoRepo *repository.ProposicaoRepository,
+	politicoRepo repository.Politicos,
+	votacaoRepo repository.Votacoes,
+	despesaRepo repository.Despesas,
+	proposicaoRepo repository.Proposicoes,
+	presencaRepo repository.Presencas,
 ) *PoliticoService {
 	return &PoliticoService{
-		debug:          debug,
 		politicoRepo:   politicoRepo,
 		votacaoRepo:    votacaoRepo,
 		despesaRepo:    despesaRepo,
 		proposicaoRepo: proposicaoRepo,
+		presencaRepo:   presencaRepo,
 	}
 }
 
 func (s *PoliticoService) Listar(ctx context.Context, filtros domain.FiltrosPoliticos) (*domain.PaginatedResponse[domain.Politico], error) {
-	if s.debug {
-		return s.listarMock(filtros), nil
-	}
 	return s.politicoRepo.Listar(ctx, filtros)
 }
 
-func (s *PoliticoService) listarMock(filtros domain.FiltrosPoliticos) *domain.PaginatedResponse[domain.Politico] {
-	allPoliticos := mock.Politicos()
-	var resultado []domain.Politico
-
-	for _, p := range allPoliticos {
-		// Filtro por nome
-		if filtros.Nome != "" {
-			nome := strings.ToLower(p.Nome)
-			nomeCivil := strings.ToLower(p.NomeCivil)
-			termo := strings.ToLower(filtros.Nome)
-			if !strings.Contains(nome, termo) && !strings.Contains(nomeCivil, termo) {
-				continue
-			}
-		}
-
-		// Filtro por partido
-		if len(filtros.Partido) > 0 {
-			found := false
-			for _, partido := range filtros.Partido {
-				if p.Partido.Sigla == partido {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-
-		// Filtro por cargo
-		if len(filtros.Cargo) > 0 {
-			found := false
-			for _, cargo := range filtros.Cargo {
-				if p.CargoAtual.Tipo == cargo {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-
-		// Filtro por esfera
-		if len(filtros.Esfera) > 0 {
-			found := false
-			for _, esfera := range filtros.Esfera {
-				if p.CargoAtual.Esfera == esfera {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-
-		// Filtro por estado
-		if len(filtros.Estado) > 0 {
-			found := false
-			for _, estado := range filtros.Estado {
-				if p.CargoAtual.Estado == estado {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-
-		// Filtro por status (em exercício)
-		if filtros.EmExercicio != nil {
-			if p.CargoAtual.EmExercicio != *filtros.EmExercicio {
-				continue
-			}
-		}
-
-		// Filtro por gênero
-		if len(filtros.Genero) > 0 {
-			found := false
-			for _, genero := range filtros.Genero {
-				if p.Genero == genero {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-
-		resultado = append(resultado, p)
-	}
-
-	// Paginação
-	pagina := filtros.Pagina
-	if pagina < 1 {
-		pagina = 1
-	}
-	porPagina := filtros.PorPagina
-	if porPagina < 1 || porPagina > 100 {
-		porPagina = 12
-	}
-
-	total := int64(len(resultado))
-	start := (pagina - 1) * porPagina
-	end := start + porPagina
+func (s *PoliticoService) BuscarPorID(ctx context.Context, id string) (*domain.Politico, error) {
+	return s.politicoRepo.BuscarPorID(ctx, id)
+}
 
-	if start > len(resultado) {
-		start = len(resultado)
-	}
-	if end > len(resultado) {
-		end = len(resultado)
+// HistoricoPartidos retorna a linha do tempo de filiações do político. Políticos sincronizados
+// antes do registro do histórico recebem apenas a filiação atual.
+func (s *PoliticoService) HistoricoPartidos(ctx context.Context, id string) ([]domain.FiliacaoPartidaria, error) {
+	politico, err := s.BuscarPorID(ctx, id)
+	if err != nil || politico == nil {
+		return nil, err
 	}
 
-	paginado := resultado[start:end]
-
-	totalPaginas := int(total) / porPagina
-	if int(total)%porPagina > 0 {
-		totalPaginas++
+	if len(politico.HistoricoPartidos) > 0 {
+		return politico.HistoricoPartidos, nil
 	}
-
-	return &domain.PaginatedResponse[domain.Politico]{
-		Data:         paginado,
-		Total:        total,
-		Pagina:       pagina,
-		PorPagina:    porPagina,
-		TotalPaginas: totalPaginas,
+	if politico.Partido.Sigla == "" {
+		return []domain.FiliacaoPartidaria{}, nil
 	}
-}
-
-func (s *PoliticoService) BuscarPorID(ctx context.Context, id string) (*domain.Politico, error) {
-	if s.debug {
-		p := mock.GetPoliticoByID(id)
-		if p == nil {
-			return nil, nil
-		}
-		return p, nil
-	}
-	return s.politicoRepo.BuscarPorID(ctx, id)
+	return []domain.FiliacaoPartidaria{{
+		Partido:    politico.Partido,
+		DataInicio: politico.CreatedAt,
+	}}, nil
 }
 
 func (s *PoliticoService) BuscarPorIDs(ctx context.Context, ids []string) ([]domain.Politico, error) {
-	if s.debug {
-		var result []domain.Politico
-		for _, id := range ids {
-			if p := mock.GetPoliticoByID(id); p != nil {
-				result = append(result, *p)
-			}
-		}
-		return result, nil
-	}
 	return s.politicoRepo.BuscarPorIDs(ctx, ids)
 }
 
@@ -201,30 +71,10 @@ func (s *PoliticoService) Buscar(ctx context.Context, query string, limite int)
 		limite = 10
 	}
 
-	if s.debug {
-		var result []domain.Politico
-		termo := strings.ToLower(query)
-		for _, p := range mock.Politicos() {
-			if strings.Contains(strings.ToLower(p.Nome), termo) ||
-				strings.Contains(strings.ToLower(p.NomeCivil), termo) {
-				result = append(result, p)
-				if len(result) >= limite {
-					break
-				}
-			}
-		}
-		return result, nil
-	}
-
 	return s.politicoRepo.Buscar(ctx, query, limite)
 }
 
 func (s *PoliticoService) BuscarEstatisticas(ctx context.Context, id string) (*domain.EstatisticasPolitico, error) {
-	if s.debug {
-		stats := mock.GetEstatisticasByID(id)
-		return stats, nil
-	}
-
 	// Contar votos
 	votosCounts, err := s.votacaoRepo.ContarPorPolitico(ctx, id)
 	if err != nil {
@@ -274,44 +124,20 @@ func (s *PoliticoService) BuscarEstatisticas(ctx context.Context, id string) (*d
 	}, nil
 }
 
-func (s *PoliticoService) ListarVotacoes(ctx context.Context, politicoID string, pagina, porPagina int) (*domain.PaginatedResponse[domain.Votacao], error) {
-	if s.debug {
-		// Retorna lista vazia em modo debug (votações são complexas de mockar)
-		return &domain.PaginatedResponse[domain.Votacao]{
-			Data:         []domain.Votacao{},
-			Total:        0,
-			Pagina:       1,
-			PorPagina:    porPagina,
-			TotalPaginas: 0,
-		}, nil
-	}
-	return s.votacaoRepo.ListarPorPolitico(ctx, politicoID, pagina, porPagina)
+func (s *PoliticoService) ListarVotacoes(ctx context.Context, politicoID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.VotacaoComProposicao], error) {
+	return s.votacaoRepo.ListarPorPolitico(ctx, politicoID, paginacao, incluir)
 }
 
-func (s *PoliticoService) ListarDespesas(ctx context.Context, politicoID string, ano, mes *int, pagina, porPagina int) (*domain.PaginatedResponse[domain.Despesa], error) {
-	if s.debug {
-		return &domain.PaginatedResponse[domain.Despesa]{
-			Data:         []domain.Despesa{},
-			Total:        0,
-			Pagina:       1,
-			PorPagina:    porPagina,
-			TotalPaginas: 0,
-		}, nil
-	}
-	return s.despesaRepo.ListarPorPolitico(ctx, politicoID, ano, mes, pagina, porPagina)
+func (s *PoliticoService) ListarDespesas(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Despesa], error) {
+	return s.despesaRepo.ListarPorPolitico(ctx, politicoID, ano, mes, paginacao)
 }
 
-func (s *PoliticoService) ListarProposicoes(ctx context.Context, politicoID string, pagina, porPagina int) (*domain.PaginatedResponse[domain.Proposicao], error) {
-	if s.debug {
-		return &domain.PaginatedResponse[domain.Proposicao]{
-			Data:         []domain.Proposicao{},
-			Total:        0,
-			Pagina:       1,
-			PorPagina:    porPagina,
-			TotalPaginas: 0,
-		}, nil
-	}
-	return s.proposicaoRepo.ListarPorAutor(ctx, politicoID, pagina, porPagina)
+func (s *PoliticoService) ListarProposicoes(ctx context.Context, politicoID string, paginacao domain.Paginacao, incluir domain.Inclusoes) (*domain.PaginatedResponse[domain.ProposicaoComAutor], error) {
+	return s.proposicaoRepo.ListarPorAutor(ctx, politicoID, paginacao, incluir)
+}
+
+func (s *PoliticoService) ListarPresencas(ctx context.Context, politicoID string, ano, mes *int, paginacao domain.Paginacao) (*domain.PaginatedResponse[domain.Presenca], error) {
+	return s.presencaRepo.ListarPorPolitico(ctx, politicoID, ano, mes, paginacao)
 }
 
 func (s *PoliticoService) Comparar(ctx context.Context, ids []string) (map[string]interface{}, error) {
@@ -329,49 +155,167 @@ func (s *PoliticoService) Comparar(ctx context.Context, ids []string) (map[strin
 		estatisticas[p.ID.Hex()] = stats
 	}
 
+	alinhamento, err := s.matrizAlinhamento(ctx, politicos)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
 		"politicos":    politicos,
 		"estatisticas": estatisticas,
+		"alinhamento":  alinhamento,
 	}, nil
 }
 
-func (s *PoliticoService) ContarPoliticos(ctx context.Context) (int64, error) {
-	if s.debug {
-		return int64(len(mock.Politicos())), nil
+// matrizAlinhamento calcula a concordância de votos entre cada par de políticos comparados
+func (s *PoliticoService) matrizAlinhamento(ctx context.Context, politicos []domain.Politico) (map[string]map[string]domain.AlinhamentoPolitico, error) {
+	matriz := make(map[string]map[string]domain.AlinhamentoPolitico)
+	votos := make(map[string]map[string]domain.TipoVoto)
+	for _, p := range politicos {
+		v, err := s.votacaoRepo.VotosPorPolitico(ctx, p.ID.Hex(), nil)
+		if err != nil {
+			return nil, err
+		}
+		votos[p.ID.Hex()] = v
 	}
-	return s.politicoRepo.Contar(ctx)
+
+	for _, a := range politicos {
+		linha := make(map[string]domain.AlinhamentoPolitico)
+		for _, b := range politicos {
+			if a.ID == b.ID {
+				continue
+			}
+			comum, concordancias := compararVotos(votos[a.ID.Hex()], votos[b.ID.Hex()])
+			linha[b.ID.Hex()] = domain.AlinhamentoPolitico{
+				PoliticoID:       b.ID,
+				VotacoesEmComum:  comum,
+				Concordancias:    concordancias,
+				PercentualAcordo: percentualAcordo(concordancias, comum),
+			}
+		}
+		matriz[a.ID.Hex()] = linha
+	}
+
+	return matriz, nil
 }
 
-func (s *PoliticoService) ContarVotacoes(ctx context.Context) (int64, error) {
-	if s.debug {
-		// Soma todas as votações mockadas
-		var total int64
-		for _, stats := range mock.Estatisticas() {
-			total += int64(stats.TotalVotacoes)
+// BuscarSimilares lista os colegas com maior e menor concordância nas votações em comum.
+// Só entram políticos com pelo menos `minimo` votações em comum, para evitar percentuais sem significado.
+func (s *PoliticoService) BuscarSimilares(ctx context.Context, id string, ano *int, minimo, limite int) (*domain.PoliticosSimilares, error) {
+	if minimo < 1 {
+		minimo = 10
+	}
+	if limite < 1 || limite > 50 {
+		limite = 10
+	}
+
+	similares := &domain.PoliticosSimilares{
+		MaisAlinhados:  []domain.AlinhamentoPolitico{},
+		MenosAlinhados: []domain.AlinhamentoPolitico{},
+	}
+
+	todos, err := s.votacaoRepo.Concordancia(ctx, id, ano)
+	if err != nil {
+		return nil, err
+	}
+
+	var alinhamentos []domain.AlinhamentoPolitico
+	for _, a := range todos {
+		if a.VotacoesEmComum < minimo {
+			continue
 		}
-		return total, nil
+		a.PercentualAcordo = percentualAcordo(a.Concordancias, a.VotacoesEmComum)
+		alinhamentos = append(alinhamentos, a)
 	}
-	return s.votacaoRepo.Contar(ctx)
+
+	sort.Slice(alinhamentos, func(i, j int) bool {
+		if alinhamentos[i].PercentualAcordo != alinhamentos[j].PercentualAcordo {
+			return alinhamentos[i].PercentualAcordo > alinhamentos[j].PercentualAcordo
+		}
+		return alinhamentos[i].VotacoesEmComum > alinhamentos[j].VotacoesEmComum
+	})
+
+	// Com menos de 2×limite colegas, eles são divididos ao meio (o do meio fica entre os mais
+	// alinhados), para que ninguém apareça nas duas listas
+	n := limite
+	if metade := (len(alinhamentos) + 1) / 2; n > metade {
+		n = metade
+	}
+	similares.MaisAlinhados = append(similares.MaisAlinhados, alinhamentos[:n]...)
+	fim := len(alinhamentos) - limite
+	if fim < n {
+		fim = n
+	}
+	for i := len(alinhamentos) - 1; i >= fim; i-- {
+		similares.MenosAlinhados = append(similares.MenosAlinhados, alinhamentos[i])
+	}
+
+	// Completar com nome, partido e estado dos colegas listados
+	ids := make([]string, 0, len(similares.MaisAlinhados)+len(similares.MenosAlinhados))
+	for _, a := range similares.MaisAlinhados {
+		ids = append(ids, a.PoliticoID.Hex())
+	}
+	for _, a := range similares.MenosAlinhados {
+		ids = append(ids, a.PoliticoID.Hex())
+	}
+
+	politicos, err := s.politicoRepo.BuscarPorIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	porID := make(map[string]domain.Politico, len(politicos))
+	for _, p := range politicos {
+		porID[p.ID.Hex()] = p
+	}
+
+	for _, lista := range [][]domain.AlinhamentoPolitico{similares.MaisAlinhados, similares.MenosAlinhados} {
+		for i := range lista {
+			if p, ok := porID[lista[i].PoliticoID.Hex()]; ok {
+				lista[i].Nome = p.Nome
+				lista[i].Partido = p.Partido.Sigla
+				lista[i].Estado = p.CargoAtual.Estado
+			}
+		}
+	}
+
+	return similares, nil
 }
 
-func (s *PoliticoService) ContarProposicoes(ctx context.Context) (int64, error) {
-	if s.debug {
-		var total int64
-		for _, stats := range mock.Estatisticas() {
-			total += int64(stats.TotalProposicoes)
+// compararVotos conta as votações em que ambos votaram e em quantas votaram igual
+func compararVotos(a, b map[string]domain.TipoVoto) (comum, concordancias int) {
+	for votacaoID, votoA := range a {
+		votoB, ok := b[votacaoID]
+		if !ok {
+			continue
+		}
+		comum++
+		if votoA == votoB {
+			concordancias++
 		}
-		return total, nil
 	}
+	return comum, concordancias
+}
+
+func percentualAcordo(concordancias, comum int) float64 {
+	if comum == 0 {
+		return 0
+	}
+	return float64(concordancias) / float64(comum) * 100
+}
+
+func (s *PoliticoService) ContarPoliticos(ctx context.Context) (int64, error) {
+	return s.politicoRepo.Contar(ctx)
+}
+
+func (s *PoliticoService) ContarVotacoes(ctx context.Context) (int64, error) {
+	return s.votacaoRepo.Contar(ctx)
+}
+
+func (s *PoliticoService) ContarProposicoes(ctx context.Context) (int64, error) {
 	return s.proposicaoRepo.Contar(ctx)
 }
 
 func (s *PoliticoService) TotalDespesas(ctx context.Context) (float64, error) {
-	if s.debug {
-		var total float64
-		for _, stats := range mock.Estatisticas() {
-			total += stats.TotalDespesas
-		}
-		return total, nil
-	}
 	return s.despesaRepo.TotalGeral(ctx)
 }
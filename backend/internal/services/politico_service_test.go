@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func novoPoliticoService(dados mock.Dados) *PoliticoService {
+	banco := memoria.NewBanco(dados)
+	return NewPoliticoService(
+		memoria.NewPoliticoRepository(banco),
+		memoria.NewVotacaoRepository(banco),
+		memoria.NewDespesaRepository(banco),
+		memoria.NewProposicaoRepository(banco),
+		memoria.NewPresencaRepository(banco),
+	)
+}
+
+// Os registros gerados pelo mock devem reproduzir as estatísticas de que partem
+func TestBuscarEstatisticasDadosMockados(t *testing.T) {
+	s := novoPoliticoService(mock.Todos())
+	ctx := context.Background()
+
+	for id, esperado := range mock.Estatisticas() {
+		stats, err := s.BuscarEstatisticas(ctx, id)
+		if err != nil {
+			t.Fatalf("BuscarEstatisticas(%s): %v", id, err)
+		}
+		if stats.TotalVotacoes != esperado.TotalVotacoes || stats.VotosSim != esperado.VotosSim ||
+			stats.VotosNao != esperado.VotosNao || stats.Abstencoes != esperado.Abstencoes || stats.Ausencias != esperado.Ausencias {
+			t.Errorf("%s: votos = %+v; esperado %+v", id, stats, esperado)
+		}
+		if stats.TotalProposicoes != esperado.TotalProposicoes || stats.ProposicoesAprovadas != esperado.ProposicoesAprovadas {
+			t.Errorf("%s: proposições = %d/%d; esperado %d/%d", id, stats.ProposicoesAprovadas, stats.TotalProposicoes, esperado.ProposicoesAprovadas, esperado.TotalProposicoes)
+		}
+		if math.Abs(stats.TotalDespesas-esperado.TotalDespesas) > 0.01 || math.Abs(stats.MediaGastoMensal-esperado.MediaGastoMensal) > 0.01 {
+			t.Errorf("%s: despesas = %.2f (média %.2f); esperado %.2f (média %.2f)", id, stats.TotalDespesas, stats.MediaGastoMensal, esperado.TotalDespesas, esperado.MediaGastoMensal)
+		}
+	}
+}
+
+func TestListarAtividadesPaginadas(t *testing.T) {
+	dados := mock.Todos()
+	s := novoPoliticoService(dados)
+	ctx := context.Background()
+	id := dados.Politicos[0].ID.Hex()
+
+	votos, err := s.ListarVotacoes(ctx, id, domain.Paginacao{Pagina: 2, PorPagina: 100}, domain.Inclusoes{})
+	if err != nil {
+		t.Fatalf("ListarVotacoes: %v", err)
+	}
+	if votos.Total != 245 || votos.TotalPaginas != 3 || len(votos.Data) != 100 {
+		t.Errorf("votações: total %d, %d páginas, %d na página", votos.Total, votos.TotalPaginas, len(votos.Data))
+	}
+	if !votos.Data[0].Data.After(votos.Data[99].Data) {
+		t.Error("votações deveriam vir das mais recentes para as mais antigas")
+	}
+
+	ano, mes := 2024, 3
+	despesas, err := s.ListarDespesas(ctx, id, &ano, &mes, domain.Paginacao{Pagina: 1})
+	if err != nil {
+		t.Fatalf("ListarDespesas: %v", err)
+	}
+	var doMes float64
+	for _, d := range despesas.Data {
+		if d.MesReferencia != mes {
+			t.Errorf("despesa de %d/%d no filtro de %d/%d", d.MesReferencia, d.AnoReferencia, mes, ano)
+		}
+		doMes += d.Valor
+	}
+	if despesas.PorPagina != 20 || math.Abs(doMes-13000) > 0.01 {
+		t.Errorf("despesas de março: %.2f em %d por página; esperado 13000.00 em 20", doMes, despesas.PorPagina)
+	}
+
+	presencas, err := s.ListarPresencas(ctx, id, nil, nil, domain.Paginacao{Pagina: 1})
+	if err != nil {
+		t.Fatalf("ListarPresencas: %v", err)
+	}
+	if presencas.Total != 245 || presencas.PorPagina != 50 {
+		t.Errorf("presenças: total %d, %d por página", presencas.Total, presencas.PorPagina)
+	}
+
+	if _, err := s.ListarProposicoes(ctx, "nao-e-um-id", domain.Paginacao{Pagina: 1, PorPagina: 20}, domain.Inclusoes{}); err == nil {
+		t.Error("ListarProposicoes com ID inválido deveria falhar")
+	}
+}
+
+func TestBuscarPorIDInexistente(t *testing.T) {
+	s := novoPoliticoService(mock.Todos())
+
+	_, err := s.BuscarPorID(context.Background(), primitive.NewObjectID().Hex())
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("erro = %v; esperado mongo.ErrNoDocuments, como no MongoDB", err)
+	}
+}
+
+func TestBuscarSimilares(t *testing.T) {
+	ana := domain.Politico{ID: primitive.NewObjectID(), Nome: "Ana", Partido: domain.Partido{Sigla: "PT"}, CargoAtual: domain.CargoAtual{Estado: "SP"}}
+	bia := domain.Politico{ID: primitive.NewObjectID(), Nome: "Bia", Partido: domain.Partido{Sigla: "PSB"}, CargoAtual: domain.CargoAtual{Estado: "PE"}}
+	caio := domain.Politico{ID: primitive.NewObjectID(), Nome: "Caio", Partido: domain.Partido{Sigla: "PL"}, CargoAtual: domain.CargoAtual{Estado: "RJ"}}
+
+	// Bia vota sempre com Ana; Caio só concorda em 1 de 4 votações, e a ausência dele não conta
+	votar := func(p domain.Politico, votos ...domain.TipoVoto) []domain.Votacao {
+		var v []domain.Votacao
+		for i, voto := range votos {
+			v = append(v, domain.Votacao{
+				PoliticoID:       p.ID,
+				VotacaoIDExterno: string(rune('a' + i)),
+				Voto:             voto,
+				Data:             time.Date(2024, 3, i+1, 0, 0, 0, 0, time.UTC),
+			})
+		}
+		return v
+	}
+	sim, nao, ausente := domain.VotoSim, domain.VotoNao, domain.VotoAusente
+
+	dados := mock.Dados{Politicos: []domain.Politico{ana, bia, caio}}
+	dados.Votacoes = append(dados.Votacoes, votar(ana, sim, sim, nao, sim, sim)...)
+	dados.Votacoes = append(dados.Votacoes, votar(bia, sim, sim, nao, sim, sim)...)
+	dados.Votacoes = append(dados.Votacoes, votar(caio, nao, nao, nao, nao, ausente)...)
+
+	s := novoPoliticoService(dados)
+	similares, err := s.BuscarSimilares(context.Background(), ana.ID.Hex(), nil, 4, 1)
+	if err != nil {
+		t.Fatalf("BuscarSimilares: %v", err)
+	}
+
+	if len(similares.MaisAlinhados) != 1 || similares.MaisAlinhados[0].PoliticoID != bia.ID {
+		t.Fatalf("mais alinhados = %+v; esperado Bia", similares.MaisAlinhados)
+	}
+	if a := similares.MaisAlinhados[0]; a.PercentualAcordo != 100 || a.VotacoesEmComum != 5 || a.Nome != "Bia" || a.Partido != "PSB" {
+		t.Errorf("alinhamento com Bia = %+v", a)
+	}
+
+	if len(similares.MenosAlinhados) != 1 || similares.MenosAlinhados[0].PoliticoID != caio.ID {
+		t.Fatalf("menos alinhados = %+v; esperado Caio", similares.MenosAlinhados)
+	}
+	if a := similares.MenosAlinhados[0]; a.PercentualAcordo != 25 || a.VotacoesEmComum != 4 || a.Estado != "RJ" {
+		t.Errorf("alinhamento com Caio = %+v", a)
+	}
+
+	// Com menos de 2×limite colegas, as listas não se repetem
+	similares, err = s.BuscarSimilares(context.Background(), ana.ID.Hex(), nil, 4, 5)
+	if err != nil {
+		t.Fatalf("BuscarSimilares: %v", err)
+	}
+	if len(similares.MaisAlinhados) != 1 || len(similares.MenosAlinhados) != 1 || similares.MenosAlinhados[0].PoliticoID != caio.ID {
+		t.Errorf("com limite 5: mais %+v, menos %+v; esperados Bia e Caio", similares.MaisAlinhados, similares.MenosAlinhados)
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrRegraQualidadeInvalida = errors.New("regra de qualidade desconhecida")
+
+const (
+	limitePioresPadrao = 20
+	limitePioresMaximo = 100
+)
+
+// QualidadeService consulta os problemas de qualidade encontrados nas sincronizações
+type QualidadeService struct {
+	qualidadeRepo repository.Qualidade
+}
+
+func NewQualidadeService(qualidadeRepo repository.Qualidade) *QualidadeService {
+	return &QualidadeService{
+		qualidadeRepo: qualidadeRepo,
+	}
+}
+
+// Relatorio retorna o relatório de qualidade da execução; sem execução, o da mais recente
+// que registrou problemas
+func (s *QualidadeService) Relatorio(ctx context.Context, execucaoID string, regra domain.RegraQualidade, limite int) (*domain.RelatorioQualidade, error) {
+	if err := validarFiltrosQualidade(execucaoID, regra); err != nil {
+		return nil, err
+	}
+	if limite < 1 {
+		limite = limitePioresPadrao
+	}
+	if limite > limitePioresMaximo {
+		limite = limitePioresMaximo
+	}
+
+	if execucaoID == "" {
+		ultima, err := s.qualidadeRepo.UltimaExecucao(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ultima == "" {
+			return relatorioVazio(""), nil
+		}
+		execucaoID = ultima
+	}
+
+	return s.qualidadeRepo.Relatorio(ctx, execucaoID, regra, limite)
+}
+
+// Listar retorna os problemas de uma execução (a mais recente, se não informada),
+// opcionalmente de uma regra ou de um registro
+func (s *QualidadeService) Listar(ctx context.Context, execucaoID string, regra domain.RegraQualidade, registro string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ProblemaQualidade], error) {
+	if err := validarFiltrosQualidade(execucaoID, regra); err != nil {
+		return nil, err
+	}
+
+	vazio := &domain.PaginatedResponse[domain.ProblemaQualidade]{
+		Data:         []domain.ProblemaQualidade{},
+		Total:        0,
+		Pagina:       1,
+		PorPagina:    porPagina,
+		TotalPaginas: 0,
+	}
+	if execucaoID == "" {
+		ultima, err := s.qualidadeRepo.UltimaExecucao(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ultima == "" {
+			return vazio, nil
+		}
+		execucaoID = ultima
+	}
+
+	result, err := s.qualidadeRepo.Listar(ctx, execucaoID, regra, registro, pagina, porPagina)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.ProblemaQualidade{}
+	}
+	return result, nil
+}
+
+func validarFiltrosQualidade(execucaoID string, regra domain.RegraQualidade) error {
+	if execucaoID != "" && !primitive.IsValidObjectID(execucaoID) {
+		return ErrIDInvalido
+	}
+	if regra != "" && !regra.Valida() {
+		return ErrRegraQualidadeInvalida
+	}
+	return nil
+}
+
+func relatorioVazio(execucaoID string) *domain.RelatorioQualidade {
+	return &domain.RelatorioQualidade{
+		ExecucaoID: execucaoID,
+		PorRegra:   map[domain.RegraQualidade]int{},
+		Piores:     []domain.OfensorQualidade{},
+		GeradoEm:   time.Now(),
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	gosync "sync"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/agendador"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/sync/orquestrador"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrPedidoSyncInvalido = errors.New("pedido de sincronização inválido")
+	ErrSyncEmAndamento    = errors.New("já existe uma sincronização em andamento")
+	ErrSyncNaoExecutando  = errors.New("a sincronização não está em andamento")
+	ErrIndisponivelDebug  = errors.New("operação indisponível em modo debug")
+)
+
+// intervaloGravacao é de quanto em quanto tempo o andamento de um job é gravado no banco,
+// valendo como batimento, e o pedido de cancelamento é conferido
+const intervaloGravacao = 5 * time.Second
+
+// validadeBatimento é quanto tempo sem batimento faz uma execução ser considerada interrompida
+const validadeBatimento = 12 * intervaloGravacao
+
+// jobSync é uma sincronização rodando neste processo
+type jobSync struct {
+	acomp    *orquestrador.Acompanhamento
+	cancelar context.CancelFunc
+}
+
+// SyncService dispara sincronizações em segundo plano a partir da API e acompanha o andamento.
+// Roda uma sincronização por vez por instância. Entre instâncias e workers, a trava de cada
+// entidade impede que a mesma entidade seja sincronizada por duas execuções ao mesmo tempo.
+// Sem orquestrador (no modo debug, sem MongoDB), o histórico pode ser consultado, mas
+// nenhuma sincronização é iniciada.
+type SyncService struct {
+	execucaoRepo repository.ExecucoesSync
+	orquestrador *orquestrador.Orquestrador
+	trava        *agendador.Trava
+
+	mu    gosync.Mutex
+	ativo map[primitive.ObjectID]*jobSync
+}
+
+func NewSyncService(execucaoRepo repository.ExecucoesSync, orq *orquestrador.Orquestrador, trava *agendador.Trava) *SyncService {
+	return &SyncService{
+		execucaoRepo: execucaoRepo,
+		orquestrador: orq,
+		trava:        trava,
+		ativo:        make(map[primitive.ObjectID]*jobSync),
+	}
+}
+
+// Iniciar valida o pedido e começa a sincronização em segundo plano
+func (s *SyncService) Iniciar(ctx context.Context, pedido domain.PedidoSync, iniciadaPor string) (*domain.ExecucaoSync, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ativo) > 0 {
+		return nil, ErrSyncEmAndamento
+	}
+
+	exec, err := s.novaExecucao(ctx, pedido, iniciadaPor, "")
+	if err != nil {
+		return nil, err
+	}
+
+	jobCtx, cancelar := context.WithCancel(context.Background())
+	job := &jobSync{acomp: orquestrador.NewAcompanhamento(*exec), cancelar: cancelar}
+	s.ativo[exec.ID] = job
+
+	go func() {
+		defer cancelar()
+		s.acompanhar(jobCtx, job.acomp, *exec)
+
+		s.mu.Lock()
+		delete(s.ativo, exec.ID)
+		s.mu.Unlock()
+	}()
+
+	return exec, nil
+}
+
+// Executar roda a sincronização até o fim na goroutine atual, gravando o andamento e o
+// resultado no histórico. É o que o agendador usa; o cancelamento vem do contexto.
+func (s *SyncService) Executar(ctx context.Context, pedido domain.PedidoSync, iniciadaPor, agendamento string) (*domain.ExecucaoSync, error) {
+	exec, err := s.novaExecucao(ctx, pedido, iniciadaPor, agendamento)
+	if err != nil {
+		return nil, err
+	}
+
+	acomp := orquestrador.NewAcompanhamento(*exec)
+	s.acompanhar(ctx, acomp, *exec)
+
+	final := acomp.Execucao()
+	return &final, nil
+}
+
+// novaExecucao valida o pedido, assume as travas das entidades e grava a execução pendente.
+// As travas ficam com a execução até acompanhar terminar.
+func (s *SyncService) novaExecucao(ctx context.Context, pedido domain.PedidoSync, iniciadaPor, agendamento string) (*domain.ExecucaoSync, error) {
+	if s.orquestrador == nil {
+		return nil, ErrIndisponivelDebug
+	}
+	if pedido.Ano == 0 {
+		pedido.Ano = time.Now().Year()
+	}
+	plano, err := orquestrador.Planejar(pedido)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPedidoSyncInvalido, err)
+	}
+
+	ocupada, err := s.trava.AdquirirEntidades(ctx, chavesEntidades(plano))
+	if err != nil {
+		return nil, err
+	}
+	if ocupada != "" {
+		return nil, fmt.Errorf("%w (%s)", ErrSyncEmAndamento, ocupada)
+	}
+
+	exec := domain.ExecucaoSync{
+		ID:          primitive.NewObjectID(),
+		Pedido:      pedido,
+		Status:      domain.StatusExecucaoPendente,
+		Etapas:      plano,
+		Erros:       []string{},
+		IniciadaPor: iniciadaPor,
+		Agendamento: agendamento,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.execucaoRepo.Salvar(ctx, exec); err != nil {
+		s.liberar(exec)
+		return nil, err
+	}
+	return &exec, nil
+}
+
+// chavesEntidades são os nomes das travas das entidades do plano
+func chavesEntidades(plano []domain.EtapaSync) []string {
+	chaves := make([]string, len(plano))
+	for i, e := range plano {
+		chaves[i] = agendador.ChaveEntidade(e.Fonte, e.Entidade)
+	}
+	return chaves
+}
+
+func (s *SyncService) liberar(exec domain.ExecucaoSync) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.trava.LiberarEntidades(ctx, chavesEntidades(exec.Etapas)); err != nil {
+		log.Printf("⚠️  Erro ao liberar as travas da sincronização %s: %v", exec.ID.Hex(), err)
+	}
+}
+
+// acompanhar executa as etapas gravando o andamento periodicamente e o resultado no fim.
+// A cada gravação confere se o cancelamento foi pedido no banco, por qualquer instância, e
+// renova as travas das entidades; se elas foram perdidas, cancela a execução.
+func (s *SyncService) acompanhar(ctx context.Context, acomp *orquestrador.Acompanhamento, exec domain.ExecucaoSync) {
+	ctx, cancelar := context.WithCancel(ctx)
+	defer cancelar()
+	defer s.liberar(exec)
+
+	acomp.Iniciar()
+	s.gravar(acomp.Execucao())
+
+	pronto := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(intervaloGravacao)
+		defer ticker.Stop()
+		renovadaEm := time.Now()
+		for {
+			select {
+			case <-ticker.C:
+				s.gravar(acomp.Execucao())
+				if s.cancelamentoSolicitado(exec.ID) {
+					log.Printf("🛑 Cancelamento solicitado da sincronização %s", exec.ID.Hex())
+					cancelar()
+				}
+				if err := s.renovar(exec); err == nil {
+					renovadaEm = time.Now()
+				} else if errors.Is(err, agendador.ErrTravaPerdida) || time.Since(renovadaEm) >= s.trava.Validade() {
+					log.Printf("🛑 Sincronização %s perdeu as travas das entidades: %v", exec.ID.Hex(), err)
+					cancelar()
+				}
+			case <-pronto:
+				return
+			}
+		}
+	}()
+
+	err := s.orquestrador.Executar(ctx, exec.ID.Hex(), exec.Pedido.Ano, exec.Etapas, acomp)
+	close(pronto)
+	acomp.Finalizar(err)
+	s.gravar(acomp.Execucao())
+}
+
+func (s *SyncService) gravar(exec domain.ExecucaoSync) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	agora := time.Now()
+	exec.BatimentoEm = &agora
+	if err := s.execucaoRepo.Salvar(ctx, exec); err != nil {
+		log.Printf("⚠️  Erro ao gravar andamento da sincronização %s: %v", exec.ID.Hex(), err)
+	}
+}
+
+func (s *SyncService) renovar(exec domain.ExecucaoSync) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := s.trava.RenovarEntidades(ctx, chavesEntidades(exec.Etapas))
+	if err != nil {
+		log.Printf("⚠️  Erro ao renovar as travas da sincronização %s: %v", exec.ID.Hex(), err)
+	}
+	return err
+}
+
+func (s *SyncService) cancelamentoSolicitado(id primitive.ObjectID) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	solicitado, err := s.execucaoRepo.CancelamentoSolicitado(ctx, id)
+	if err != nil {
+		log.Printf("⚠️  Erro ao conferir o cancelamento da sincronização %s: %v", id.Hex(), err)
+	}
+	return solicitado
+}
+
+// RecuperarInterrompidas marca como interrompidas as execuções deixadas em andamento por
+// processos que pararam sem registrar o fim (sem batimento há mais de validadeBatimento).
+// É chamado ao iniciar a API e o worker.
+func (s *SyncService) RecuperarInterrompidas(ctx context.Context) (int64, error) {
+	return s.execucaoRepo.MarcarInterrompidas(ctx, time.Now().Add(-validadeBatimento))
+}
+
+// BuscarPorID retorna o andamento da execução; as que rodam nesta instância vêm da memória
+func (s *SyncService) BuscarPorID(ctx context.Context, id string) (*domain.ExecucaoSync, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrIDInvalido
+	}
+
+	s.mu.Lock()
+	job, ok := s.ativo[objectID]
+	s.mu.Unlock()
+	if ok {
+		exec := job.acomp.Execucao()
+		return &exec, nil
+	}
+
+	return s.execucaoRepo.BuscarPorID(ctx, objectID)
+}
+
+// Cancelar interrompe uma sincronização em andamento. As desta instância são canceladas na
+// hora; as de outras instâncias e do worker, quando o processo que as executa vir o pedido
+// gravado no banco (em até intervaloGravacao). As etapas param assim que os itens em
+// processamento terminam.
+func (s *SyncService) Cancelar(ctx context.Context, id string) (*domain.ExecucaoSync, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrIDInvalido
+	}
+
+	s.mu.Lock()
+	job, ok := s.ativo[objectID]
+	s.mu.Unlock()
+	if !ok {
+		exec, err := s.execucaoRepo.SolicitarCancelamento(ctx, objectID)
+		if errors.Is(err, repository.ErrExecucaoNaoEncontrada) {
+			if _, err := s.execucaoRepo.BuscarPorID(ctx, objectID); err != nil {
+				return nil, err
+			}
+			return nil, ErrSyncNaoExecutando
+		}
+		return exec, err
+	}
+
+	job.cancelar()
+	exec := job.acomp.Execucao()
+	return &exec, nil
+}
+
+func (s *SyncService) Listar(ctx context.Context, status domain.StatusExecucao, agendamento string, pagina, porPagina int) (*domain.PaginatedResponse[domain.ExecucaoSync], error) {
+	result, err := s.execucaoRepo.Listar(ctx, status, agendamento, pagina, porPagina)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.ExecucaoSync{}
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sem orquestrador, como no modo debug, o histórico funciona em memória, mas nada é iniciado
+func TestSyncSemOrquestrador(t *testing.T) {
+	repo := memoria.NewExecucaoSyncRepository(memoria.NewBanco(mock.Dados{}))
+	s := NewSyncService(repo, nil, nil)
+	ctx := context.Background()
+
+	if _, err := s.Iniciar(ctx, domain.PedidoSync{Fonte: "camara"}, "teste"); !errors.Is(err, ErrIndisponivelDebug) {
+		t.Fatalf("Iniciar = %v; esperado ErrIndisponivelDebug", err)
+	}
+
+	antiga := time.Now().Add(-time.Hour)
+	parada := domain.ExecucaoSync{ID: primitive.NewObjectID(), Status: domain.StatusExecucaoExecutando, CreatedAt: antiga, BatimentoEm: &antiga}
+	rodando := domain.ExecucaoSync{ID: primitive.NewObjectID(), Status: domain.StatusExecucaoExecutando, CreatedAt: time.Now()}
+	for _, e := range []domain.ExecucaoSync{parada, rodando} {
+		if err := repo.Salvar(ctx, e); err != nil {
+			t.Fatalf("Salvar: %v", err)
+		}
+	}
+
+	if _, err := s.Cancelar(ctx, rodando.ID.Hex()); err != nil {
+		t.Fatalf("Cancelar: %v", err)
+	}
+	// A gravação seguinte do andamento não apaga o pedido de cancelamento
+	if err := repo.Salvar(ctx, rodando); err != nil {
+		t.Fatalf("Salvar: %v", err)
+	}
+	if solicitado, _ := repo.CancelamentoSolicitado(ctx, rodando.ID); !solicitado {
+		t.Error("cancelamento solicitado perdido na gravação do andamento")
+	}
+
+	if n, err := s.RecuperarInterrompidas(ctx); err != nil || n != 1 {
+		t.Fatalf("RecuperarInterrompidas = %d, %v; esperado 1", n, err)
+	}
+	if _, err := s.Cancelar(ctx, parada.ID.Hex()); !errors.Is(err, ErrSyncNaoExecutando) {
+		t.Errorf("Cancelar interrompida = %v; esperado ErrSyncNaoExecutando", err)
+	}
+
+	result, err := s.Listar(ctx, domain.StatusExecucaoInterrompida, "", 1, 20)
+	if err != nil {
+		t.Fatalf("Listar: %v", err)
+	}
+	if result.Total != 1 || result.Data[0].ID != parada.ID {
+		t.Errorf("interrompidas = %+v; esperado só a parada", result.Data)
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrIDInvalido        = errors.New("ID inválido")
+	ErrUnificacaoMesmoID = errors.New("principal e duplicado são o mesmo político")
+)
+
+type UnificacaoService struct {
+	unificacaoRepo repository.Unificacoes
+}
+
+func NewUnificacaoService(unificacaoRepo repository.Unificacoes) *UnificacaoService {
+	return &UnificacaoService{
+		unificacaoRepo: unificacaoRepo,
+	}
+}
+
+// Unificar incorpora o cadastro duplicado ao principal
+func (s *UnificacaoService) Unificar(ctx context.Context, principalID, duplicadoID, motivo string) (*domain.Unificacao, error) {
+	principal, err := primitive.ObjectIDFromHex(principalID)
+	if err != nil {
+		return nil, ErrIDInvalido
+	}
+	duplicado, err := primitive.ObjectIDFromHex(duplicadoID)
+	if err != nil {
+		return nil, ErrIDInvalido
+	}
+	if principal == duplicado {
+		return nil, ErrUnificacaoMesmoID
+	}
+
+	return s.unificacaoRepo.Unificar(ctx, principal, duplicado, motivo)
+}
+
+// Desfazer separa novamente os cadastros de uma unificação ativa
+func (s *UnificacaoService) Desfazer(ctx context.Context, id string) (*domain.Unificacao, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrIDInvalido
+	}
+
+	return s.unificacaoRepo.Desfazer(ctx, objectID)
+}
+
+func (s *UnificacaoService) Listar(ctx context.Context, status domain.StatusUnificacao, pagina, porPagina int) (*domain.PaginatedResponse[domain.Unificacao], error) {
+	result, err := s.unificacaoRepo.Listar(ctx, status, pagina, porPagina)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.Unificacao{}
+	}
+	return result, nil
+}
+
+// ListarRevisoes retorna a fila de casos ambíguos deixados pelas sincronizações
+func (s *UnificacaoService) ListarRevisoes(ctx context.Context, status domain.StatusRevisao, pagina, porPagina int) (*domain.PaginatedResponse[domain.RevisaoIdentidade], error) {
+	result, err := s.unificacaoRepo.ListarRevisoes(ctx, status, pagina, porPagina)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		result.Data = []domain.RevisaoIdentidade{}
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mock"
+	"github.com/lupa-cidada/backend/internal/repository"
+	"github.com/lupa-cidada/backend/internal/repository/memoria"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Em memória, a unificação move os registros, descarta os repetidos e desfaz tudo, como no MongoDB
+func TestUnificarEDesfazerEmMemoria(t *testing.T) {
+	principal := domain.Politico{ID: primitive.NewObjectID(), Nome: "Fulano", CPF: "12345678909"}
+	duplicado := domain.Politico{ID: primitive.NewObjectID(), Nome: "Fulano de Tal", IDExternoCamara: 204554}
+	data := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	dados := mock.Dados{
+		Politicos: []domain.Politico{principal, duplicado},
+		Votacoes: []domain.Votacao{
+			{ID: primitive.NewObjectID(), PoliticoID: principal.ID, VotacaoIDExterno: "1", Voto: domain.VotoSim, Data: data},
+			{ID: primitive.NewObjectID(), PoliticoID: duplicado.ID, VotacaoIDExterno: "1", Voto: domain.VotoSim, Data: data},
+			{ID: primitive.NewObjectID(), PoliticoID: duplicado.ID, VotacaoIDExterno: "2", Voto: domain.VotoNao, Data: data},
+		},
+	}
+	banco := memoria.NewBanco(dados)
+	s := NewUnificacaoService(memoria.NewUnificacaoRepository(banco))
+	politicos := NewPoliticoService(memoria.NewPoliticoRepository(banco), memoria.NewVotacaoRepository(banco),
+		memoria.NewDespesaRepository(banco), memoria.NewProposicaoRepository(banco), memoria.NewPresencaRepository(banco))
+	ctx := context.Background()
+
+	votos := func(id primitive.ObjectID) int64 {
+		result, err := politicos.ListarVotacoes(ctx, id.Hex(), domain.Paginacao{}, domain.Inclusoes{})
+		if err != nil {
+			t.Fatalf("ListarVotacoes: %v", err)
+		}
+		return result.Total
+	}
+
+	unificacao, err := s.Unificar(ctx, principal.ID.Hex(), duplicado.ID.Hex(), "mesmo CPF")
+	if err != nil {
+		t.Fatalf("Unificar: %v", err)
+	}
+	if n := votos(principal.ID); n != 2 {
+		t.Errorf("principal com %d votos; esperado 2, sem o voto repetido", n)
+	}
+	if len(unificacao.Referencias.Repetidos.Votacoes) != 1 || len(unificacao.Referencias.CamposCopiados) != 1 {
+		t.Errorf("referências = %+v", unificacao.Referencias)
+	}
+	if p, _ := politicos.BuscarPorID(ctx, duplicado.ID.Hex()); p != nil {
+		t.Error("o duplicado deveria ter sido removido")
+	}
+
+	if _, err := s.Desfazer(ctx, unificacao.ID.Hex()); err != nil {
+		t.Fatalf("Desfazer: %v", err)
+	}
+	if a, b := votos(principal.ID), votos(duplicado.ID); a != 1 || b != 2 {
+		t.Errorf("depois de desfazer: %d e %d votos; esperado 1 e 2", a, b)
+	}
+	p, err := politicos.BuscarPorID(ctx, principal.ID.Hex())
+	if err != nil || p.IDExternoCamara != 0 {
+		t.Errorf("o principal deveria devolver o ID da Câmara: %+v, %v", p, err)
+	}
+	if _, err := s.Desfazer(ctx, unificacao.ID.Hex()); !errors.Is(err, repository.ErrUnificacaoDesfeita) {
+		t.Errorf("desfazer de novo: %v", err)
+	}
+}
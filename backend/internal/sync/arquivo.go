@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	gosync "sync"
+	"time"
+)
+
+// ErrNaoArquivado indica que não há resposta arquivada para o endereço
+var ErrNaoArquivado = errors.New("resposta não arquivada")
+
+// Arquivo guarda as respostas brutas das APIs, para que as sincronizações possam ser
+// refeitas sem acessar a rede (ex.: depois de mudar o mapeamento dos dados).
+// As implementações ficam em internal/sync/arquivo.
+type Arquivo interface {
+	// Guardar arquiva o corpo da resposta do endereço, coletada no instante informado
+	Guardar(ctx context.Context, url string, coletadoEm time.Time, corpo []byte) error
+	// Buscar retorna a resposta mais recente do endereço coletada até o dia de ate, com o
+	// instante em que foi coletada, ou ErrNaoArquivado
+	Buscar(ctx context.Context, url string, ate time.Time) ([]byte, time.Time, error)
+}
+
+type chaveArquivo struct{}
+
+type chaveReproducao struct{}
+
+// reproducao é a leitura das respostas arquivadas até uma data
+type reproducao struct {
+	arquivo Arquivo
+	ate     time.Time
+	coletas *coletas
+}
+
+// coletas guarda quando foram coletadas as respostas lidas do arquivo, para que as fontes
+// dos documentos reproduzidos tenham a data da coleta original
+type coletas struct {
+	mu     gosync.Mutex
+	porURL map[string]time.Time
+	ultima time.Time
+}
+
+func (c *coletas) registrar(url string, coletadoEm time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.porURL[url] = coletadoEm
+	if coletadoEm.After(c.ultima) {
+		c.ultima = coletadoEm
+	}
+}
+
+// de retorna quando foi coletada a resposta do endereço. Fontes que não correspondem a uma
+// requisição (ex.: endereço sem os parâmetros de paginação) ficam com a coleta mais recente
+// lida até agora, e sem nenhuma, com o fim da reprodução.
+func (c *coletas) de(url string, ate time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.porURL[url]; ok {
+		return t
+	}
+	if !c.ultima.IsZero() {
+		return c.ultima
+	}
+	return ate
+}
+
+// ComArquivo faz o HTTPClient arquivar as respostas das requisições feitas com o contexto
+func ComArquivo(ctx context.Context, a Arquivo) context.Context {
+	return context.WithValue(ctx, chaveArquivo{}, a)
+}
+
+// ComReproducao faz o HTTPClient responder as requisições feitas com o contexto a partir do
+// arquivo, sem acessar a rede, usando a resposta mais recente coletada até o dia de ate
+func ComReproducao(ctx context.Context, a Arquivo, ate time.Time) context.Context {
+	return context.WithValue(ctx, chaveReproducao{}, reproducao{
+		arquivo: a,
+		ate:     ate,
+		coletas: &coletas{porURL: map[string]time.Time{}},
+	})
+}
+
+// EmReproducao indica se o contexto está reproduzindo respostas arquivadas
+func EmReproducao(ctx context.Context) bool {
+	_, ok := ctx.Value(chaveReproducao{}).(reproducao)
+	return ok
+}
+
+func arquivoDe(ctx context.Context) Arquivo {
+	a, _ := ctx.Value(chaveArquivo{}).(Arquivo)
+	return a
+}
+
+func reproducaoDe(ctx context.Context) (reproducao, bool) {
+	r, ok := ctx.Value(chaveReproducao{}).(reproducao)
+	return r, ok
+}
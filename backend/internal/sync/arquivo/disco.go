@@ -0,0 +1,230 @@
+// Package arquivo guarda as respostas brutas das APIs consultadas pelas sincronizações.
+// O conteúdo é endereçado pelo SHA-256 do corpo, então respostas iguais coletadas em dias
+// diferentes ocupam espaço uma vez só; o índice aponta, para cada endereço e dia, a
+// resposta coletada por último.
+package arquivo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/sync"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DestinoGridFS guarda o arquivo no MongoDB em vez do disco
+const DestinoGridFS = "gridfs"
+
+// formatoDia é a chave de data do índice; os dias são contados no horário de Brasília,
+// como as cotas e os agendamentos
+const formatoDia = "2006-01-02"
+
+// fuso é o horário em que os dias do índice viram
+var fuso = carregarFuso()
+
+func carregarFuso() *time.Location {
+	if loc, err := time.LoadLocation("America/Sao_Paulo"); err == nil {
+		return loc
+	}
+	return time.FixedZone("BRT", -3*60*60)
+}
+
+// Abrir retorna o arquivo do destino: "gridfs" para o banco ou o caminho de um diretório.
+// Sem destino, retorna nil (arquivo desativado).
+func Abrir(db *mongo.Database, destino string) (sync.Arquivo, error) {
+	switch destino {
+	case "":
+		return nil, nil
+	case DestinoGridFS:
+		g, err := NewGridFS(db)
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	default:
+		d, err := NewDisco(destino)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+}
+
+// Disco guarda o arquivo em um diretório:
+//
+//	objetos/ab/abcdef….json.gz      corpo das respostas, pelo SHA-256
+//	indice/12/1234…/url             endereço original
+//	indice/12/1234…/2024-05-01      SHA-256 da resposta coletada por último no dia e, na
+//	                                linha seguinte, o instante da coleta (RFC 3339)
+type Disco struct {
+	dir string
+}
+
+func NewDisco(dir string) (*Disco, error) {
+	if dir == "" {
+		return nil, errors.New("diretório do arquivo não informado")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Disco{dir: dir}, nil
+}
+
+func (d *Disco) Guardar(_ context.Context, url string, coletadoEm time.Time, corpo []byte) error {
+	hash := Hash(corpo)
+
+	objeto := d.caminhoObjeto(hash)
+	if _, err := os.Stat(objeto); errors.Is(err, fs.ErrNotExist) {
+		compactado, err := compactar(corpo)
+		if err != nil {
+			return err
+		}
+		if err := gravarAtomico(objeto, compactado); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	indice := d.caminhoIndice(url)
+	if _, err := os.Stat(filepath.Join(indice, "url")); errors.Is(err, fs.ErrNotExist) {
+		if err := gravarAtomico(filepath.Join(indice, "url"), []byte(url)); err != nil {
+			return err
+		}
+	}
+	entrada := hash + "\n" + coletadoEm.Format(time.RFC3339Nano) + "\n"
+	return gravarAtomico(filepath.Join(indice, dia(coletadoEm)), []byte(entrada))
+}
+
+func (d *Disco) Buscar(_ context.Context, url string, ate time.Time) ([]byte, time.Time, error) {
+	indice := d.caminhoIndice(url)
+	entradas, err := os.ReadDir(indice)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, time.Time{}, fmt.Errorf("%w: %s", sync.ErrNaoArquivado, url)
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	limite := dia(ate)
+	var dias []string
+	for _, e := range entradas {
+		nome := e.Name()
+		if _, err := time.Parse(formatoDia, nome); err == nil && nome <= limite {
+			dias = append(dias, nome)
+		}
+	}
+	if len(dias) == 0 {
+		return nil, time.Time{}, fmt.Errorf("%w: %s até %s", sync.ErrNaoArquivado, url, limite)
+	}
+	sort.Strings(dias)
+
+	ultimo := dias[len(dias)-1]
+	conteudo, err := os.ReadFile(filepath.Join(indice, ultimo))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	hash, coletadoEm := lerEntrada(string(conteudo), ultimo)
+	compactado, err := os.ReadFile(d.caminhoObjeto(hash))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("resposta arquivada de %s ilegível: %w", url, err)
+	}
+	corpo, err := descompactar(compactado)
+	return corpo, coletadoEm, err
+}
+
+// lerEntrada separa o hash e o instante da coleta de uma entrada do índice. Entradas
+// gravadas antes de o instante ser guardado ficam com o início do dia.
+func lerEntrada(conteudo, nomeDia string) (string, time.Time) {
+	linhas := strings.Fields(conteudo)
+	if len(linhas) > 1 {
+		if coletadoEm, err := time.Parse(time.RFC3339Nano, linhas[1]); err == nil {
+			return linhas[0], coletadoEm
+		}
+	}
+	inicio, _ := time.ParseInLocation(formatoDia, nomeDia, fuso)
+	if len(linhas) == 0 {
+		return "", inicio
+	}
+	return linhas[0], inicio
+}
+
+func (d *Disco) caminhoObjeto(hash string) string {
+	return filepath.Join(d.dir, "objetos", hash[:2], hash+".json.gz")
+}
+
+func (d *Disco) caminhoIndice(url string) string {
+	chave := Hash([]byte(url))
+	return filepath.Join(d.dir, "indice", chave[:2], chave)
+}
+
+// Hash é o endereço do conteúdo no arquivo
+func Hash(corpo []byte) string {
+	soma := sha256.Sum256(corpo)
+	return hex.EncodeToString(soma[:])
+}
+
+func dia(t time.Time) string {
+	return t.In(fuso).Format(formatoDia)
+}
+
+// ParseDia lê uma data AAAA-MM-DD como o início desse dia no horário de Brasília, o dia em
+// que o arquivo indexa as respostas coletadas nele
+func ParseDia(s string) (time.Time, error) {
+	return time.ParseInLocation(formatoDia, s, fuso)
+}
+
+// gravarAtomico grava em um arquivo temporário e o renomeia, para que leituras
+// concorrentes nunca vejam o arquivo pela metade
+func gravarAtomico(caminho string, conteudo []byte) error {
+	if err := os.MkdirAll(filepath.Dir(caminho), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(caminho), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(conteudo); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), caminho)
+}
+
+func compactar(corpo []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(corpo); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func descompactar(compactado []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(compactado))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,51 @@
+package arquivo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/sync"
+)
+
+// A reprodução conta os dias no horário de Brasília e devolve às fontes o instante da coleta original
+func TestReproducaoUsaColetaArquivada(t *testing.T) {
+	d, err := NewDisco(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	url := "https://dadosabertos.camara.leg.br/api/v2/deputados/204554"
+
+	// 23h30 de 1º de maio em Brasília já é 2 de maio em UTC
+	coletadoEm := time.Date(2024, 5, 2, 2, 30, 0, 0, time.UTC)
+	if err := d.Guardar(ctx, url, coletadoEm, []byte(`{"dados":{"id":204554}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A data vem como a de -replay-data no cmd/sync
+	ateUmDeMaio, err := ParseDia("2024-05-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, lido, err := d.Buscar(ctx, url, ateUmDeMaio)
+	if err != nil {
+		t.Fatalf("a resposta coletada na noite de 1º de maio deveria estar no dia 1º: %v", err)
+	}
+	if !lido.Equal(coletadoEm) {
+		t.Errorf("coletado em %v; esperado %v", lido, coletadoEm)
+	}
+
+	ctx = sync.ComReproducao(ctx, d, ateUmDeMaio)
+	var resposta struct {
+		Dados struct {
+			ID int `json:"id"`
+		} `json:"dados"`
+	}
+	if err := sync.NewHTTPClient(1).Get(ctx, url, &resposta); err != nil || resposta.Dados.ID != 204554 {
+		t.Fatalf("Get em reprodução: %+v, %v", resposta, err)
+	}
+	if fonte := sync.NovaFonte(ctx, "camara", "204554", url); !fonte.ColetadoEm.Equal(coletadoEm) {
+		t.Errorf("fonte coletada em %v; esperado %v", fonte.ColetadoEm, coletadoEm)
+	}
+}
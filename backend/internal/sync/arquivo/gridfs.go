@@ -0,0 +1,89 @@
+package arquivo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/sync"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFS guarda o arquivo no MongoDB: o corpo das respostas no bucket arquivo_bruto, com o
+// SHA-256 como nome, e o índice por endereço e dia na coleção arquivo_bruto_indice
+type GridFS struct {
+	bucket *gridfs.Bucket
+	indice *mongo.Collection
+}
+
+// entradaIndice aponta a resposta coletada por último para um endereço em um dia
+type entradaIndice struct {
+	URL        string    `bson:"url"`
+	Dia        string    `bson:"dia"`
+	Hash       string    `bson:"hash"`
+	ColetadoEm time.Time `bson:"coletado_em"`
+}
+
+func NewGridFS(db *mongo.Database) (*GridFS, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName("arquivo_bruto"))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFS{
+		bucket: bucket,
+		indice: db.Collection("arquivo_bruto_indice"),
+	}, nil
+}
+
+func (g *GridFS) Guardar(ctx context.Context, url string, coletadoEm time.Time, corpo []byte) error {
+	hash := Hash(corpo)
+
+	// Respostas iguais são guardadas uma vez só. Duas gravações simultâneas do mesmo
+	// conteúdo geram revisões repetidas, o que não atrapalha a leitura.
+	n, err := g.bucket.GetFilesCollection().CountDocuments(ctx, bson.M{"filename": hash}, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		compactado, err := compactar(corpo)
+		if err != nil {
+			return err
+		}
+		if _, err := g.bucket.UploadFromStream(hash, bytes.NewReader(compactado)); err != nil {
+			return err
+		}
+	}
+
+	entrada := entradaIndice{URL: url, Dia: dia(coletadoEm), Hash: hash, ColetadoEm: coletadoEm}
+	_, err = g.indice.ReplaceOne(ctx,
+		bson.M{"url": entrada.URL, "dia": entrada.Dia},
+		entrada,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (g *GridFS) Buscar(ctx context.Context, url string, ate time.Time) ([]byte, time.Time, error) {
+	limite := dia(ate)
+	opts := options.FindOne().SetSort(bson.D{{Key: "dia", Value: -1}})
+
+	var entrada entradaIndice
+	err := g.indice.FindOne(ctx, bson.M{"url": url, "dia": bson.M{"$lte": limite}}, opts).Decode(&entrada)
+	if err == mongo.ErrNoDocuments {
+		return nil, time.Time{}, fmt.Errorf("%w: %s até %s", sync.ErrNaoArquivado, url, limite)
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := g.bucket.DownloadToStreamByName(entrada.Hash, &buf); err != nil {
+		return nil, time.Time{}, fmt.Errorf("resposta arquivada de %s ilegível: %w", url, err)
+	}
+	corpo, err := descompactar(buf.Bytes())
+	return corpo, entrada.ColetadoEm, err
+}
@@ -8,8 +8,13 @@ import (
 	syncpkg "sync"
 	"time"
 
+	"github.com/lupa-cidada/backend/internal/auditoria"
 	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/identidade"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"github.com/lupa-cidada/backend/internal/qualidade"
 	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/pkg/documento"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -22,15 +27,25 @@ const (
 
 // CamaraSync sincroniza dados da Câmara dos Deputados
 type CamaraSync struct {
-	client *sync.HTTPClient
-	db     *mongo.Database
+	client     *sync.HTTPClient
+	baseURL    string
+	db         *mongo.Database
+	partidos   *partidos.Catalogo
+	auditoria  *auditoria.Auditoria
+	identidade *identidade.Resolvedor
 }
 
-// NewCamaraSync cria um novo sincronizador
-func NewCamaraSync(db *mongo.Database) *CamaraSync {
+// NewCamaraSync cria um novo sincronizador. As opções trocam o endereço da API ou o
+// transporte HTTP (usado nos testes com fixtures).
+func NewCamaraSync(db *mongo.Database, opcoes ...sync.Opcao) *CamaraSync {
+	o := sync.AplicarOpcoes(BaseURL, opcoes)
 	return &CamaraSync{
-		client: sync.NewHTTPClient(15), // 15 requests por segundo (aumentado para acelerar)
-		db:     db,
+		client:     sync.NewHTTPClient(15).UsarTransporte(o.Transporte), // 15 requests por segundo (aumentado para acelerar)
+		baseURL:    o.BaseURL,
+		db:         db,
+		partidos:   partidos.NewCatalogo(db),
+		auditoria:  auditoria.New(db, "camara"),
+		identidade: identidade.New(db),
 	}
 }
 
@@ -39,13 +54,13 @@ func (s *CamaraSync) SyncDeputados(ctx context.Context) error {
 	log.Println("📥 Buscando deputados da Câmara...")
 
 	// Buscar deputados da legislatura atual (57)
-	url := fmt.Sprintf("%s/deputados?idLegislatura=57&itens=100&ordem=ASC&ordenarPor=nome", BaseURL)
+	url := fmt.Sprintf("%s/deputados?idLegislatura=57&itens=100&ordem=ASC&ordenarPor=nome", s.baseURL)
 
 	var allDeputados []DeputadoResumo
 
 	for url != "" {
 		var resp DeputadoResponse
-		if err := s.client.Get(url, &resp); err != nil {
+		if err := s.client.Get(ctx, url, &resp); err != nil {
 			return fmt.Errorf("erro ao buscar deputados: %w", err)
 		}
 
@@ -63,6 +78,8 @@ func (s *CamaraSync) SyncDeputados(ctx context.Context) error {
 	}
 
 	log.Printf("📊 Total: %d deputados encontrados", len(allDeputados))
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(len(allDeputados))
 
 	// Processar deputados em paralelo com worker pool
 	const numWorkers = 10 // Número de goroutines simultâneas
@@ -78,13 +95,18 @@ func (s *CamaraSync) SyncDeputados(ctx context.Context) error {
 		go func() {
 			defer wg.Done()
 			for dep := range deputadosChan {
+				if ctx.Err() != nil {
+					continue // Sincronização cancelada: só esvaziar a fila
+				}
 				if err := s.syncDeputado(ctx, dep); err != nil {
 					mu.Lock()
 					errors++
 					mu.Unlock()
 					log.Printf("⚠️  Erro ao sincronizar deputado %s: %v", dep.Nome, err)
+					progresso.Erro(fmt.Errorf("deputado %s: %w", dep.Nome, err))
 					continue
 				}
+				progresso.Processado()
 
 				mu.Lock()
 				processed++
@@ -98,12 +120,18 @@ func (s *CamaraSync) SyncDeputados(ctx context.Context) error {
 
 	// Enviar deputados para processamento
 	for _, dep := range allDeputados {
+		if ctx.Err() != nil {
+			break
+		}
 		deputadosChan <- dep
 	}
 	close(deputadosChan)
 
 	// Aguardar conclusão
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	if errors > 0 {
 		log.Printf("⚠️  %d erros durante a sincronização", errors)
@@ -113,54 +141,28 @@ func (s *CamaraSync) SyncDeputados(ctx context.Context) error {
 	return nil
 }
 
-// buscarPoliticoExistente busca um político existente por CPF ou nome+data de nascimento
-func (s *CamaraSync) buscarPoliticoExistente(ctx context.Context, cpf string, nomeCivil string, dataNascimento time.Time) (*domain.Politico, error) {
-	collection := s.db.Collection("politicos")
-
-	// Tentar buscar por CPF primeiro (mais confiável)
-	if cpf != "" {
-		var politico domain.Politico
-		err := collection.FindOne(ctx, bson.M{"cpf": cpf}).Decode(&politico)
-		if err == nil {
-			return &politico, nil
-		}
-		if err != mongo.ErrNoDocuments {
-			return nil, err
-		}
-	}
-
-	// Se não encontrou por CPF, tentar por nome civil + data de nascimento
-	if nomeCivil != "" && !dataNascimento.IsZero() {
-		var politico domain.Politico
-		err := collection.FindOne(ctx, bson.M{
-			"nome_civil":      nomeCivil,
-			"data_nascimento": dataNascimento,
-		}).Decode(&politico)
-		if err == nil {
-			return &politico, nil
-		}
-		if err != mongo.ErrNoDocuments {
-			return nil, err
-		}
-	}
-
-	return nil, nil // Não encontrado
-}
-
 // syncDeputado sincroniza um deputado específico
 func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error {
 	// Buscar detalhes do deputado
-	url := fmt.Sprintf("%s/deputados/%d", BaseURL, dep.ID)
+	url := fmt.Sprintf("%s/deputados/%d", s.baseURL, dep.ID)
 	var detalhes DeputadoDetalheResponse
-	if err := s.client.Get(url, &detalhes); err != nil {
+	if err := s.client.Get(ctx, url, &detalhes); err != nil {
 		return fmt.Errorf("erro ao buscar detalhes: %w", err)
 	}
 
 	d := detalhes.Dados
 	dataNascimento := ParseDate(d.DataNascimento)
+	cpf := documento.Normalizar(d.CPF) // Só os dígitos, como a identidade o procura
 
 	// Buscar se o político já existe no banco
-	politicoExistente, err := s.buscarPoliticoExistente(ctx, d.CPF, d.NomeCivil, dataNascimento)
+	politicoExistente, err := s.identidade.Resolver(ctx, identidade.Candidato{
+		Fonte:          "camara",
+		IDCamara:       d.ID,
+		CPF:            cpf,
+		Nome:           d.UltimoStatus.Nome,
+		NomeCivil:      d.NomeCivil,
+		DataNascimento: dataNascimento,
+	})
 	if err != nil {
 		return fmt.Errorf("erro ao buscar político existente: %w", err)
 	}
@@ -238,6 +240,9 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 		}
 
 		// Atualizar outros dados se necessário
+		if cpf != "" {
+			politico.CPF = cpf // Também corrige os gravados com pontuação
+		}
 		if d.UltimoStatus.URLFoto != "" {
 			politico.FotoURL = d.UltimoStatus.URLFoto
 		}
@@ -260,26 +265,20 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 			politico.UFNascimento = d.UfNascimento
 		}
 		politico.RedesSociais = mapRedesSociais(d.RedeSocial)
-		politico.Partido = domain.Partido{
-			Sigla: d.UltimoStatus.SiglaPartido,
-			Nome:  "",
-			Cor:   getPartidoCor(d.UltimoStatus.SiglaPartido),
-		}
+		partido := s.partidos.Resolver(ctx, d.UltimoStatus.SiglaPartido)
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, politico.HistoricoPartidos, politico.Partido, partido, politico.CreatedAt, time.Now())
+		politico.Partido = partido
 	} else {
 		// Novo político - criar registro
 		politico = domain.Politico{
-			CPF:            d.CPF,
+			CPF:            cpf,
 			Nome:           d.UltimoStatus.Nome,
 			NomeCivil:      d.NomeCivil,
 			NomeEleitoral:  d.UltimoStatus.NomeEleitoral,
 			FotoURL:        d.UltimoStatus.URLFoto,
 			DataNascimento: dataNascimento,
 			Genero:         mapGenero(d.Sexo),
-			Partido: domain.Partido{
-				Sigla: d.UltimoStatus.SiglaPartido,
-				Nome:  "",
-				Cor:   getPartidoCor(d.UltimoStatus.SiglaPartido),
-			},
+			Partido:        s.partidos.Resolver(ctx, d.UltimoStatus.SiglaPartido),
 			Contato: domain.Contato{
 				Email: d.UltimoStatus.Email,
 			},
@@ -293,6 +292,7 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 			CreatedAt:           time.Now(),
 			UpdatedAt:           time.Now(),
 		}
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, nil, domain.Partido{}, politico.Partido, time.Time{}, politico.CreatedAt)
 
 		// Se está em exercício, definir como cargo atual
 		// Se não está, adicionar ao histórico
@@ -319,7 +319,6 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 	politico.UpdatedAt = time.Now()
 
 	// Upsert no MongoDB
-	collection := s.db.Collection("politicos")
 	var filter bson.M
 
 	if politicoExistente != nil {
@@ -327,15 +326,7 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 		filter = bson.M{"_id": politicoExistente.ID}
 	} else {
 		// Inserir novo
-		filter = bson.M{
-			"$or": []bson.M{
-				{"cpf": d.CPF},
-				{
-					"nome_civil":      d.NomeCivil,
-					"data_nascimento": dataNascimento,
-				},
-			},
-		}
+		filter = bson.M{"id_externo_camara": d.ID}
 	}
 
 	update := bson.M{
@@ -350,6 +341,7 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 			"partido":              politico.Partido,
 			"cargo_atual":          politico.CargoAtual,
 			"historico_cargos":     politico.HistoricoCargos,
+			"historico_partidos":   politico.HistoricoPartidos,
 			"contato":              politico.Contato,
 			"redes_sociais":        politico.RedesSociais,
 			"salario_bruto":        politico.SalarioBruto,
@@ -359,6 +351,7 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 			"uf_nascimento":        politico.UFNascimento,
 			"website":              politico.Website,
 			"id_externo_camara":    d.ID,
+			"fontes.camara":        sync.NovaFonte(ctx, "camara", fmt.Sprint(d.ID), url),
 			"updated_at":           time.Now(),
 		},
 		"$setOnInsert": bson.M{
@@ -367,9 +360,7 @@ func (s *CamaraSync) syncDeputado(ctx context.Context, dep DeputadoResumo) error
 		},
 	}
 
-	opts := options.Update().SetUpsert(true)
-	_, err = collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return s.auditoria.AtualizarPolitico(ctx, filter, update, true)
 }
 
 // SyncDespesas sincroniza despesas dos deputados
@@ -390,6 +381,14 @@ func (s *CamaraSync) SyncDespesas(ctx context.Context, ano int) error {
 	}
 
 	log.Printf("   %d políticos encontrados para sincronizar despesas", len(politicos))
+	progresso := sync.ProgressoDe(ctx)
+	comIDCamara := 0
+	for _, politico := range politicos {
+		if politico.IDExternoCamara != 0 {
+			comIDCamara++
+		}
+	}
+	progresso.Total(comIDCamara)
 
 	// Processar despesas em paralelo
 	const numWorkers = 5
@@ -403,11 +402,14 @@ func (s *CamaraSync) SyncDespesas(ctx context.Context, ano int) error {
 		go func() {
 			defer wg.Done()
 			for politico := range politicosChan {
-				if politico.IDExternoCamara == 0 {
+				if politico.IDExternoCamara == 0 || ctx.Err() != nil {
 					continue
 				}
 				if err := s.SyncDespesasPorDeputado(ctx, politico.IDExternoCamara, ano); err != nil {
 					log.Printf("⚠️  Erro ao sincronizar despesas do deputado %s: %v", politico.Nome, err)
+					progresso.Erro(fmt.Errorf("despesas de %s: %w", politico.Nome, err))
+				} else {
+					progresso.Processado()
 				}
 				mu.Lock()
 				processed++
@@ -420,12 +422,18 @@ func (s *CamaraSync) SyncDespesas(ctx context.Context, ano int) error {
 	}
 
 	for _, politico := range politicos {
+		if ctx.Err() != nil {
+			break
+		}
 		if politico.IDExternoCamara != 0 {
 			politicosChan <- politico
 		}
 	}
 	close(politicosChan)
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	log.Println("✅ Sincronização de despesas concluída!")
 	return nil
@@ -433,13 +441,13 @@ func (s *CamaraSync) SyncDespesas(ctx context.Context, ano int) error {
 
 // SyncDespesasPorDeputado sincroniza despesas de um deputado específico
 func (s *CamaraSync) SyncDespesasPorDeputado(ctx context.Context, deputadoID int, ano int) error {
-	url := fmt.Sprintf("%s/deputados/%d/despesas?ano=%d&itens=100&ordem=ASC&ordenarPor=mes", BaseURL, deputadoID, ano)
+	url := fmt.Sprintf("%s/deputados/%d/despesas?ano=%d&itens=100&ordem=ASC&ordenarPor=mes", s.baseURL, deputadoID, ano)
 	despesasCollection := s.db.Collection("despesas")
 
 	var allDespesas []Despesa
 	for url != "" {
 		var resp DespesasResponse
-		if err := s.client.Get(url, &resp); err != nil {
+		if err := s.client.Get(ctx, url, &resp); err != nil {
 			return fmt.Errorf("erro ao buscar despesas: %w", err)
 		}
 
@@ -462,42 +470,79 @@ func (s *CamaraSync) SyncDespesasPorDeputado(ctx context.Context, deputadoID int
 		return fmt.Errorf("político não encontrado com ID externo %d: %w", deputadoID, err)
 	}
 
+	// Marca desta execução, usada na reconciliação para achar despesas que sumiram da API
+	inicio := time.Now().Truncate(time.Millisecond)
+	falhas := 0
+
+	// Quantas vezes cada despesa sem codDocumento já apareceu na resposta: lançamentos
+	// idênticos são despesas distintas e se diferenciam pela ordem em que vêm
+	ocorrencias := make(map[string]int)
+
 	for _, despesa := range allDespesas {
 		dataDoc := ParseDate(despesa.DataDocumento)
 		if dataDoc.IsZero() {
 			dataDoc = time.Date(despesa.Ano, time.Month(despesa.Mes), 1, 0, 0, 0, 0, time.UTC)
 		}
 
+		// Normalizar CPF/CNPJ do fornecedor (sem pontuação, dígitos verificadores conferidos)
+		cnpjFornecedor, tipoDocumento := documento.Identificar(despesa.CNPJCPFFornecedor)
+		if cnpjFornecedor != "" && tipoDocumento == documento.TipoInvalido {
+			log.Printf("⚠️  Documento de fornecedor inválido em despesa do deputado %d: %q", deputadoID, despesa.CNPJCPFFornecedor)
+		}
+
 		despesaDoc := domain.Despesa{
-			PoliticoID:     politico.ID,
-			Tipo:           despesa.TipoDespesa,
-			Descricao:      fmt.Sprintf("%s - %s", despesa.TipoDespesa, despesa.NomeFornecedor),
-			Fornecedor:     despesa.NomeFornecedor,
-			CNPJFornecedor: despesa.CNPJCPFFornecedor,
-			Valor:          despesa.ValorLiquido,
-			Data:           dataDoc,
-			MesReferencia:  despesa.Mes,
-			AnoReferencia:  despesa.Ano,
-			DocumentoURL:   despesa.URLDocumento,
+			PoliticoID:              politico.ID,
+			Tipo:                    despesa.TipoDespesa,
+			Descricao:               fmt.Sprintf("%s - %s", despesa.TipoDespesa, despesa.NomeFornecedor),
+			Fornecedor:              despesa.NomeFornecedor,
+			CNPJFornecedor:          cnpjFornecedor,
+			TipoDocumentoFornecedor: string(tipoDocumento),
+			Valor:                   despesa.ValorLiquido,
+			Data:                    dataDoc,
+			MesReferencia:           despesa.Mes,
+			AnoReferencia:           despesa.Ano,
+			DocumentoURL:            despesa.URLDocumento,
+			CodDocumento:            despesa.CodDocumento,
+			NumDocumento:            despesa.NumDocumento,
+			CodLote:                 despesa.CodLote,
+			Parcela:                 despesa.Parcela,
+			NumRessarcimento:        despesa.NumRessarcimento,
 		}
+		qualidade.De(ctx).ValidarDespesa(ctx, "camara", politico, despesaDoc,
+			fmt.Sprintf("documento %d de %02d/%d", despesa.CodDocumento, despesa.Mes, despesa.Ano))
 
-		// Upsert despesa
-		filter := bson.M{
-			"politico_id":     politico.ID,
-			"ano_referencia":  despesa.Ano,
-			"mes_referencia":  despesa.Mes,
-			"tipo":            despesa.TipoDespesa,
-			"cnpj_fornecedor": despesa.CNPJCPFFornecedor,
-			"valor":           despesa.ValorLiquido,
+		codDocumento := ""
+		if despesa.CodDocumento != 0 {
+			codDocumento = fmt.Sprint(despesa.CodDocumento)
+		}
+
+		// Upsert despesa pela identidade do documento na Câmara, para que dois recibos
+		// idênticos não se fundam e um valor corrigido atualize o registro existente
+		ocorrencia := 0
+		if despesa.CodDocumento == 0 {
+			chave := fmt.Sprint(despesa.NumDocumento, despesa.CodLote, despesa.Parcela, despesaDoc.AnoReferencia,
+				despesaDoc.MesReferencia, despesaDoc.Tipo, despesaDoc.CNPJFornecedor, despesaDoc.Data, despesaDoc.Valor)
+			ocorrencia = ocorrencias[chave]
+			ocorrencias[chave]++
 		}
+		filter := despesaFilter(politico.ID, despesa, despesaDoc, ocorrencia)
 
 		update := bson.M{
 			"$set": bson.M{
-				"descricao":     despesaDoc.Descricao,
-				"fornecedor":    despesaDoc.Fornecedor,
-				"data":          despesaDoc.Data,
-				"documento_url": despesaDoc.DocumentoURL,
-				"updated_at":    time.Now(),
+				"tipo":                      despesaDoc.Tipo,
+				"descricao":                 despesaDoc.Descricao,
+				"fornecedor":                despesaDoc.Fornecedor,
+				"cnpj_fornecedor":           despesaDoc.CNPJFornecedor,
+				"tipo_documento_fornecedor": despesaDoc.TipoDocumentoFornecedor,
+				"valor":                     despesaDoc.Valor,
+				"data":                      despesaDoc.Data,
+				"mes_referencia":            despesaDoc.MesReferencia,
+				"ano_referencia":            despesaDoc.AnoReferencia,
+				"documento_url":             despesaDoc.DocumentoURL,
+				"num_ressarcimento":         despesa.NumRessarcimento,
+				"fonte":                     sync.NovaFonte(ctx, "camara", codDocumento, fmt.Sprintf("%s/deputados/%d/despesas?ano=%d&mes=%d", s.baseURL, deputadoID, despesa.Ano, despesa.Mes)),
+				"sincronizado_em":           inicio,
+				"updated_at":                time.Now(),
 			},
 			"$setOnInsert": bson.M{
 				"_id":        primitive.NewObjectID(),
@@ -509,10 +554,70 @@ func (s *CamaraSync) SyncDespesasPorDeputado(ctx context.Context, deputadoID int
 		_, err := despesasCollection.UpdateOne(ctx, filter, update, opts)
 		if err != nil {
 			log.Printf("⚠️  Erro ao salvar despesa: %v", err)
+			falhas++
 			continue
 		}
 	}
 
+	return s.reconciliarDespesas(ctx, politico, ano, inicio, len(allDespesas), falhas)
+}
+
+// despesaFilter monta a identidade de uma despesa a partir dos códigos do documento na Câmara.
+// Alguns lançamentos vêm sem codDocumento; nesses casos usa os campos do próprio recibo e a
+// ocorrência, a posição do lançamento entre os idênticos a ele na resposta.
+func despesaFilter(politicoID primitive.ObjectID, despesa Despesa, despesaDoc domain.Despesa, ocorrencia int) bson.M {
+	if despesa.CodDocumento != 0 {
+		return bson.M{
+			"politico_id":   politicoID,
+			"cod_documento": despesa.CodDocumento,
+			"num_documento": despesa.NumDocumento,
+			"cod_lote":      despesa.CodLote,
+			"parcela":       despesa.Parcela,
+		}
+	}
+
+	return bson.M{
+		"politico_id":     politicoID,
+		"cod_documento":   0,
+		"num_documento":   despesa.NumDocumento,
+		"cod_lote":        despesa.CodLote,
+		"parcela":         despesa.Parcela,
+		"ano_referencia":  despesaDoc.AnoReferencia,
+		"mes_referencia":  despesaDoc.MesReferencia,
+		"tipo":            despesaDoc.Tipo,
+		"cnpj_fornecedor": despesaDoc.CNPJFornecedor,
+		"data":            despesaDoc.Data,
+		"valor":           despesaDoc.Valor,
+		"ocorrencia":      ocorrencia,
+	}
+}
+
+// reconciliarDespesas remove as despesas do ano que não vieram mais da API (estornadas ou
+// reclassificadas pela Câmara), além de registros antigos gravados com a chave anterior.
+// Só roda se todas as despesas foram gravadas, para não apagar dados por causa de falhas locais.
+func (s *CamaraSync) reconciliarDespesas(ctx context.Context, politico domain.Politico, ano int, inicio time.Time, recebidas, falhas int) error {
+	if falhas > 0 {
+		log.Printf("⚠️  Reconciliação de despesas de %s ignorada: %d falhas ao salvar", politico.Nome, falhas)
+		return nil
+	}
+	if recebidas == 0 {
+		// Uma resposta vazia pode ser instabilidade da API; melhor manter os dados atuais
+		return nil
+	}
+
+	result, err := s.db.Collection("despesas").DeleteMany(ctx, bson.M{
+		"politico_id":     politico.ID,
+		"ano_referencia":  ano,
+		"sincronizado_em": bson.M{"$ne": inicio},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao reconciliar despesas: %w", err)
+	}
+
+	if result.DeletedCount > 0 {
+		log.Printf("   %s: %d despesas removidas por não existirem mais na Câmara", politico.Nome, result.DeletedCount)
+	}
+
 	return nil
 }
 
@@ -521,14 +626,14 @@ func (s *CamaraSync) SyncVotacoes(ctx context.Context, ano int) error {
 	log.Printf("📥 Buscando votações do ano %d...", ano)
 
 	// Buscar todas as votações do ano
-	url := fmt.Sprintf("%s/votacoes?ano=%d&itens=100&ordem=ASC&ordenarPor=data", BaseURL, ano)
+	url := fmt.Sprintf("%s/votacoes?ano=%d&itens=100&ordem=ASC&ordenarPor=data", s.baseURL, ano)
 	votacoesCollection := s.db.Collection("votacoes")
 	proposicoesCollection := s.db.Collection("proposicoes")
 
 	var allVotacoes []Votacao
 	for url != "" {
 		var resp VotacoesResponse
-		if err := s.client.Get(url, &resp); err != nil {
+		if err := s.client.Get(ctx, url, &resp); err != nil {
 			return fmt.Errorf("erro ao buscar votações: %w", err)
 		}
 
@@ -546,6 +651,8 @@ func (s *CamaraSync) SyncVotacoes(ctx context.Context, ano int) error {
 	}
 
 	log.Printf("📊 Total: %d votações encontradas", len(allVotacoes))
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(len(allVotacoes))
 
 	// Processar votações em paralelo
 	const numWorkers = 5
@@ -559,7 +666,11 @@ func (s *CamaraSync) SyncVotacoes(ctx context.Context, ano int) error {
 		go func() {
 			defer wg.Done()
 			for votacao := range votacoesChan {
+				if ctx.Err() != nil {
+					continue
+				}
 				s.processarVotacao(ctx, votacao, votacoesCollection, proposicoesCollection)
+				progresso.Processado()
 				mu.Lock()
 				processed++
 				if processed%50 == 0 {
@@ -571,10 +682,16 @@ func (s *CamaraSync) SyncVotacoes(ctx context.Context, ano int) error {
 	}
 
 	for _, votacao := range allVotacoes {
+		if ctx.Err() != nil {
+			break
+		}
 		votacoesChan <- votacao
 	}
 	close(votacoesChan)
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	log.Println("✅ Sincronização de votações concluída!")
 	return nil
@@ -606,9 +723,9 @@ func (s *CamaraSync) SyncProposicoes(ctx context.Context, ano int) error {
 	// Por enquanto, vamos buscar todas as proposições do ano e filtrar depois
 
 	// Primeiro, descobrir quantas páginas existem
-	url := fmt.Sprintf("%s/proposicoes?ano=%d&itens=100&ordem=ASC&ordenarPor=id", BaseURL, ano)
+	url := fmt.Sprintf("%s/proposicoes?ano=%d&itens=100&ordem=ASC&ordenarPor=id", s.baseURL, ano)
 	var firstResp ProposicoesResponse
-	if err := s.client.Get(url, &firstResp); err != nil {
+	if err := s.client.Get(ctx, url, &firstResp); err != nil {
 		return fmt.Errorf("erro ao buscar primeira página: %w", err)
 	}
 
@@ -663,9 +780,12 @@ func (s *CamaraSync) SyncProposicoes(ctx context.Context, ano int) error {
 		go func() {
 			defer wgPaginas.Done()
 			for pagina := range paginasChan {
-				pageURL := fmt.Sprintf("%s/proposicoes?ano=%d&itens=100&ordem=ASC&ordenarPor=id&pagina=%d", BaseURL, ano, pagina)
+				if ctx.Err() != nil {
+					continue
+				}
+				pageURL := fmt.Sprintf("%s/proposicoes?ano=%d&itens=100&ordem=ASC&ordenarPor=id&pagina=%d", s.baseURL, ano, pagina)
 				var resp ProposicoesResponse
-				if err := s.client.Get(pageURL, &resp); err != nil {
+				if err := s.client.Get(ctx, pageURL, &resp); err != nil {
 					// Página não existe ou erro - ignorar (pode ser além do limite)
 					muPaginas.Lock()
 					errosPaginas++
@@ -699,7 +819,13 @@ func (s *CamaraSync) SyncProposicoes(ctx context.Context, ano int) error {
 		log.Printf("⚠️  %d erros ao buscar páginas", errosPaginas)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	log.Printf("📊 Total: %d proposições encontradas", len(allProposicoes))
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(len(allProposicoes))
 
 	// Processar proposições em paralelo (aumentado para acelerar)
 	const numWorkers = 30
@@ -713,7 +839,11 @@ func (s *CamaraSync) SyncProposicoes(ctx context.Context, ano int) error {
 		go func() {
 			defer wg.Done()
 			for prop := range proposicoesChan {
+				if ctx.Err() != nil {
+					continue
+				}
 				s.processarProposicao(ctx, prop, proposicoesCollection)
+				progresso.Processado()
 				mu.Lock()
 				processed++
 				if processed%500 == 0 {
@@ -725,10 +855,16 @@ func (s *CamaraSync) SyncProposicoes(ctx context.Context, ano int) error {
 	}
 
 	for _, prop := range allProposicoes {
+		if ctx.Err() != nil {
+			break
+		}
 		proposicoesChan <- prop
 	}
 	close(proposicoesChan)
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	log.Println("✅ Sincronização de proposições concluída!")
 	return nil
@@ -757,9 +893,11 @@ func (s *CamaraSync) processarVotacao(ctx context.Context, votacao Votacao, vota
 				"ementa":     proposicao.Ementa,
 				"updated_at": time.Now(),
 			},
+			// A fonte só é gravada na criação; a sincronização de proposições a atualiza
 			"$setOnInsert": bson.M{
 				"_id":        primitive.NewObjectID(),
 				"situacao":   domain.SituacaoEmTramitacao,
+				"fonte":      sync.NovaFonte(ctx, "camara", fmt.Sprint(votacao.Proposicao.ID), fmt.Sprintf("%s/proposicoes/%d", s.baseURL, votacao.Proposicao.ID)),
 				"created_at": time.Now(),
 			},
 		}
@@ -775,9 +913,9 @@ func (s *CamaraSync) processarVotacao(ctx context.Context, votacao Votacao, vota
 	}
 
 	// Buscar votos dos deputados nesta votação
-	votosURL := fmt.Sprintf("%s/votacoes/%s/votos", BaseURL, votacao.ID)
+	votosURL := fmt.Sprintf("%s/votacoes/%s/votos", s.baseURL, votacao.ID)
 	var votosResp VotoDeputadoResponse
-	if err := s.client.Get(votosURL, &votosResp); err != nil {
+	if err := s.client.Get(ctx, votosURL, &votosResp); err != nil {
 		log.Printf("⚠️  Erro ao buscar votos da votação %s: %v", votacao.ID, err)
 		return
 	}
@@ -792,15 +930,18 @@ func (s *CamaraSync) processarVotacao(ctx context.Context, votacao Votacao, vota
 		var politico domain.Politico
 		politicoFilter := bson.M{"id_externo_camara": voto.Deputado.ID}
 		err := s.db.Collection("politicos").FindOne(ctx, politicoFilter).Decode(&politico)
+		if err == mongo.ErrNoDocuments {
+			qualidade.De(ctx).VotoPoliticoDesconhecido(ctx, "camara", "deputado", voto.Deputado.ID, voto.Deputado.Nome, votacao.ID)
+			continue
+		}
 		if err != nil {
 			continue
 		}
 
 		tipoVoto := mapTipoVoto(voto.TipoVoto)
 		filter := bson.M{
-			"politico_id": politico.ID,
-			"data":        dataVotacao,
-			"sessao":      votacao.SiglaOrgao,
+			"politico_id":        politico.ID,
+			"votacao_id_externo": votacao.ID,
 		}
 
 		update := bson.M{
@@ -809,6 +950,7 @@ func (s *CamaraSync) processarVotacao(ctx context.Context, votacao Votacao, vota
 				"proposicao_id": proposicaoID,
 				"data":          dataVotacao,
 				"sessao":        votacao.SiglaOrgao,
+				"fonte":         sync.NovaFonte(ctx, "camara", votacao.ID, votosURL),
 			},
 			"$setOnInsert": bson.M{
 				"_id": primitive.NewObjectID(),
@@ -826,9 +968,9 @@ func (s *CamaraSync) processarVotacao(ctx context.Context, votacao Votacao, vota
 // processarProposicao processa uma proposição individual (usado em goroutines)
 func (s *CamaraSync) processarProposicao(ctx context.Context, prop Proposicao, proposicoesCollection *mongo.Collection) {
 	// Buscar detalhes da proposição
-	url := fmt.Sprintf("%s/proposicoes/%d", BaseURL, prop.ID)
+	url := fmt.Sprintf("%s/proposicoes/%d", s.baseURL, prop.ID)
 	var detalhes ProposicaoDetalheResponse
-	if err := s.client.Get(url, &detalhes); err != nil {
+	if err := s.client.Get(ctx, url, &detalhes); err != nil {
 		// Erro não crítico, continuar
 		return
 	}
@@ -836,12 +978,12 @@ func (s *CamaraSync) processarProposicao(ctx context.Context, prop Proposicao, p
 	d := detalhes.Dados
 
 	// Buscar autores da proposição (essencial)
-	autoresURL := fmt.Sprintf("%s/proposicoes/%d/autores", BaseURL, prop.ID)
+	autoresURL := fmt.Sprintf("%s/proposicoes/%d/autores", s.baseURL, prop.ID)
 	var autoresResp AutoresResponse
 	var autorID primitive.ObjectID
 	var coautoresIDs []primitive.ObjectID
 
-	if err := s.client.Get(autoresURL, &autoresResp); err == nil {
+	if err := s.client.Get(ctx, autoresURL, &autoresResp); err == nil {
 		for _, autor := range autoresResp.Dados {
 			var politico domain.Politico
 			politicoFilter := bson.M{"id_externo_camara": autor.ID}
@@ -866,9 +1008,9 @@ func (s *CamaraSync) processarProposicao(ctx context.Context, prop Proposicao, p
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		tramitacoesURL := fmt.Sprintf("%s/proposicoes/%d/tramitacoes?itens=100", BaseURL, prop.ID)
+		tramitacoesURL := fmt.Sprintf("%s/proposicoes/%d/tramitacoes?itens=100", s.baseURL, prop.ID)
 		var tramitacoesResp TramitacoesResponse
-		if err := s.client.Get(tramitacoesURL, &tramitacoesResp); err == nil {
+		if err := s.client.Get(ctx, tramitacoesURL, &tramitacoesResp); err == nil {
 			var tempTramitacoes []domain.TramitacaoItem
 			for _, tram := range tramitacoesResp.Dados {
 				dataTram := ParseDate(tram.DataHora)
@@ -890,9 +1032,9 @@ func (s *CamaraSync) processarProposicao(ctx context.Context, prop Proposicao, p
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		temasURL := fmt.Sprintf("%s/proposicoes/%d/temas", BaseURL, prop.ID)
+		temasURL := fmt.Sprintf("%s/proposicoes/%d/temas", s.baseURL, prop.ID)
 		var temasResp TemasResponse
-		if err := s.client.Get(temasURL, &temasResp); err == nil {
+		if err := s.client.Get(ctx, temasURL, &temasResp); err == nil {
 			var tempTemas []string
 			for _, tema := range temasResp.Dados {
 				if tema.Nome != "" {
@@ -962,6 +1104,7 @@ func (s *CamaraSync) processarProposicao(ctx context.Context, prop Proposicao, p
 			"situacao":      proposicaoDoc.Situacao,
 			"tema":          proposicaoDoc.Tema,
 			"tramitacao":    proposicaoDoc.Tramitacao,
+			"fonte":         sync.NovaFonte(ctx, "camara", fmt.Sprint(prop.ID), url),
 			"updated_at":    time.Now(),
 		},
 		"$setOnInsert": bson.M{
@@ -982,13 +1125,13 @@ func (s *CamaraSync) SyncPresencas(ctx context.Context, ano int) error {
 	log.Printf("📥 Buscando presenças em eventos do ano %d...", ano)
 
 	// Buscar eventos do ano (sessões plenárias, reuniões de comissões, etc.)
-	url := fmt.Sprintf("%s/eventos?ano=%d&itens=100&ordem=ASC&ordenarPor=dataHoraInicio", BaseURL, ano)
+	url := fmt.Sprintf("%s/eventos?ano=%d&itens=100&ordem=ASC&ordenarPor=dataHoraInicio", s.baseURL, ano)
 	presencasCollection := s.db.Collection("presencas")
 
 	var allEventos []Evento
 	for url != "" {
 		var resp EventosResponse
-		if err := s.client.Get(url, &resp); err != nil {
+		if err := s.client.Get(ctx, url, &resp); err != nil {
 			return fmt.Errorf("erro ao buscar eventos: %w", err)
 		}
 
@@ -1006,6 +1149,8 @@ func (s *CamaraSync) SyncPresencas(ctx context.Context, ano int) error {
 	}
 
 	log.Printf("📊 Total: %d eventos encontrados", len(allEventos))
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(len(allEventos))
 
 	// Processar eventos em paralelo
 	const numWorkers = 5
@@ -1019,7 +1164,11 @@ func (s *CamaraSync) SyncPresencas(ctx context.Context, ano int) error {
 		go func() {
 			defer wg.Done()
 			for evento := range eventosChan {
+				if ctx.Err() != nil {
+					continue
+				}
 				s.processarEvento(ctx, evento, presencasCollection)
+				progresso.Processado()
 				mu.Lock()
 				processed++
 				if processed%50 == 0 {
@@ -1031,10 +1180,16 @@ func (s *CamaraSync) SyncPresencas(ctx context.Context, ano int) error {
 	}
 
 	for _, evento := range allEventos {
+		if ctx.Err() != nil {
+			break
+		}
 		eventosChan <- evento
 	}
 	close(eventosChan)
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	log.Println("✅ Sincronização de presenças concluída!")
 	return nil
@@ -1043,9 +1198,9 @@ func (s *CamaraSync) SyncPresencas(ctx context.Context, ano int) error {
 // processarEvento processa um evento individual (usado em goroutines)
 func (s *CamaraSync) processarEvento(ctx context.Context, evento Evento, presencasCollection *mongo.Collection) {
 	// Buscar presenças do evento
-	presencasURL := fmt.Sprintf("%s/eventos/%d/presencas", BaseURL, evento.ID)
+	presencasURL := fmt.Sprintf("%s/eventos/%d/presencas", s.baseURL, evento.ID)
 	var presencasResp PresencasEventoResponse
-	if err := s.client.Get(presencasURL, &presencasResp); err != nil {
+	if err := s.client.Get(ctx, presencasURL, &presencasResp); err != nil {
 		// Alguns eventos podem não ter presenças registradas
 		return
 	}
@@ -1078,6 +1233,7 @@ func (s *CamaraSync) processarEvento(ctx context.Context, evento Evento, presenc
 		update := bson.M{
 			"$set": bson.M{
 				"presente": true,
+				"fonte":    sync.NovaFonte(ctx, "camara", fmt.Sprint(evento.ID), presencasURL),
 			},
 			"$setOnInsert": bson.M{
 				"_id": primitive.NewObjectID(),
@@ -1149,34 +1305,3 @@ func mapRedesSociais(redes []string) domain.RedesSociais {
 	return rs
 }
 
-// getPartidoCor retorna a cor do partido
-func getPartidoCor(sigla string) string {
-	cores := map[string]string{
-		"PT":            "#CC0000",
-		"PL":            "#003366",
-		"UNIÃO":         "#2E3092",
-		"PP":            "#0066CC",
-		"MDB":           "#00AA00",
-		"PSD":           "#FF6600",
-		"REPUBLICANOS":  "#0033CC",
-		"PDT":           "#FF0000",
-		"PSDB":          "#003399",
-		"PSOL":          "#FFD700",
-		"PSB":           "#FF6347",
-		"PODE":          "#00CED1",
-		"CIDADANIA":     "#9932CC",
-		"AVANTE":        "#FF8C00",
-		"SOLIDARIEDADE": "#FF4500",
-		"PCdoB":         "#8B0000",
-		"PV":            "#228B22",
-		"NOVO":          "#FF6600",
-		"REDE":          "#00AA66",
-		"PRD":           "#1E90FF",
-		"AGIR":          "#4169E1",
-	}
-
-	if cor, ok := cores[strings.ToUpper(sigla)]; ok {
-		return cor
-	}
-	return "#666666"
-}
@@ -0,0 +1,253 @@
+package camara
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lupa-cidada/backend/internal/auditoria"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/qualidade"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/internal/sync/fixtures"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func novoSync(t *testing.T) (*CamaraSync, *mongo.Database) {
+	t.Helper()
+	db := mongomemtest.Banco(t)
+	return NewCamaraSync(db, sync.ComTransporte(fixtures.Novo("testdata"))), db
+}
+
+func buscarPolitico(t *testing.T, db *mongo.Database, idCamara int) domain.Politico {
+	t.Helper()
+	var p domain.Politico
+	if err := db.Collection("politicos").FindOne(context.Background(), bson.M{"id_externo_camara": idCamara}).Decode(&p); err != nil {
+		t.Fatalf("deputado %d não gravado: %v", idCamara, err)
+	}
+	return p
+}
+
+func contar(t *testing.T, db *mongo.Database, colecao string, filtro bson.M) int64 {
+	t.Helper()
+	n, err := db.Collection(colecao).CountDocuments(context.Background(), filtro)
+	if err != nil {
+		t.Fatalf("erro ao contar %s: %v", colecao, err)
+	}
+	return n
+}
+
+func TestSyncDeputados(t *testing.T) {
+	s, db := novoSync(t)
+	ctx := auditoria.ComExecucao(context.Background(), "execucao-teste")
+
+	if err := s.SyncDeputados(ctx); err != nil {
+		t.Fatalf("SyncDeputados: %v", err)
+	}
+
+	ana := buscarPolitico(t, db, 1001)
+	if ana.NomeCivil != "Ana Maria Ribeiro" || ana.Genero != domain.GeneroFeminino {
+		t.Errorf("dados pessoais = %q, %q", ana.NomeCivil, ana.Genero)
+	}
+	if ana.CargoAtual.Tipo != domain.CargoDeputadoFederal || !ana.CargoAtual.EmExercicio || ana.CargoAtual.Estado != "SP" {
+		t.Errorf("cargo atual = %+v", ana.CargoAtual)
+	}
+	if ana.Contato.Gabinete != "4, 5, Sala 512" || ana.Contato.Telefone != "3215-5512" {
+		t.Errorf("contato = %+v", ana.Contato)
+	}
+	fonte := ana.Fontes["camara"]
+	if fonte.IDExterno != "1001" || fonte.URL != BaseURL+"/deputados/1001" || fonte.ExecucaoID != "execucao-teste" {
+		t.Errorf("fonte = %+v", fonte)
+	}
+
+	// Licenciado: o mandato vai para o histórico e o DEM é resolvido para o sucessor
+	bruno := buscarPolitico(t, db, 1002)
+	if bruno.CargoAtual.Tipo != "" || len(bruno.HistoricoCargos) != 1 || bruno.HistoricoCargos[0].Tipo != domain.CargoDeputadoFederal {
+		t.Errorf("cargos do licenciado = %+v / %+v", bruno.CargoAtual, bruno.HistoricoCargos)
+	}
+	if bruno.Partido.Sigla != "UNIÃO" {
+		t.Errorf("partido = %q; esperado UNIÃO", bruno.Partido.Sigla)
+	}
+
+	// Repetir a sincronização com os mesmos dados não duplica cadastros
+	if err := s.SyncDeputados(ctx); err != nil {
+		t.Fatalf("SyncDeputados (repetição): %v", err)
+	}
+	if n := contar(t, db, "politicos", bson.M{}); n != 2 {
+		t.Errorf("%d políticos depois de repetir; esperado 2", n)
+	}
+	if n := contar(t, db, "politicos", bson.M{"id_externo_camara": 1001}); n != 1 {
+		t.Errorf("%d cadastros da deputada 1001; esperado 1", n)
+	}
+}
+
+// A primeira sincronização registra a criação dos cadastros; repetir com os mesmos dados não
+// registra nada, e um campo alterado na Câmara gera uma alteração só, com os dois valores
+func TestSyncDeputadosRegistraAlteracoes(t *testing.T) {
+	s, db := novoSync(t)
+	ctx := context.Background()
+
+	if err := s.SyncDeputados(auditoria.ComExecucao(ctx, "primeira")); err != nil {
+		t.Fatalf("SyncDeputados: %v", err)
+	}
+	ana := buscarPolitico(t, db, 1001)
+	if n := contar(t, db, "alteracoes", bson.M{}); n != 2 {
+		t.Errorf("%d alterações na primeira sincronização; esperadas as 2 criações", n)
+	}
+	if n := contar(t, db, "alteracoes", bson.M{"politico_id": ana.ID, "operacao": domain.OperacaoCriacao, "execucao_id": "primeira"}); n != 1 {
+		t.Errorf("%d criações registradas para a deputada 1001; esperada 1", n)
+	}
+
+	if err := s.SyncDeputados(auditoria.ComExecucao(ctx, "repeticao")); err != nil {
+		t.Fatalf("SyncDeputados (repetição): %v", err)
+	}
+	if n := contar(t, db, "alteracoes", bson.M{"execucao_id": "repeticao"}); n != 0 {
+		t.Errorf("%d alterações registradas sem mudança nos dados", n)
+	}
+
+	// As mesmas respostas, com outro e-mail para a deputada 1001
+	dir := t.TempDir()
+	arquivos, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range arquivos {
+		corpo, err := os.ReadFile(filepath.Join("testdata", a.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Name() == "api_v2_deputados_1001.json" {
+			corpo = bytes.ReplaceAll(corpo, []byte("dep.anaribeiro@camara.leg.br"), []byte("ana.ribeiro@camara.leg.br"))
+		}
+		if err := os.WriteFile(filepath.Join(dir, a.Name()), corpo, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alterado := NewCamaraSync(db, sync.ComTransporte(fixtures.Novo(dir)))
+	if err := alterado.SyncDeputados(auditoria.ComExecucao(ctx, "email")); err != nil {
+		t.Fatalf("SyncDeputados (e-mail alterado): %v", err)
+	}
+
+	var alteracoes []domain.Alteracao
+	cursor, err := db.Collection("alteracoes").Find(ctx, bson.M{"execucao_id": "email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.All(ctx, &alteracoes); err != nil {
+		t.Fatal(err)
+	}
+	if len(alteracoes) != 1 {
+		t.Fatalf("%d alterações com o e-mail trocado; esperada 1: %+v", len(alteracoes), alteracoes)
+	}
+	a := alteracoes[0]
+	anterior, _ := a.ValorAnterior.(bson.D)
+	novo, _ := a.ValorNovo.(bson.D)
+	if a.PoliticoID != ana.ID || a.Operacao != domain.OperacaoAtualizacao || a.Campo != "contato" ||
+		anterior.Map()["email"] != "dep.anaribeiro@camara.leg.br" || novo.Map()["email"] != "ana.ribeiro@camara.leg.br" {
+		t.Errorf("alteração = %+v", a)
+	}
+}
+
+func TestSyncVotacoes(t *testing.T) {
+	s, db := novoSync(t)
+	validador := qualidade.NewValidador(nil, "execucao-teste")
+	ctx := qualidade.ComValidador(context.Background(), validador)
+
+	if err := s.SyncDeputados(ctx); err != nil {
+		t.Fatalf("SyncDeputados: %v", err)
+	}
+	if err := s.SyncVotacoes(ctx, 2024); err != nil {
+		t.Fatalf("SyncVotacoes: %v", err)
+	}
+
+	var proposicao domain.Proposicao
+	if err := db.Collection("proposicoes").FindOne(ctx, bson.M{"tipo": "PL", "numero": "1234", "ano": 2024}).Decode(&proposicao); err != nil {
+		t.Fatalf("proposição da votação não criada: %v", err)
+	}
+	if proposicao.Situacao != domain.SituacaoEmTramitacao || proposicao.Fonte == nil || proposicao.Fonte.IDExterno != "2400001" {
+		t.Errorf("proposição = %+v", proposicao)
+	}
+
+	ana := buscarPolitico(t, db, 1001)
+	var voto domain.Votacao
+	if err := db.Collection("votacoes").FindOne(ctx, bson.M{"politico_id": ana.ID}).Decode(&voto); err != nil {
+		t.Fatalf("voto da deputada 1001 não gravado: %v", err)
+	}
+	if voto.Voto != domain.VotoSim || voto.VotacaoIDExterno != "2401234-56" || voto.ProposicaoID != proposicao.ID || voto.Sessao != "PLEN" {
+		t.Errorf("voto = %+v", voto)
+	}
+
+	bruno := buscarPolitico(t, db, 1002)
+	if n := contar(t, db, "votacoes", bson.M{"politico_id": bruno.ID, "voto": domain.VotoNao}); n != 1 {
+		t.Errorf("%d votos NÃO do deputado 1002; esperado 1", n)
+	}
+
+	// O deputado 9999 não está cadastrado: o voto é descartado e vira problema de qualidade
+	if n := contar(t, db, "votacoes", bson.M{}); n != 2 {
+		t.Errorf("%d votos gravados; esperado 2", n)
+	}
+	if n := validador.Contagem()[domain.RegraVotoPoliticoDesconhecido]; n != 1 {
+		t.Errorf("%d votos de políticos desconhecidos; esperado 1", n)
+	}
+}
+
+func TestSyncDespesas(t *testing.T) {
+	s, db := novoSync(t)
+	ctx := context.Background()
+
+	if err := s.SyncDeputados(ctx); err != nil {
+		t.Fatalf("SyncDeputados: %v", err)
+	}
+	// Só a deputada em exercício tem despesas buscadas; a segunda página vem pelo link "next"
+	if err := s.SyncDespesas(ctx, 2024); err != nil {
+		t.Fatalf("SyncDespesas: %v", err)
+	}
+
+	ana := buscarPolitico(t, db, 1001)
+	if n := contar(t, db, "despesas", bson.M{"politico_id": ana.ID}); n != 4 {
+		t.Fatalf("%d despesas gravadas; esperado 4", n)
+	}
+
+	var combustivel domain.Despesa
+	if err := db.Collection("despesas").FindOne(ctx, bson.M{"cod_documento": 7700001}).Decode(&combustivel); err != nil {
+		t.Fatalf("despesa 7700001 não gravada: %v", err)
+	}
+	if combustivel.CNPJFornecedor != "11222333000181" || combustivel.TipoDocumentoFornecedor != "CNPJ" || combustivel.Valor != 250.4 {
+		t.Errorf("despesa = %+v", combustivel)
+	}
+	if combustivel.Fonte == nil || !strings.HasSuffix(combustivel.Fonte.URL, "/deputados/1001/despesas?ano=2024&mes=1") {
+		t.Errorf("fonte da despesa = %+v", combustivel.Fonte)
+	}
+
+	var aluguel domain.Despesa
+	if err := db.Collection("despesas").FindOne(ctx, bson.M{"num_documento": "REC-3"}).Decode(&aluguel); err != nil {
+		t.Fatalf("despesa sem codDocumento não gravada: %v", err)
+	}
+	if aluguel.TipoDocumentoFornecedor != "CPF" {
+		t.Errorf("tipo do documento do fornecedor = %q; esperado CPF", aluguel.TipoDocumentoFornecedor)
+	}
+
+	// Os dois lançamentos idênticos sem codDocumento são despesas distintas
+	if n := contar(t, db, "despesas", bson.M{"num_documento": "REC-3"}); n != 2 {
+		t.Errorf("%d lançamentos REC-3 gravados; esperados os 2 da resposta", n)
+	}
+
+	// Repetir atualiza os mesmos registros em vez de duplicar, e a reconciliação mantém os
+	// dois lançamentos idênticos
+	if err := s.SyncDespesas(ctx, 2024); err != nil {
+		t.Fatalf("SyncDespesas (repetição): %v", err)
+	}
+	if n := contar(t, db, "despesas", bson.M{}); n != 4 {
+		t.Errorf("%d despesas depois de repetir; esperado 4", n)
+	}
+	for ocorrencia := 0; ocorrencia < 2; ocorrencia++ {
+		if n := contar(t, db, "despesas", bson.M{"num_documento": "REC-3", "ocorrencia": ocorrencia}); n != 1 {
+			t.Errorf("ocorrência %d de REC-3: %d despesas depois de repetir; esperada 1", ocorrencia, n)
+		}
+	}
+}
@@ -1,13 +1,25 @@
 package sync
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"time"
 )
 
+// ErroStatus é o erro de uma resposta com status diferente de 200
+type ErroStatus struct {
+	Status int
+	Corpo  string
+}
+
+func (e *ErroStatus) Error() string {
+	return fmt.Sprintf("status %d: %s", e.Status, e.Corpo)
+}
+
 // HTTPClient é um cliente HTTP com rate limiting
 type HTTPClient struct {
 	client      *http.Client
@@ -24,11 +36,34 @@ func NewHTTPClient(requestsPerSecond int) *HTTPClient {
 	}
 }
 
-// Get faz uma requisição GET e decodifica o JSON
-func (c *HTTPClient) Get(url string, result interface{}) error {
-	<-c.rateLimiter // Rate limiting
+// UsarTransporte troca o transporte HTTP do cliente; nil mantém o padrão
+func (c *HTTPClient) UsarTransporte(t http.RoundTripper) *HTTPClient {
+	if t != nil {
+		c.client.Transport = t
+	}
+	return c
+}
+
+// Get faz uma requisição GET e decodifica o JSON. Com um arquivo no contexto (ComArquivo),
+// a resposta é arquivada; em reprodução (ComReproducao), vem do arquivo, sem acessar a rede.
+func (c *HTTPClient) Get(ctx context.Context, url string, result interface{}) error {
+	if r, ok := reproducaoDe(ctx); ok {
+		body, coletadoEm, err := r.arquivo.Buscar(ctx, url, r.ate)
+		if err != nil {
+			return fmt.Errorf("erro ao ler resposta arquivada: %w", err)
+		}
+		r.coletas.registrar(url, coletadoEm)
+		return decodificar(body, result)
+	}
+
+	// Rate limiting
+	select {
+	case <-c.rateLimiter:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("erro ao criar request: %w", err)
 	}
@@ -44,7 +79,7 @@ func (c *HTTPClient) Get(url string, result interface{}) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+		return &ErroStatus{Status: resp.StatusCode, Corpo: string(body)}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -52,6 +87,17 @@ func (c *HTTPClient) Get(url string, result interface{}) error {
 		return fmt.Errorf("erro ao ler resposta: %w", err)
 	}
 
+	// Falhar ao arquivar não deve interromper a sincronização
+	if a := arquivoDe(ctx); a != nil {
+		if err := a.Guardar(ctx, url, time.Now(), body); err != nil {
+			log.Printf("⚠️  Erro ao arquivar resposta de %s: %v", url, err)
+		}
+	}
+
+	return decodificar(body, result)
+}
+
+func decodificar(body []byte, result interface{}) error {
 	if err := json.Unmarshal(body, result); err != nil {
 		return fmt.Errorf("erro ao decodificar JSON: %w", err)
 	}
@@ -0,0 +1,122 @@
+// Package fixtures grava e reproduz respostas das APIs em arquivos, para testar as
+// sincronizações sem acessar a rede. Cada endereço vira um arquivo JSON no diretório de
+// fixtures, nomeado pelo caminho e pelos parâmetros da requisição (ver Nome).
+//
+// Para gravar as fixtures que faltam a partir das APIs reais (as existentes não mudam,
+// então é só apagar um arquivo para regravá-lo):
+//
+//	GRAVAR_FIXTURES=1 go test ./internal/sync/...
+package fixtures
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lupa-cidada/backend/internal/sync/arquivo"
+)
+
+// VariavelGravar é a variável de ambiente que liga a gravação
+const VariavelGravar = "GRAVAR_FIXTURES"
+
+// tamanhoMaximoNome evita nomes de arquivo longos demais para o sistema de arquivos
+const tamanhoMaximoNome = 150
+
+// Transporte é um http.RoundTripper que responde com as fixtures do diretório.
+// Gravando, as requisições sem fixture vão à rede e as respostas 200 são salvas antes de
+// voltar.
+type Transporte struct {
+	dir    string
+	gravar bool
+	rede   http.RoundTripper
+}
+
+// Novo cria o transporte do diretório; grava as que faltam se GRAVAR_FIXTURES estiver definida
+func Novo(dir string) *Transporte {
+	return &Transporte{
+		dir:    dir,
+		gravar: os.Getenv(VariavelGravar) != "",
+		rede:   http.DefaultTransport,
+	}
+}
+
+func (t *Transporte) RoundTrip(req *http.Request) (*http.Response, error) {
+	caminho := filepath.Join(t.dir, Nome(req.URL))
+	corpo, err := os.ReadFile(caminho)
+	if errors.Is(err, fs.ErrNotExist) && t.gravar {
+		return t.gravarResposta(req, caminho)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("fixture ausente para %s (%s); grave com %s=1", req.URL, caminho, VariavelGravar)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resposta(req, corpo), nil
+}
+
+func (t *Transporte) gravarResposta(req *http.Request, caminho string) (*http.Response, error) {
+	resp, err := t.rede.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	corpo, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(caminho, corpo, 0o644); err != nil {
+		return nil, err
+	}
+	return resposta(req, corpo), nil
+}
+
+func resposta(req *http.Request, corpo []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(corpo)),
+		ContentLength: int64(len(corpo)),
+		Request:       req,
+	}
+}
+
+// Nome é o arquivo da fixture de um endereço: o caminho e os parâmetros, sem o host, com os
+// separadores trocados (ex.: /api/v2/deputados?ano=2024 → api_v2_deputados__ano-2024.json).
+// Nomes longos demais são encurtados com o hash do endereço.
+func Nome(u *url.URL) string {
+	nome := strings.ReplaceAll(strings.Trim(u.Path, "/"), "/", "_")
+	if u.RawQuery != "" {
+		consulta := strings.NewReplacer("&", "_", "=", "-").Replace(u.RawQuery)
+		nome += "__" + consulta
+	}
+	nome = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, nome)
+	nome = strings.TrimSuffix(nome, ".json")
+
+	if len(nome) > tamanhoMaximoNome {
+		nome = nome[:tamanhoMaximoNome-17] + "_" + arquivo.Hash([]byte(u.String()))[:16]
+	}
+	return nome + ".json"
+}
@@ -0,0 +1,25 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/auditoria"
+	"github.com/lupa-cidada/backend/internal/domain"
+)
+
+// NovaFonte descreve o registro de origem de um documento coletado pela execução do contexto:
+// agora, ou em reprodução, quando a resposta arquivada foi coletada
+func NovaFonte(ctx context.Context, sistema, idExterno, url string) domain.Fonte {
+	coletadoEm := time.Now()
+	if r, ok := reproducaoDe(ctx); ok {
+		coletadoEm = r.coletas.de(url, r.ate)
+	}
+	return domain.Fonte{
+		Sistema:    sistema,
+		IDExterno:  idExterno,
+		URL:        url,
+		ColetadoEm: coletadoEm,
+		ExecucaoID: auditoria.Execucao(ctx),
+	}
+}
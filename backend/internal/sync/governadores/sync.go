@@ -7,22 +7,32 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lupa-cidada/backend/internal/auditoria"
 	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/identidade"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/pkg/documento"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // GovernadoresSync sincroniza dados dos Governadores
 type GovernadoresSync struct {
-	db *mongo.Database
+	db         *mongo.Database
+	partidos   *partidos.Catalogo
+	auditoria  *auditoria.Auditoria
+	identidade *identidade.Resolvedor
 }
 
 // NewGovernadoresSync cria um novo sincronizador
 func NewGovernadoresSync(db *mongo.Database) *GovernadoresSync {
 	return &GovernadoresSync{
-		db: db,
+		db:         db,
+		partidos:   partidos.NewCatalogo(db),
+		auditoria:  auditoria.New(db, "governadores"),
+		identidade: identidade.New(db),
 	}
 }
 
@@ -37,9 +47,14 @@ func (s *GovernadoresSync) SyncGovernadores(ctx context.Context) error {
 	governadores := []GovernadorData{
 		// Adicionar governadores aqui
 		// Exemplo (atualizar com dados reais):
-		// {Nome: "Nome do Governador", Estado: "SP", Partido: "PT", ...},
+		// {Nome: "Nome do Governador", Estado: "SP", Partido: "PT", FonteURL: "https://...", ...},
 	}
 
+	return s.Sincronizar(ctx, governadores)
+}
+
+// Sincronizar grava os governadores informados (usado por SyncGovernadores e pelos testes)
+func (s *GovernadoresSync) Sincronizar(ctx context.Context, governadores []GovernadorData) error {
 	// Se a lista estiver vazia, logar aviso
 	if len(governadores) == 0 {
 		log.Println("⚠️  Lista de governadores vazia. Adicione os dados em sync.go")
@@ -48,12 +63,19 @@ func (s *GovernadoresSync) SyncGovernadores(ctx context.Context) error {
 	}
 
 	log.Printf("📊 Total: %d governadores para sincronizar", len(governadores))
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(len(governadores))
 
 	for i, gov := range governadores {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := s.syncGovernador(ctx, gov); err != nil {
 			log.Printf("⚠️  Erro ao sincronizar governador %s (%s): %v", gov.Nome, gov.Estado, err)
+			progresso.Erro(fmt.Errorf("governador %s (%s): %w", gov.Nome, gov.Estado, err))
 			continue
 		}
+		progresso.Processado()
 
 		if (i+1)%10 == 0 {
 			log.Printf("   Processados %d/%d governadores", i+1, len(governadores))
@@ -66,25 +88,19 @@ func (s *GovernadoresSync) SyncGovernadores(ctx context.Context) error {
 
 // syncGovernador sincroniza um governador específico
 func (s *GovernadoresSync) syncGovernador(ctx context.Context, g GovernadorData) error {
-	collection := s.db.Collection("politicos")
+	// O CPF é gravado só com os dígitos, como a identidade o procura
+	cpf := documento.Normalizar(g.CPF)
 
 	// Buscar se o político já existe
-	filter := bson.M{
-		"$or": []bson.M{
-			{"cpf": g.CPF},
-			{
-				"nome_civil": strings.ToUpper(g.NomeCivil),
-				"data_nascimento": bson.M{
-					"$gte": g.DataNascimento.AddDate(0, 0, -1),
-					"$lte": g.DataNascimento.AddDate(0, 0, 1),
-				},
-			},
-		},
+	candidato := identidade.Candidato{
+		Fonte:          "governadores",
+		CPF:            cpf,
+		Nome:           g.Nome,
+		NomeCivil:      g.NomeCivil,
+		DataNascimento: g.DataNascimento,
 	}
-
-	var politicoExistente *domain.Politico
-	err := collection.FindOne(ctx, filter).Decode(&politicoExistente)
-	if err != nil && err != mongo.ErrNoDocuments {
+	politicoExistente, err := s.identidade.Resolver(ctx, candidato)
+	if err != nil {
 		return fmt.Errorf("erro ao buscar político existente: %w", err)
 	}
 
@@ -159,6 +175,9 @@ func (s *GovernadoresSync) syncGovernador(ctx context.Context, g GovernadorData)
 		}
 
 		// Atualizar outros dados
+		if cpf != "" {
+			politico.CPF = cpf // Também corrige os gravados com pontuação
+		}
 		if g.FotoURL != "" {
 			politico.FotoURL = g.FotoURL
 		}
@@ -168,26 +187,20 @@ func (s *GovernadoresSync) syncGovernador(ctx context.Context, g GovernadorData)
 		if g.Telefone != "" {
 			politico.Contato.Telefone = g.Telefone
 		}
-		politico.Partido = domain.Partido{
-			Sigla: g.Partido,
-			Nome:  "",
-			Cor:   getPartidoCor(g.Partido),
-		}
+		partido := s.partidos.Resolver(ctx, g.Partido)
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, politico.HistoricoPartidos, politico.Partido, partido, politico.CreatedAt, time.Now())
+		politico.Partido = partido
 	} else {
 		// Novo político - criar registro
 		politico = domain.Politico{
-			CPF:             g.CPF,
-			Nome:            g.Nome,
-			NomeCivil:       g.NomeCivil,
-			FotoURL:         g.FotoURL,
-			DataNascimento:  g.DataNascimento,
-			Genero:          mapGenero(g.Genero),
-			Partido: domain.Partido{
-				Sigla: g.Partido,
-				Nome:  "",
-				Cor:   getPartidoCor(g.Partido),
-			},
-			CargoAtual: novoCargo,
+			CPF:            cpf,
+			Nome:           g.Nome,
+			NomeCivil:      g.NomeCivil,
+			FotoURL:        g.FotoURL,
+			DataNascimento: g.DataNascimento,
+			Genero:         mapGenero(g.Genero),
+			Partido:        s.partidos.Resolver(ctx, g.Partido),
+			CargoAtual:     novoCargo,
 			Contato: domain.Contato{
 				Email:    g.Email,
 				Telefone: g.Telefone,
@@ -198,6 +211,7 @@ func (s *GovernadoresSync) syncGovernador(ctx context.Context, g GovernadorData)
 			CreatedAt:       time.Now(),
 			UpdatedAt:       time.Now(),
 		}
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, nil, domain.Partido{}, politico.Partido, time.Time{}, politico.CreatedAt)
 		historicoCargos = []domain.CargoAtual{}
 	}
 
@@ -205,35 +219,29 @@ func (s *GovernadoresSync) syncGovernador(ctx context.Context, g GovernadorData)
 	politico.UpdatedAt = time.Now()
 
 	// Upsert no banco
-	updateFilter := bson.M{
-		"$or": []bson.M{
-			{"cpf": g.CPF},
-			{
-				"nome_civil": strings.ToUpper(g.NomeCivil),
-				"data_nascimento": bson.M{
-					"$gte": g.DataNascimento.AddDate(0, 0, -1),
-					"$lte": g.DataNascimento.AddDate(0, 0, 1),
-				},
-			},
-		},
+	updateFilter := identidade.FiltroNovo(candidato)
+	if politicoExistente != nil {
+		updateFilter = bson.M{"_id": politicoExistente.ID}
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"cpf":              politico.CPF,
-			"nome":             politico.Nome,
-			"nome_civil":       politico.NomeCivil,
-			"foto_url":         politico.FotoURL,
-			"data_nascimento":  politico.DataNascimento,
-			"genero":           politico.Genero,
-			"partido":          politico.Partido,
-			"cargo_atual":      politico.CargoAtual,
-			"historico_cargos": politico.HistoricoCargos,
-			"contato":          politico.Contato,
-			"redes_sociais":    politico.RedesSociais,
-			"salario_bruto":    politico.SalarioBruto,
-			"salario_liquido":  politico.SalarioLiquido,
-			"updated_at":       time.Now(),
+			"cpf":                 politico.CPF,
+			"nome":                politico.Nome,
+			"nome_civil":          politico.NomeCivil,
+			"foto_url":            politico.FotoURL,
+			"data_nascimento":     politico.DataNascimento,
+			"genero":              politico.Genero,
+			"partido":             politico.Partido,
+			"cargo_atual":         politico.CargoAtual,
+			"historico_cargos":    politico.HistoricoCargos,
+			"historico_partidos":  politico.HistoricoPartidos,
+			"contato":             politico.Contato,
+			"redes_sociais":       politico.RedesSociais,
+			"salario_bruto":       politico.SalarioBruto,
+			"salario_liquido":     politico.SalarioLiquido,
+			"fontes.governadores": sync.NovaFonte(ctx, "governadores", g.Estado, g.FonteURL),
+			"updated_at":          time.Now(),
 		},
 		"$setOnInsert": bson.M{
 			"_id":        primitive.NewObjectID(),
@@ -241,8 +249,7 @@ func (s *GovernadoresSync) syncGovernador(ctx context.Context, g GovernadorData)
 		},
 	}
 
-	opts := options.Update().SetUpsert(true)
-	_, err = collection.UpdateOne(ctx, updateFilter, update, opts)
+	err = s.auditoria.AtualizarPolitico(ctx, updateFilter, update, true)
 	if err != nil {
 		return fmt.Errorf("erro ao salvar governador: %w", err)
 	}
@@ -263,39 +270,4 @@ func mapGenero(sexo string) domain.Genero {
 	}
 }
 
-// getPartidoCor retorna a cor do partido (simplificado)
-func getPartidoCor(sigla string) string {
-	cores := map[string]string{
-		"PT":   "#E31E24",
-		"PSDB": "#003399",
-		"PSB":  "#FF6600",
-		"PMDB": "#0066CC",
-		"DEM":  "#0066CC",
-		"PP":   "#0066CC",
-		"PSD":  "#FF6600",
-		"PR":   "#0066CC",
-		"PDT":  "#FF0000",
-		"PTB":  "#0066CC",
-		"PPS":  "#FF6600",
-		"PV":   "#00AA00",
-		"PCdoB": "#E31E24",
-		"PSC":  "#0066CC",
-		"PMN":  "#0066CC",
-		"PRP":  "#0066CC",
-		"PHS":  "#0066CC",
-		"PRTB": "#0066CC",
-		"PTC":  "#0066CC",
-		"PSL":  "#0066CC",
-		"REDE": "#00AA00",
-		"MDB":  "#00AA00",
-		"NOVO": "#FF6600",
-		"PL":   "#003366",
-		"UNIÃO": "#0066CC",
-	}
-
-	if cor, ok := cores[strings.ToUpper(sigla)]; ok {
-		return cor
-	}
-	return "#666666"
-}
 
@@ -0,0 +1,149 @@
+package governadores
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func carregarGovernadores(t *testing.T) []GovernadorData {
+	t.Helper()
+	dados, err := os.ReadFile("testdata/governadores.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var governadores []GovernadorData
+	if err := json.Unmarshal(dados, &governadores); err != nil {
+		t.Fatalf("fixture inválida: %v", err)
+	}
+	return governadores
+}
+
+func TestSincronizar(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politicos := db.Collection("politicos")
+
+	// Senadora eleita governadora: encontrada pelo CPF, o mandato no Senado vai para o histórico
+	inicioSenado := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	senadora := domain.Politico{
+		ID:              primitive.NewObjectID(),
+		CPF:             "11144477735",
+		Nome:            "Helena Duarte",
+		NomeCivil:       "Helena Duarte Campos",
+		IDExternoSenado: "5010",
+		Partido:         domain.Partido{Sigla: "PSB"},
+		CargoAtual: domain.CargoAtual{
+			Tipo:        domain.CargoSenador,
+			Esfera:      domain.EsferaFederal,
+			Estado:      "PE",
+			DataInicio:  inicioSenado,
+			EmExercicio: true,
+		},
+		HistoricoCargos: []domain.CargoAtual{},
+		CreatedAt:       inicioSenado,
+	}
+	if _, err := politicos.InsertOne(ctx, senadora); err != nil {
+		t.Fatalf("erro ao cadastrar senadora: %v", err)
+	}
+
+	s := NewGovernadoresSync(db)
+	governadores := carregarGovernadores(t)
+	if err := s.Sincronizar(ctx, governadores); err != nil {
+		t.Fatalf("Sincronizar: %v", err)
+	}
+	// Repetir não duplica o governador sem CPF, que é gravado pelo nome civil e nascimento
+	if err := s.Sincronizar(ctx, governadores); err != nil {
+		t.Fatalf("Sincronizar (repetição): %v", err)
+	}
+
+	if n, _ := politicos.CountDocuments(ctx, bson.M{}); n != 2 {
+		t.Fatalf("%d políticos; esperado 2", n)
+	}
+
+	var helena domain.Politico
+	if err := politicos.FindOne(ctx, bson.M{"_id": senadora.ID}).Decode(&helena); err != nil {
+		t.Fatalf("senadora não encontrada: %v", err)
+	}
+	if helena.CargoAtual.Tipo != domain.CargoGovernador || helena.CargoAtual.Esfera != domain.EsferaEstadual {
+		t.Errorf("cargo atual = %+v", helena.CargoAtual)
+	}
+	// A fonte manda o CPF com pontuação; o cadastro continua só com os dígitos
+	if helena.CPF != "11144477735" {
+		t.Errorf("CPF = %q; esperado 11144477735", helena.CPF)
+	}
+	if len(helena.HistoricoCargos) != 1 || helena.HistoricoCargos[0].Tipo != domain.CargoSenador {
+		t.Errorf("histórico = %+v; esperado só o mandato de senadora", helena.HistoricoCargos)
+	}
+	if f := helena.Fontes["governadores"]; f.IDExterno != "PE" || f.URL != "https://www.pe.gov.br/governadora" {
+		t.Errorf("fonte = %+v", f)
+	}
+
+	// PSL foi incorporado pelo União Brasil
+	var otavio domain.Politico
+	if err := politicos.FindOne(ctx, bson.M{"nome_civil": "Otávio Lins Barreto"}).Decode(&otavio); err != nil {
+		t.Fatalf("governador novo não gravado: %v", err)
+	}
+	if otavio.Partido.Sigla != "UNIÃO" || otavio.CargoAtual.Estado != "AL" {
+		t.Errorf("governador novo = %q, %+v", otavio.Partido.Sigla, otavio.CargoAtual)
+	}
+}
+
+// Sem data de nascimento, só o CPF identifica o governador: repetir a sincronização não pode
+// criar outro cadastro
+func TestSincronizarSemDataNascimento(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politicos := db.Collection("politicos")
+
+	governador := GovernadorData{
+		Nome:        "Rui Prado",
+		NomeCivil:   "Rui Prado Mesquita",
+		CPF:         "529.982.247-25",
+		Partido:     "PSB",
+		Estado:      "SE",
+		DataInicio:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		EmExercicio: true,
+	}
+
+	s := NewGovernadoresSync(db)
+	for i := 0; i < 3; i++ {
+		if err := s.Sincronizar(ctx, []GovernadorData{governador}); err != nil {
+			t.Fatalf("Sincronizar (%dª vez): %v", i+1, err)
+		}
+	}
+
+	var gravados []domain.Politico
+	cursor, err := politicos.Find(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.All(ctx, &gravados); err != nil {
+		t.Fatal(err)
+	}
+	if len(gravados) != 1 {
+		t.Fatalf("%d cadastros depois de três sincronizações; esperado 1", len(gravados))
+	}
+	if gravados[0].CPF != "52998224725" {
+		t.Errorf("CPF = %q; esperado 52998224725", gravados[0].CPF)
+	}
+}
+
+func TestSincronizarListaVazia(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+
+	if err := NewGovernadoresSync(db).SyncGovernadores(ctx); err != nil {
+		t.Fatalf("SyncGovernadores: %v", err)
+	}
+	if n, _ := db.Collection("politicos").CountDocuments(ctx, bson.M{}); n != 0 {
+		t.Errorf("%d políticos gravados com a lista vazia", n)
+	}
+}
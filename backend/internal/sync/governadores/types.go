@@ -17,6 +17,7 @@ type GovernadorData struct {
 	FotoURL        string
 	Email          string
 	Telefone       string
+	FonteURL       string // Página oficial de onde os dados foram copiados
 }
 
 // ParseDate converte string de data para time.Time
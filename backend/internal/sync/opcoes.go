@@ -0,0 +1,33 @@
+package sync
+
+import "net/http"
+
+// Opcoes definem de onde um sincronizador busca os dados. Sem opções, usa a API oficial
+// pela rede; os testes trocam o endereço (ex.: um httptest.Server) ou o transporte
+// (ex.: fixtures.Transporte) para não depender das APIs do governo.
+type Opcoes struct {
+	BaseURL    string
+	Transporte http.RoundTripper
+}
+
+// Opcao ajusta as opções de um sincronizador
+type Opcao func(*Opcoes)
+
+// ComBaseURL troca o endereço da API
+func ComBaseURL(url string) Opcao {
+	return func(o *Opcoes) { o.BaseURL = url }
+}
+
+// ComTransporte troca o transporte HTTP das requisições
+func ComTransporte(t http.RoundTripper) Opcao {
+	return func(o *Opcoes) { o.Transporte = t }
+}
+
+// AplicarOpcoes retorna as opções ajustadas, partindo do endereço padrão da API
+func AplicarOpcoes(baseURL string, opcoes []Opcao) Opcoes {
+	o := Opcoes{BaseURL: baseURL}
+	for _, opcao := range opcoes {
+		opcao(&o)
+	}
+	return o
+}
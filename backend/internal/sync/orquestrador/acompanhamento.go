@@ -0,0 +1,176 @@
+package orquestrador
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gosync "sync"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+)
+
+// maxErros limita quantas mensagens de erro ficam guardadas na execução
+const maxErros = 100
+
+// Acompanhamento guarda o andamento de uma execução enquanto as etapas rodam. Os métodos
+// podem ser chamados de várias goroutines; um Acompanhamento nil descarta tudo.
+type Acompanhamento struct {
+	mu   gosync.Mutex
+	exec domain.ExecucaoSync
+}
+
+func NewAcompanhamento(exec domain.ExecucaoSync) *Acompanhamento {
+	if exec.Erros == nil {
+		exec.Erros = []string{}
+	}
+	return &Acompanhamento{exec: exec}
+}
+
+// Execucao retorna uma cópia do estado atual da execução
+func (a *Acompanhamento) Execucao() domain.ExecucaoSync {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	exec := a.exec
+	exec.Etapas = append([]domain.EtapaSync(nil), a.exec.Etapas...)
+	exec.Erros = append([]string{}, a.exec.Erros...)
+	if a.exec.Qualidade != nil {
+		exec.Qualidade = make(map[domain.RegraQualidade]int, len(a.exec.Qualidade))
+		for regra, n := range a.exec.Qualidade {
+			exec.Qualidade[regra] = n
+		}
+	}
+	return exec
+}
+
+// Iniciar marca a execução como em andamento
+func (a *Acompanhamento) Iniciar() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agora := time.Now()
+	a.exec.Status = domain.StatusExecucaoExecutando
+	a.exec.InicioEm = &agora
+}
+
+// Finalizar registra o resultado da execução; erros de cancelamento a marcam como cancelada
+func (a *Acompanhamento) Finalizar(err error) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agora := time.Now()
+	a.exec.FimEm = &agora
+	switch {
+	case err == nil:
+		a.exec.Status = domain.StatusExecucaoConcluida
+	case errors.Is(err, context.Canceled):
+		a.exec.Status = domain.StatusExecucaoCancelada
+	default:
+		a.exec.Status = domain.StatusExecucaoFalhou
+		a.registrarErro(err.Error())
+	}
+
+	for i := range a.exec.Etapas {
+		if a.exec.Etapas[i].Status == domain.StatusExecucaoPendente || a.exec.Etapas[i].Status == domain.StatusExecucaoExecutando {
+			a.exec.Etapas[i].Status = domain.StatusExecucaoCancelada
+		}
+	}
+}
+
+func (a *Acompanhamento) iniciarEtapa(i int) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agora := time.Now()
+	a.exec.Etapas[i].Status = domain.StatusExecucaoExecutando
+	a.exec.Etapas[i].InicioEm = &agora
+}
+
+func (a *Acompanhamento) concluirEtapa(i int, err error) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agora := time.Now()
+	etapa := &a.exec.Etapas[i]
+	etapa.FimEm = &agora
+	switch {
+	case err == nil:
+		etapa.Status = domain.StatusExecucaoConcluida
+	case errors.Is(err, context.Canceled):
+		etapa.Status = domain.StatusExecucaoCancelada
+	default:
+		etapa.Status = domain.StatusExecucaoFalhou
+		etapa.Mensagem = err.Error()
+		a.registrarErro(fmt.Sprintf("%s/%s: %v", etapa.Fonte, etapa.Entidade, err))
+	}
+}
+
+// registrarQualidade guarda a contagem de problemas de qualidade encontrados até agora
+func (a *Acompanhamento) registrarQualidade(contagem map[domain.RegraQualidade]int) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.exec.Qualidade = contagem
+}
+
+// registrarErro guarda a mensagem, descartando as mais antigas além do limite (chamar com mu travado)
+func (a *Acompanhamento) registrarErro(msg string) {
+	a.exec.Erros = append(a.exec.Erros, msg)
+	if len(a.exec.Erros) > maxErros {
+		a.exec.Erros = a.exec.Erros[len(a.exec.Erros)-maxErros:]
+	}
+}
+
+// progressoEtapa liga o acompanhamento de sync.Progresso a uma etapa
+type progressoEtapa struct {
+	a *Acompanhamento
+	i int
+}
+
+func (a *Acompanhamento) progressoEtapa(i int) progressoEtapa {
+	return progressoEtapa{a: a, i: i}
+}
+
+func (p progressoEtapa) Total(n int) {
+	if p.a == nil {
+		return
+	}
+	p.a.mu.Lock()
+	defer p.a.mu.Unlock()
+	p.a.exec.Etapas[p.i].Total = n
+}
+
+func (p progressoEtapa) Processado() {
+	if p.a == nil {
+		return
+	}
+	p.a.mu.Lock()
+	defer p.a.mu.Unlock()
+	p.a.exec.Etapas[p.i].Processados++
+}
+
+func (p progressoEtapa) Erro(err error) {
+	if p.a == nil {
+		return
+	}
+	p.a.mu.Lock()
+	defer p.a.mu.Unlock()
+	etapa := &p.a.exec.Etapas[p.i]
+	etapa.Erros++
+	p.a.registrarErro(fmt.Sprintf("%s/%s: %v", etapa.Fonte, etapa.Entidade, err))
+}
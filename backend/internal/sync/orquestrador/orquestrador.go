@@ -0,0 +1,247 @@
+// Package orquestrador decide o que sincronizar e em que ordem, e executa as sincronizações
+// das fontes. É usado tanto pelo comando de sincronização quanto pelos jobs da API.
+package orquestrador
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lupa-cidada/backend/internal/analise"
+	"github.com/lupa-cidada/backend/internal/auditoria"
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"github.com/lupa-cidada/backend/internal/qualidade"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/internal/sync/camara"
+	"github.com/lupa-cidada/backend/internal/sync/governadores"
+	"github.com/lupa-cidada/backend/internal/sync/presidente"
+	"github.com/lupa-cidada/backend/internal/sync/senado"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FonteTodas sincroniza todas as fontes
+const FonteTodas = "todas"
+
+// etapa é uma sincronização que pode ser pedida individualmente
+type etapa struct {
+	fonte    string
+	entidade string
+	titulo   string
+	executar func(ctx context.Context, r *rodada, ano int) error
+}
+
+// etapas na ordem de execução: políticos antes dos dados ligados a eles, e a análise
+// de despesas por último
+var etapas = []etapa{
+	{"camara", "deputados", "🏛️  CÂMARA DOS DEPUTADOS", func(ctx context.Context, r *rodada, _ int) error {
+		return r.camara().SyncDeputados(ctx)
+	}},
+	{"camara", "votacoes", "📊 VOTAÇÕES DA CÂMARA", func(ctx context.Context, r *rodada, ano int) error {
+		return r.camara().SyncVotacoes(ctx, ano)
+	}},
+	{"camara", "proposicoes", "📄 PROPOSIÇÕES DA CÂMARA", func(ctx context.Context, r *rodada, ano int) error {
+		return r.camara().SyncProposicoes(ctx, ano)
+	}},
+	{"camara", "despesas", "💰 DESPESAS DA CÂMARA", func(ctx context.Context, r *rodada, ano int) error {
+		return r.camara().SyncDespesas(ctx, ano)
+	}},
+	{"camara", "presencas", "✅ PRESENÇAS EM EVENTOS DA CÂMARA", func(ctx context.Context, r *rodada, ano int) error {
+		return r.camara().SyncPresencas(ctx, ano)
+	}},
+	{"senado", "senadores", "🏛️  SENADO FEDERAL", func(ctx context.Context, r *rodada, _ int) error {
+		return senado.NewSenadoSync(r.db).SyncSenadores(ctx)
+	}},
+	{"presidente", "presidente", "🇧🇷 PRESIDÊNCIA DA REPÚBLICA", func(ctx context.Context, r *rodada, _ int) error {
+		return presidente.NewPresidenteSync(r.db).SyncPresidente(ctx)
+	}},
+	{"governadores", "governadores", "🏛️  GOVERNADORES DOS ESTADOS", func(ctx context.Context, r *rodada, _ int) error {
+		return governadores.NewGovernadoresSync(r.db).SyncGovernadores(ctx)
+	}},
+	{"analise", "alertas", "🚨 ALERTAS DE DESPESAS", func(ctx context.Context, r *rodada, ano int) error {
+		return analise.NewAnaliseDespesas(r.db).Executar(ctx, ano)
+	}},
+}
+
+// Orquestrador executa as etapas de sincronização sobre um banco
+type Orquestrador struct {
+	db      *mongo.Database
+	arquivo sync.Arquivo
+}
+
+// New cria o orquestrador; com arquivo, as respostas das APIs são arquivadas para reprocessamento
+func New(db *mongo.Database, arquivo sync.Arquivo) *Orquestrador {
+	return &Orquestrador{db: db, arquivo: arquivo}
+}
+
+// rodada guarda o que é compartilhado entre as etapas de uma mesma execução
+type rodada struct {
+	db *mongo.Database
+
+	// A Câmara é compartilhada entre as etapas para reaproveitar o limitador de requisições
+	camaraSync *camara.CamaraSync
+}
+
+func (r *rodada) camara() *camara.CamaraSync {
+	if r.camaraSync == nil {
+		r.camaraSync = camara.NewCamaraSync(r.db)
+	}
+	return r.camaraSync
+}
+
+// Planejar valida o pedido e retorna as etapas a executar, na ordem
+func Planejar(p domain.PedidoSync) ([]domain.EtapaSync, error) {
+	fonte := strings.ToLower(p.Fonte)
+	todas := fonte == "" || fonte == FonteTodas
+
+	if !todas && !fonteValida(fonte) {
+		return nil, fmt.Errorf("fonte desconhecida: %s", p.Fonte)
+	}
+
+	pedidas := make(map[string]bool)
+	for _, e := range p.Entidades {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if !entidadeValida(fonte, todas, e) {
+			return nil, fmt.Errorf("entidade desconhecida para a fonte %s: %s", p.Fonte, e)
+		}
+		pedidas[e] = true
+	}
+
+	var plano []domain.EtapaSync
+	for _, e := range etapas {
+		if !todas && e.fonte != fonte {
+			continue
+		}
+		if len(pedidas) > 0 && !pedidas[e.entidade] {
+			continue
+		}
+		plano = append(plano, domain.EtapaSync{
+			Fonte:    e.fonte,
+			Entidade: e.entidade,
+			Status:   domain.StatusExecucaoPendente,
+		})
+	}
+
+	if len(plano) == 0 {
+		return nil, fmt.Errorf("nada a sincronizar")
+	}
+	return plano, nil
+}
+
+// Fontes lista as fontes e as entidades que cada uma sincroniza
+func Fontes() map[string][]string {
+	fontes := make(map[string][]string)
+	for _, e := range etapas {
+		fontes[e.fonte] = append(fontes[e.fonte], e.entidade)
+	}
+	return fontes
+}
+
+func fonteValida(fonte string) bool {
+	for _, e := range etapas {
+		if e.fonte == fonte {
+			return true
+		}
+	}
+	return false
+}
+
+func entidadeValida(fonte string, todas bool, entidade string) bool {
+	for _, e := range etapas {
+		if (todas || e.fonte == fonte) && e.entidade == entidade {
+			return true
+		}
+	}
+	return false
+}
+
+// Executar roda as etapas planejadas em ordem, associando as alterações ao ID da execução.
+// Uma etapa com erro não impede as seguintes; o cancelamento do contexto interrompe tudo.
+// Os registros gravados são validados pelo validador de qualidade do contexto; sem um,
+// é criado um para a execução.
+func (o *Orquestrador) Executar(ctx context.Context, execucaoID string, ano int, plano []domain.EtapaSync, acomp *Acompanhamento) error {
+	ctx = auditoria.ComExecucao(ctx, execucaoID)
+	log.Printf("🆔 Execução: %s", execucaoID)
+
+	if o.arquivo != nil && !sync.EmReproducao(ctx) {
+		ctx = sync.ComArquivo(ctx, o.arquivo)
+	}
+
+	validador := qualidade.De(ctx)
+	if validador == nil {
+		validador = qualidade.NewValidador(o.db, execucaoID)
+		ctx = qualidade.ComValidador(ctx, validador)
+	}
+	defer registrarQualidade(validador, acomp)
+
+	// Garantir que o cadastro de partidos existe antes de sincronizar políticos
+	if err := partidos.Semear(ctx, o.db); err != nil {
+		log.Printf("⚠️ Erro ao popular partidos: %v", err)
+	}
+
+	r := &rodada{db: o.db}
+	falhas := 0
+	for i, p := range plano {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		e := buscarEtapa(p.Fonte, p.Entidade)
+		log.Println("")
+		log.Println(e.titulo)
+		log.Println(strings.Repeat("-", len([]rune(e.titulo))))
+
+		acomp.iniciarEtapa(i)
+		err := e.executar(sync.ComProgresso(ctx, acomp.progressoEtapa(i)), r, ano)
+		acomp.concluirEtapa(i, err)
+		acomp.registrarQualidade(validador.Contagem())
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			falhas++
+			log.Printf("❌ Erro na sincronização (%s/%s): %v", p.Fonte, p.Entidade, err)
+		}
+	}
+
+	if falhas > 0 {
+		return fmt.Errorf("%d de %d etapas falharam", falhas, len(plano))
+	}
+	return nil
+}
+
+// registrarQualidade guarda na execução e mostra no log os problemas de qualidade encontrados
+func registrarQualidade(validador *qualidade.Validador, acomp *Acompanhamento) {
+	contagem := validador.Contagem()
+	acomp.registrarQualidade(contagem)
+
+	total := 0
+	for _, n := range contagem {
+		total += n
+	}
+	if total == 0 {
+		log.Println("🧪 Qualidade: nenhum problema encontrado")
+		return
+	}
+
+	log.Printf("🧪 Qualidade: %d problemas encontrados", total)
+	for _, regra := range domain.RegrasQualidade {
+		if n := contagem[regra]; n > 0 {
+			log.Printf("   %s: %d", regra, n)
+		}
+	}
+}
+
+func buscarEtapa(fonte, entidade string) etapa {
+	for _, e := range etapas {
+		if e.fonte == fonte && e.entidade == entidade {
+			return e
+		}
+	}
+	panic("etapa de sincronização desconhecida: " + fonte + "/" + entidade)
+}
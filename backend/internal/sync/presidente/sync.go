@@ -7,22 +7,32 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lupa-cidada/backend/internal/auditoria"
 	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/identidade"
+	"github.com/lupa-cidada/backend/internal/partidos"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/pkg/documento"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // PresidenteSync sincroniza dados do Presidente da República
 type PresidenteSync struct {
-	db *mongo.Database
+	db         *mongo.Database
+	partidos   *partidos.Catalogo
+	auditoria  *auditoria.Auditoria
+	identidade *identidade.Resolvedor
 }
 
 // NewPresidenteSync cria um novo sincronizador
 func NewPresidenteSync(db *mongo.Database) *PresidenteSync {
 	return &PresidenteSync{
-		db: db,
+		db:         db,
+		partidos:   partidos.NewCatalogo(db),
+		auditoria:  auditoria.New(db, "presidente"),
+		identidade: identidade.New(db),
 	}
 }
 
@@ -45,15 +55,45 @@ func (s *PresidenteSync) SyncPresidente(ctx context.Context) error {
 		EmExercicio:    true,
 		FotoURL:        "https://www.gov.br/planalto/pt-br/acompanhe-o-planalto/fotos-do-presidente",
 		Email:          "presidencia@planalto.gov.br",
+		FonteURL:       "https://www.gov.br/planalto/",
 	}
 
-	return s.syncPresidente(ctx, presidenteData)
+	return s.Sincronizar(ctx, presidenteData)
+}
+
+// Sincronizar grava o presidente informado e tira o cargo de quem o ocupava antes
+// (usado por SyncPresidente e pelos testes)
+func (s *PresidenteSync) Sincronizar(ctx context.Context, presidenteData PresidenteData) error {
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(1)
+	if err := s.syncPresidente(ctx, presidenteData); err != nil {
+		progresso.Erro(err)
+		return err
+	}
+	progresso.Processado()
+	return nil
 }
 
 // syncPresidente sincroniza um presidente específico
 func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) error {
 	collection := s.db.Collection("politicos")
 
+	// O CPF é gravado só com os dígitos, como a identidade o procura
+	cpf := documento.Normalizar(p.CPF)
+
+	// Buscar o cadastro do presidente (pode ser o mesmo que já tem o cargo de presidente)
+	candidato := identidade.Candidato{
+		Fonte:          "presidente",
+		CPF:            cpf,
+		Nome:           p.Nome,
+		NomeCivil:      p.NomeCivil,
+		DataNascimento: p.DataNascimento,
+	}
+	politicoExistente, err := s.identidade.Resolver(ctx, candidato)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar político existente: %w", err)
+	}
+
 	// IMPORTANTE: Remover cargo de presidente de TODOS os outros políticos primeiro
 	// (só pode haver um presidente em exercício por vez)
 	filterOutrosPresidentes := bson.M{
@@ -75,11 +115,8 @@ func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) e
 
 	// Remover cargo de presidente de todos os outros (exceto se for a mesma pessoa)
 	for _, outro := range outrosPresidentes {
-		nomeOutro := strings.ToUpper(strings.TrimSpace(outro.NomeCivil))
-		nomeNovo := strings.ToUpper(strings.TrimSpace(p.NomeCivil))
-		
 		// Se não é a mesma pessoa, remover cargo de presidente
-		if !strings.Contains(nomeOutro, nomeNovo) && !strings.Contains(nomeNovo, nomeOutro) {
+		if politicoExistente == nil || outro.ID != politicoExistente.ID {
 			log.Printf("⚠️  Removendo cargo de presidente de: %s", outro.Nome)
 			
 			cargoAnterior := outro.CargoAtual
@@ -111,61 +148,12 @@ func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) e
 				}
 			}
 			
-			collection.UpdateOne(ctx, bson.M{"_id": outro.ID}, bson.M{
+			s.auditoria.AtualizarPolitico(ctx, bson.M{"_id": outro.ID}, bson.M{
 				"$set": bson.M{
 					"cargo_atual": novoCargoAntigo,
 					"historico_cargos": historicoAntigo,
 				},
-			})
-		}
-	}
-
-	// Agora buscar o político correto (pode ser o mesmo que já tinha cargo de presidente)
-	var politicoExistente *domain.Politico
-	filterPresidente := bson.M{
-		"cargo_atual.tipo": domain.CargoPresidente,
-		"cargo_atual.em_exercicio": true,
-	}
-	err = collection.FindOne(ctx, filterPresidente).Decode(&politicoExistente)
-	if err != nil && err != mongo.ErrNoDocuments {
-		return fmt.Errorf("erro ao buscar presidente existente: %w", err)
-	}
-
-	// Se encontrou presidente, verificar se é a mesma pessoa
-	if politicoExistente != nil {
-		nomeExistente := strings.ToUpper(strings.TrimSpace(politicoExistente.NomeCivil))
-		nomeNovo := strings.ToUpper(strings.TrimSpace(p.NomeCivil))
-		
-		// Se os nomes são muito diferentes, buscar novamente
-		if !strings.Contains(nomeExistente, nomeNovo) && !strings.Contains(nomeNovo, nomeExistente) {
-			// Presidente diferente, buscar pelo nome correto
-			politicoExistente = nil
-		}
-	}
-
-	// Se não encontrou por cargo ou é pessoa diferente, buscar por CPF ou nome+data
-	if politicoExistente == nil {
-		filter := bson.M{}
-		
-		// Se tem CPF, buscar por CPF
-		if p.CPF != "" {
-			filter["cpf"] = p.CPF
-		} else {
-			// Buscar por nome civil exato (case insensitive) E data de nascimento exata
-			// Usar regex para match exato do nome
-			nomeRegex := "^" + strings.ReplaceAll(strings.ToUpper(p.NomeCivil), " ", "\\s+") + "$"
-			filter["$and"] = []bson.M{
-				{"nome_civil": bson.M{"$regex": nomeRegex, "$options": "i"}},
-				{"data_nascimento": bson.M{
-					"$gte": p.DataNascimento.AddDate(0, 0, -1),
-					"$lte": p.DataNascimento.AddDate(0, 0, 1),
-				}},
-			}
-		}
-
-		err = collection.FindOne(ctx, filter).Decode(&politicoExistente)
-		if err != nil && err != mongo.ErrNoDocuments {
-			return fmt.Errorf("erro ao buscar político existente: %w", err)
+			}, false)
 		}
 	}
 
@@ -234,31 +222,25 @@ func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) e
 		if p.Telefone != "" {
 			politico.Contato.Telefone = p.Telefone
 		}
-		if p.CPF != "" {
-			politico.CPF = p.CPF
-		}
-		politico.Partido = domain.Partido{
-			Sigla: p.Partido,
-			Nome:  "",
-			Cor:   getPartidoCor(p.Partido),
+		if cpf != "" {
+			politico.CPF = cpf
 		}
+		partido := s.partidos.Resolver(ctx, p.Partido)
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, politico.HistoricoPartidos, politico.Partido, partido, politico.CreatedAt, time.Now())
+		politico.Partido = partido
 		politico.DataNascimento = p.DataNascimento
 		politico.Genero = mapGenero(p.Genero)
 	} else {
 		// Novo político - criar registro
 		politico = domain.Politico{
-			CPF:             p.CPF,
-			Nome:            p.Nome,
-			NomeCivil:       p.NomeCivil,
-			FotoURL:         p.FotoURL,
-			DataNascimento:  p.DataNascimento,
-			Genero:          mapGenero(p.Genero),
-			Partido: domain.Partido{
-				Sigla: p.Partido,
-				Nome:  "",
-				Cor:   getPartidoCor(p.Partido),
-			},
-			CargoAtual: novoCargo,
+			CPF:            cpf,
+			Nome:           p.Nome,
+			NomeCivil:      p.NomeCivil,
+			FotoURL:        p.FotoURL,
+			DataNascimento: p.DataNascimento,
+			Genero:         mapGenero(p.Genero),
+			Partido:        s.partidos.Resolver(ctx, p.Partido),
+			CargoAtual:     novoCargo,
 			Contato: domain.Contato{
 				Email:    p.Email,
 				Telefone: p.Telefone,
@@ -269,6 +251,7 @@ func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) e
 			CreatedAt:       time.Now(),
 			UpdatedAt:       time.Now(),
 		}
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, nil, domain.Partido{}, politico.Partido, time.Time{}, politico.CreatedAt)
 		historicoCargos = []domain.CargoAtual{}
 	}
 
@@ -276,41 +259,29 @@ func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) e
 	politico.UpdatedAt = time.Now()
 
 	// Upsert no banco
-	// Se já existe um presidente, usar o ID dele, senão buscar por CPF ou nome+data
-	var updateFilter bson.M
+	updateFilter := identidade.FiltroNovo(candidato)
 	if politicoExistente != nil {
 		updateFilter = bson.M{"_id": politicoExistente.ID}
-	} else {
-		updateFilter = bson.M{}
-		if p.CPF != "" {
-			updateFilter["cpf"] = p.CPF
-		} else {
-			updateFilter["$and"] = []bson.M{
-				{"nome_civil": bson.M{"$regex": "^" + strings.ToUpper(p.NomeCivil) + "$", "$options": "i"}},
-				{"data_nascimento": bson.M{
-					"$gte": p.DataNascimento.AddDate(0, 0, -1),
-					"$lte": p.DataNascimento.AddDate(0, 0, 1),
-				}},
-			}
-		}
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"cpf":              politico.CPF,
-			"nome":             politico.Nome,
-			"nome_civil":       politico.NomeCivil,
-			"foto_url":         politico.FotoURL,
-			"data_nascimento":  politico.DataNascimento,
-			"genero":           politico.Genero,
-			"partido":          politico.Partido,
-			"cargo_atual":      politico.CargoAtual,
-			"historico_cargos": politico.HistoricoCargos,
-			"contato":          politico.Contato,
-			"redes_sociais":    politico.RedesSociais,
-			"salario_bruto":    politico.SalarioBruto,
-			"salario_liquido":  politico.SalarioLiquido,
-			"updated_at":       time.Now(),
+			"cpf":                politico.CPF,
+			"nome":               politico.Nome,
+			"nome_civil":         politico.NomeCivil,
+			"foto_url":           politico.FotoURL,
+			"data_nascimento":    politico.DataNascimento,
+			"genero":             politico.Genero,
+			"partido":            politico.Partido,
+			"cargo_atual":        politico.CargoAtual,
+			"historico_cargos":   politico.HistoricoCargos,
+			"historico_partidos": politico.HistoricoPartidos,
+			"contato":            politico.Contato,
+			"redes_sociais":      politico.RedesSociais,
+			"salario_bruto":      politico.SalarioBruto,
+			"salario_liquido":    politico.SalarioLiquido,
+			"fontes.presidente":  sync.NovaFonte(ctx, "presidente", "", p.FonteURL),
+			"updated_at":         time.Now(),
 		},
 		"$setOnInsert": bson.M{
 			"_id":        primitive.NewObjectID(),
@@ -318,8 +289,7 @@ func (s *PresidenteSync) syncPresidente(ctx context.Context, p PresidenteData) e
 		},
 	}
 
-	opts := options.Update().SetUpsert(true)
-	_, err = collection.UpdateOne(ctx, updateFilter, update, opts)
+	err = s.auditoria.AtualizarPolitico(ctx, updateFilter, update, true)
 	if err != nil {
 		return fmt.Errorf("erro ao salvar presidente: %w", err)
 	}
@@ -340,39 +310,4 @@ func mapGenero(sexo string) domain.Genero {
 	}
 }
 
-// getPartidoCor retorna a cor do partido (simplificado)
-func getPartidoCor(sigla string) string {
-	cores := map[string]string{
-		"PT":   "#E31E24",
-		"PSDB": "#003399",
-		"PSB":  "#FF6600",
-		"PMDB": "#0066CC",
-		"DEM":  "#0066CC",
-		"PP":   "#0066CC",
-		"PSD":  "#FF6600",
-		"PR":   "#0066CC",
-		"PDT":  "#FF0000",
-		"PTB":  "#0066CC",
-		"PPS":  "#FF6600",
-		"PV":   "#00AA00",
-		"PCdoB": "#E31E24",
-		"PSC":  "#0066CC",
-		"PMN":  "#0066CC",
-		"PRP":  "#0066CC",
-		"PHS":  "#0066CC",
-		"PRTB": "#0066CC",
-		"PTC":  "#0066CC",
-		"PSL":  "#0066CC",
-		"REDE": "#00AA00",
-		"MDB":  "#00AA00",
-		"NOVO": "#FF6600",
-		"PL":   "#003366",
-		"UNIÃO": "#0066CC",
-	}
-
-	if cor, ok := cores[strings.ToUpper(sigla)]; ok {
-		return cor
-	}
-	return "#666666"
-}
 
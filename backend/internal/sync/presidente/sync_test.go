@@ -0,0 +1,90 @@
+package presidente
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSincronizarTrocaDePresidente(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politicos := db.Collection("politicos")
+
+	// Presidente anterior, que antes foi governador: ao sair, volta a ter o último cargo
+	inicioGoverno := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	inicioMandato := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	governo := domain.CargoAtual{
+		Tipo:       domain.CargoGovernador,
+		Esfera:     domain.EsferaEstadual,
+		Estado:     "BA",
+		DataInicio: inicioGoverno,
+		DataFim:    time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	anterior := domain.Politico{
+		ID:        primitive.NewObjectID(),
+		Nome:      "Roberto Farias",
+		NomeCivil: "Roberto Farias Neto",
+		CargoAtual: domain.CargoAtual{
+			Tipo:        domain.CargoPresidente,
+			Esfera:      domain.EsferaFederal,
+			Estado:      "BR",
+			DataInicio:  inicioMandato,
+			EmExercicio: true,
+		},
+		HistoricoCargos: []domain.CargoAtual{governo},
+		CreatedAt:       inicioGoverno,
+	}
+	if _, err := politicos.InsertOne(ctx, anterior); err != nil {
+		t.Fatalf("erro ao cadastrar presidente anterior: %v", err)
+	}
+
+	dados, err := os.ReadFile("testdata/presidente.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var novo PresidenteData
+	if err := json.Unmarshal(dados, &novo); err != nil {
+		t.Fatalf("fixture inválida: %v", err)
+	}
+
+	s := NewPresidenteSync(db)
+	if err := s.Sincronizar(ctx, novo); err != nil {
+		t.Fatalf("Sincronizar: %v", err)
+	}
+
+	// Só pode haver um presidente em exercício
+	cursor, err := politicos.Find(ctx, bson.M{"cargo_atual.tipo": domain.CargoPresidente, "cargo_atual.em_exercicio": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var presidentes []domain.Politico
+	if err := cursor.All(ctx, &presidentes); err != nil {
+		t.Fatal(err)
+	}
+	if len(presidentes) != 1 || presidentes[0].NomeCivil != "Marta Albuquerque Nunes" {
+		t.Fatalf("presidentes em exercício = %+v", presidentes)
+	}
+	marta := presidentes[0]
+	if marta.Partido.Sigla != "PDT" || marta.Genero != domain.GeneroFeminino || marta.Fontes["presidente"].URL != "https://www.gov.br/planalto/" {
+		t.Errorf("presidente nova = %q, %q, %+v", marta.Partido.Sigla, marta.Genero, marta.Fontes)
+	}
+
+	var roberto domain.Politico
+	if err := politicos.FindOne(ctx, bson.M{"_id": anterior.ID}).Decode(&roberto); err != nil {
+		t.Fatalf("presidente anterior não encontrado: %v", err)
+	}
+	if roberto.CargoAtual.Tipo != domain.CargoGovernador || roberto.CargoAtual.Estado != "BA" {
+		t.Errorf("cargo restaurado = %+v; esperado o de governador", roberto.CargoAtual)
+	}
+	if len(roberto.HistoricoCargos) != 1 || roberto.HistoricoCargos[0].Tipo != domain.CargoPresidente || roberto.HistoricoCargos[0].EmExercicio {
+		t.Errorf("histórico = %+v; esperado o mandato de presidente encerrado", roberto.HistoricoCargos)
+	}
+}
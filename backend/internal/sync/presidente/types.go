@@ -20,6 +20,7 @@ type PresidenteData struct {
 	FotoURL        string
 	Email          string
 	Telefone       string
+	FonteURL       string // Página oficial de onde os dados foram copiados
 }
 
 // ParseDate converte string de data para time.Time
@@ -0,0 +1,33 @@
+package sync
+
+import "context"
+
+// Progresso recebe o andamento de uma sincronização. As sincronizações informam o total de
+// itens encontrados e cada item processado ou com erro; quem as executa decide o que fazer
+// com isso (o comando de sincronização ignora, os jobs da API expõem em /admin/sync).
+type Progresso interface {
+	Total(n int)
+	Processado()
+	Erro(err error)
+}
+
+type chaveProgresso struct{}
+
+// ComProgresso associa o acompanhamento ao contexto da sincronização
+func ComProgresso(ctx context.Context, p Progresso) context.Context {
+	return context.WithValue(ctx, chaveProgresso{}, p)
+}
+
+// ProgressoDe retorna o acompanhamento do contexto, ou um que descarta tudo
+func ProgressoDe(ctx context.Context) Progresso {
+	if p, ok := ctx.Value(chaveProgresso{}).(Progresso); ok {
+		return p
+	}
+	return semProgresso{}
+}
+
+type semProgresso struct{}
+
+func (semProgresso) Total(int)   {}
+func (semProgresso) Processado() {}
+func (semProgresso) Erro(error)  {}
@@ -7,12 +7,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lupa-cidada/backend/internal/auditoria"
 	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/identidade"
+	"github.com/lupa-cidada/backend/internal/partidos"
 	"github.com/lupa-cidada/backend/internal/sync"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -21,15 +23,25 @@ const (
 
 // SenadoSync sincroniza dados do Senado Federal
 type SenadoSync struct {
-	client *sync.HTTPClient
-	db     *mongo.Database
+	client     *sync.HTTPClient
+	baseURL    string
+	db         *mongo.Database
+	partidos   *partidos.Catalogo
+	auditoria  *auditoria.Auditoria
+	identidade *identidade.Resolvedor
 }
 
-// NewSenadoSync cria um novo sincronizador
-func NewSenadoSync(db *mongo.Database) *SenadoSync {
+// NewSenadoSync cria um novo sincronizador. As opções trocam o endereço da API ou o
+// transporte HTTP (usado nos testes com fixtures).
+func NewSenadoSync(db *mongo.Database, opcoes ...sync.Opcao) *SenadoSync {
+	o := sync.AplicarOpcoes(BaseURL, opcoes)
 	return &SenadoSync{
-		client: sync.NewHTTPClient(3), // 3 requests por segundo (Senado é mais lento)
-		db:     db,
+		client:     sync.NewHTTPClient(3).UsarTransporte(o.Transporte), // 3 requests por segundo (Senado é mais lento)
+		baseURL:    o.BaseURL,
+		db:         db,
+		partidos:   partidos.NewCatalogo(db),
+		auditoria:  auditoria.New(db, "senado"),
+		identidade: identidade.New(db),
 	}
 }
 
@@ -37,22 +49,29 @@ func NewSenadoSync(db *mongo.Database) *SenadoSync {
 func (s *SenadoSync) SyncSenadores(ctx context.Context) error {
 	log.Println("📥 Buscando senadores do Senado Federal...")
 
-	url := fmt.Sprintf("%s/senador/lista/atual.json", BaseURL)
+	url := fmt.Sprintf("%s/senador/lista/atual.json", s.baseURL)
 
 	var resp SenadoresResponse
-	if err := s.client.Get(url, &resp); err != nil {
+	if err := s.client.Get(ctx, url, &resp); err != nil {
 		return fmt.Errorf("erro ao buscar senadores: %w", err)
 	}
 
 	senadores := resp.ListaParlamentarEmExercicio.Parlamentares.Parlamentar
 	log.Printf("📊 Total: %d senadores encontrados", len(senadores))
+	progresso := sync.ProgressoDe(ctx)
+	progresso.Total(len(senadores))
 
 	for i, sen := range senadores {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := s.syncSenador(ctx, sen); err != nil {
 			log.Printf("⚠️  Erro ao sincronizar senador %s: %v",
 				sen.IdentificacaoParlamentar.NomeParlamentar, err)
+			progresso.Erro(fmt.Errorf("senador %s: %w", sen.IdentificacaoParlamentar.NomeParlamentar, err))
 			continue
 		}
+		progresso.Processado()
 
 		if (i+1)%20 == 0 {
 			log.Printf("   Processados %d/%d senadores", i+1, len(senadores))
@@ -63,35 +82,14 @@ func (s *SenadoSync) SyncSenadores(ctx context.Context) error {
 	return nil
 }
 
-// buscarPoliticoExistente busca um político existente por nome+data de nascimento
-func (s *SenadoSync) buscarPoliticoExistente(ctx context.Context, nomeCivil string, dataNascimento time.Time) (*domain.Politico, error) {
-	collection := s.db.Collection("politicos")
-
-	if nomeCivil != "" && !dataNascimento.IsZero() {
-		var politico domain.Politico
-		err := collection.FindOne(ctx, bson.M{
-			"nome_civil":      nomeCivil,
-			"data_nascimento": dataNascimento,
-		}).Decode(&politico)
-		if err == nil {
-			return &politico, nil
-		}
-		if err != mongo.ErrNoDocuments {
-			return nil, err
-		}
-	}
-
-	return nil, nil // Não encontrado
-}
-
 // syncSenador sincroniza um senador específico
 func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 	id := sen.IdentificacaoParlamentar
 
 	// Buscar detalhes do senador
-	url := fmt.Sprintf("%s/senador/%s.json", BaseURL, id.CodigoParlamentar)
+	url := fmt.Sprintf("%s/senador/%s.json", s.baseURL, id.CodigoParlamentar)
 	var detalhes SenadorDetalheResponse
-	if err := s.client.Get(url, &detalhes); err != nil {
+	if err := s.client.Get(ctx, url, &detalhes); err != nil {
 		// Se falhar, usar dados básicos
 		log.Printf("   Usando dados básicos para %s", id.NomeParlamentar)
 	}
@@ -100,7 +98,13 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 	dataNascimento := ParseDate(d.DadosBasicosParlamentar.DataNascimento)
 
 	// Buscar se o político já existe no banco
-	politicoExistente, err := s.buscarPoliticoExistente(ctx, id.NomeCompletoParlamentar, dataNascimento)
+	politicoExistente, err := s.identidade.Resolver(ctx, identidade.Candidato{
+		Fonte:          "senado",
+		IDSenado:       id.CodigoParlamentar,
+		Nome:           id.NomeParlamentar,
+		NomeCivil:      id.NomeCompletoParlamentar,
+		DataNascimento: dataNascimento,
+	})
 	if err != nil {
 		return fmt.Errorf("erro ao buscar político existente: %w", err)
 	}
@@ -175,11 +179,9 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 		if d.DadosBasicosParlamentar.EnderecoParlamentar != "" {
 			politico.Contato.Gabinete = d.DadosBasicosParlamentar.EnderecoParlamentar
 		}
-		politico.Partido = domain.Partido{
-			Sigla: id.SiglaPartidoParlamentar,
-			Nome:  "",
-			Cor:   getPartidoCor(id.SiglaPartidoParlamentar),
-		}
+		partido := s.partidos.Resolver(ctx, id.SiglaPartidoParlamentar)
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, politico.HistoricoPartidos, politico.Partido, partido, politico.CreatedAt, time.Now())
+		politico.Partido = partido
 	} else {
 		// Novo político - criar registro
 		politico = domain.Politico{
@@ -188,12 +190,8 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 			FotoURL:        id.URLFotoParlamentar,
 			DataNascimento: dataNascimento,
 			Genero:         mapGenero(id.SexoParlamentar),
-			Partido: domain.Partido{
-				Sigla: id.SiglaPartidoParlamentar,
-				Nome:  "",
-				Cor:   getPartidoCor(id.SiglaPartidoParlamentar),
-			},
-			CargoAtual: novoCargo,
+			Partido:        s.partidos.Resolver(ctx, id.SiglaPartidoParlamentar),
+			CargoAtual:     novoCargo,
 			Contato: domain.Contato{
 				Email:    id.EmailParlamentar,
 				Gabinete: d.DadosBasicosParlamentar.EnderecoParlamentar,
@@ -204,6 +202,7 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 			CreatedAt:       time.Now(),
 			UpdatedAt:       time.Now(),
 		}
+		politico.HistoricoPartidos = s.partidos.AtualizarHistorico(ctx, nil, domain.Partido{}, politico.Partido, time.Time{}, politico.CreatedAt)
 		historicoCargos = []domain.CargoAtual{}
 	}
 
@@ -217,7 +216,6 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 	politico.UpdatedAt = time.Now()
 
 	// Upsert no MongoDB
-	collection := s.db.Collection("politicos")
 	var filter bson.M
 
 	if politicoExistente != nil {
@@ -225,27 +223,27 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 		filter = bson.M{"_id": politicoExistente.ID}
 	} else {
 		// Inserir novo
-		filter = bson.M{
-			"nome_civil":      id.NomeCompletoParlamentar,
-			"data_nascimento": dataNascimento,
-		}
+		filter = bson.M{"id_externo_senado": id.CodigoParlamentar}
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"nome":             politico.Nome,
-			"nome_civil":       politico.NomeCivil,
-			"foto_url":         politico.FotoURL,
-			"data_nascimento":  politico.DataNascimento,
-			"genero":           politico.Genero,
-			"partido":          politico.Partido,
-			"cargo_atual":      politico.CargoAtual,
-			"historico_cargos": politico.HistoricoCargos,
-			"contato":          politico.Contato,
-			"redes_sociais":    politico.RedesSociais,
-			"salario_bruto":    politico.SalarioBruto,
-			"salario_liquido":  politico.SalarioLiquido,
-			"updated_at":       time.Now(),
+			"nome":               politico.Nome,
+			"nome_civil":         politico.NomeCivil,
+			"foto_url":           politico.FotoURL,
+			"data_nascimento":    politico.DataNascimento,
+			"genero":             politico.Genero,
+			"partido":            politico.Partido,
+			"cargo_atual":        politico.CargoAtual,
+			"historico_cargos":   politico.HistoricoCargos,
+			"historico_partidos": politico.HistoricoPartidos,
+			"contato":            politico.Contato,
+			"redes_sociais":      politico.RedesSociais,
+			"salario_bruto":      politico.SalarioBruto,
+			"salario_liquido":    politico.SalarioLiquido,
+			"id_externo_senado":  id.CodigoParlamentar,
+			"fontes.senado":      sync.NovaFonte(ctx, "senado", id.CodigoParlamentar, url),
+			"updated_at":         time.Now(),
 		},
 		"$setOnInsert": bson.M{
 			"_id":        primitive.NewObjectID(),
@@ -253,9 +251,7 @@ func (s *SenadoSync) syncSenador(ctx context.Context, sen Parlamentar) error {
 		},
 	}
 
-	opts := options.Update().SetUpsert(true)
-	_, err = collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return s.auditoria.AtualizarPolitico(ctx, filter, update, true)
 }
 
 // mapGenero converte o gênero da API para nosso modelo
@@ -270,32 +266,3 @@ func mapGenero(sexo string) domain.Genero {
 	}
 }
 
-// getPartidoCor retorna a cor do partido
-func getPartidoCor(sigla string) string {
-	cores := map[string]string{
-		"PT":            "#CC0000",
-		"PL":            "#003366",
-		"UNIÃO":         "#2E3092",
-		"PP":            "#0066CC",
-		"MDB":           "#00AA00",
-		"PSD":           "#FF6600",
-		"REPUBLICANOS":  "#0033CC",
-		"PDT":           "#FF0000",
-		"PSDB":          "#003399",
-		"PSOL":          "#FFD700",
-		"PSB":           "#FF6347",
-		"PODE":          "#00CED1",
-		"CIDADANIA":     "#9932CC",
-		"AVANTE":        "#FF8C00",
-		"SOLIDARIEDADE": "#FF4500",
-		"PCdoB":         "#8B0000",
-		"PV":            "#228B22",
-		"NOVO":          "#FF6600",
-		"REDE":          "#00AA66",
-	}
-
-	if cor, ok := cores[strings.ToUpper(sigla)]; ok {
-		return cor
-	}
-	return "#666666"
-}
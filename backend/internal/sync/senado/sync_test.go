@@ -0,0 +1,84 @@
+package senado
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lupa-cidada/backend/internal/domain"
+	"github.com/lupa-cidada/backend/internal/mongomem/mongomemtest"
+	"github.com/lupa-cidada/backend/internal/sync"
+	"github.com/lupa-cidada/backend/internal/sync/fixtures"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSyncSenadores(t *testing.T) {
+	db := mongomemtest.Banco(t)
+	ctx := context.Background()
+	politicos := db.Collection("politicos")
+
+	// Ex-deputado cadastrado pela Câmara, que agora é senador: deve ser reconhecido pelo
+	// nome civil e pela data de nascimento, sem criar outro cadastro
+	inicioDeputado := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	exDeputado := domain.Politico{
+		ID:              primitive.NewObjectID(),
+		Nome:            "Carlos Menezes",
+		NomeCivil:       "Carlos Menezes Filho",
+		DataNascimento:  time.Date(1960, 7, 20, 0, 0, 0, 0, time.UTC),
+		IDExternoCamara: 1003,
+		Partido:         domain.Partido{Sigla: "MDB"},
+		CargoAtual: domain.CargoAtual{
+			Tipo:        domain.CargoDeputadoFederal,
+			Esfera:      domain.EsferaFederal,
+			Estado:      "SE",
+			DataInicio:  inicioDeputado,
+			EmExercicio: true,
+		},
+		HistoricoCargos: []domain.CargoAtual{},
+		CreatedAt:       inicioDeputado,
+	}
+	if _, err := politicos.InsertOne(ctx, exDeputado); err != nil {
+		t.Fatalf("erro ao cadastrar ex-deputado: %v", err)
+	}
+
+	s := NewSenadoSync(db, sync.ComTransporte(fixtures.Novo("testdata")))
+	if err := s.SyncSenadores(ctx); err != nil {
+		t.Fatalf("SyncSenadores: %v", err)
+	}
+
+	if n, _ := politicos.CountDocuments(ctx, bson.M{}); n != 2 {
+		t.Fatalf("%d políticos; esperado 2 (ex-deputado unificado e senadora nova)", n)
+	}
+
+	var carlos domain.Politico
+	if err := politicos.FindOne(ctx, bson.M{"_id": exDeputado.ID}).Decode(&carlos); err != nil {
+		t.Fatalf("ex-deputado não encontrado: %v", err)
+	}
+	if carlos.IDExternoSenado != "5001" || carlos.IDExternoCamara != 1003 {
+		t.Errorf("IDs externos = câmara %d, senado %q", carlos.IDExternoCamara, carlos.IDExternoSenado)
+	}
+	if carlos.CargoAtual.Tipo != domain.CargoSenador || carlos.CargoAtual.Estado != "SE" {
+		t.Errorf("cargo atual = %+v", carlos.CargoAtual)
+	}
+	if len(carlos.HistoricoCargos) != 1 || carlos.HistoricoCargos[0].Tipo != domain.CargoDeputadoFederal || carlos.HistoricoCargos[0].EmExercicio {
+		t.Errorf("histórico = %+v; esperado o mandato de deputado encerrado", carlos.HistoricoCargos)
+	}
+	if carlos.Contato.Telefone != "33031234" {
+		t.Errorf("telefone = %q", carlos.Contato.Telefone)
+	}
+	if f := carlos.Fontes["senado"]; f.IDExterno != "5001" || f.URL != BaseURL+"/senador/5001.json" {
+		t.Errorf("fonte = %+v", f)
+	}
+
+	var daniela domain.Politico
+	if err := politicos.FindOne(ctx, bson.M{"id_externo_senado": "5002"}).Decode(&daniela); err != nil {
+		t.Fatalf("senadora 5002 não gravada: %v", err)
+	}
+	if daniela.Genero != domain.GeneroFeminino || daniela.Partido.Sigla != "PSD" {
+		t.Errorf("senadora = %q, %q", daniela.Genero, daniela.Partido.Sigla)
+	}
+	if !daniela.CargoAtual.DataInicio.Equal(time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("início do mandato = %v", daniela.CargoAtual.DataInicio)
+	}
+}
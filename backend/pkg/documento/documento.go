@@ -0,0 +1,100 @@
+package documento
+
+import "strings"
+
+// Tipo representa o tipo de documento de pessoa física ou jurídica
+type Tipo string
+
+const (
+	TipoCPF      Tipo = "CPF"
+	TipoCNPJ     Tipo = "CNPJ"
+	TipoInvalido Tipo = ""
+)
+
+// Normalizar remove pontuação e espaços, mantendo apenas os dígitos
+func Normalizar(doc string) string {
+	var b strings.Builder
+	for _, r := range doc {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Identificar normaliza o documento e retorna seu tipo, validando os dígitos verificadores.
+// Documentos com tamanho errado ou dígitos inválidos retornam TipoInvalido.
+func Identificar(doc string) (string, Tipo) {
+	digitos := Normalizar(doc)
+
+	switch {
+	case len(digitos) == 11 && ValidarCPF(digitos):
+		return digitos, TipoCPF
+	case len(digitos) == 14 && ValidarCNPJ(digitos):
+		return digitos, TipoCNPJ
+	default:
+		return digitos, TipoInvalido
+	}
+}
+
+// Pesos do segundo dígito verificador; o primeiro usa os mesmos sem o peso inicial
+var (
+	pesosCPF  = []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}
+	pesosCNPJ = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+// ValidarCPF verifica os dígitos verificadores de um CPF (com ou sem pontuação)
+func ValidarCPF(cpf string) bool {
+	d := Normalizar(cpf)
+	if len(d) != 11 || repetido(d) {
+		return false
+	}
+
+	return digitoVerificador(d[:9], pesosCPF[1:]) == int(d[9]-'0') &&
+		digitoVerificador(d[:10], pesosCPF) == int(d[10]-'0')
+}
+
+// ValidarCNPJ verifica os dígitos verificadores de um CNPJ (com ou sem pontuação)
+func ValidarCNPJ(cnpj string) bool {
+	d := Normalizar(cnpj)
+	if len(d) != 14 || repetido(d) {
+		return false
+	}
+
+	return digitoVerificador(d[:12], pesosCNPJ[1:]) == int(d[12]-'0') &&
+		digitoVerificador(d[:13], pesosCNPJ) == int(d[13]-'0')
+}
+
+// CompletarCPF acrescenta os dígitos verificadores aos 9 primeiros dígitos de um CPF
+func CompletarCPF(base string) string {
+	return completar(base, pesosCPF)
+}
+
+// CompletarCNPJ acrescenta os dígitos verificadores aos 12 primeiros dígitos de um CNPJ
+func CompletarCNPJ(base string) string {
+	return completar(base, pesosCNPJ)
+}
+
+func completar(base string, pesos []int) string {
+	d := base + string(rune('0'+digitoVerificador(base, pesos[1:])))
+	return d + string(rune('0'+digitoVerificador(d, pesos)))
+}
+
+// digitoVerificador calcula o dígito pelo módulo 11 usado em CPF e CNPJ
+func digitoVerificador(digitos string, pesos []int) int {
+	soma := 0
+	for i, r := range digitos {
+		soma += int(r-'0') * pesos[i]
+	}
+
+	resto := soma % 11
+	if resto < 2 {
+		return 0
+	}
+	return 11 - resto
+}
+
+// repetido detecta sequências como 000.000.000-00, que passam no cálculo mas não são válidas
+func repetido(d string) bool {
+	return strings.Count(d, d[:1]) == len(d)
+}
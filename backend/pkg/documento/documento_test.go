@@ -0,0 +1,64 @@
+package documento
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIdentificar(t *testing.T) {
+	casos := []struct {
+		doc     string
+		digitos string
+		tipo    Tipo
+	}{
+		{"123.456.789-09", "12345678909", TipoCPF},
+		{"529.982.247-25", "52998224725", TipoCPF},
+		{"11.222.333/0001-81", "11222333000181", TipoCNPJ},
+		{" 11222333000181 ", "11222333000181", TipoCNPJ},
+		// Dígitos verificadores errados: o primeiro e o segundo
+		{"123.456.789-19", "12345678919", TipoInvalido},
+		{"123.456.789-08", "12345678908", TipoInvalido},
+		{"11.222.333/0001-91", "11222333000191", TipoInvalido},
+		{"11.222.333/0001-80", "11222333000180", TipoInvalido},
+		// Sequências repetidas passam no cálculo mas não são documentos
+		{"111.111.111-11", "11111111111", TipoInvalido},
+		{"00.000.000/0000-00", "00000000000000", TipoInvalido},
+		// Tamanhos que não são de CPF nem de CNPJ
+		{"1234567890", "1234567890", TipoInvalido},
+		{"", "", TipoInvalido},
+	}
+
+	for _, c := range casos {
+		digitos, tipo := Identificar(c.doc)
+		if digitos != c.digitos || tipo != c.tipo {
+			t.Errorf("Identificar(%q) = %q, %q; esperado %q, %q", c.doc, digitos, tipo, c.digitos, c.tipo)
+		}
+	}
+}
+
+// Os dígitos calculados por Completar passam na validação, inclusive quando o resto é 0 ou 1
+// (dígito 0), e mudar qualquer um deles invalida o documento
+func TestCompletar(t *testing.T) {
+	if cpf := CompletarCPF("123456789"); cpf != "12345678909" {
+		t.Errorf("CompletarCPF = %s; esperado 12345678909", cpf)
+	}
+	if cnpj := CompletarCNPJ("112223330001"); cnpj != "11222333000181" {
+		t.Errorf("CompletarCNPJ = %s; esperado 11222333000181", cnpj)
+	}
+
+	for i := 1; i < 200; i++ {
+		cpf := CompletarCPF(fmt.Sprintf("%09d", i*4999))
+		cnpj := CompletarCNPJ(fmt.Sprintf("%08d0001", i*49999))
+		if !ValidarCPF(cpf) || !ValidarCNPJ(cnpj) {
+			t.Fatalf("documentos completados inválidos: %s, %s", cpf, cnpj)
+		}
+		if ValidarCPF(trocarUltimo(cpf)) || ValidarCNPJ(trocarUltimo(cnpj)) {
+			t.Fatalf("dígito verificador trocado aceito: %s, %s", trocarUltimo(cpf), trocarUltimo(cnpj))
+		}
+	}
+}
+
+func trocarUltimo(doc string) string {
+	ultimo := doc[len(doc)-1]
+	return doc[:len(doc)-1] + string('0'+(ultimo-'0'+1)%10)
+}
@@ -10,6 +10,16 @@ db.createCollection('proposicoes');
 db.createCollection('despesas');
 db.createCollection('presencas');
 db.createCollection('partidos');
+db.createCollection('alertas');
+db.createCollection('cadastro_fornecedores');
+db.createCollection('alteracoes');
+db.createCollection('revisoes_identidade');
+db.createCollection('unificacoes');
+db.createCollection('chaves_api');
+db.createCollection('execucoes_sync');
+db.createCollection('travas');
+db.createCollection('problemas_qualidade');
+db.createCollection('arquivo_bruto_indice');
 
 // Índices para políticos
 db.politicos.createIndex({ "nome": "text", "nome_civil": "text" });
@@ -20,6 +30,12 @@ db.politicos.createIndex({ "cargo_atual.estado": 1 });
 db.politicos.createIndex({ "cargo_atual.em_exercicio": 1 });
 db.politicos.createIndex({ "genero": 1 });
 db.politicos.createIndex({ "created_at": -1 });
+db.politicos.createIndex({ "historico_partidos.data_inicio": -1 });
+db.politicos.createIndex({ "cpf": 1 });
+db.politicos.createIndex({ "data_nascimento": 1 });
+db.politicos.createIndex({ "id_externo_camara": 1 });
+db.politicos.createIndex({ "id_externo_senado": 1 });
+db.politicos.createIndex({ "id_externo_tse": 1 });
 
 // Índices para votações
 db.votacoes.createIndex({ "politico_id": 1 });
@@ -27,6 +43,8 @@ db.votacoes.createIndex({ "proposicao_id": 1 });
 db.votacoes.createIndex({ "data": -1 });
 db.votacoes.createIndex({ "voto": 1 });
 db.votacoes.createIndex({ "politico_id": 1, "data": -1 });
+db.votacoes.createIndex({ "politico_id": 1, "votacao_id_externo": 1 });
+db.votacoes.createIndex({ "votacao_id_externo": 1, "voto": 1 });
 
 // Índices para proposições
 db.proposicoes.createIndex({ "tipo": 1 });
@@ -43,34 +61,61 @@ db.despesas.createIndex({ "data": -1 });
 db.despesas.createIndex({ "ano_referencia": 1, "mes_referencia": 1 });
 db.despesas.createIndex({ "politico_id": 1, "ano_referencia": 1 });
 db.despesas.createIndex({ "valor": -1 });
+db.despesas.createIndex({ "cnpj_fornecedor": 1, "data": -1 });
+db.despesas.createIndex({ "politico_id": 1, "cod_documento": 1, "num_documento": 1, "cod_lote": 1, "parcela": 1 });
+db.despesas.createIndex({ "politico_id": 1, "ano_referencia": 1, "sincronizado_em": 1 });
 
 // Índices para presenças
 db.presencas.createIndex({ "politico_id": 1 });
 db.presencas.createIndex({ "data": -1 });
 db.presencas.createIndex({ "politico_id": 1, "data": -1 });
 
-// Inserir partidos iniciais
-db.partidos.insertMany([
-  { sigla: "PT", nome: "Partido dos Trabalhadores", cor: "#CC0000" },
-  { sigla: "PL", nome: "Partido Liberal", cor: "#003366" },
-  { sigla: "UNIÃO", nome: "União Brasil", cor: "#2E3092" },
-  { sigla: "PP", nome: "Progressistas", cor: "#0066CC" },
-  { sigla: "MDB", nome: "Movimento Democrático Brasileiro", cor: "#00AA00" },
-  { sigla: "PSD", nome: "Partido Social Democrático", cor: "#FF6600" },
-  { sigla: "REPUBLICANOS", nome: "Republicanos", cor: "#0033CC" },
-  { sigla: "PDT", nome: "Partido Democrático Trabalhista", cor: "#FF0000" },
-  { sigla: "PSDB", nome: "Partido da Social Democracia Brasileira", cor: "#003399" },
-  { sigla: "PSOL", nome: "Partido Socialismo e Liberdade", cor: "#FFD700" },
-  { sigla: "PSB", nome: "Partido Socialista Brasileiro", cor: "#FF6347" },
-  { sigla: "PODE", nome: "Podemos", cor: "#00CED1" },
-  { sigla: "CIDADANIA", nome: "Cidadania", cor: "#9932CC" },
-  { sigla: "AVANTE", nome: "Avante", cor: "#FF8C00" },
-  { sigla: "SOLIDARIEDADE", nome: "Solidariedade", cor: "#FF4500" },
-  { sigla: "PCdoB", nome: "Partido Comunista do Brasil", cor: "#8B0000" },
-  { sigla: "PV", nome: "Partido Verde", cor: "#228B22" },
-  { sigla: "NOVO", nome: "Partido Novo", cor: "#FF6600" },
-  { sigla: "REDE", nome: "Rede Sustentabilidade", cor: "#00AA66" }
-]);
+// Índices para alertas de despesas
+db.alertas.createIndex({ "chave": 1 }, { unique: true });
+db.alertas.createIndex({ "politico_id": 1 });
+db.alertas.createIndex({ "tipo": 1 });
+db.alertas.createIndex({ "severidade": 1 });
+db.alertas.createIndex({ "ano_referencia": -1, "mes_referencia": -1 });
+db.cadastro_fornecedores.createIndex({ "cnpj": 1 }, { unique: true });
+
+// Índices para a auditoria de alterações nos políticos
+db.alteracoes.createIndex({ "politico_id": 1, "created_at": -1 });
+db.alteracoes.createIndex({ "execucao_id": 1 });
+
+// Índices para a fila de revisão de identidades ambíguas
+db.revisoes_identidade.createIndex({ "status": 1, "created_at": -1 });
+db.revisoes_identidade.createIndex({ "fonte": 1, "nome_civil": 1, "data_nascimento": 1 });
+
+// Índices para as unificações manuais de cadastros
+db.unificacoes.createIndex({ "status": 1, "created_at": -1 });
+db.unificacoes.createIndex({ "duplicado.cpf": 1 });
+db.unificacoes.createIndex({ "duplicado.id_externo_camara": 1 });
+db.unificacoes.createIndex({ "duplicado.id_externo_senado": 1 });
+db.unificacoes.createIndex({ "duplicado.id_externo_tse": 1 });
+
+// Índices para as chaves de API (só o hash da chave é guardado)
+db.chaves_api.createIndex({ "hash": 1 }, { unique: true });
+
+// Índices para o histórico de sincronizações
+db.execucoes_sync.createIndex({ "created_at": -1 });
+db.execucoes_sync.createIndex({ "status": 1, "created_at": -1 });
+db.execucoes_sync.createIndex({ "agendamento": 1, "created_at": -1 });
+
+// Índices para os problemas de qualidade encontrados nas sincronizações
+db.problemas_qualidade.createIndex({ "created_at": -1 });
+db.problemas_qualidade.createIndex({ "chave": 1 }, { unique: true });
+db.problemas_qualidade.createIndex({ "visto_em": -1 });
+db.problemas_qualidade.createIndex({ "execucao_id": 1, "regra": 1 });
+db.problemas_qualidade.createIndex({ "execucao_id": 1, "registro": 1 });
+
+// Índice do arquivo de respostas brutas das APIs (ARQUIVO_BRUTO=gridfs)
+db.arquivo_bruto_indice.createIndex({ "url": 1, "dia": -1 }, { unique: true });
+
+// Índices para partidos
+db.partidos.createIndex({ "sigla": 1 }, { unique: true });
+db.partidos.createIndex({ "siglas_anteriores": 1 });
+
+// Os partidos são gravados pela API, pelo cmd/sync e pelo seed a partir de internal/partidos
 
 print('✅ Banco de dados lupa_cidada inicializado com sucesso!');
 